@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+// allowedAddressPair allows traffic from the addresses of IPNet with MACAddress, in addition to
+// the IP/MAC pairs of the VM.
+type allowedAddressPair struct {
+	IPNet      *net.IPNet
+	MACAddress net.HardwareAddr
+}
+
+func (p allowedAddressPair) String() string {
+	return p.IPNet.String() + " " + p.MACAddress.String()
+}
+
+// family returns the family of the addresses of the pair.
+func (p allowedAddressPair) family() ipFamily {
+	return getIPFamily(p.IPNet.IP)
+}
+
+func (a *agent) AddAllowedAddressPairs(ctx context.Context, req *pb.AddAllowedAddressPairsRequest) (*pb.AddAllowedAddressPairsResponse, error) {
+	if req.Interface == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "interface must be set")
+	}
+	if _, err := parseAllowedAddressPairs(req.AllowedAddressPairs, nil); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse allowed address pairs: %+v", err)
+	}
+
+	unlock, err := a.lockSecurityGroup(ctx, req.Interface)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	record, err := a.getSecurityGroupRecord(ctx, req.Interface)
+	if err != nil {
+		return nil, err
+	}
+	intf, err := parseSecurityGroupLink(record)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "invalid security group record: %+v", err)
+	}
+	added, err := parseAllowedAddressPairs(req.AllowedAddressPairs, intf.MACAddress)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse allowed address pairs: %+v", err)
+	}
+
+	allowed := make(map[string]bool)
+	for _, pair := range intf.AllowedAddressPairs {
+		allowed[pair.String()] = true
+	}
+	record = proto.Clone(record).(*pb.AddSecurityGroupRequest)
+	for i, pair := range added {
+		if allowed[pair.String()] {
+			continue
+		}
+		allowed[pair.String()] = true
+		intf.AllowedAddressPairs = append(intf.AllowedAddressPairs, pair)
+		record.AllowedAddressPairs = append(record.AllowedAddressPairs, req.AllowedAddressPairs[i])
+	}
+
+	if err := a.firewall.UpdateSource(ctx, intf); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update allowed address pairs: %+v", err)
+	}
+	if err := a.securityGroups.Put(record); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to record security group: %+v", err)
+	}
+
+	return &pb.AddAllowedAddressPairsResponse{}, nil
+}
+
+func (a *agent) RemoveAllowedAddressPairs(ctx context.Context, req *pb.RemoveAllowedAddressPairsRequest) (*pb.RemoveAllowedAddressPairsResponse, error) {
+	if req.Interface == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "interface must be set")
+	}
+	if _, err := parseAllowedAddressPairs(req.AllowedAddressPairs, nil); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse allowed address pairs: %+v", err)
+	}
+
+	unlock, err := a.lockSecurityGroup(ctx, req.Interface)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	record, err := a.getSecurityGroupRecord(ctx, req.Interface)
+	if err != nil {
+		return nil, err
+	}
+	intf, err := parseSecurityGroupLink(record)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "invalid security group record: %+v", err)
+	}
+	removed, err := parseAllowedAddressPairs(req.AllowedAddressPairs, intf.MACAddress)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse allowed address pairs: %+v", err)
+	}
+
+	remove := make(map[string]bool)
+	for _, pair := range removed {
+		remove[pair.String()] = true
+	}
+	// the pairs of the link are parsed from the pairs of the record in the same order
+	pairs, recorded := intf.AllowedAddressPairs, record.AllowedAddressPairs
+	intf.AllowedAddressPairs = nil
+	record = proto.Clone(record).(*pb.AddSecurityGroupRequest)
+	record.AllowedAddressPairs = nil
+	var removedIPs []net.IP
+	for i, pair := range pairs {
+		if !remove[pair.String()] {
+			intf.AllowedAddressPairs = append(intf.AllowedAddressPairs, pair)
+			record.AllowedAddressPairs = append(record.AllowedAddressPairs, recorded[i])
+			continue
+		}
+		// connections of a network can belong to other virtual machines, so that only the ones of an address are deleted
+		if ones, bits := pair.IPNet.Mask.Size(); ones == bits {
+			removedIPs = append(removedIPs, pair.IPNet.IP)
+		}
+	}
+
+	if err := a.firewall.UpdateSource(ctx, intf); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update allowed address pairs: %+v", err)
+	}
+	if err := a.securityGroups.Put(record); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to record security group: %+v", err)
+	}
+
+	var disallowedIPs []net.IP
+	for _, ipAddr := range removedIPs {
+		if !intf.allows(ipAddr) {
+			disallowedIPs = append(disallowedIPs, ipAddr)
+		}
+	}
+	a.deleteConntrackEntriesOf(req.Interface, disallowedIPs)
+
+	return &pb.RemoveAllowedAddressPairsResponse{}, nil
+}
+
+// getSecurityGroupRecord returns the record of the security group of name, which the source chain is built from.
+func (a *agent) getSecurityGroupRecord(ctx context.Context, name string) (*pb.AddSecurityGroupRequest, error) {
+	record, err := a.securityGroups.Get(name)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get security group record: %+v", err)
+	}
+	if record != nil {
+		return record, nil
+	}
+
+	exists, err := a.firewall.Exists(ctx, link{Name: name})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to check security group: %+v", err)
+	}
+	if !exists {
+		return nil, notFoundError("security group", name)
+	}
+	// security groups added before the records were introduced are not recorded
+	return nil, status.Errorf(codes.FailedPrecondition, "security group of %s is not recorded, add it again", name)
+}
+
+// parseAllowedAddressPairs parses pairs. The pairs without a MAC address are paired with macAddr.
+func parseAllowedAddressPairs(pairs []*pb.AllowedAddressPair, macAddr net.HardwareAddr) ([]allowedAddressPair, error) {
+	parsed := make([]allowedAddressPair, 0, len(pairs))
+	for _, pair := range pairs {
+		p, err := parseAllowedAddressPair(pair, macAddr)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, p)
+	}
+	return parsed, nil
+}
+
+func parseAllowedAddressPair(pair *pb.AllowedAddressPair, macAddr net.HardwareAddr) (allowedAddressPair, error) {
+	var ipNet *net.IPNet
+	if ip := net.ParseIP(pair.IpAddress); ip != nil {
+		bits := 8 * net.IPv6len
+		if ip.To4() != nil {
+			ip, bits = ip.To4(), 8*net.IPv4len
+		}
+		ipNet = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+	} else {
+		_, n, err := net.ParseCIDR(pair.IpAddress)
+		if err != nil {
+			return allowedAddressPair{}, fmt.Errorf("invalid IP address or CIDR %q", pair.IpAddress)
+		}
+		ipNet = n
+	}
+
+	if pair.MacAddress != "" {
+		mac, err := net.ParseMAC(pair.MacAddress)
+		if err != nil {
+			return allowedAddressPair{}, fmt.Errorf("failed to parse MAC address of %s: %w", pair.IpAddress, err)
+		}
+		macAddr = mac
+	}
+	return allowedAddressPair{IPNet: ipNet, MACAddress: macAddr}, nil
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vishvananda/netlink"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+func TestAddAllowedAddressPairs(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *pb.AddAllowedAddressPairsRequest
+		// unrecorded deletes the record of the security group before the call
+		unrecorded bool
+		want       codes.Code
+		// wantSource and wantSource6 are the sources of the allow rules in the source chains
+		wantSource  []string
+		wantSource6 []string
+	}{
+		{
+			name: "address and networks",
+			req: &pb.AddAllowedAddressPairsRequest{
+				Interface: "tap0",
+				AllowedAddressPairs: []*pb.AllowedAddressPair{
+					{IpAddress: "192.0.2.200"},
+					{IpAddress: "198.51.100.17/28", MacAddress: "52:54:00:00:00:09"},
+					{IpAddress: "2001:db8:1::/64"},
+				},
+			},
+			want: codes.OK,
+			wantSource: []string{
+				"-s 192.0.2.100/32 -m mac --mac-source 52:54:00:00:00:01",
+				"-s 192.0.2.10/32 -m mac --mac-source 52:54:00:00:00:01",
+				"-s 192.0.2.200/32 -m mac --mac-source 52:54:00:00:00:01",
+				"-s 198.51.100.16/28 -m mac --mac-source 52:54:00:00:00:09",
+			},
+			wantSource6: []string{
+				"-s 2001:db8::100/128 -m mac --mac-source 52:54:00:00:00:01",
+				"-s 2001:db8:1::/64 -m mac --mac-source 52:54:00:00:00:01",
+				"-s fe80::/10 -m mac --mac-source 52:54:00:00:00:01",
+				"-s ::/128 -p ipv6-icmp -m mac --mac-source 52:54:00:00:00:01",
+			},
+		},
+		{
+			name: "allowed pair",
+			req: &pb.AddAllowedAddressPairsRequest{
+				Interface:           "tap0",
+				AllowedAddressPairs: []*pb.AllowedAddressPair{{IpAddress: "192.0.2.10/32", MacAddress: "52:54:00:00:00:01"}},
+			},
+			want: codes.OK,
+			wantSource: []string{
+				"-s 192.0.2.100/32 -m mac --mac-source 52:54:00:00:00:01",
+				"-s 192.0.2.10/32 -m mac --mac-source 52:54:00:00:00:01",
+			},
+			wantSource6: []string{
+				"-s 2001:db8::100/128 -m mac --mac-source 52:54:00:00:00:01",
+				"-s fe80::/10 -m mac --mac-source 52:54:00:00:00:01",
+				"-s ::/128 -p ipv6-icmp -m mac --mac-source 52:54:00:00:00:01",
+			},
+		},
+		{
+			name: "empty interface",
+			req:  &pb.AddAllowedAddressPairsRequest{AllowedAddressPairs: []*pb.AllowedAddressPair{{IpAddress: "192.0.2.200"}}},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "invalid address",
+			req:  &pb.AddAllowedAddressPairsRequest{Interface: "tap0", AllowedAddressPairs: []*pb.AllowedAddressPair{{IpAddress: "192.0.2"}}},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "invalid mac address",
+			req:  &pb.AddAllowedAddressPairsRequest{Interface: "tap0", AllowedAddressPairs: []*pb.AllowedAddressPair{{IpAddress: "192.0.2.200", MacAddress: "52:54:00"}}},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "missing security group",
+			req:  &pb.AddAllowedAddressPairsRequest{Interface: "tap9", AllowedAddressPairs: []*pb.AllowedAddressPair{{IpAddress: "192.0.2.200"}}},
+			want: codes.NotFound,
+		},
+		{
+			name:       "unrecorded security group",
+			req:        &pb.AddAllowedAddressPairsRequest{Interface: "tap0", AllowedAddressPairs: []*pb.AllowedAddressPair{{IpAddress: "192.0.2.200"}}},
+			unrecorded: true,
+			want:       codes.FailedPrecondition,
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		ctx := context.Background()
+		if _, err := a.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{}); err != nil {
+			t.Fatalf("%s: failed to setup default security group: %+v", test.name, err)
+		}
+		req := &pb.AddSecurityGroupRequest{
+			Interface:           "tap0",
+			IpAddress:           "192.0.2.100",
+			Ipv6Address:         "2001:db8::100",
+			MacAddress:          "52:54:00:00:00:01",
+			IngressRules:        []*pb.SecurityGroupRule{{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 22}},
+			AllowedAddressPairs: []*pb.AllowedAddressPair{{IpAddress: "192.0.2.10"}},
+		}
+		if _, err := a.AddSecurityGroup(ctx, req); err != nil {
+			t.Fatalf("%s: failed to add security group: %+v", test.name, err)
+		}
+		if test.unrecorded {
+			if err := a.securityGroups.Delete("tap0"); err != nil {
+				t.Fatalf("%s: failed to delete security group record: %+v", test.name, err)
+			}
+		}
+		before := savedFakeIPTables(t, f.iptables)
+
+		_, err := a.AddAllowedAddressPairs(ctx, test.req)
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %+v", test.name, test.want, err)
+			continue
+		}
+		if test.want != codes.OK {
+			continue
+		}
+
+		for _, source := range []struct {
+			iptables *fakeIPTables
+			want     []string
+		}{
+			{iptables: f.iptables, want: test.wantSource},
+			{iptables: f.ip6tables, want: test.wantSource6},
+		} {
+			got := source.iptables.rules(tableFilter, "callisto-stap0")
+			if len(got) != len(source.want)+1 {
+				t.Errorf("%s: want %d rules in source chain, but got %q", test.name, len(source.want)+1, got)
+				continue
+			}
+			for i, want := range source.want {
+				if !strings.HasPrefix(got[i], want+" ") {
+					t.Errorf("%s: want %s in source rule %d, but got %s", test.name, want, i, got[i])
+				}
+			}
+		}
+
+		// the other chains are not rebuilt
+		after := savedFakeIPTables(t, f.iptables)
+		for chain, rules := range before {
+			if chain == "callisto-stap0" {
+				continue
+			}
+			if strings.Join(after[chain], "\n") != strings.Join(rules, "\n") {
+				t.Errorf("%s: want %q in %s, but got %q", test.name, rules, chain, after[chain])
+			}
+		}
+
+		// the recorded pairs are the ones in the chains
+		records, err := a.securityGroups.List()
+		if err != nil {
+			t.Fatalf("%s: failed to list security group records: %+v", test.name, err)
+		}
+		intf, err := parseSecurityGroupLink(records[0])
+		if err != nil {
+			t.Fatalf("%s: invalid security group record: %+v", test.name, err)
+		}
+		if _, drifted, err := a.firewall.Verify(ctx, []link{intf}); err != nil || len(drifted) != 0 {
+			t.Errorf("%s: want no drifted security groups, but got %q: %+v", test.name, drifted, err)
+		}
+	}
+}
+
+func TestRemoveAllowedAddressPairs(t *testing.T) {
+	tests := []struct {
+		name  string
+		pairs []*pb.AllowedAddressPair
+		// conntrackErr is returned when deleting conntrack entries
+		conntrackErr error
+		want         codes.Code
+		// wantSource is the number of allow rules in the IPv4 source chain
+		wantSource int
+		wantFlows  int
+	}{
+		{
+			name:       "address",
+			pairs:      []*pb.AllowedAddressPair{{IpAddress: "192.0.2.10"}},
+			want:       codes.OK,
+			wantSource: 3,
+			wantFlows:  3,
+		},
+		{
+			name:       "network",
+			pairs:      []*pb.AllowedAddressPair{{IpAddress: "198.51.100.16/28", MacAddress: "52:54:00:00:00:09"}},
+			want:       codes.OK,
+			wantSource: 3,
+			wantFlows:  4,
+		},
+		{
+			name:       "address allowed with another mac address",
+			pairs:      []*pb.AllowedAddressPair{{IpAddress: "192.0.2.20", MacAddress: "52:54:00:00:00:09"}},
+			want:       codes.OK,
+			wantSource: 3,
+			wantFlows:  4,
+		},
+		{
+			name:       "pair that is not allowed",
+			pairs:      []*pb.AllowedAddressPair{{IpAddress: "192.0.2.10", MacAddress: "52:54:00:00:00:09"}},
+			want:       codes.OK,
+			wantSource: 4,
+			wantFlows:  4,
+		},
+		{
+			name:         "conntrack failure",
+			pairs:        []*pb.AllowedAddressPair{{IpAddress: "192.0.2.10"}},
+			conntrackErr: errors.New("operation not permitted"),
+			want:         codes.OK,
+			wantSource:   3,
+			wantFlows:    4,
+		},
+		{
+			name:       "missing security group",
+			pairs:      nil,
+			want:       codes.NotFound,
+			wantSource: 4,
+			wantFlows:  4,
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		ctx := context.Background()
+		if _, err := a.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{}); err != nil {
+			t.Fatalf("%s: failed to setup default security group: %+v", test.name, err)
+		}
+		req := &pb.AddSecurityGroupRequest{
+			Interface:  "tap0",
+			IpAddress:  "192.0.2.100",
+			MacAddress: "52:54:00:00:00:01",
+			AllowedAddressPairs: []*pb.AllowedAddressPair{
+				{IpAddress: "192.0.2.10"},
+				{IpAddress: "198.51.100.16/28", MacAddress: "52:54:00:00:00:09"},
+				{IpAddress: "192.0.2.20"},
+				{IpAddress: "192.0.2.20", MacAddress: "52:54:00:00:00:09"},
+			},
+		}
+		if _, err := a.AddSecurityGroup(ctx, req); err != nil {
+			t.Fatalf("%s: failed to add security group: %+v", test.name, err)
+		}
+		f.netlink.flows = []*netlink.ConntrackFlow{
+			testConntrackFlow("192.0.2.10", "203.0.113.1"),
+			testConntrackFlow("198.51.100.17", "203.0.113.1"),
+			testConntrackFlow("192.0.2.20", "203.0.113.1"),
+			testConntrackFlow("192.0.2.100", "203.0.113.1"),
+		}
+		f.netlink.conntrackErr = test.conntrackErr
+
+		intf := "tap0"
+		if test.want == codes.NotFound {
+			intf = "tap9"
+		}
+		_, err := a.RemoveAllowedAddressPairs(ctx, &pb.RemoveAllowedAddressPairsRequest{Interface: intf, AllowedAddressPairs: test.pairs})
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %+v", test.name, test.want, err)
+			continue
+		}
+
+		// the source chain has the allow rules of the address of the VM, the remaining pairs, and the drop rule
+		if got := len(f.iptables.rules(tableFilter, "callisto-stap0")); got != 1+test.wantSource+1 {
+			t.Errorf("%s: want %d rules in source chain, but got %d", test.name, 1+test.wantSource+1, got)
+		}
+		if len(f.netlink.flows) != test.wantFlows {
+			t.Errorf("%s: want %d conntrack flows, but got %d", test.name, test.wantFlows, len(f.netlink.flows))
+		}
+		record, err := a.securityGroups.Get("tap0")
+		if err != nil {
+			t.Fatalf("%s: failed to get security group record: %+v", test.name, err)
+		}
+		if len(record.AllowedAddressPairs) != test.wantSource {
+			t.Errorf("%s: want %d recorded pairs, but got %d", test.name, test.wantSource, len(record.AllowedAddressPairs))
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	grpc_ctxtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	roleAdmin    = "admin"
+	roleReadOnly = "read-only"
+
+	healthServicePrefix = "/grpc.health.v1.Health/"
+)
+
+// methodReadOnly classifies every method of the agent api by whether it only reads state.
+// read-only role can call only the methods listed as true here, so that a new method is not allowed to
+// the role by its name, and the tests fail until it is classified.
+var methodReadOnly = map[string]bool{
+	"/agent.Agent/GetISCSIQualifiedName":     true,
+	"/agent.Agent/GetIPTables":               true,
+	"/agent.Agent/SetupDefaultSecurityGroup": false,
+	"/agent.Agent/AddSecurityGroup":          false,
+	"/agent.Agent/RemoveSecurityGroup":       false,
+	"/agent.Agent/UpdateSecurityGroupRules":  false,
+	"/agent.Agent/GetInterfaceName":          true,
+	"/agent.Agent/AddBridge":                 false,
+	"/agent.Agent/AddVLANInterface":          false,
+	"/agent.Agent/AddInterfaceToBridge":      false,
+	"/agent.Agent/AddVirtualMachine":         false,
+	"/agent.Agent/ConnectBlockDevice":        false,
+	"/agent.Agent/GetVirtualMachineState":    true,
+	"/agent.Agent/ListVirtualMachineState":   true,
+	"/agent.Agent/StartVirtualMachine":       false,
+	"/agent.Agent/AttachBlockDevice":         false,
+	"/agent.Agent/AttachInterface":           false,
+	"/agent.Agent/DeleteBridge":              false,
+	"/agent.Agent/DeleteVLANInterface":       false,
+	"/agent.Agent/DeleteInterfaceFromBridge": false,
+	"/agent.Agent/DeleteVirtualMachine":      false,
+	"/agent.Agent/DisconnectBlockDevice":     false,
+	"/agent.Agent/StopVirtualMachine":        false,
+	"/agent.Agent/DetachBlockDevice":         false,
+	"/agent.Agent/DetachInterface":           false,
+	"/agent.Agent/GetOperation":              true,
+	"/agent.Agent/ListOperations":            true,
+	"/agent.Agent/CancelOperation":           false,
+	"/agent.Agent/WaitOperation":             true,
+	"/agent.Agent/RunPreflightChecks":        false,
+	"/agent.Agent/SetRemoteGroupMembers":     false,
+	"/agent.Agent/DeleteRemoteGroup":         false,
+	"/agent.Agent/ListRemoteGroups":          true,
+	"/agent.Agent/AddAllowedAddressPairs":    false,
+	"/agent.Agent/RemoveAllowedAddressPairs": false,
+}
+
+type authorizationConfig struct {
+	// Identities maps a client identity to its role.
+	// The identity is the common name of the client certificate or the identity of a token.
+	Identities map[string]string `yaml:"identities"`
+	Tokens     []tokenConfig     `yaml:"tokens"`
+}
+
+type tokenConfig struct {
+	Identity  string `yaml:"identity"`
+	TokenFile string `yaml:"token_file"`
+}
+
+// Enabled reports whether the authorization is configured.
+func (c authorizationConfig) Enabled() bool {
+	return len(c.Identities) != 0
+}
+
+func (c authorizationConfig) validate() error {
+	for identity, role := range c.Identities {
+		switch role {
+		case roleAdmin, roleReadOnly:
+		default:
+			return fmt.Errorf("invalid role %q of identity %q", role, identity)
+		}
+	}
+	for _, token := range c.Tokens {
+		if token.Identity == "" || token.TokenFile == "" {
+			return fmt.Errorf("identity and token_file of token must be set")
+		}
+		if _, ok := c.Identities[token.Identity]; !ok {
+			return fmt.Errorf("identity %q of token has no role", token.Identity)
+		}
+	}
+	return nil
+}
+
+// authorizer authenticates clients by their certificate or a bearer token,
+// and allows the methods permitted to the role of the client.
+type authorizer struct {
+	identities map[string]string
+	tokens     map[string][]byte
+}
+
+func newAuthorizer(c authorizationConfig) (*authorizer, error) {
+	tokens := make(map[string][]byte, len(c.Tokens))
+	for _, token := range c.Tokens {
+		b, err := ioutil.ReadFile(token.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read token file: %w", err)
+		}
+		b = []byte(strings.TrimSpace(string(b)))
+		if len(b) == 0 {
+			return nil, fmt.Errorf("token file is empty: %s", token.TokenFile)
+		}
+		tokens[token.Identity] = b
+	}
+
+	return &authorizer{
+		identities: c.Identities,
+		tokens:     tokens,
+	}, nil
+}
+
+// UnaryServerInterceptor returns a new unary server interceptor that authorizes the calls.
+func (a *authorizer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a new stream server interceptor that authorizes the calls.
+func (a *authorizer) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.authorize(stream.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
+
+func (a *authorizer) authorize(ctx context.Context, fullMethod string) error {
+	identity, err := a.identify(ctx)
+	if err != nil {
+		return err
+	}
+	grpc_ctxtags.Extract(ctx).Set("auth.identity", identity)
+
+	role, ok := a.identities[identity]
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "unknown identity: %s", identity)
+	}
+	if !roleAllows(role, fullMethod) {
+		return status.Errorf(codes.PermissionDenied, "identity %s is not allowed to call %s", identity, fullMethod)
+	}
+	return nil
+}
+
+// identify returns the identity of the client.
+// A bearer token takes precedence over the client certificate.
+func (a *authorizer) identify(ctx context.Context) (string, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) != 0 {
+			token := strings.TrimPrefix(values[0], "Bearer ")
+			for identity, t := range a.tokens {
+				if subtle.ConstantTimeCompare([]byte(token), t) == 1 {
+					return identity, nil
+				}
+			}
+			return "", status.Errorf(codes.Unauthenticated, "invalid token")
+		}
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", status.Errorf(codes.Unauthenticated, "failed to get peer")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return "", status.Errorf(codes.Unauthenticated, "client certificate or token is required")
+	}
+	return tlsInfo.State.VerifiedChains[0][0].Subject.CommonName, nil
+}
+
+// roleAllows reports whether role is allowed to call fullMethod.
+// read-only role can call the read-only methods of methodReadOnly and the health service.
+func roleAllows(role, fullMethod string) bool {
+	switch role {
+	case roleAdmin:
+		return true
+	case roleReadOnly:
+		if strings.HasPrefix(fullMethod, healthServicePrefix) {
+			return true
+		}
+		return methodReadOnly[fullMethod]
+	default:
+		return false
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+func TestAuthorizerAuthorize(t *testing.T) {
+	a := &authorizer{
+		identities: map[string]string{
+			"satelit":    roleAdmin,
+			"monitoring": roleReadOnly,
+			"nobody":     "unknown",
+		},
+		tokens: map[string][]byte{
+			"satelit":    []byte("admin-token"),
+			"monitoring": []byte("monitoring-token"),
+			"nobody":     []byte("nobody-token"),
+		},
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		method string
+		want   codes.Code
+	}{
+		{
+			name:   "admin can call mutating method",
+			token:  "admin-token",
+			method: "/agent.Agent/AddSecurityGroup",
+			want:   codes.OK,
+		},
+		{
+			name:   "read-only can call get method",
+			token:  "monitoring-token",
+			method: "/agent.Agent/GetVirtualMachineState",
+			want:   codes.OK,
+		},
+		{
+			name:   "read-only can call list method",
+			token:  "monitoring-token",
+			method: "/agent.Agent/ListVirtualMachineState",
+			want:   codes.OK,
+		},
+		{
+			name:   "read-only can wait for operation",
+			token:  "monitoring-token",
+			method: "/agent.Agent/WaitOperation",
+			want:   codes.OK,
+		},
+		{
+			name:   "read-only cannot cancel operation",
+			token:  "monitoring-token",
+			method: "/agent.Agent/CancelOperation",
+			want:   codes.PermissionDenied,
+		},
+		{
+			name:   "read-only cannot call unclassified method",
+			token:  "monitoring-token",
+			method: "/agent.Agent/GetOrCreateVirtualMachine",
+			want:   codes.PermissionDenied,
+		},
+		{
+			name:   "read-only can call health check",
+			token:  "monitoring-token",
+			method: "/grpc.health.v1.Health/Check",
+			want:   codes.OK,
+		},
+		{
+			name:   "read-only cannot call mutating method",
+			token:  "monitoring-token",
+			method: "/agent.Agent/StartVirtualMachine",
+			want:   codes.PermissionDenied,
+		},
+		{
+			name:   "unknown role is denied",
+			token:  "nobody-token",
+			method: "/agent.Agent/GetVirtualMachineState",
+			want:   codes.PermissionDenied,
+		},
+		{
+			name:   "invalid token",
+			token:  "invalid-token",
+			method: "/agent.Agent/GetVirtualMachineState",
+			want:   codes.Unauthenticated,
+		},
+		{
+			name:   "no credentials",
+			method: "/agent.Agent/GetVirtualMachineState",
+			want:   codes.Unauthenticated,
+		},
+	}
+	for _, test := range tests {
+		ctx := context.Background()
+		if test.token != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+test.token))
+		}
+
+		err := a.authorize(ctx, test.method)
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %s (%v)", test.name, test.want, got, err)
+		}
+	}
+}
+
+func TestMethodReadOnly(t *testing.T) {
+	service := pb.File_agent_proto.Services().ByName("Agent")
+	methods := service.Methods()
+
+	defined := map[string]bool{}
+	for i := 0; i < methods.Len(); i++ {
+		fullMethod := fmt.Sprintf("/%s/%s", service.FullName(), methods.Get(i).Name())
+		defined[fullMethod] = true
+		if _, ok := methodReadOnly[fullMethod]; !ok {
+			t.Errorf("%s is not classified in methodReadOnly", fullMethod)
+		}
+	}
+	for fullMethod := range methodReadOnly {
+		if !defined[fullMethod] {
+			t.Errorf("%s is classified in methodReadOnly, but not defined", fullMethod)
+		}
+	}
+}
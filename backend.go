@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"strings"
+
+	libvirt "github.com/digitalocean/go-libvirt"
+	"github.com/google/nftables"
+	"github.com/lovi-cloud/go-os-brick/osbrick"
+	"github.com/vishvananda/netlink"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// libvirtAPI is the subset of libvirtd procedures used by the agent.
+type libvirtAPI interface {
+	ConnectListAllDomains(ctx context.Context, needResults int32, flags libvirt.ConnectListAllDomainsFlags) ([]libvirt.Domain, uint32, error)
+	ConnectListAllNetworks(ctx context.Context, needResults int32, flags libvirt.ConnectListAllNetworksFlags) ([]libvirt.Network, uint32, error)
+	DomainAttachDeviceFlags(ctx context.Context, dom libvirt.Domain, xml string, flags uint32) error
+	DomainCreate(ctx context.Context, dom libvirt.Domain) error
+	DomainDefineXML(ctx context.Context, xml string) (libvirt.Domain, error)
+	DomainDestroy(ctx context.Context, dom libvirt.Domain) error
+	DomainDetachDeviceFlags(ctx context.Context, dom libvirt.Domain, xml string, flags uint32) error
+	DomainGetState(ctx context.Context, dom libvirt.Domain, flags uint32) (int32, int32, error)
+	DomainGetXMLDesc(ctx context.Context, dom libvirt.Domain, flags libvirt.DomainXMLFlags) (string, error)
+	DomainLookupByUUID(ctx context.Context, uuid libvirt.UUID) (libvirt.Domain, error)
+	DomainUndefine(ctx context.Context, dom libvirt.Domain) error
+	NetworkCreate(ctx context.Context, network libvirt.Network) error
+	NetworkDefineXML(ctx context.Context, xml string) (libvirt.Network, error)
+	NetworkDestroy(ctx context.Context, network libvirt.Network) error
+	NetworkLookupByName(ctx context.Context, name string) (libvirt.Network, error)
+	NetworkUndefine(ctx context.Context, network libvirt.Network) error
+}
+
+// libvirtProvider provides the client of the current libvirtd connection.
+type libvirtProvider interface {
+	API() (libvirtAPI, error)
+}
+
+// libvirtClient returns the client of the current libvirtd connection.
+func (a *agent) libvirtClient() (libvirtAPI, error) {
+	return a.libvirt.API()
+}
+
+// netlinkAPI is the subset of netlink functions used by the agent.
+type netlinkAPI interface {
+	LinkByName(name string) (netlink.Link, error)
+	LinkByIndex(index int) (netlink.Link, error)
+	LinkList() ([]netlink.Link, error)
+	LinkAdd(link netlink.Link) error
+	LinkDel(link netlink.Link) error
+	LinkSetUp(link netlink.Link) error
+	LinkSetDown(link netlink.Link) error
+	LinkSetMaster(link, master netlink.Link) error
+	LinkSetNoMaster(link netlink.Link) error
+	AddrAdd(link netlink.Link, addr *netlink.Addr) error
+	AddrDel(link netlink.Link, addr *netlink.Addr) error
+	AddrList(link netlink.Link, family int) ([]netlink.Addr, error)
+	VethPeerIndex(link *netlink.Veth) (int, error)
+	ConntrackDeleteFilter(table netlink.ConntrackTableType, family netlink.InetFamily, filter netlink.CustomConntrackFilter) (uint, error)
+}
+
+// hostNetlink operates the links of the host.
+type hostNetlink struct{}
+
+func (hostNetlink) LinkByName(name string) (netlink.Link, error) {
+	return netlink.LinkByName(name)
+}
+
+func (hostNetlink) LinkByIndex(index int) (netlink.Link, error) {
+	return netlink.LinkByIndex(index)
+}
+
+func (hostNetlink) LinkList() ([]netlink.Link, error) {
+	return netlink.LinkList()
+}
+
+func (hostNetlink) LinkAdd(link netlink.Link) error {
+	return netlink.LinkAdd(link)
+}
+
+func (hostNetlink) LinkDel(link netlink.Link) error {
+	return netlink.LinkDel(link)
+}
+
+func (hostNetlink) LinkSetUp(link netlink.Link) error {
+	return netlink.LinkSetUp(link)
+}
+
+func (hostNetlink) LinkSetDown(link netlink.Link) error {
+	return netlink.LinkSetDown(link)
+}
+
+func (hostNetlink) LinkSetMaster(link, master netlink.Link) error {
+	return netlink.LinkSetMaster(link, master)
+}
+
+func (hostNetlink) LinkSetNoMaster(link netlink.Link) error {
+	return netlink.LinkSetNoMaster(link)
+}
+
+func (hostNetlink) AddrAdd(link netlink.Link, addr *netlink.Addr) error {
+	return netlink.AddrAdd(link, addr)
+}
+
+func (hostNetlink) AddrDel(link netlink.Link, addr *netlink.Addr) error {
+	return netlink.AddrDel(link, addr)
+}
+
+func (hostNetlink) AddrList(link netlink.Link, family int) ([]netlink.Addr, error) {
+	return netlink.AddrList(link, family)
+}
+
+func (hostNetlink) VethPeerIndex(link *netlink.Veth) (int, error) {
+	return netlink.VethPeerIndex(link)
+}
+
+func (hostNetlink) ConntrackDeleteFilter(table netlink.ConntrackTableType, family netlink.InetFamily, filter netlink.CustomConntrackFilter) (uint, error) {
+	return netlink.ConntrackDeleteFilter(table, family, filter)
+}
+
+// iptablesAPI is the subset of iptables commands used by the agent.
+type iptablesAPI interface {
+	ChainExists(ctx context.Context, table, chain string) (bool, error)
+	ListChains(ctx context.Context, table string) ([]string, error)
+	ListWithCounters(ctx context.Context, table, chain string) ([]string, error)
+	// Save returns the rules of table in the format of iptables-save.
+	Save(ctx context.Context, table string) (string, error)
+	// Restore applies rules in the format of iptables-restore to table without flushing the other chains.
+	Restore(ctx context.Context, table, rules string) error
+	Exists(ctx context.Context, table, chain string, rulespec ...string) (bool, error)
+}
+
+// nftablesAPI is the subset of nftables operations used by the agent. Changes are queued until
+// Flush sends them in a batch, which the kernel applies atomically.
+type nftablesAPI interface {
+	ListTablesOfFamily(ctx context.Context, family nftables.TableFamily) ([]*nftables.Table, error)
+	ListChainsOfTableFamily(ctx context.Context, family nftables.TableFamily) ([]*nftables.Chain, error)
+	GetRules(ctx context.Context, t *nftables.Table, c *nftables.Chain) ([]*nftables.Rule, error)
+	GetSets(ctx context.Context, t *nftables.Table) ([]*nftables.Set, error)
+	GetSetElements(ctx context.Context, s *nftables.Set) ([]nftables.SetElement, error)
+
+	AddTable(t *nftables.Table) *nftables.Table
+	AddChain(c *nftables.Chain) *nftables.Chain
+	FlushChain(c *nftables.Chain)
+	DelChain(c *nftables.Chain)
+	AddRule(r *nftables.Rule) *nftables.Rule
+	InsertRule(r *nftables.Rule) *nftables.Rule
+	DelRule(r *nftables.Rule) error
+	AddSet(s *nftables.Set, vals []nftables.SetElement) error
+	FlushSet(s *nftables.Set)
+	SetAddElements(s *nftables.Set, vals []nftables.SetElement) error
+	DelSet(s *nftables.Set)
+	// Flush sends the queued changes. It does nothing if no change is queued.
+	Flush(ctx context.Context) error
+}
+
+// errIPSetInUse is returned when a set is destroyed while iptables rules refer to it.
+var errIPSetInUse = errors.New("set is in use")
+
+// ipsetAPI is the subset of ipset commands used by the agent. Sets are hash:ip sets of IPv4 addresses.
+type ipsetAPI interface {
+	// Create creates set if it does not exist.
+	Create(ctx context.Context, set string) error
+	// Replace replaces the members of set at once by swapping it with temporary, creating set if it does not exist.
+	Replace(ctx context.Context, set, temporary string, members []net.IP) error
+	Destroy(ctx context.Context, set string) error
+	// List returns the members of every set.
+	List(ctx context.Context) (map[string][]net.IP, error)
+}
+
+// hostIPSet operates the ipsets of the host by the ipset command.
+type hostIPSet struct{}
+
+func (h hostIPSet) Create(ctx context.Context, set string) error {
+	_, err := h.run(ctx, "", "create", set, "hash:ip", "family", "inet", "-exist")
+	return err
+}
+
+func (h hostIPSet) Replace(ctx context.Context, set, temporary string, members []net.IP) error {
+	var script strings.Builder
+	fmt.Fprintf(&script, "create %s hash:ip family inet -exist\n", temporary)
+	fmt.Fprintf(&script, "flush %s\n", temporary)
+	for _, member := range members {
+		fmt.Fprintf(&script, "add %s %s\n", temporary, member)
+	}
+	fmt.Fprintf(&script, "create %s hash:ip family inet -exist\n", set)
+	fmt.Fprintf(&script, "swap %s %s\n", temporary, set)
+	fmt.Fprintf(&script, "destroy %s\n", temporary)
+
+	_, err := h.run(ctx, script.String(), "restore")
+	return err
+}
+
+func (h hostIPSet) Destroy(ctx context.Context, set string) error {
+	_, err := h.run(ctx, "", "destroy", set)
+	return err
+}
+
+func (h hostIPSet) List(ctx context.Context) (map[string][]net.IP, error) {
+	out, err := h.run(ctx, "", "save")
+	if err != nil {
+		return nil, err
+	}
+
+	sets := map[string][]net.IP{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "create":
+			sets[fields[1]] = []net.IP{}
+		case "add":
+			if len(fields) < 3 {
+				continue
+			}
+			if ip := net.ParseIP(fields[2]); ip != nil {
+				sets[fields[1]] = append(sets[fields[1]], ip)
+			}
+		}
+	}
+	return sets, nil
+}
+
+func (hostIPSet) run(ctx context.Context, stdin string, args ...string) (out string, err error) {
+	ctx, span := startSpan(ctx, "ipset."+args[0], attribute.String("ipset.args", strings.Join(args, " ")))
+	defer func() { endSpan(span, err) }()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "ipset", args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if strings.Contains(msg, "in use by a kernel component") {
+			return "", fmt.Errorf("failed to execute ipset %s: %s: %w", args[0], msg, errIPSetInUse)
+		}
+		return "", fmt.Errorf("failed to execute ipset %s: %w (output: %s)", args[0], err, msg)
+	}
+	return stdout.String(), nil
+}
+
+// osbrickAPI is the subset of iSCSI operations used by the agent.
+type osbrickAPI interface {
+	GetIQN(ctx context.Context) (string, error)
+	ConnectSinglePathVolume(ctx context.Context, targetPortalIP string, hostLUNID int) (string, error)
+	ConnectMultipathVolume(ctx context.Context, targetPortalIPs []string, hostLUNID int) (string, error)
+	DisconnectSinglePathVolume(ctx context.Context, targetPortalIP string, hostLUNID int) error
+	DisconnectVolume(ctx context.Context, targetPortalIPs []string, hostLUNID int) error
+}
+
+// hostOSBrick operates the iSCSI sessions of the host.
+type hostOSBrick struct{}
+
+func (hostOSBrick) GetIQN(ctx context.Context) (string, error) {
+	return osbrick.GetIQN(ctx)
+}
+
+func (hostOSBrick) ConnectSinglePathVolume(ctx context.Context, targetPortalIP string, hostLUNID int) (string, error) {
+	return osbrick.ConnectSinglePathVolume(ctx, targetPortalIP, hostLUNID)
+}
+
+func (hostOSBrick) ConnectMultipathVolume(ctx context.Context, targetPortalIPs []string, hostLUNID int) (string, error) {
+	return osbrick.ConnectMultipathVolume(ctx, targetPortalIPs, hostLUNID)
+}
+
+func (hostOSBrick) DisconnectSinglePathVolume(ctx context.Context, targetPortalIP string, hostLUNID int) error {
+	return osbrick.DisconnectSinglePathVolume(ctx, targetPortalIP, hostLUNID)
+}
+
+func (hostOSBrick) DisconnectVolume(ctx context.Context, targetPortalIPs []string, hostLUNID int) error {
+	return osbrick.DisconnectVolume(ctx, targetPortalIPs, hostLUNID)
+}
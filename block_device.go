@@ -3,25 +3,53 @@ package main
 import (
 	"context"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
-	"github.com/lovi-cloud/go-os-brick/osbrick"
 	pb "github.com/lovi-cloud/teleskop/protoc/agent"
 )
 
 func (a *agent) ConnectBlockDevice(ctx context.Context, req *pb.ConnectBlockDeviceRequest) (*pb.ConnectBlockDeviceResponse, error) {
+	if req.Async {
+		op, err := a.operations.Start(ctx, "/agent.Agent/ConnectBlockDevice", func(ctx context.Context, progress *operationProgress) (proto.Message, error) {
+			return a.connectBlockDevice(ctx, req, progress)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &pb.ConnectBlockDeviceResponse{
+			OperationId: op.Id,
+		}, nil
+	}
+
+	return a.connectBlockDevice(ctx, req, nil)
+}
+
+func (a *agent) connectBlockDevice(ctx context.Context, req *pb.ConnectBlockDeviceRequest, progress *operationProgress) (*pb.ConnectBlockDeviceResponse, error) {
+	progress.Report(0, "waiting for other heavy operations")
+	release, err := a.acquireHeavyOperation(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	var deviceName string
-	var err error
 
+	ctx, span := startISCSISpan(ctx, "ConnectVolume", req.PortalAddresses, req.HostLunId)
+	defer func() { endSpan(span, err) }()
+
+	progress.Report(10, "connecting volume")
 	switch len(req.PortalAddresses) {
 	case 1:
-		deviceName, err = osbrick.ConnectSinglePathVolume(ctx, req.PortalAddresses[0], int(req.HostLunId))
+		deviceName, err = a.osbrick.ConnectSinglePathVolume(ctx, req.PortalAddresses[0], int(req.HostLunId))
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to connect block device: %+v", err)
 		}
 	default:
-		deviceName, err = osbrick.ConnectMultipathVolume(ctx, req.PortalAddresses, int(req.HostLunId))
+		deviceName, err = a.osbrick.ConnectMultipathVolume(ctx, req.PortalAddresses, int(req.HostLunId))
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to connect block device: %+v", err)
 		}
@@ -33,16 +61,50 @@ func (a *agent) ConnectBlockDevice(ctx context.Context, req *pb.ConnectBlockDevi
 }
 
 func (a *agent) DisconnectBlockDevice(ctx context.Context, req *pb.DisconnectBlockDeviceRequest) (*pb.DisconnectBlockDeviceResponse, error) {
+	if req.Async {
+		op, err := a.operations.Start(ctx, "/agent.Agent/DisconnectBlockDevice", func(ctx context.Context, progress *operationProgress) (proto.Message, error) {
+			return a.disconnectBlockDevice(ctx, req, progress)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &pb.DisconnectBlockDeviceResponse{
+			OperationId: op.Id,
+		}, nil
+	}
+
+	return a.disconnectBlockDevice(ctx, req, nil)
+}
+
+func (a *agent) disconnectBlockDevice(ctx context.Context, req *pb.DisconnectBlockDeviceRequest, progress *operationProgress) (*pb.DisconnectBlockDeviceResponse, error) {
+	progress.Report(0, "waiting for other heavy operations")
+	release, err := a.acquireHeavyOperation(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	ctx, span := startISCSISpan(ctx, "DisconnectVolume", req.PortalAddresses, req.HostLunId)
+	defer func() { endSpan(span, err) }()
+
+	progress.Report(10, "disconnecting volume")
 	switch len(req.PortalAddresses) {
 	case 1:
-		if err := osbrick.DisconnectSinglePathVolume(ctx, req.PortalAddresses[0], int(req.HostLunId)); err != nil {
+		if err = a.osbrick.DisconnectSinglePathVolume(ctx, req.PortalAddresses[0], int(req.HostLunId)); err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to disconnect block device: %+v", err)
 		}
 	default:
-		if err := osbrick.DisconnectVolume(ctx, req.PortalAddresses, int(req.HostLunId)); err != nil {
+		if err = a.osbrick.DisconnectVolume(ctx, req.PortalAddresses, int(req.HostLunId)); err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to disconnect block device: %+v", err)
 		}
 	}
 
 	return &pb.DisconnectBlockDeviceResponse{}, nil
 }
+
+func startISCSISpan(ctx context.Context, operation string, portalAddresses []string, hostLunID uint32) (context.Context, trace.Span) {
+	return startSpan(ctx, "iscsi."+operation,
+		attribute.StringSlice("iscsi.portal_addresses", portalAddresses),
+		attribute.Int64("iscsi.host_lun_id", int64(hostLunID)),
+	)
+}
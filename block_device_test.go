@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+func TestConnectBlockDevice(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        *pb.ConnectBlockDeviceRequest
+		err        error
+		want       codes.Code
+		wantDevice string
+	}{
+		{
+			name:       "single path",
+			req:        &pb.ConnectBlockDeviceRequest{PortalAddresses: []string{"192.0.2.10"}, HostLunId: 1},
+			want:       codes.OK,
+			wantDevice: "/dev/sdb",
+		},
+		{
+			name:       "multipath",
+			req:        &pb.ConnectBlockDeviceRequest{PortalAddresses: []string{"192.0.2.10", "192.0.2.11"}, HostLunId: 1},
+			want:       codes.OK,
+			wantDevice: "/dev/dm-1",
+		},
+		{
+			name: "failed to connect",
+			req:  &pb.ConnectBlockDeviceRequest{PortalAddresses: []string{"192.0.2.10"}, HostLunId: 1},
+			err:  fmt.Errorf("iscsiadm: No session found"),
+			want: codes.Internal,
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		f.osbrick.err = test.err
+
+		resp, err := a.ConnectBlockDevice(context.Background(), test.req)
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %+v", test.name, test.want, err)
+			continue
+		}
+		if test.want != codes.OK {
+			continue
+		}
+		if resp.DeviceName != test.wantDevice {
+			t.Errorf("%s: want %s, but got %s", test.name, test.wantDevice, resp.DeviceName)
+		}
+		if _, ok := f.osbrick.volumes[fakeVolumeKey(test.req.PortalAddresses, int(test.req.HostLunId))]; !ok {
+			t.Errorf("%s: volume should be connected", test.name)
+		}
+	}
+}
+
+func TestDisconnectBlockDevice(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *pb.DisconnectBlockDeviceRequest
+		err  error
+		want codes.Code
+	}{
+		{
+			name: "single path",
+			req:  &pb.DisconnectBlockDeviceRequest{PortalAddresses: []string{"192.0.2.10"}, HostLunId: 1},
+			want: codes.OK,
+		},
+		{
+			name: "multipath",
+			req:  &pb.DisconnectBlockDeviceRequest{PortalAddresses: []string{"192.0.2.10", "192.0.2.11"}, HostLunId: 1},
+			want: codes.OK,
+		},
+		{
+			name: "failed to disconnect",
+			req:  &pb.DisconnectBlockDeviceRequest{PortalAddresses: []string{"192.0.2.10"}, HostLunId: 1},
+			err:  fmt.Errorf("iscsiadm: No session found"),
+			want: codes.Internal,
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		ctx := context.Background()
+		if _, err := a.ConnectBlockDevice(ctx, &pb.ConnectBlockDeviceRequest{PortalAddresses: test.req.PortalAddresses, HostLunId: test.req.HostLunId}); err != nil {
+			t.Fatalf("%s: failed to connect block device: %+v", test.name, err)
+		}
+		f.osbrick.err = test.err
+
+		_, err := a.DisconnectBlockDevice(ctx, test.req)
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %+v", test.name, test.want, err)
+			continue
+		}
+		if test.want == codes.OK && len(f.osbrick.volumes) != 0 {
+			t.Errorf("%s: volume should be disconnected", test.name)
+		}
+	}
+}
+
+func TestConnectBlockDeviceAsync(t *testing.T) {
+	a, f := newTestAgent(t)
+	ctx := context.Background()
+
+	resp, err := a.ConnectBlockDevice(ctx, &pb.ConnectBlockDeviceRequest{PortalAddresses: []string{"192.0.2.10"}, HostLunId: 1, Async: true})
+	if err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	if resp.OperationId == "" || resp.DeviceName != "" {
+		t.Fatalf("want only operation id, but got %+v", resp)
+	}
+
+	op, err := a.WaitOperation(ctx, &pb.WaitOperationRequest{Id: resp.OperationId, TimeoutSeconds: 5})
+	if err != nil {
+		t.Fatalf("failed to wait operation: %+v", err)
+	}
+	if op.Operation.State != pb.Operation_SUCCEEDED {
+		t.Fatalf("want %s, but got %s", pb.Operation_SUCCEEDED, op.Operation.State)
+	}
+	if got := op.Operation.GetConnectBlockDevice().GetDeviceName(); got != "/dev/sdb" {
+		t.Errorf("want /dev/sdb, but got %s", got)
+	}
+
+	dresp, err := a.DisconnectBlockDevice(ctx, &pb.DisconnectBlockDeviceRequest{PortalAddresses: []string{"192.0.2.10"}, HostLunId: 1, Async: true})
+	if err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	dop, err := a.operations.Wait(waitCtx, dresp.OperationId)
+	if err != nil {
+		t.Fatalf("failed to wait operation: %+v", err)
+	}
+	if dop.State != pb.Operation_SUCCEEDED {
+		t.Errorf("want %s, but got %s", pb.Operation_SUCCEEDED, dop.State)
+	}
+	if len(f.osbrick.volumes) != 0 {
+		t.Errorf("volume should be disconnected")
+	}
+}
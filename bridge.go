@@ -5,10 +5,10 @@ import (
 	"context"
 	"fmt"
 	"net"
-	"os"
 	"text/template"
 
 	"github.com/vishvananda/netlink"
+	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -22,7 +22,7 @@ const networkTmplStr = `
 </network>
 `
 
-var networkTmpl *template.Template
+var networkTmpl = template.Must(template.New("networkTmpl").Parse(networkTmplStr))
 
 func (a *agent) GetInterfaceName(ctx context.Context, req *pb.GetInterfaceNameRequest) (*pb.GetInterfaceNameResponse, error) {
 	return &pb.GetInterfaceNameResponse{
@@ -31,18 +31,20 @@ func (a *agent) GetInterfaceName(ctx context.Context, req *pb.GetInterfaceNameRe
 }
 
 func (a *agent) AddBridge(ctx context.Context, req *pb.AddBridgeRequest) (*pb.AddBridgeResponse, error) {
-	if networkTmpl == nil {
-		tmp, err := template.New("networkTmpl").Parse(networkTmplStr)
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "failed to parse network template: %+v", err)
-		}
-		networkTmpl = tmp
+	libvirtClient, err := a.libvirtClient()
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := a.lockBridge(ctx, req.Name)
+	if err != nil {
+		return nil, err
 	}
+	defer unlock()
 
 	var (
 		ip    net.IP
 		ipnet *net.IPNet
-		err   error
 	)
 	if !req.InternalOnly {
 		ip, ipnet, err = net.ParseCIDR(req.MetadataCidr)
@@ -51,10 +53,9 @@ func (a *agent) AddBridge(ctx context.Context, req *pb.AddBridgeRequest) (*pb.Ad
 		}
 	}
 
-	_, err = a.libvirtClient.NetworkLookupByName(req.Name)
+	_, err = libvirtClient.NetworkLookupByName(ctx, req.Name)
 	if err == nil {
-		// TODO: already exists
-		return &pb.AddBridgeResponse{}, nil
+		return nil, alreadyExistsError("bridge", req.Name)
 	}
 
 	var buff bytes.Buffer
@@ -62,68 +63,90 @@ func (a *agent) AddBridge(ctx context.Context, req *pb.AddBridgeRequest) (*pb.Ad
 		return nil, status.Errorf(codes.Internal, "failed to execute network template: %+v", err)
 	}
 
-	network, err := a.libvirtClient.NetworkDefineXML(buff.String())
+	network, err := libvirtClient.NetworkDefineXML(ctx, buff.String())
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "failed to define network: %+v", err)
 	}
 
-	if err := a.libvirtClient.NetworkCreate(network); err != nil {
+	if err := libvirtClient.NetworkCreate(ctx, network); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to start network: %+v", err)
 	}
 
 	if !req.InternalOnly {
-		err := addTeleskopInterface(ctx, req.Name, ip, ipnet)
+		err := a.addTeleskopInterface(ctx, req.Name, ip, ipnet)
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to add dhcp interface: %+v", err)
 		}
 	}
 
-	fmt.Printf("starting network: %+v\n", network)
+	a.logger.Info("started network", zap.String("name", network.Name))
 
 	return &pb.AddBridgeResponse{}, nil
 }
 
 func (a *agent) DeleteBridge(ctx context.Context, req *pb.DeleteBridgeRequest) (*pb.DeleteBridgeResponse, error) {
-	network, err := a.libvirtClient.NetworkLookupByName(req.Name)
+	libvirtClient, err := a.libvirtClient()
 	if err != nil {
-		// TODO: not found
-		return &pb.DeleteBridgeResponse{}, nil
+		return nil, err
 	}
 
-	if err := a.libvirtClient.NetworkDestroy(network); err != nil {
+	unlock, err := a.lockBridge(ctx, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	network, err := libvirtClient.NetworkLookupByName(ctx, req.Name)
+	if isNetworkNotFound(err) {
+		return nil, notFoundError("bridge", req.Name)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to lookup network: %+v", err)
+	}
+
+	if err := libvirtClient.NetworkDestroy(ctx, network); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to stop network: %+v", err)
 	}
 
-	if err := a.libvirtClient.NetworkUndefine(network); err != nil {
+	if err := libvirtClient.NetworkUndefine(ctx, network); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to undefine network: %+v", err)
 	}
 
-	if err := deleteTeleskopInterfaceIfExists(ctx, req.Name); err != nil {
+	if err := a.deleteTeleskopInterfaceIfExists(ctx, req.Name); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to delete teleskop interface: %+v", err)
 	}
 
-	fmt.Printf("stopped network: %+v\n", network)
+	a.logger.Info("stopped network", zap.String("name", network.Name))
 
 	return &pb.DeleteBridgeResponse{}, nil
 }
 
 func (a *agent) AddInterfaceToBridge(ctx context.Context, req *pb.AddInterfaceToBridgeRequest) (*pb.AddInterfaceToBridgeResponse, error) {
-	bridge, err := netlink.LinkByName(req.Bridge)
+	unlock, err := a.lockBridge(ctx, req.Bridge)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	bridge, err := a.netlink.LinkByName(req.Bridge)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "failed to find bridge: %+v", err)
 	}
 
-	link, err := netlink.LinkByName(req.Interface)
+	link, err := a.netlink.LinkByName(req.Interface)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "failed to find interface: %+v", err)
 	}
 
-	if link.Attrs().MasterIndex != 0 {
-		// TODO: already added
-		return &pb.AddInterfaceToBridgeResponse{}, nil
+	switch link.Attrs().MasterIndex {
+	case 0:
+	case bridge.Attrs().Index:
+		return nil, alreadyExistsError("bridge member", req.Interface)
+	default:
+		return nil, status.Errorf(codes.FailedPrecondition, "interface %s is already added to another bridge", req.Interface)
 	}
 
-	if err := netlink.LinkSetMaster(link, bridge); err != nil {
+	if err := a.netlink.LinkSetMaster(link, bridge); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to add interface to bridge: %+v", err)
 	}
 
@@ -131,29 +154,35 @@ func (a *agent) AddInterfaceToBridge(ctx context.Context, req *pb.AddInterfaceTo
 }
 
 func (a *agent) DeleteInterfaceFromBridge(ctx context.Context, req *pb.DeleteInterfaceFromBridgeRequest) (*pb.DeleteInterfaceFromBridgeResponse, error) {
-	link, err := netlink.LinkByName(req.Interface)
+	unlock, err := a.lockBridge(ctx, req.Bridge)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	link, err := a.netlink.LinkByName(req.Interface)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "failed to find interface: %+v", err)
 	}
 
-	if err := netlink.LinkSetNoMaster(link); err != nil {
+	if err := a.netlink.LinkSetNoMaster(link); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to delete interface from bridge: %+v", err)
 	}
 
 	return &pb.DeleteInterfaceFromBridgeResponse{}, nil
 }
 
-func addTeleskopInterface(ctx context.Context, name string, ip net.IP, ipnet *net.IPNet) error {
-	veth, vethPeer, err := createVethPeerIfNotExists(ctx, name)
+func (a *agent) addTeleskopInterface(ctx context.Context, name string, ip net.IP, ipnet *net.IPNet) error {
+	veth, vethPeer, err := a.createVethPeerIfNotExists(ctx, name)
 	if err != nil {
 		return err
 	}
 
-	bridge, err := netlink.LinkByName(name)
+	bridge, err := a.netlink.LinkByName(name)
 	if err != nil {
 		return fmt.Errorf("failed to find bridge name=%s: %w", name, err)
 	}
-	err = netlink.LinkSetMaster(veth, bridge)
+	err = a.netlink.LinkSetMaster(veth, bridge)
 	if err != nil {
 		return fmt.Errorf("failed to set link master link=%s bridge=%s: %w", veth.Attrs().Name, bridge.Attrs().Name, err)
 	}
@@ -163,16 +192,16 @@ func addTeleskopInterface(ctx context.Context, name string, ip net.IP, ipnet *ne
 	if err != nil {
 		return fmt.Errorf("failed to parse addr=\"%s/%d\": %w", ip.String(), mask, err)
 	}
-	err = netlink.AddrAdd(vethPeer, addr)
+	err = a.netlink.AddrAdd(vethPeer, addr)
 	if err != nil {
 		return fmt.Errorf("failed to addr add link=%s, addr=%s: %w", vethPeer.Attrs().Name, addr, err)
 	}
 
-	err = netlink.LinkSetUp(veth)
+	err = a.netlink.LinkSetUp(veth)
 	if err != nil {
 		return fmt.Errorf("failed to set up link=%s: %w", veth.Attrs().Name, err)
 	}
-	err = netlink.LinkSetUp(vethPeer)
+	err = a.netlink.LinkSetUp(vethPeer)
 	if err != nil {
 		return fmt.Errorf("failed to set up link=%s: %w", vethPeer.Attrs().Name, err)
 	}
@@ -180,38 +209,38 @@ func addTeleskopInterface(ctx context.Context, name string, ip net.IP, ipnet *ne
 	return nil
 }
 
-func deleteTeleskopInterfaceIfExists(ctx context.Context, name string) error {
-	veth, vethPeer, err := createVethPeerIfNotExists(ctx, name)
+func (a *agent) deleteTeleskopInterfaceIfExists(ctx context.Context, name string) error {
+	veth, vethPeer, err := a.createVethPeerIfNotExists(ctx, name)
 	if err != nil {
 		return nil
 	}
 
-	err = netlink.LinkSetDown(veth)
+	err = a.netlink.LinkSetDown(veth)
 	if err != nil {
 		return fmt.Errorf("failed to set down link=%s: %w", veth.Attrs().Name, err)
 	}
-	err = netlink.LinkSetDown(vethPeer)
+	err = a.netlink.LinkSetDown(vethPeer)
 	if err != nil {
 		return fmt.Errorf("failed to set down link=%s: %w", vethPeer.Attrs().Name, err)
 	}
 
-	addrs, err := netlink.AddrList(vethPeer, netlink.FAMILY_V4)
+	addrs, err := a.netlink.AddrList(vethPeer, netlink.FAMILY_V4)
 	if err != nil {
 		return fmt.Errorf("failed to get addr list link=%s: %w", vethPeer.Attrs().Name, err)
 	}
 	for _, addr := range addrs {
-		err = netlink.AddrDel(vethPeer, &addr)
+		err = a.netlink.AddrDel(vethPeer, &addr)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "failed to delete addr link=%s, addr=%s: %+v\n", vethPeer.Attrs().Name, addr, err)
+			a.logger.Warn("failed to delete addr", zap.String("link", vethPeer.Attrs().Name), zap.String("addr", addr.String()), zap.Error(err))
 		}
 	}
 
-	err = netlink.LinkSetNoMaster(veth)
+	err = a.netlink.LinkSetNoMaster(veth)
 	if err != nil {
 		return fmt.Errorf("failed to set link no master link=%s: %w", veth.Attrs().Name, err)
 	}
 
-	err = netlink.LinkDel(veth)
+	err = a.netlink.LinkDel(veth)
 	if err != nil {
 		return fmt.Errorf("failed to delete link link=%s: %w", veth.Attrs().Name, err)
 	}
@@ -219,14 +248,14 @@ func deleteTeleskopInterfaceIfExists(ctx context.Context, name string) error {
 	return nil
 }
 
-func createVethPeerIfNotExists(ctx context.Context, name string) (netlink.Link, netlink.Link, error) {
+func (a *agent) createVethPeerIfNotExists(ctx context.Context, name string) (netlink.Link, netlink.Link, error) {
 	var err error
 	var veth, vethPeer netlink.Link
 
-	vethName := fmt.Sprintf("%s-dhcp", name)
-	vethPeerName := fmt.Sprintf("dhcp-%s", name)
+	vethName := name + teleskopInterfaceSuffix
+	vethPeerName := fmt.Sprintf("%s-%s", a.dhcpInterfacePrefix, name)
 
-	veth, err = netlink.LinkByName(vethName)
+	veth, err = a.netlink.LinkByName(vethName)
 	if err != nil {
 		veth = &netlink.Veth{
 			LinkAttrs: netlink.LinkAttrs{
@@ -234,7 +263,7 @@ func createVethPeerIfNotExists(ctx context.Context, name string) (netlink.Link,
 			},
 			PeerName: vethPeerName,
 		}
-		err2 := netlink.LinkAdd(veth)
+		err2 := a.netlink.LinkAdd(veth)
 		if err2 != nil {
 			return nil, nil, fmt.Errorf("failed to add new link name=%s: %w", vethName, err)
 		}
@@ -243,11 +272,11 @@ func createVethPeerIfNotExists(ctx context.Context, name string) (netlink.Link,
 	if !ok {
 		return nil, nil, fmt.Errorf("invalid link name=%s", veth.Attrs().Name)
 	}
-	peerIndex, err := netlink.VethPeerIndex(v)
+	peerIndex, err := a.netlink.VethPeerIndex(v)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to find veth peer index name=%s: %w", veth.Attrs().Name, err)
 	}
-	vethPeer, err = netlink.LinkByIndex(peerIndex)
+	vethPeer, err = a.netlink.LinkByIndex(peerIndex)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to find veth peer link by index=%d: %w", peerIndex, err)
 	}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/vishvananda/netlink"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+func TestGetInterfaceName(t *testing.T) {
+	a, _ := newTestAgent(t)
+
+	resp, err := a.GetInterfaceName(context.Background(), &pb.GetInterfaceNameRequest{})
+	if err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	if resp.InterfaceName != "bond0.1000" {
+		t.Errorf("want bond0.1000, but got %s", resp.InterfaceName)
+	}
+}
+
+func TestAddBridge(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		req      *pb.AddBridgeRequest
+		want     codes.Code
+		wantAddr string
+	}{
+		{
+			name:     "new bridge",
+			req:      &pb.AddBridgeRequest{Name: "br1000", MetadataCidr: "169.254.169.254/16"},
+			want:     codes.OK,
+			wantAddr: "169.254.169.254/16",
+		},
+		{
+			name: "internal only bridge",
+			req:  &pb.AddBridgeRequest{Name: "br1000", InternalOnly: true},
+			want: codes.OK,
+		},
+		{
+			name:     "existing bridge",
+			existing: "br1000",
+			req:      &pb.AddBridgeRequest{Name: "br1000", MetadataCidr: "169.254.169.254/16"},
+			want:     codes.AlreadyExists,
+		},
+		{
+			name: "invalid metadata cidr",
+			req:  &pb.AddBridgeRequest{Name: "br1000", MetadataCidr: "169.254.169.254"},
+			want: codes.InvalidArgument,
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		ctx := context.Background()
+		if test.existing != "" {
+			if _, err := a.AddBridge(ctx, &pb.AddBridgeRequest{Name: test.existing, InternalOnly: true}); err != nil {
+				t.Fatalf("%s: failed to add bridge: %+v", test.name, err)
+			}
+		}
+
+		_, err := a.AddBridge(ctx, test.req)
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %+v", test.name, test.want, err)
+			continue
+		}
+		if test.want != codes.OK {
+			continue
+		}
+
+		bridge, err := f.netlink.LinkByName(test.req.Name)
+		if err != nil {
+			t.Fatalf("%s: bridge should be created but: %+v", test.name, err)
+		}
+		veth, err := f.netlink.LinkByName(test.req.Name + teleskopInterfaceSuffix)
+		if test.req.InternalOnly {
+			if err == nil {
+				t.Errorf("%s: teleskop interface should not be created", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: teleskop interface should be created but: %+v", test.name, err)
+		}
+		if veth.Attrs().MasterIndex != bridge.Attrs().Index {
+			t.Errorf("%s: want master %d, but got %d", test.name, bridge.Attrs().Index, veth.Attrs().MasterIndex)
+		}
+		peer, err := f.netlink.LinkByName("dhcp-" + test.req.Name)
+		if err != nil {
+			t.Fatalf("%s: dhcp interface should be created but: %+v", test.name, err)
+		}
+		addrs, _ := f.netlink.AddrList(peer, netlink.FAMILY_V4)
+		if len(addrs) != 1 || addrs[0].IPNet.String() != test.wantAddr {
+			t.Errorf("%s: want address %s, but got %+v", test.name, test.wantAddr, addrs)
+		}
+		if peer.Attrs().Flags&net.FlagUp == 0 {
+			t.Errorf("%s: dhcp interface should be up", test.name)
+		}
+	}
+}
+
+func TestDeleteBridge(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing *pb.AddBridgeRequest
+		// lookupErr is returned by libvirtd when looking up the network
+		lookupErr error
+		want      codes.Code
+	}{
+		{
+			name:     "bridge with teleskop interface",
+			existing: &pb.AddBridgeRequest{Name: "br1000", MetadataCidr: "169.254.169.254/16"},
+			want:     codes.OK,
+		},
+		{
+			name:     "internal only bridge",
+			existing: &pb.AddBridgeRequest{Name: "br1000", InternalOnly: true},
+			want:     codes.OK,
+		},
+		{
+			name: "missing bridge",
+			want: codes.NotFound,
+		},
+		{
+			name:      "lookup failure",
+			existing:  &pb.AddBridgeRequest{Name: "br1000", MetadataCidr: "169.254.169.254/16"},
+			lookupErr: errors.New("connection reset by peer"),
+			want:      codes.Internal,
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		ctx := context.Background()
+		if test.existing != nil {
+			if _, err := a.AddBridge(ctx, test.existing); err != nil {
+				t.Fatalf("%s: failed to add bridge: %+v", test.name, err)
+			}
+		}
+		f.libvirt.lookupErr = test.lookupErr
+
+		_, err := a.DeleteBridge(ctx, &pb.DeleteBridgeRequest{Name: "br1000"})
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %+v", test.name, test.want, err)
+			continue
+		}
+		if test.want != codes.OK && test.want != codes.NotFound {
+			continue
+		}
+
+		links, _ := f.netlink.LinkList()
+		if len(links) != 0 {
+			t.Errorf("%s: all links should be deleted, but got %d links", test.name, len(links))
+		}
+		if _, err := f.libvirt.NetworkLookupByName(ctx, "br1000"); err == nil {
+			t.Errorf("%s: network should be undefined", test.name)
+		}
+	}
+}
+
+func TestAddInterfaceToBridge(t *testing.T) {
+	tests := []struct {
+		name   string
+		master string
+		req    *pb.AddInterfaceToBridgeRequest
+		want   codes.Code
+	}{
+		{
+			name: "free interface",
+			req:  &pb.AddInterfaceToBridgeRequest{Bridge: "br1000", Interface: "tap0"},
+			want: codes.OK,
+		},
+		{
+			name:   "interface is already added",
+			master: "br1000",
+			req:    &pb.AddInterfaceToBridgeRequest{Bridge: "br1000", Interface: "tap0"},
+			want:   codes.AlreadyExists,
+		},
+		{
+			name:   "interface is added to another bridge",
+			master: "br1001",
+			req:    &pb.AddInterfaceToBridgeRequest{Bridge: "br1000", Interface: "tap0"},
+			want:   codes.FailedPrecondition,
+		},
+		{
+			name: "missing bridge",
+			req:  &pb.AddInterfaceToBridgeRequest{Bridge: "br2000", Interface: "tap0"},
+			want: codes.NotFound,
+		},
+		{
+			name: "missing interface",
+			req:  &pb.AddInterfaceToBridgeRequest{Bridge: "br1000", Interface: "tap1"},
+			want: codes.NotFound,
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		ctx := context.Background()
+		for _, name := range []string{"br1000", "br1001"} {
+			f.netlink.LinkAdd(&netlink.Bridge{LinkAttrs: netlink.LinkAttrs{Name: name}})
+		}
+		tap := &netlink.Tuntap{LinkAttrs: netlink.LinkAttrs{Name: "tap0"}}
+		f.netlink.LinkAdd(tap)
+		if test.master != "" {
+			master, _ := f.netlink.LinkByName(test.master)
+			f.netlink.LinkSetMaster(tap, master)
+		}
+
+		_, err := a.AddInterfaceToBridge(ctx, test.req)
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %+v", test.name, test.want, err)
+			continue
+		}
+		if test.want != codes.OK {
+			continue
+		}
+
+		bridge, _ := f.netlink.LinkByName(test.req.Bridge)
+		if tap.MasterIndex != bridge.Attrs().Index {
+			t.Errorf("%s: want master %d, but got %d", test.name, bridge.Attrs().Index, tap.MasterIndex)
+		}
+	}
+}
+
+func TestDeleteInterfaceFromBridge(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *pb.DeleteInterfaceFromBridgeRequest
+		want codes.Code
+	}{
+		{
+			name: "bridge member",
+			req:  &pb.DeleteInterfaceFromBridgeRequest{Bridge: "br1000", Interface: "tap0"},
+			want: codes.OK,
+		},
+		{
+			name: "missing interface",
+			req:  &pb.DeleteInterfaceFromBridgeRequest{Bridge: "br1000", Interface: "tap1"},
+			want: codes.NotFound,
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		bridge := &netlink.Bridge{LinkAttrs: netlink.LinkAttrs{Name: "br1000"}}
+		f.netlink.LinkAdd(bridge)
+		tap := &netlink.Tuntap{LinkAttrs: netlink.LinkAttrs{Name: "tap0"}}
+		f.netlink.LinkAdd(tap)
+		f.netlink.LinkSetMaster(tap, bridge)
+
+		_, err := a.DeleteInterfaceFromBridge(context.Background(), test.req)
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %+v", test.name, test.want, err)
+			continue
+		}
+		if test.want == codes.OK && tap.MasterIndex != 0 {
+			t.Errorf("%s: interface should be removed from bridge, but master is %d", test.name, tap.MasterIndex)
+		}
+	}
+}
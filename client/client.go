@@ -0,0 +1,150 @@
+// Package client is a client of the teleskop agent api.
+//
+// Requests are sent with a default deadline, and retried with backoff while the agent is unavailable.
+// Mutating requests get a request_id if it is not set, so that the agent executes a retried request only once.
+// Errors returned by the agent are *Error, which can be compared with ErrNotFound, ErrAlreadyExists and so on by errors.Is.
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+const (
+	defaultTimeout        = 30 * time.Second
+	defaultMaxAttempts    = 4
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 2 * time.Second
+)
+
+// Client is a client of a teleskop agent.
+type Client struct {
+	pb.AgentClient
+
+	conn *grpc.ClientConn
+}
+
+type options struct {
+	tlsConfig      *tls.Config
+	token          string
+	timeout        time.Duration
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	dialOptions    []grpc.DialOption
+}
+
+// Option configures a Client.
+type Option func(*options)
+
+// WithTLSConfig connects to the agent with TLS. Without it, the connection is insecure.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(o *options) {
+		o.tlsConfig = config
+	}
+}
+
+// WithToken authenticates requests with a bearer token. It requires TLS.
+func WithToken(token string) Option {
+	return func(o *options) {
+		o.token = token
+	}
+}
+
+// WithTimeout sets the deadline of requests whose context has no deadline. Zero disables it.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithRetry sets the max number of attempts of a request and the backoff between them.
+// The backoff doubles after each attempt up to maxBackoff. maxAttempts 1 disables retries.
+func WithRetry(maxAttempts int, initialBackoff, maxBackoff time.Duration) Option {
+	return func(o *options) {
+		o.maxAttempts = maxAttempts
+		o.initialBackoff = initialBackoff
+		o.maxBackoff = maxBackoff
+	}
+}
+
+// WithDialOptions appends gRPC dial options.
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(o *options) {
+		o.dialOptions = append(o.dialOptions, opts...)
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		timeout:        defaultTimeout,
+		maxAttempts:    defaultMaxAttempts,
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.maxAttempts < 1 {
+		o.maxAttempts = 1
+	}
+	return o
+}
+
+// Dial returns a client of the agent at addr. It does not wait for the connection to be established,
+// and requests sent before that are retried until the deadline.
+func Dial(ctx context.Context, addr string, opts ...Option) (*Client, error) {
+	o := newOptions(opts)
+
+	dialOpts := []grpc.DialOption{
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                10 * time.Second,
+			Timeout:             3 * time.Second,
+			PermitWithoutStream: true,
+		}),
+		grpc.WithUnaryInterceptor(o.unaryClientInterceptor()),
+	}
+	if o.tlsConfig != nil {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(o.tlsConfig)))
+	} else {
+		dialOpts = append(dialOpts, grpc.WithInsecure())
+	}
+	if o.token != "" {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(tokenCredentials(o.token)))
+	}
+	dialOpts = append(dialOpts, o.dialOptions...)
+
+	conn, err := grpc.DialContext(ctx, addr, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial to teleskop agent %s: %w", addr, err)
+	}
+
+	return &Client{
+		AgentClient: pb.NewAgentClient(conn),
+		conn:        conn,
+	}, nil
+}
+
+// Close closes the connection to the agent.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// tokenCredentials sends a bearer token with each request.
+type tokenCredentials string
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return true
+}
@@ -0,0 +1,323 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+// fakeAgent records requests and returns the queued errors before succeeding.
+type fakeAgent struct {
+	pb.UnimplementedAgentServer
+
+	mu       sync.Mutex
+	calls    []string
+	requests []interface{}
+	errs     map[string][]error
+	deadline time.Duration
+}
+
+func (f *fakeAgent) record(ctx context.Context, method string, req interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, method)
+	f.requests = append(f.requests, req)
+	if d, ok := ctx.Deadline(); ok {
+		f.deadline = time.Until(d)
+	}
+	if errs := f.errs[method]; len(errs) > 0 {
+		f.errs[method] = errs[1:]
+		return errs[0]
+	}
+	return nil
+}
+
+func (f *fakeAgent) GetVirtualMachineState(ctx context.Context, req *pb.GetVirtualMachineStateRequest) (*pb.GetVirtualMachineStateResponse, error) {
+	if err := f.record(ctx, "GetVirtualMachineState", req); err != nil {
+		return nil, err
+	}
+	return &pb.GetVirtualMachineStateResponse{State: &pb.VirtualMachineState{Uuid: req.Uuid}}, nil
+}
+
+func (f *fakeAgent) StartVirtualMachine(ctx context.Context, req *pb.StartVirtualMachineRequest) (*pb.StartVirtualMachineResponse, error) {
+	if err := f.record(ctx, "StartVirtualMachine", req); err != nil {
+		return nil, err
+	}
+	return &pb.StartVirtualMachineResponse{Uuid: req.Uuid}, nil
+}
+
+func (f *fakeAgent) WaitOperation(ctx context.Context, req *pb.WaitOperationRequest) (*pb.WaitOperationResponse, error) {
+	if err := f.record(ctx, "WaitOperation", req); err != nil {
+		return nil, err
+	}
+	return &pb.WaitOperationResponse{}, nil
+}
+
+func (f *fakeAgent) ConnectBlockDevice(ctx context.Context, req *pb.ConnectBlockDeviceRequest) (*pb.ConnectBlockDeviceResponse, error) {
+	if err := f.record(ctx, "ConnectBlockDevice", req); err != nil {
+		return nil, err
+	}
+	return &pb.ConnectBlockDeviceResponse{DeviceName: "/dev/dm-0"}, nil
+}
+
+func (f *fakeAgent) DisconnectBlockDevice(ctx context.Context, req *pb.DisconnectBlockDeviceRequest) (*pb.DisconnectBlockDeviceResponse, error) {
+	if err := f.record(ctx, "DisconnectBlockDevice", req); err != nil {
+		return nil, err
+	}
+	return &pb.DisconnectBlockDeviceResponse{}, nil
+}
+
+func (f *fakeAgent) AttachBlockDevice(ctx context.Context, req *pb.AttachBlockDeviceRequest) (*pb.AttachBlockDeviceResponse, error) {
+	if err := f.record(ctx, "AttachBlockDevice", req); err != nil {
+		return nil, err
+	}
+	return &pb.AttachBlockDeviceResponse{Uuid: req.Uuid}, nil
+}
+
+func (f *fakeAgent) DetachBlockDevice(ctx context.Context, req *pb.DetachBlockDeviceRequest) (*pb.DetachBlockDeviceResponse, error) {
+	if err := f.record(ctx, "DetachBlockDevice", req); err != nil {
+		return nil, err
+	}
+	return &pb.DetachBlockDeviceResponse{}, nil
+}
+
+func (f *fakeAgent) AddVirtualMachine(ctx context.Context, req *pb.AddVirtualMachineRequest) (*pb.AddVirtualMachineResponse, error) {
+	if err := f.record(ctx, "AddVirtualMachine", req); err != nil {
+		return nil, err
+	}
+	return &pb.AddVirtualMachineResponse{Uuid: "uuid", Name: req.Name}, nil
+}
+
+func (f *fakeAgent) DeleteVirtualMachine(ctx context.Context, req *pb.DeleteVirtualMachineRequest) (*pb.DeleteVirtualMachineResponse, error) {
+	if err := f.record(ctx, "DeleteVirtualMachine", req); err != nil {
+		return nil, err
+	}
+	return &pb.DeleteVirtualMachineResponse{}, nil
+}
+
+func (f *fakeAgent) AttachInterface(ctx context.Context, req *pb.AttachInterfaceRequest) (*pb.AttachInterfaceResponse, error) {
+	if err := f.record(ctx, "AttachInterface", req); err != nil {
+		return nil, err
+	}
+	return &pb.AttachInterfaceResponse{Uuid: req.Uuid}, nil
+}
+
+func (f *fakeAgent) AddSecurityGroup(ctx context.Context, req *pb.AddSecurityGroupRequest) (*pb.AddSecurityGroupResponse, error) {
+	if err := f.record(ctx, "AddSecurityGroup", req); err != nil {
+		return nil, err
+	}
+	return &pb.AddSecurityGroupResponse{}, nil
+}
+
+func (f *fakeAgent) RemoveSecurityGroup(ctx context.Context, req *pb.RemoveSecurityGroupRequest) (*pb.RemoveSecurityGroupResponse, error) {
+	if err := f.record(ctx, "RemoveSecurityGroup", req); err != nil {
+		return nil, err
+	}
+	return &pb.RemoveSecurityGroupResponse{}, nil
+}
+
+// newTestClient returns a client connected to a fake agent that fails with errs first.
+func newTestClient(t *testing.T, errs map[string][]error, opts ...Option) (*Client, *fakeAgent) {
+	t.Helper()
+
+	agent := &fakeAgent{errs: errs}
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb.RegisterAgentServer(server, agent)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	opts = append([]Option{
+		WithRetry(3, time.Millisecond, time.Millisecond),
+		WithDialOptions(grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return lis.Dial()
+		})),
+	}, opts...)
+	c, err := Dial(context.Background(), "bufnet", opts...)
+	if err != nil {
+		t.Fatalf("failed to dial: %+v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c, agent
+}
+
+func TestRetry(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+	tests := []struct {
+		name      string
+		call      func(c *Client) error
+		errs      map[string][]error
+		wantCalls int
+		wantErr   error
+	}{
+		{
+			name: "read only request",
+			call: func(c *Client) error {
+				_, err := c.GetVirtualMachineState(context.Background(), &pb.GetVirtualMachineStateRequest{Uuid: "uuid"})
+				return err
+			},
+			errs:      map[string][]error{"GetVirtualMachineState": {unavailable, unavailable}},
+			wantCalls: 3,
+		},
+		{
+			name: "mutating request",
+			call: func(c *Client) error {
+				_, err := c.StartVirtualMachine(context.Background(), &pb.StartVirtualMachineRequest{Uuid: "uuid"})
+				return err
+			},
+			errs:      map[string][]error{"StartVirtualMachine": {unavailable}},
+			wantCalls: 2,
+		},
+		{
+			name: "exceeds max attempts",
+			call: func(c *Client) error {
+				_, err := c.StartVirtualMachine(context.Background(), &pb.StartVirtualMachineRequest{Uuid: "uuid"})
+				return err
+			},
+			errs:      map[string][]error{"StartVirtualMachine": {unavailable, unavailable, unavailable}},
+			wantCalls: 3,
+			wantErr:   ErrUnavailable,
+		},
+		{
+			name: "not retryable error",
+			call: func(c *Client) error {
+				_, err := c.StartVirtualMachine(context.Background(), &pb.StartVirtualMachineRequest{Uuid: "uuid"})
+				return err
+			},
+			errs:      map[string][]error{"StartVirtualMachine": {status.Error(codes.FailedPrecondition, "running")}},
+			wantCalls: 1,
+			wantErr:   ErrFailedPrecondition,
+		},
+	}
+	for _, test := range tests {
+		c, agent := newTestClient(t, test.errs)
+
+		err := test.call(c)
+		if test.wantErr == nil && err != nil {
+			t.Fatalf("%s: should not be error but: %+v", test.name, err)
+		}
+		if test.wantErr != nil && !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: want %+v, but got %+v", test.name, test.wantErr, err)
+		}
+		if len(agent.calls) != test.wantCalls {
+			t.Errorf("%s: want %d calls, but got %d", test.name, test.wantCalls, len(agent.calls))
+		}
+	}
+}
+
+func TestRetrySameRequestID(t *testing.T) {
+	c, agent := newTestClient(t, map[string][]error{
+		"StartVirtualMachine": {status.Error(codes.Unavailable, "unavailable")},
+	})
+
+	req := &pb.StartVirtualMachineRequest{Uuid: "uuid"}
+	if _, err := c.StartVirtualMachine(context.Background(), req); err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	if req.RequestId != "" {
+		t.Errorf("request of the caller should not be modified, but got request id %s", req.RequestId)
+	}
+
+	first := agent.requests[0].(*pb.StartVirtualMachineRequest).RequestId
+	second := agent.requests[1].(*pb.StartVirtualMachineRequest).RequestId
+	if first == "" {
+		t.Errorf("request id should be set")
+	}
+	if first != second {
+		t.Errorf("want %s, but got %s", first, second)
+	}
+}
+
+func TestDefaultTimeout(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  func() (context.Context, context.CancelFunc)
+		call func(ctx context.Context, c *Client) error
+		want time.Duration
+	}{
+		{
+			name: "default timeout",
+			ctx:  func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetVirtualMachineState(ctx, &pb.GetVirtualMachineStateRequest{Uuid: "uuid"})
+				return err
+			},
+			want: 10 * time.Second,
+		},
+		{
+			name: "deadline of the caller",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Minute)
+			},
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetVirtualMachineState(ctx, &pb.GetVirtualMachineStateRequest{Uuid: "uuid"})
+				return err
+			},
+			want: time.Minute,
+		},
+		{
+			name: "wait operation",
+			ctx:  func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.WaitOperation(ctx, &pb.WaitOperationRequest{Id: "id", TimeoutSeconds: 60})
+				return err
+			},
+			want: 70 * time.Second,
+		},
+	}
+	for _, test := range tests {
+		c, agent := newTestClient(t, nil, WithTimeout(10*time.Second))
+
+		ctx, cancel := test.ctx()
+		err := test.call(ctx, c)
+		cancel()
+		if err != nil {
+			t.Fatalf("%s: should not be error but: %+v", test.name, err)
+		}
+		if agent.deadline > test.want || agent.deadline < test.want-5*time.Second {
+			t.Errorf("%s: want %s, but got %s", test.name, test.want, agent.deadline)
+		}
+	}
+}
+
+func TestPool(t *testing.T) {
+	p := NewPool()
+
+	c1, err := p.Get(context.Background(), "passthrough:///agent1")
+	if err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	c2, err := p.Get(context.Background(), "passthrough:///agent1")
+	if err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	if c1 != c2 {
+		t.Errorf("clients of the same address should be shared")
+	}
+
+	c3, err := p.Get(context.Background(), "passthrough:///agent2")
+	if err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	if c1 == c3 {
+		t.Errorf("clients of different addresses should not be shared")
+	}
+
+	if err := p.Close(); err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	if _, err := p.Get(context.Background(), "passthrough:///agent1"); err == nil {
+		t.Errorf("should be error after close but not")
+	}
+}
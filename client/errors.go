@@ -0,0 +1,91 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Errors that *Error can be compared with by errors.Is.
+var (
+	ErrNotFound           = errors.New("not found")
+	ErrDomainNotFound     = errors.New("domain not found")
+	ErrBridgeNotFound     = errors.New("bridge not found")
+	ErrAlreadyExists      = errors.New("already exists")
+	ErrInvalidArgument    = errors.New("invalid argument")
+	ErrFailedPrecondition = errors.New("failed precondition")
+	ErrUnavailable        = errors.New("unavailable")
+	ErrUnauthenticated    = errors.New("unauthenticated")
+	ErrPermissionDenied   = errors.New("permission denied")
+)
+
+// Error is an error returned by the agent.
+type Error struct {
+	Code    codes.Code
+	Message string
+	// ResourceType and ResourceName are set when the error is about a resource, e.g. "domain" and its uuid.
+	ResourceType string
+	ResourceName string
+
+	status *status.Status
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	return fmt.Sprintf("teleskop: %s: %s", e.Code, e.Message)
+}
+
+// GRPCStatus returns the status returned by the agent, so that status.FromError works on *Error.
+func (e *Error) GRPCStatus() *status.Status {
+	return e.status
+}
+
+// Is reports whether e matches target, one of the errors of this package.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.Code == codes.NotFound
+	case ErrDomainNotFound:
+		return e.Code == codes.NotFound && e.ResourceType == "domain"
+	case ErrBridgeNotFound:
+		return e.Code == codes.NotFound && e.ResourceType == "bridge"
+	case ErrAlreadyExists:
+		return e.Code == codes.AlreadyExists
+	case ErrInvalidArgument:
+		return e.Code == codes.InvalidArgument
+	case ErrFailedPrecondition:
+		return e.Code == codes.FailedPrecondition
+	case ErrUnavailable:
+		return e.Code == codes.Unavailable
+	case ErrUnauthenticated:
+		return e.Code == codes.Unauthenticated
+	case ErrPermissionDenied:
+		return e.Code == codes.PermissionDenied
+	default:
+		return false
+	}
+}
+
+// toError converts a gRPC error to *Error. Other errors are returned as is.
+func toError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() == codes.OK {
+		return err
+	}
+
+	e := &Error{
+		Code:    st.Code(),
+		Message: st.Message(),
+		status:  st,
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ResourceInfo); ok {
+			e.ResourceType = info.ResourceType
+			e.ResourceName = info.ResourceName
+		}
+	}
+	return e
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func resourceStatus(t *testing.T, code codes.Code, resourceType, name string) error {
+	t.Helper()
+
+	st, err := status.New(code, resourceType+" "+name).WithDetails(&errdetails.ResourceInfo{
+		ResourceType: resourceType,
+		ResourceName: name,
+	})
+	if err != nil {
+		t.Fatalf("failed to add details: %+v", err)
+	}
+	return st.Err()
+}
+
+func TestToError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		want    []error
+		notWant []error
+	}{
+		{
+			name:    "domain not found",
+			err:     resourceStatus(t, codes.NotFound, "domain", "uuid"),
+			want:    []error{ErrNotFound, ErrDomainNotFound},
+			notWant: []error{ErrBridgeNotFound, ErrAlreadyExists},
+		},
+		{
+			name:    "bridge not found",
+			err:     resourceStatus(t, codes.NotFound, "bridge", "br1000"),
+			want:    []error{ErrNotFound, ErrBridgeNotFound},
+			notWant: []error{ErrDomainNotFound},
+		},
+		{
+			name:    "not found without details",
+			err:     status.Error(codes.NotFound, "operation is not found"),
+			want:    []error{ErrNotFound},
+			notWant: []error{ErrDomainNotFound},
+		},
+		{
+			name:    "already exists",
+			err:     resourceStatus(t, codes.AlreadyExists, "bridge", "br1000"),
+			want:    []error{ErrAlreadyExists},
+			notWant: []error{ErrNotFound},
+		},
+		{
+			name: "invalid argument",
+			err:  status.Error(codes.InvalidArgument, "invalid uuid"),
+			want: []error{ErrInvalidArgument},
+		},
+		{
+			name: "wrapped",
+			err:  fmt.Errorf("failed to start: %w", toError(status.Error(codes.FailedPrecondition, "running"))),
+			want: []error{ErrFailedPrecondition},
+		},
+	}
+	for _, test := range tests {
+		err := toError(test.err)
+		for _, want := range test.want {
+			if !errors.Is(err, want) {
+				t.Errorf("%s: want %+v, but got %+v", test.name, want, err)
+			}
+		}
+		for _, notWant := range test.notWant {
+			if errors.Is(err, notWant) {
+				t.Errorf("%s: should not be %+v", test.name, notWant)
+			}
+		}
+		if status.Code(err) != status.Code(test.err) {
+			t.Errorf("%s: want %s, but got %s", test.name, status.Code(test.err), status.Code(err))
+		}
+	}
+}
+
+func TestErrorResource(t *testing.T) {
+	err := toError(resourceStatus(t, codes.NotFound, "domain", "uuid"))
+
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Fatalf("want *Error, but got %T", err)
+	}
+	if e.ResourceType != "domain" {
+		t.Errorf("want %s, but got %s", "domain", e.ResourceType)
+	}
+	if e.ResourceName != "uuid" {
+		t.Errorf("want %s, but got %s", "uuid", e.ResourceName)
+	}
+}
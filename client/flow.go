@@ -0,0 +1,202 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+// Volume is an iSCSI volume exported to the hypervisor.
+type Volume struct {
+	PortalAddresses []string
+	HostLUNID       uint32
+}
+
+// IOTune limits the I/O of a block device. Zero values are unlimited.
+type IOTune struct {
+	ReadBytesSec  uint32
+	WriteBytesSec uint32
+	ReadIopsSec   uint32
+	WriteIopsSec  uint32
+}
+
+// Interface is a network interface of a virtual machine.
+// If IPAddress is set, a security group allowing IngressRules and EgressRules is added to the interface.
+type Interface struct {
+	Bridge          string
+	Name            string
+	MACAddress      string
+	IPAddress       string
+	IPv6Address     string // optional, see AddSecurityGroupRequest
+	InboundAverage  uint32
+	OutboundAverage uint32
+	IngressRules    []*pb.SecurityGroupRule
+	EgressRules     []*pb.SecurityGroupRule
+	// AllowedAddressPairs are the other addresses that the virtual machine sends traffic from
+	AllowedAddressPairs []*pb.AllowedAddressPair
+}
+
+// VirtualMachine is the spec of a virtual machine booted by BootVirtualMachine.
+type VirtualMachine struct {
+	Name         string
+	Vcpus        uint32
+	MemoryKiB    uint64
+	PinningGroup string
+	BootVolume   Volume
+	BootIOTune   IOTune
+	Interfaces   []Interface
+}
+
+// AttachVolume connects v to the hypervisor and attaches it to the virtual machine as targetDevice.
+// It returns the device name of v on the hypervisor. If attaching fails, v is disconnected again.
+func (c *Client) AttachVolume(ctx context.Context, uuid string, v Volume, targetDevice string, tune IOTune) (string, error) {
+	device, err := c.connectVolume(ctx, v)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = c.AttachBlockDevice(ctx, &pb.AttachBlockDeviceRequest{
+		Uuid:          uuid,
+		SourceDevice:  device,
+		TargetDevice:  targetDevice,
+		ReadBytesSec:  tune.ReadBytesSec,
+		WriteBytesSec: tune.WriteBytesSec,
+		ReadIopsSec:   tune.ReadIopsSec,
+		WriteIopsSec:  tune.WriteIopsSec,
+	})
+	if err != nil {
+		c.rollback(c.disconnectVolume(v))
+		return "", fmt.Errorf("failed to attach block device: %w", err)
+	}
+
+	return device, nil
+}
+
+// DetachVolume detaches v from the virtual machine and disconnects it from the hypervisor.
+func (c *Client) DetachVolume(ctx context.Context, uuid string, v Volume, sourceDevice, targetDevice string) error {
+	_, err := c.DetachBlockDevice(ctx, &pb.DetachBlockDeviceRequest{
+		Uuid:         uuid,
+		SourceDevice: sourceDevice,
+		TargetDevice: targetDevice,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to detach block device: %w", err)
+	}
+
+	if err := c.disconnectVolume(v)(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// BootVirtualMachine connects the boot volume, defines the virtual machine, attaches its interfaces
+// and starts it. It returns the uuid of the virtual machine. When a step fails, the completed steps
+// are rolled back.
+func (c *Client) BootVirtualMachine(ctx context.Context, vm VirtualMachine) (string, error) {
+	var undo []func(ctx context.Context) error
+	fail := func(err error) (string, error) {
+		for i := len(undo) - 1; i >= 0; i-- {
+			c.rollback(undo[i])
+		}
+		return "", err
+	}
+
+	device, err := c.connectVolume(ctx, vm.BootVolume)
+	if err != nil {
+		return fail(err)
+	}
+	undo = append(undo, c.disconnectVolume(vm.BootVolume))
+
+	resp, err := c.AddVirtualMachine(ctx, &pb.AddVirtualMachineRequest{
+		Name:             vm.Name,
+		Vcpus:            vm.Vcpus,
+		MemoryKib:        vm.MemoryKiB,
+		BootDevice:       device,
+		ReadBytesSec:     vm.BootIOTune.ReadBytesSec,
+		WriteBytesSec:    vm.BootIOTune.WriteBytesSec,
+		ReadIopsSec:      vm.BootIOTune.ReadIopsSec,
+		WriteIopsSec:     vm.BootIOTune.WriteIopsSec,
+		PinningGroupName: vm.PinningGroup,
+	})
+	if err != nil {
+		return fail(fmt.Errorf("failed to add virtual machine: %w", err))
+	}
+	uuid := resp.Uuid
+	undo = append(undo, func(ctx context.Context) error {
+		_, err := c.DeleteVirtualMachine(ctx, &pb.DeleteVirtualMachineRequest{Uuid: uuid})
+		return err
+	})
+
+	for _, iface := range vm.Interfaces {
+		_, err := c.AttachInterface(ctx, &pb.AttachInterfaceRequest{
+			Uuid:            uuid,
+			Bridge:          iface.Bridge,
+			InboundAverage:  iface.InboundAverage,
+			OutboundAverage: iface.OutboundAverage,
+			Name:            iface.Name,
+			MacAddress:      iface.MACAddress,
+		})
+		if err != nil {
+			return fail(fmt.Errorf("failed to attach interface %s: %w", iface.Name, err))
+		}
+
+		if iface.IPAddress == "" {
+			continue
+		}
+		// RemoveSecurityGroup is idempotent, so it also cleans up a partially added security group
+		name := iface.Name
+		undo = append(undo, func(ctx context.Context) error {
+			_, err := c.RemoveSecurityGroup(ctx, &pb.RemoveSecurityGroupRequest{Interface: name})
+			return err
+		})
+		_, err = c.AddSecurityGroup(ctx, &pb.AddSecurityGroupRequest{
+			Interface:           iface.Name,
+			IpAddress:           iface.IPAddress,
+			Ipv6Address:         iface.IPv6Address,
+			MacAddress:          iface.MACAddress,
+			IngressRules:        iface.IngressRules,
+			EgressRules:         iface.EgressRules,
+			AllowedAddressPairs: iface.AllowedAddressPairs,
+		})
+		if err != nil {
+			return fail(fmt.Errorf("failed to add security group of %s: %w", iface.Name, err))
+		}
+	}
+
+	if _, err := c.StartVirtualMachine(ctx, &pb.StartVirtualMachineRequest{Uuid: uuid}); err != nil {
+		return fail(fmt.Errorf("failed to start virtual machine: %w", err))
+	}
+
+	return uuid, nil
+}
+
+func (c *Client) connectVolume(ctx context.Context, v Volume) (string, error) {
+	resp, err := c.ConnectBlockDevice(ctx, &pb.ConnectBlockDeviceRequest{
+		PortalAddresses: v.PortalAddresses,
+		HostLunId:       v.HostLUNID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to connect block device: %w", err)
+	}
+	return resp.DeviceName, nil
+}
+
+func (c *Client) disconnectVolume(v Volume) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := c.DisconnectBlockDevice(ctx, &pb.DisconnectBlockDeviceRequest{
+			PortalAddresses: v.PortalAddresses,
+			HostLunId:       v.HostLUNID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to disconnect block device: %w", err)
+		}
+		return nil
+	}
+}
+
+// rollback runs undo with a new context, as the context of the failed step may be already done.
+// Errors are ignored because the error of the failed step is more important to the caller.
+func (c *Client) rollback(undo func(ctx context.Context) error) {
+	_ = undo(context.Background())
+}
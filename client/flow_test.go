@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+var testVolume = Volume{PortalAddresses: []string{"192.0.2.10"}, HostLUNID: 1}
+
+func TestAttachVolume(t *testing.T) {
+	tests := []struct {
+		name      string
+		errs      map[string][]error
+		wantCalls []string
+		wantErr   error
+	}{
+		{
+			name:      "attach",
+			wantCalls: []string{"ConnectBlockDevice", "AttachBlockDevice"},
+		},
+		{
+			name:      "failed to connect",
+			errs:      map[string][]error{"ConnectBlockDevice": {status.Error(codes.InvalidArgument, "invalid portal address")}},
+			wantCalls: []string{"ConnectBlockDevice"},
+			wantErr:   ErrInvalidArgument,
+		},
+		{
+			name:      "failed to attach",
+			errs:      map[string][]error{"AttachBlockDevice": {status.Error(codes.NotFound, "domain is not found")}},
+			wantCalls: []string{"ConnectBlockDevice", "AttachBlockDevice", "DisconnectBlockDevice"},
+			wantErr:   ErrNotFound,
+		},
+	}
+	for _, test := range tests {
+		c, agent := newTestClient(t, test.errs)
+
+		device, err := c.AttachVolume(context.Background(), "uuid", testVolume, "vdb", IOTune{ReadIopsSec: 1000})
+		if test.wantErr == nil {
+			if err != nil {
+				t.Fatalf("%s: should not be error but: %+v", test.name, err)
+			}
+			if device != "/dev/dm-0" {
+				t.Errorf("%s: want %s, but got %s", test.name, "/dev/dm-0", device)
+			}
+			req := agent.requests[1].(*pb.AttachBlockDeviceRequest)
+			if req.SourceDevice != "/dev/dm-0" || req.TargetDevice != "vdb" || req.ReadIopsSec != 1000 {
+				t.Errorf("%s: unexpected request %+v", test.name, req)
+			}
+		} else if err == nil {
+			t.Errorf("%s: should be error but not", test.name)
+		} else if !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: want %+v, but got %+v", test.name, test.wantErr, err)
+		}
+
+		if got := strings.Join(agent.calls, ","); got != strings.Join(test.wantCalls, ",") {
+			t.Errorf("%s: want %s, but got %s", test.name, strings.Join(test.wantCalls, ","), got)
+		}
+	}
+}
+
+func TestDetachVolume(t *testing.T) {
+	c, agent := newTestClient(t, nil)
+
+	if err := c.DetachVolume(context.Background(), "uuid", testVolume, "/dev/dm-0", "vdb"); err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	want := "DetachBlockDevice,DisconnectBlockDevice"
+	if got := strings.Join(agent.calls, ","); got != want {
+		t.Errorf("want %s, but got %s", want, got)
+	}
+}
+
+func TestBootVirtualMachine(t *testing.T) {
+	vm := VirtualMachine{
+		Name:       "vm1",
+		Vcpus:      2,
+		MemoryKiB:  2097152,
+		BootVolume: testVolume,
+		Interfaces: []Interface{
+			{Bridge: "br1000", Name: "tap0", MACAddress: "52:54:00:00:00:01", IPAddress: "192.0.2.100"},
+			{Bridge: "br2000", Name: "tap1", MACAddress: "52:54:00:00:00:02"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		errs      map[string][]error
+		wantCalls []string
+		wantErr   bool
+	}{
+		{
+			name: "boot",
+			wantCalls: []string{
+				"ConnectBlockDevice", "AddVirtualMachine",
+				"AttachInterface", "AddSecurityGroup", "AttachInterface",
+				"StartVirtualMachine",
+			},
+		},
+		{
+			name: "failed to add virtual machine",
+			errs: map[string][]error{"AddVirtualMachine": {status.Error(codes.Internal, "failed")}},
+			wantCalls: []string{
+				"ConnectBlockDevice", "AddVirtualMachine",
+				"DisconnectBlockDevice",
+			},
+			wantErr: true,
+		},
+		{
+			name: "failed to start",
+			errs: map[string][]error{"StartVirtualMachine": {status.Error(codes.Internal, "failed")}},
+			wantCalls: []string{
+				"ConnectBlockDevice", "AddVirtualMachine",
+				"AttachInterface", "AddSecurityGroup", "AttachInterface",
+				"StartVirtualMachine",
+				"RemoveSecurityGroup", "DeleteVirtualMachine", "DisconnectBlockDevice",
+			},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		c, agent := newTestClient(t, test.errs)
+
+		uuid, err := c.BootVirtualMachine(context.Background(), vm)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%s: should be error but not", test.name)
+			}
+		} else {
+			if err != nil {
+				t.Fatalf("%s: should not be error but: %+v", test.name, err)
+			}
+			if uuid != "uuid" {
+				t.Errorf("%s: want %s, but got %s", test.name, "uuid", uuid)
+			}
+			req := agent.requests[1].(*pb.AddVirtualMachineRequest)
+			if req.BootDevice != "/dev/dm-0" {
+				t.Errorf("%s: want %s, but got %s", test.name, "/dev/dm-0", req.BootDevice)
+			}
+		}
+
+		if got := strings.Join(agent.calls, ","); got != strings.Join(test.wantCalls, ",") {
+			t.Errorf("%s: want %s, but got %s", test.name, strings.Join(test.wantCalls, ","), got)
+		}
+	}
+}
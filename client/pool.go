@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Pool shares a connection per agent address. It is safe for concurrent use.
+type Pool struct {
+	opts []Option
+
+	mu      sync.Mutex
+	clients map[string]*Client
+	closed  bool
+}
+
+// NewPool returns a pool of clients configured with opts.
+func NewPool(opts ...Option) *Pool {
+	return &Pool{
+		opts:    opts,
+		clients: map[string]*Client{},
+	}
+}
+
+// Get returns the client of the agent at addr, dialing it on the first call.
+// The client must not be closed by the caller.
+func (p *Pool) Get(ctx context.Context, addr string) (*Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, fmt.Errorf("pool is closed")
+	}
+	if c, ok := p.clients[addr]; ok {
+		return c, nil
+	}
+
+	c, err := Dial(ctx, addr, p.opts...)
+	if err != nil {
+		return nil, err
+	}
+	p.clients[addr] = c
+	return c, nil
+}
+
+// Remove closes the connection to the agent at addr, e.g. when the hypervisor is decommissioned.
+func (p *Pool) Remove(addr string) error {
+	p.mu.Lock()
+	c, ok := p.clients[addr]
+	delete(p.clients, addr)
+	p.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	return c.Close()
+}
+
+// Close closes all connections of the pool.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	clients := p.clients
+	p.clients = map[string]*Client{}
+	p.closed = true
+	p.mu.Unlock()
+
+	var firstErr error
+	for addr, c := range clients {
+		if err := c.Close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to close connection to %s: %w", addr, err)
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+// unaryClientInterceptor applies the default deadline and request_id, retries the request while
+// the agent is unavailable, and converts the error to *Error.
+func (o *options) unaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, ok := ctx.Deadline(); !ok && o.timeout > 0 {
+			timeout := o.timeout
+			if r, ok := req.(*pb.WaitOperationRequest); ok {
+				// WaitOperation blocks on the agent up to timeout_seconds
+				timeout += time.Duration(r.TimeoutSeconds) * time.Second
+			}
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		req = withRequestID(req)
+
+		backoff := o.initialBackoff
+		for attempt := 1; ; attempt++ {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if err == nil {
+				return nil
+			}
+			if attempt >= o.maxAttempts || !retryable(err) {
+				return toError(err)
+			}
+
+			select {
+			case <-time.After(jitter(backoff)):
+			case <-ctx.Done():
+				return toError(err)
+			}
+			backoff *= 2
+			if backoff > o.maxBackoff {
+				backoff = o.maxBackoff
+			}
+		}
+	}
+}
+
+// withRequestID returns a copy of req with a new request_id if req has an empty request_id field.
+// All mutating requests have request_id, so every request is safe to retry with the same request_id.
+// The request of the caller is not modified.
+func withRequestID(req interface{}) interface{} {
+	m, ok := req.(proto.Message)
+	if !ok {
+		return req
+	}
+	r := m.ProtoReflect()
+	fd := r.Descriptor().Fields().ByName("request_id")
+	if fd == nil || r.Get(fd).String() != "" {
+		return req
+	}
+
+	clone := proto.Clone(m)
+	clone.ProtoReflect().Set(fd, protoreflect.ValueOfString(newRequestID()))
+	return clone
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// fall back to the time, it is unique enough within the idempotency window
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// retryable reports whether err is a transient error of the connection or the agent.
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// jitter returns a random duration in [d/2, d).
+func jitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(d/2)))
+	if err != nil {
+		return d
+	}
+	return d/2 + time.Duration(n.Int64())
+}
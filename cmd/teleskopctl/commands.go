@@ -0,0 +1,839 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+// callFunc calls an RPC of the agent.
+type callFunc func(ctx context.Context, client pb.AgentClient) (proto.Message, error)
+
+type command struct {
+	name string
+	args string
+	help string
+	// rpc is the name of the RPC called by the command.
+	rpc string
+	// run parses the flags and the arguments of the command, and returns the call of the RPC.
+	run func(fs *flag.FlagSet, args []string) (callFunc, error)
+}
+
+var commands = []command{
+	{
+		name: "iqn",
+		help: "show the iSCSI qualified name of the hypervisor",
+		rpc:  "GetISCSIQualifiedName",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			if _, err := parseArgs(fs, args, 0); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.GetISCSIQualifiedName(ctx, &pb.GetISCSIQualifiedNameRequest{})
+			}, nil
+		},
+	},
+	{
+		name: "iptables",
+		help: "show iptables rules managed by the agent",
+		rpc:  "GetIPTables",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			intf := fs.String("interface", "", "only show the chains and rules of the interface")
+			if _, err := parseArgs(fs, args, 0); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.GetIPTables(ctx, &pb.GetIPTablesRequest{Interface: *intf})
+			}, nil
+		},
+	},
+	{
+		name: "interface-name",
+		help: "show the interface name of the teleskop network",
+		rpc:  "GetInterfaceName",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			if _, err := parseArgs(fs, args, 0); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.GetInterfaceName(ctx, &pb.GetInterfaceNameRequest{})
+			}, nil
+		},
+	},
+	{
+		name: "sg setup",
+		help: "set up the default security group chains",
+		rpc:  "SetupDefaultSecurityGroup",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			if _, err := parseArgs(fs, args, 0); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{})
+			}, nil
+		},
+	},
+	{
+		name: "sg add",
+		args: "<interface> <ip address> <mac address>",
+		help: "add a security group for the interface of a virtual machine",
+		rpc:  "AddSecurityGroup",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			rules := defineRuleFlags(fs)
+			ipv6Addr := fs.String("ipv6-address", "", "global IPv6 address of the virtual machine")
+			var pairs pairList
+			fs.Var(&pairs, "allowed-address-pair", "additional address of the virtual machine as <ip address or cidr>[,<mac address>] (repeatable)")
+			a, err := parseArgs(fs, args, 3)
+			if err != nil {
+				return nil, err
+			}
+			req := &pb.AddSecurityGroupRequest{
+				Interface:           a[0],
+				IpAddress:           a[1],
+				Ipv6Address:         *ipv6Addr,
+				MacAddress:          a[2],
+				IngressRules:        rules.ingress,
+				EgressRules:         rules.egress,
+				AllowedAddressPairs: pairs,
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.AddSecurityGroup(ctx, req)
+			}, nil
+		},
+	},
+	{
+		name: "sg update",
+		args: "<interface>",
+		help: "replace the rules of the security group of the interface",
+		rpc:  "UpdateSecurityGroupRules",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			rules := defineRuleFlags(fs)
+			a, err := parseArgs(fs, args, 1)
+			if err != nil {
+				return nil, err
+			}
+			req := &pb.UpdateSecurityGroupRulesRequest{
+				Interface:    a[0],
+				IngressRules: rules.ingress,
+				EgressRules:  rules.egress,
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.UpdateSecurityGroupRules(ctx, req)
+			}, nil
+		},
+	},
+	{
+		name: "sg remove",
+		args: "<interface>",
+		help: "remove the security group of the interface and its conntrack entries",
+		rpc:  "RemoveSecurityGroup",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			a, err := parseArgs(fs, args, 1)
+			if err != nil {
+				return nil, err
+			}
+			req := &pb.RemoveSecurityGroupRequest{Interface: a[0]}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.RemoveSecurityGroup(ctx, req)
+			}, nil
+		},
+	},
+	{
+		name: "pair add",
+		args: "<interface> <ip address or cidr>[,<mac address>]...",
+		help: "allow traffic from additional addresses of the virtual machine, e.g. virtual IPs",
+		rpc:  "AddAllowedAddressPairs",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			intf, pairs, err := parsePairArgs(fs, args)
+			if err != nil {
+				return nil, err
+			}
+			req := &pb.AddAllowedAddressPairsRequest{Interface: intf, AllowedAddressPairs: pairs}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.AddAllowedAddressPairs(ctx, req)
+			}, nil
+		},
+	},
+	{
+		name: "pair remove",
+		args: "<interface> <ip address or cidr>[,<mac address>]...",
+		help: "remove allowed address pairs of the virtual machine and the conntrack entries of their addresses",
+		rpc:  "RemoveAllowedAddressPairs",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			intf, pairs, err := parsePairArgs(fs, args)
+			if err != nil {
+				return nil, err
+			}
+			req := &pb.RemoveAllowedAddressPairsRequest{Interface: intf, AllowedAddressPairs: pairs}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.RemoveAllowedAddressPairs(ctx, req)
+			}, nil
+		},
+	},
+	{
+		name: "group set",
+		args: "<name> [ip address...]",
+		help: "replace the members of a remote group of security group rules",
+		rpc:  "SetRemoteGroupMembers",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			if err := parseFlags(fs, args); err != nil {
+				return nil, err
+			}
+			if fs.NArg() < 1 {
+				return nil, fmt.Errorf("name is required")
+			}
+			req := &pb.SetRemoteGroupMembersRequest{
+				Name:        fs.Arg(0),
+				IpAddresses: fs.Args()[1:],
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.SetRemoteGroupMembers(ctx, req)
+			}, nil
+		},
+	},
+	{
+		name: "group delete",
+		args: "<name>",
+		help: "delete a remote group that is not referred by security group rules",
+		rpc:  "DeleteRemoteGroup",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			a, err := parseArgs(fs, args, 1)
+			if err != nil {
+				return nil, err
+			}
+			req := &pb.DeleteRemoteGroupRequest{Name: a[0]}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.DeleteRemoteGroup(ctx, req)
+			}, nil
+		},
+	},
+	{
+		name: "group list",
+		help: "list remote groups and their members",
+		rpc:  "ListRemoteGroups",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			if _, err := parseArgs(fs, args, 0); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.ListRemoteGroups(ctx, &pb.ListRemoteGroupsRequest{})
+			}, nil
+		},
+	},
+	{
+		name: "bridge add",
+		args: "<name>",
+		help: "add a bridge",
+		rpc:  "AddBridge",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			metadataCIDR := fs.String("metadata-cidr", "", "address of the metadata interface in CIDR notation")
+			internalOnly := fs.Bool("internal-only", false, "add the bridge without the metadata interface")
+			a, err := parseArgs(fs, args, 1)
+			if err != nil {
+				return nil, err
+			}
+			req := &pb.AddBridgeRequest{
+				Name:         a[0],
+				MetadataCidr: *metadataCIDR,
+				InternalOnly: *internalOnly,
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.AddBridge(ctx, req)
+			}, nil
+		},
+	},
+	{
+		name: "bridge delete",
+		args: "<name>",
+		help: "delete a bridge",
+		rpc:  "DeleteBridge",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			a, err := parseArgs(fs, args, 1)
+			if err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.DeleteBridge(ctx, &pb.DeleteBridgeRequest{Name: a[0]})
+			}, nil
+		},
+	},
+	{
+		name: "bridge add-interface",
+		args: "<bridge> <interface>",
+		help: "add an interface to a bridge",
+		rpc:  "AddInterfaceToBridge",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			a, err := parseArgs(fs, args, 2)
+			if err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.AddInterfaceToBridge(ctx, &pb.AddInterfaceToBridgeRequest{Bridge: a[0], Interface: a[1]})
+			}, nil
+		},
+	},
+	{
+		name: "bridge delete-interface",
+		args: "<bridge> <interface>",
+		help: "delete an interface from a bridge",
+		rpc:  "DeleteInterfaceFromBridge",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			a, err := parseArgs(fs, args, 2)
+			if err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.DeleteInterfaceFromBridge(ctx, &pb.DeleteInterfaceFromBridgeRequest{Bridge: a[0], Interface: a[1]})
+			}, nil
+		},
+	},
+	{
+		name: "vlan add",
+		args: "<parent interface> <vlan id>",
+		help: "add a VLAN interface",
+		rpc:  "AddVLANInterface",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			a, err := parseArgs(fs, args, 2)
+			if err != nil {
+				return nil, err
+			}
+			vlanID, err := parseUint32("vlan id", a[1])
+			if err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.AddVLANInterface(ctx, &pb.AddVLANInterfaceRequest{ParentInterface: a[0], VlanId: vlanID})
+			}, nil
+		},
+	},
+	{
+		name: "vlan delete",
+		args: "<parent interface> <vlan id>",
+		help: "delete a VLAN interface",
+		rpc:  "DeleteVLANInterface",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			a, err := parseArgs(fs, args, 2)
+			if err != nil {
+				return nil, err
+			}
+			vlanID, err := parseUint32("vlan id", a[1])
+			if err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.DeleteVLANInterface(ctx, &pb.DeleteVLANInterfaceRequest{ParentInterface: a[0], VlanId: vlanID})
+			}, nil
+		},
+	},
+	{
+		name: "vm add",
+		args: "<name>",
+		help: "define a virtual machine",
+		rpc:  "AddVirtualMachine",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			vcpus := fs.Uint("vcpus", 1, "number of vCPUs")
+			memoryKiB := fs.Uint64("memory-kib", 1048576, "memory size in KiB")
+			bootDevice := fs.String("boot-device", "", "block device to boot from, such as /dev/dm-0")
+			pinningGroup := fs.String("pinning-group", "", "name of the CPU pinning group")
+			limits := defineIOTuneFlags(fs)
+			a, err := parseArgs(fs, args, 1)
+			if err != nil {
+				return nil, err
+			}
+			req := &pb.AddVirtualMachineRequest{
+				Name:             a[0],
+				Vcpus:            uint32(*vcpus),
+				MemoryKib:        *memoryKiB,
+				BootDevice:       *bootDevice,
+				ReadBytesSec:     uint32(limits.readBytesSec),
+				WriteBytesSec:    uint32(limits.writeBytesSec),
+				ReadIopsSec:      uint32(limits.readIopsSec),
+				WriteIopsSec:     uint32(limits.writeIopsSec),
+				PinningGroupName: *pinningGroup,
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.AddVirtualMachine(ctx, req)
+			}, nil
+		},
+	},
+	{
+		name: "vm get",
+		args: "<uuid>",
+		help: "show the state of a virtual machine",
+		rpc:  "GetVirtualMachineState",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			a, err := parseArgs(fs, args, 1)
+			if err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.GetVirtualMachineState(ctx, &pb.GetVirtualMachineStateRequest{Uuid: a[0]})
+			}, nil
+		},
+	},
+	{
+		name: "vm list",
+		help: "list the states of virtual machines",
+		rpc:  "ListVirtualMachineState",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			if _, err := parseArgs(fs, args, 0); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.ListVirtualMachineState(ctx, &pb.ListVirtualMachineStateRequest{})
+			}, nil
+		},
+	},
+	{
+		name: "vm start",
+		args: "<uuid>",
+		help: "start a virtual machine",
+		rpc:  "StartVirtualMachine",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			a, err := parseArgs(fs, args, 1)
+			if err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.StartVirtualMachine(ctx, &pb.StartVirtualMachineRequest{Uuid: a[0]})
+			}, nil
+		},
+	},
+	{
+		name: "vm stop",
+		args: "<uuid>",
+		help: "stop a virtual machine forcibly",
+		rpc:  "StopVirtualMachine",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			a, err := parseArgs(fs, args, 1)
+			if err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.StopVirtualMachine(ctx, &pb.StopVirtualMachineRequest{Uuid: a[0]})
+			}, nil
+		},
+	},
+	{
+		name: "vm delete",
+		args: "<uuid>",
+		help: "undefine a virtual machine",
+		rpc:  "DeleteVirtualMachine",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			a, err := parseArgs(fs, args, 1)
+			if err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.DeleteVirtualMachine(ctx, &pb.DeleteVirtualMachineRequest{Uuid: a[0]})
+			}, nil
+		},
+	},
+	{
+		name: "vm attach-disk",
+		args: "<uuid> <source device> <target device>",
+		help: "attach a block device to a virtual machine",
+		rpc:  "AttachBlockDevice",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			limits := defineIOTuneFlags(fs)
+			a, err := parseArgs(fs, args, 3)
+			if err != nil {
+				return nil, err
+			}
+			req := &pb.AttachBlockDeviceRequest{
+				Uuid:          a[0],
+				SourceDevice:  a[1],
+				TargetDevice:  a[2],
+				ReadBytesSec:  uint32(limits.readBytesSec),
+				WriteBytesSec: uint32(limits.writeBytesSec),
+				ReadIopsSec:   uint32(limits.readIopsSec),
+				WriteIopsSec:  uint32(limits.writeIopsSec),
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.AttachBlockDevice(ctx, req)
+			}, nil
+		},
+	},
+	{
+		name: "vm detach-disk",
+		args: "<uuid> <source device> <target device>",
+		help: "detach a block device from a virtual machine",
+		rpc:  "DetachBlockDevice",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			a, err := parseArgs(fs, args, 3)
+			if err != nil {
+				return nil, err
+			}
+			req := &pb.DetachBlockDeviceRequest{
+				Uuid:         a[0],
+				SourceDevice: a[1],
+				TargetDevice: a[2],
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.DetachBlockDevice(ctx, req)
+			}, nil
+		},
+	},
+	{
+		name: "vm attach-interface",
+		args: "<uuid> <bridge> <interface> <mac address>",
+		help: "attach an interface to a virtual machine",
+		rpc:  "AttachInterface",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			inbound := fs.Uint("inbound-average", 0, "average inbound bandwidth in KB/s")
+			outbound := fs.Uint("outbound-average", 0, "average outbound bandwidth in KB/s")
+			a, err := parseArgs(fs, args, 4)
+			if err != nil {
+				return nil, err
+			}
+			req := &pb.AttachInterfaceRequest{
+				Uuid:            a[0],
+				Bridge:          a[1],
+				Name:            a[2],
+				MacAddress:      a[3],
+				InboundAverage:  uint32(*inbound),
+				OutboundAverage: uint32(*outbound),
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.AttachInterface(ctx, req)
+			}, nil
+		},
+	},
+	{
+		name: "vm detach-interface",
+		args: "<uuid> <bridge> <interface> <mac address>",
+		help: "detach an interface from a virtual machine",
+		rpc:  "DetachInterface",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			inbound := fs.Uint("inbound-average", 0, "average inbound bandwidth in KB/s given on attach")
+			outbound := fs.Uint("outbound-average", 0, "average outbound bandwidth in KB/s given on attach")
+			a, err := parseArgs(fs, args, 4)
+			if err != nil {
+				return nil, err
+			}
+			req := &pb.DetachInterfaceRequest{
+				Uuid:            a[0],
+				Bridge:          a[1],
+				Name:            a[2],
+				MacAddress:      a[3],
+				InboundAverage:  uint32(*inbound),
+				OutboundAverage: uint32(*outbound),
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.DetachInterface(ctx, req)
+			}, nil
+		},
+	},
+	{
+		name: "disk connect",
+		args: "<host lun id> <portal address>...",
+		help: "connect an iSCSI volume, with multipath if several portals are given",
+		rpc:  "ConnectBlockDevice",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			async := fs.Bool("async", false, "return an operation id without waiting for the connection")
+			hostLUNID, portals, err := parseVolumeArgs(fs, args)
+			if err != nil {
+				return nil, err
+			}
+			req := &pb.ConnectBlockDeviceRequest{
+				PortalAddresses: portals,
+				HostLunId:       hostLUNID,
+				Async:           *async,
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.ConnectBlockDevice(ctx, req)
+			}, nil
+		},
+	},
+	{
+		name: "disk disconnect",
+		args: "<host lun id> <portal address>...",
+		help: "disconnect an iSCSI volume",
+		rpc:  "DisconnectBlockDevice",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			async := fs.Bool("async", false, "return an operation id without waiting for the disconnection")
+			hostLUNID, portals, err := parseVolumeArgs(fs, args)
+			if err != nil {
+				return nil, err
+			}
+			req := &pb.DisconnectBlockDeviceRequest{
+				PortalAddresses: portals,
+				HostLunId:       hostLUNID,
+				Async:           *async,
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.DisconnectBlockDevice(ctx, req)
+			}, nil
+		},
+	},
+	{
+		name: "op get",
+		args: "<id>",
+		help: "show a long-running operation",
+		rpc:  "GetOperation",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			a, err := parseArgs(fs, args, 1)
+			if err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.GetOperation(ctx, &pb.GetOperationRequest{Id: a[0]})
+			}, nil
+		},
+	},
+	{
+		name: "op list",
+		help: "list long-running operations",
+		rpc:  "ListOperations",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			method := fs.String("method", "", "full method name to filter, such as /agent.Agent/ConnectBlockDevice")
+			if _, err := parseArgs(fs, args, 0); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.ListOperations(ctx, &pb.ListOperationsRequest{Method: *method})
+			}, nil
+		},
+	},
+	{
+		name: "op cancel",
+		args: "<id>",
+		help: "cancel a long-running operation",
+		rpc:  "CancelOperation",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			a, err := parseArgs(fs, args, 1)
+			if err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.CancelOperation(ctx, &pb.CancelOperationRequest{Id: a[0]})
+			}, nil
+		},
+	},
+	{
+		name: "op wait",
+		args: "<id>",
+		help: "wait for a long-running operation to finish",
+		rpc:  "WaitOperation",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			wait := fs.Duration("wait", 0, "max duration to wait, shorter than -timeout (0 waits until -timeout)")
+			a, err := parseArgs(fs, args, 1)
+			if err != nil {
+				return nil, err
+			}
+			req := &pb.WaitOperationRequest{
+				Id:             a[0],
+				TimeoutSeconds: uint32(*wait / time.Second),
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.WaitOperation(ctx, req)
+			}, nil
+		},
+	},
+	{
+		name: "preflight",
+		help: "check the requirements of the hypervisor",
+		rpc:  "RunPreflightChecks",
+		run: func(fs *flag.FlagSet, args []string) (callFunc, error) {
+			fix := fs.Bool("fix", false, "apply safe fixes of failed checks, e.g. loading kernel modules")
+			if _, err := parseArgs(fs, args, 0); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context, client pb.AgentClient) (proto.Message, error) {
+				return client.RunPreflightChecks(ctx, &pb.RunPreflightChecksRequest{Fix: *fix})
+			}, nil
+		},
+	},
+}
+
+// findCommand returns the command named by the leading words of args, and the rest of args.
+func findCommand(args []string) (*command, []string) {
+	for n := 2; n >= 1; n-- {
+		if len(args) < n {
+			continue
+		}
+		name := strings.Join(args[:n], " ")
+		for i := range commands {
+			if commands[i].name == name {
+				return &commands[i], args[n:]
+			}
+		}
+	}
+	return nil, nil
+}
+
+// parseFlags parses the flags of a command. Errors are reported to the user by the flag package.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return err
+		}
+		return errUsage
+	}
+	return nil
+}
+
+// parseArgs parses the flags, and returns exactly n positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string, n int) ([]string, error) {
+	if err := parseFlags(fs, args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() != n {
+		return nil, fmt.Errorf("%d arguments are required, but got %d", n, fs.NArg())
+	}
+	return fs.Args(), nil
+}
+
+// parsePairArgs parses an interface followed by at least one allowed address pair.
+func parsePairArgs(fs *flag.FlagSet, args []string) (string, []*pb.AllowedAddressPair, error) {
+	if err := parseFlags(fs, args); err != nil {
+		return "", nil, err
+	}
+	if fs.NArg() < 2 {
+		return "", nil, fmt.Errorf("interface and at least one allowed address pair are required")
+	}
+	var pairs pairList
+	for _, arg := range fs.Args()[1:] {
+		if err := pairs.Set(arg); err != nil {
+			return "", nil, err
+		}
+	}
+	return fs.Arg(0), pairs, nil
+}
+
+func parseVolumeArgs(fs *flag.FlagSet, args []string) (uint32, []string, error) {
+	if err := parseFlags(fs, args); err != nil {
+		return 0, nil, err
+	}
+	if fs.NArg() < 2 {
+		return 0, nil, fmt.Errorf("host lun id and at least one portal address are required")
+	}
+	hostLUNID, err := parseUint32("host lun id", fs.Arg(0))
+	if err != nil {
+		return 0, nil, err
+	}
+	return hostLUNID, fs.Args()[1:], nil
+}
+
+func parseUint32(name, s string) (uint32, error) {
+	v, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return uint32(v), nil
+}
+
+type ioTuneFlags struct {
+	readBytesSec  uint
+	writeBytesSec uint
+	readIopsSec   uint
+	writeIopsSec  uint
+}
+
+func defineIOTuneFlags(fs *flag.FlagSet) *ioTuneFlags {
+	var f ioTuneFlags
+	fs.UintVar(&f.readBytesSec, "read-bytes-sec", 0, "read throughput limit in bytes per second (0 is unlimited)")
+	fs.UintVar(&f.writeBytesSec, "write-bytes-sec", 0, "write throughput limit in bytes per second (0 is unlimited)")
+	fs.UintVar(&f.readIopsSec, "read-iops-sec", 0, "read IOPS limit (0 is unlimited)")
+	fs.UintVar(&f.writeIopsSec, "write-iops-sec", 0, "write IOPS limit (0 is unlimited)")
+	return &f
+}
+
+// ruleList is a flag of security group rules that can be given multiple times.
+// A rule is written as <protocol>[:<port range>|<icmp type>][@<remote cidr>|<remote group>], e.g. tcp:22,
+// udp:8000-8100@192.0.2.0/24, icmp:echo-request, icmpv6:packet-too-big@2001:db8::/32 or tcp:5432@db-clients.
+type ruleList []*pb.SecurityGroupRule
+
+func (l *ruleList) String() string {
+	return fmt.Sprintf("%d rules", len(*l))
+}
+
+func (l *ruleList) Set(s string) error {
+	rule, err := parseRule(s)
+	if err != nil {
+		return err
+	}
+	*l = append(*l, rule)
+	return nil
+}
+
+// pairList is a flag of allowed address pairs that can be given multiple times. A pair is written as
+// <ip address or cidr>[,<mac address>], e.g. 192.0.2.10 or 192.0.2.0/28,52:54:00:00:00:09.
+type pairList []*pb.AllowedAddressPair
+
+func (l *pairList) String() string {
+	return fmt.Sprintf("%d pairs", len(*l))
+}
+
+func (l *pairList) Set(s string) error {
+	pair := &pb.AllowedAddressPair{IpAddress: s}
+	if i := strings.Index(s, ","); i >= 0 {
+		pair.IpAddress, pair.MacAddress = s[:i], s[i+1:]
+	}
+	if pair.IpAddress == "" {
+		return fmt.Errorf("ip address of pair %q is empty", s)
+	}
+	*l = append(*l, pair)
+	return nil
+}
+
+type ruleFlags struct {
+	ingress ruleList
+	egress  ruleList
+}
+
+func defineRuleFlags(fs *flag.FlagSet) *ruleFlags {
+	var f ruleFlags
+	fs.Var(&f.ingress, "ingress", "allowed traffic to the virtual machine as <protocol>[:<ports>|<icmp type>][@<cidr>|<group>] (repeatable)")
+	fs.Var(&f.egress, "egress", "allowed traffic from the virtual machine as <protocol>[:<ports>|<icmp type>][@<cidr>|<group>] (repeatable)")
+	return &f
+}
+
+func parseRule(s string) (*pb.SecurityGroupRule, error) {
+	rule := &pb.SecurityGroupRule{}
+	if i := strings.Index(s, "@"); i >= 0 {
+		if remote := s[i+1:]; strings.Contains(remote, "/") {
+			rule.RemoteCidr = remote
+		} else {
+			rule.RemoteGroup = remote
+		}
+		s = s[:i]
+	}
+
+	protocol, match := s, ""
+	if i := strings.Index(s, ":"); i >= 0 {
+		protocol, match = s[:i], s[i+1:]
+	}
+	v, ok := pb.SecurityGroupRule_Protocol_value[strings.ToUpper(protocol)]
+	if !ok {
+		return nil, fmt.Errorf("unknown protocol %s", protocol)
+	}
+	rule.Protocol = pb.SecurityGroupRule_Protocol(v)
+
+	if match == "" {
+		return rule, nil
+	}
+	if rule.Protocol == pb.SecurityGroupRule_ICMP || rule.Protocol == pb.SecurityGroupRule_ICMPV6 {
+		rule.IcmpType = match
+		return rule, nil
+	}
+	ports := strings.SplitN(match, "-", 2)
+	min, err := parseUint32("port", ports[0])
+	if err != nil {
+		return nil, err
+	}
+	rule.PortRangeMin, rule.PortRangeMax = min, min
+	if len(ports) == 2 {
+		if rule.PortRangeMax, err = parseUint32("port", ports[1]); err != nil {
+			return nil, err
+		}
+	}
+	return rule, nil
+}
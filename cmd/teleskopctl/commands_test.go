@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+// recordingClient returns a client that records requests instead of sending them.
+func recordingClient(t *testing.T, method *string, req *proto.Message) pb.AgentClient {
+	t.Helper()
+
+	conn, err := grpc.Dial("passthrough:///teleskop", grpc.WithInsecure(), grpc.WithUnaryInterceptor(
+		func(ctx context.Context, m string, r, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			*method = m
+			*req = r.(proto.Message)
+			return nil
+		},
+	))
+	if err != nil {
+		t.Fatalf("failed to dial: %+v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewAgentClient(conn)
+}
+
+func TestCommandsCoverAllRPCs(t *testing.T) {
+	covered := map[string]bool{}
+	for _, cmd := range commands {
+		covered[cmd.rpc] = true
+	}
+
+	methods := pb.File_agent_proto.Services().ByName("Agent").Methods()
+	for i := 0; i < methods.Len(); i++ {
+		if name := string(methods.Get(i).Name()); !covered[name] {
+			t.Errorf("%s has no command", name)
+		}
+	}
+}
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		args       string
+		wantMethod string
+		wantReq    proto.Message
+		wantErr    bool
+	}{
+		{
+			args:       "vm list",
+			wantMethod: "/agent.Agent/ListVirtualMachineState",
+			wantReq:    &pb.ListVirtualMachineStateRequest{},
+		},
+		{
+			args:       "vm start 3f2504e0-4f89-11d3-9a0c-0305e82c3301",
+			wantMethod: "/agent.Agent/StartVirtualMachine",
+			wantReq:    &pb.StartVirtualMachineRequest{Uuid: "3f2504e0-4f89-11d3-9a0c-0305e82c3301"},
+		},
+		{
+			args:       "vm add -vcpus 2 -memory-kib 2097152 -boot-device /dev/dm-0 -read-iops-sec 1000 vm1",
+			wantMethod: "/agent.Agent/AddVirtualMachine",
+			wantReq:    &pb.AddVirtualMachineRequest{Name: "vm1", Vcpus: 2, MemoryKib: 2097152, BootDevice: "/dev/dm-0", ReadIopsSec: 1000},
+		},
+		{
+			args:       "vm attach-interface -inbound-average 1000 uuid br1000 tap0 52:54:00:00:00:01",
+			wantMethod: "/agent.Agent/AttachInterface",
+			wantReq:    &pb.AttachInterfaceRequest{Uuid: "uuid", Bridge: "br1000", Name: "tap0", MacAddress: "52:54:00:00:00:01", InboundAverage: 1000},
+		},
+		{
+			args:       "bridge add -metadata-cidr 169.254.169.254/16 br1000",
+			wantMethod: "/agent.Agent/AddBridge",
+			wantReq:    &pb.AddBridgeRequest{Name: "br1000", MetadataCidr: "169.254.169.254/16"},
+		},
+		{
+			args:       "vlan add bond0 1000",
+			wantMethod: "/agent.Agent/AddVLANInterface",
+			wantReq:    &pb.AddVLANInterfaceRequest{ParentInterface: "bond0", VlanId: 1000},
+		},
+		{
+			args:       "disk connect -async 1 192.0.2.10 192.0.2.11",
+			wantMethod: "/agent.Agent/ConnectBlockDevice",
+			wantReq:    &pb.ConnectBlockDeviceRequest{HostLunId: 1, PortalAddresses: []string{"192.0.2.10", "192.0.2.11"}, Async: true},
+		},
+		{
+			args:       "op wait -wait 1m id",
+			wantMethod: "/agent.Agent/WaitOperation",
+			wantReq:    &pb.WaitOperationRequest{Id: "id", TimeoutSeconds: 60},
+		},
+		{
+			args:       "iptables -interface tap0",
+			wantMethod: "/agent.Agent/GetIPTables",
+			wantReq:    &pb.GetIPTablesRequest{Interface: "tap0"},
+		},
+		{
+			args:       "sg setup",
+			wantMethod: "/agent.Agent/SetupDefaultSecurityGroup",
+			wantReq:    &pb.SetupDefaultSecurityGroupRequest{},
+		},
+		{
+			args:       "sg add -ingress tcp:22 -ingress icmp:8/0@192.0.2.0/24 -egress any tap0 192.0.2.100 52:54:00:00:00:01",
+			wantMethod: "/agent.Agent/AddSecurityGroup",
+			wantReq: &pb.AddSecurityGroupRequest{
+				Interface:  "tap0",
+				IpAddress:  "192.0.2.100",
+				MacAddress: "52:54:00:00:00:01",
+				IngressRules: []*pb.SecurityGroupRule{
+					{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 22, PortRangeMax: 22},
+					{Protocol: pb.SecurityGroupRule_ICMP, IcmpType: "8/0", RemoteCidr: "192.0.2.0/24"},
+				},
+				EgressRules: []*pb.SecurityGroupRule{{Protocol: pb.SecurityGroupRule_ANY}},
+			},
+		},
+		{
+			args:       "sg add -ipv6-address 2001:db8::100 -ingress icmpv6:packet-too-big@2001:db8::/32 tap0 192.0.2.100 52:54:00:00:00:01",
+			wantMethod: "/agent.Agent/AddSecurityGroup",
+			wantReq: &pb.AddSecurityGroupRequest{
+				Interface:    "tap0",
+				IpAddress:    "192.0.2.100",
+				Ipv6Address:  "2001:db8::100",
+				MacAddress:   "52:54:00:00:00:01",
+				IngressRules: []*pb.SecurityGroupRule{{Protocol: pb.SecurityGroupRule_ICMPV6, IcmpType: "packet-too-big", RemoteCidr: "2001:db8::/32"}},
+			},
+		},
+		{
+			args:       "sg add -allowed-address-pair 192.0.2.10 -allowed-address-pair 2001:db8::/64,52:54:00:00:00:09 tap0 192.0.2.100 52:54:00:00:00:01",
+			wantMethod: "/agent.Agent/AddSecurityGroup",
+			wantReq: &pb.AddSecurityGroupRequest{
+				Interface:  "tap0",
+				IpAddress:  "192.0.2.100",
+				MacAddress: "52:54:00:00:00:01",
+				AllowedAddressPairs: []*pb.AllowedAddressPair{
+					{IpAddress: "192.0.2.10"},
+					{IpAddress: "2001:db8::/64", MacAddress: "52:54:00:00:00:09"},
+				},
+			},
+		},
+		{
+			args:       "pair add tap0 192.0.2.10 192.0.2.16/28,52:54:00:00:00:09",
+			wantMethod: "/agent.Agent/AddAllowedAddressPairs",
+			wantReq: &pb.AddAllowedAddressPairsRequest{
+				Interface: "tap0",
+				AllowedAddressPairs: []*pb.AllowedAddressPair{
+					{IpAddress: "192.0.2.10"},
+					{IpAddress: "192.0.2.16/28", MacAddress: "52:54:00:00:00:09"},
+				},
+			},
+		},
+		{
+			args:       "pair remove tap0 192.0.2.10",
+			wantMethod: "/agent.Agent/RemoveAllowedAddressPairs",
+			wantReq:    &pb.RemoveAllowedAddressPairsRequest{Interface: "tap0", AllowedAddressPairs: []*pb.AllowedAddressPair{{IpAddress: "192.0.2.10"}}},
+		},
+		{
+			args:       "sg update -ingress udp:8000-8100@198.51.100.0/24 tap0",
+			wantMethod: "/agent.Agent/UpdateSecurityGroupRules",
+			wantReq: &pb.UpdateSecurityGroupRulesRequest{
+				Interface:    "tap0",
+				IngressRules: []*pb.SecurityGroupRule{{Protocol: pb.SecurityGroupRule_UDP, PortRangeMin: 8000, PortRangeMax: 8100, RemoteCidr: "198.51.100.0/24"}},
+			},
+		},
+		{
+			args:       "sg update -ingress tcp:5432@db-clients tap0",
+			wantMethod: "/agent.Agent/UpdateSecurityGroupRules",
+			wantReq: &pb.UpdateSecurityGroupRulesRequest{
+				Interface:    "tap0",
+				IngressRules: []*pb.SecurityGroupRule{{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 5432, PortRangeMax: 5432, RemoteGroup: "db-clients"}},
+			},
+		},
+		{
+			args:       "group set db-clients 192.0.2.1 192.0.2.2",
+			wantMethod: "/agent.Agent/SetRemoteGroupMembers",
+			wantReq:    &pb.SetRemoteGroupMembersRequest{Name: "db-clients", IpAddresses: []string{"192.0.2.1", "192.0.2.2"}},
+		},
+		{
+			args:       "group delete db-clients",
+			wantMethod: "/agent.Agent/DeleteRemoteGroup",
+			wantReq:    &pb.DeleteRemoteGroupRequest{Name: "db-clients"},
+		},
+		{
+			args:    "group set",
+			wantErr: true,
+		},
+		{
+			args:    "pair add tap0",
+			wantErr: true,
+		},
+		{
+			args:    "sg update -ingress sctp:22 tap0",
+			wantErr: true,
+		},
+		{
+			args:       "sg remove tap0",
+			wantMethod: "/agent.Agent/RemoveSecurityGroup",
+			wantReq:    &pb.RemoveSecurityGroupRequest{Interface: "tap0"},
+		},
+		{
+			args:       "preflight -fix",
+			wantMethod: "/agent.Agent/RunPreflightChecks",
+			wantReq:    &pb.RunPreflightChecksRequest{Fix: true},
+		},
+		{
+			args:    "vm start",
+			wantErr: true,
+		},
+		{
+			args:    "vlan add bond0 vlan1000",
+			wantErr: true,
+		},
+		{
+			args:    "disk connect 1",
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		cmd, rest := findCommand(strings.Fields(test.args))
+		if cmd == nil {
+			t.Fatalf("%s: command should be found", test.args)
+		}
+		fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+
+		call, err := cmd.run(fs, rest)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%s: should be error but not", test.args)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: should not be error but: %+v", test.args, err)
+		}
+
+		var (
+			method string
+			req    proto.Message
+		)
+		if _, err := call(context.Background(), recordingClient(t, &method, &req)); err != nil {
+			t.Fatalf("%s: failed to call: %+v", test.args, err)
+		}
+		if method != test.wantMethod {
+			t.Errorf("%s: want %s, but got %s", test.args, test.wantMethod, method)
+		}
+		if !proto.Equal(req, test.wantReq) {
+			t.Errorf("%s: want %+v, but got %+v", test.args, test.wantReq, req)
+		}
+	}
+}
+
+func TestRequestIDInterceptor(t *testing.T) {
+	tests := []struct {
+		name string
+		req  proto.Message
+		want proto.Message
+	}{
+		{
+			name: "mutating request",
+			req:  &pb.StartVirtualMachineRequest{Uuid: "uuid"},
+			want: &pb.StartVirtualMachineRequest{Uuid: "uuid", RequestId: "req-1"},
+		},
+		{
+			name: "request with request id",
+			req:  &pb.StartVirtualMachineRequest{Uuid: "uuid", RequestId: "req-2"},
+			want: &pb.StartVirtualMachineRequest{Uuid: "uuid", RequestId: "req-2"},
+		},
+		{
+			name: "request without request id field",
+			req:  &pb.GetVirtualMachineStateRequest{Uuid: "uuid"},
+			want: &pb.GetVirtualMachineStateRequest{Uuid: "uuid"},
+		},
+	}
+	for _, test := range tests {
+		interceptor := requestIDInterceptor("req-1")
+		invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return nil
+		}
+		if err := interceptor(context.Background(), "/agent.Agent/StartVirtualMachine", test.req, nil, nil, invoker); err != nil {
+			t.Fatalf("%s: should not be error but: %+v", test.name, err)
+		}
+		if !proto.Equal(test.req, test.want) {
+			t.Errorf("%s: want %+v, but got %+v", test.name, test.want, test.req)
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	yaml "gopkg.in/yaml.v2"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+const (
+	envPrefix = "TELESKOPCTL_"
+
+	defaultAddress = "127.0.0.1:5000"
+)
+
+var errUsage = errors.New("invalid usage")
+
+type options struct {
+	Address   string
+	Config    string
+	Output    string
+	Timeout   time.Duration
+	RequestID string
+	TokenFile string
+	TLS       tlsOptions
+}
+
+// tlsOptions are the same TLS settings as the agent. The certificate of the agent can be used
+// as a client certificate, because the agent verifies clients with the same CA.
+type tlsOptions struct {
+	CAFile     string `yaml:"ca_file"`
+	CertFile   string `yaml:"cert_file"`
+	KeyFile    string `yaml:"key_file"`
+	ServerName string `yaml:"server_name"`
+}
+
+func (o tlsOptions) enabled() bool {
+	return o.CAFile != "" || o.CertFile != "" || o.KeyFile != ""
+}
+
+// agentConfig is the part of the agent config file used by teleskopctl.
+type agentConfig struct {
+	ListenAddress string     `yaml:"listen_address"`
+	TLS           tlsOptions `yaml:"tls"`
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		if !errors.Is(err, errUsage) {
+			if st, ok := status.FromError(err); ok {
+				fmt.Fprintf(os.Stderr, "Error: %s: %s\n", st.Code(), st.Message())
+			} else {
+				fmt.Fprintf(os.Stderr, "Error: %+v\n", err)
+			}
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string, w io.Writer) error {
+	var opts options
+	fs := flag.NewFlagSet("teleskopctl", flag.ContinueOnError)
+	fs.StringVar(&opts.Address, "addr", "", "teleskop agent address (default \""+defaultAddress+"\", or listen_address of -config)")
+	fs.StringVar(&opts.Config, "config", "", "path of teleskop agent config file to read listen_address and tls from")
+	fs.StringVar(&opts.Output, "o", outputTable, "output format (table, json)")
+	fs.DurationVar(&opts.Timeout, "timeout", 30*time.Second, "timeout of the whole command")
+	fs.StringVar(&opts.RequestID, "request-id", "", "request_id of mutating requests to make retries safe")
+	fs.StringVar(&opts.TokenFile, "token-file", "", "file of bearer token to authenticate")
+	fs.StringVar(&opts.TLS.CAFile, "tls-ca", "", "CA certificate to verify agent api")
+	fs.StringVar(&opts.TLS.CertFile, "tls-cert", "", "client certificate for agent api")
+	fs.StringVar(&opts.TLS.KeyFile, "tls-key", "", "client private key for agent api")
+	fs.StringVar(&opts.TLS.ServerName, "tls-server-name", "", "server name to verify agent api certificate")
+	fs.Usage = func() { usage(fs) }
+	if err := setFlagsFromEnv(fs); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return errUsage
+	}
+	if err := opts.complete(); err != nil {
+		return err
+	}
+	if opts.Output != outputTable && opts.Output != outputJSON {
+		return fmt.Errorf("unknown output format: %s", opts.Output)
+	}
+
+	cmd, rest := findCommand(fs.Args())
+	if cmd == nil {
+		usage(fs)
+		return errUsage
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	cmdFlags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	cmdFlags.Usage = func() {
+		fmt.Fprintf(cmdFlags.Output(), "Usage: teleskopctl %s [flags] %s\n\n%s\n\n", cmd.name, cmd.args, cmd.help)
+		cmdFlags.PrintDefaults()
+	}
+	call, err := cmd.run(cmdFlags, rest)
+	if err != nil {
+		switch err {
+		case flag.ErrHelp:
+			return nil
+		case errUsage:
+			return errUsage
+		}
+		fmt.Fprintf(cmdFlags.Output(), "Error: %+v\n", err)
+		cmdFlags.Usage()
+		return errUsage
+	}
+
+	conn, err := dial(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	resp, err := call(ctx, pb.NewAgentClient(conn))
+	if err != nil {
+		return err
+	}
+	return printResponse(w, opts.Output, resp)
+}
+
+// complete fills the options that are not given by flags from the agent config file.
+func (o *options) complete() error {
+	if o.Config != "" {
+		b, err := ioutil.ReadFile(o.Config)
+		if err != nil {
+			return fmt.Errorf("failed to read config file: %w", err)
+		}
+		var c agentConfig
+		if err := yaml.Unmarshal(b, &c); err != nil {
+			return fmt.Errorf("failed to parse config file: %w", err)
+		}
+		if o.Address == "" && c.ListenAddress != "" {
+			host, port, err := net.SplitHostPort(c.ListenAddress)
+			if err != nil {
+				return fmt.Errorf("failed to parse listen_address: %w", err)
+			}
+			if host == "" || host == "0.0.0.0" || host == "::" {
+				host = "127.0.0.1"
+			}
+			o.Address = net.JoinHostPort(host, port)
+		}
+		if !o.TLS.enabled() {
+			serverName := o.TLS.ServerName
+			o.TLS = c.TLS
+			if serverName != "" {
+				o.TLS.ServerName = serverName
+			}
+		}
+	}
+	if o.Address == "" {
+		o.Address = defaultAddress
+	}
+	if o.TLS.enabled() && o.TLS.CAFile == "" {
+		return fmt.Errorf("-tls-ca is required to verify agent api")
+	}
+	if (o.TLS.CertFile == "") != (o.TLS.KeyFile == "") {
+		return fmt.Errorf("-tls-cert and -tls-key must be set together")
+	}
+	return nil
+}
+
+func dial(ctx context.Context, opts options) (*grpc.ClientConn, error) {
+	dialOpts := []grpc.DialOption{
+		grpc.WithBlock(),
+		grpc.FailOnNonTempDialError(true),
+		grpc.WithUnaryInterceptor(requestIDInterceptor(opts.RequestID)),
+	}
+
+	if opts.TLS.enabled() {
+		config, err := clientTLSConfig(opts.TLS)
+		if err != nil {
+			return nil, err
+		}
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(config)))
+	} else {
+		dialOpts = append(dialOpts, grpc.WithInsecure())
+	}
+
+	if opts.TokenFile != "" {
+		b, err := ioutil.ReadFile(opts.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read token file: %w", err)
+		}
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(tokenCredentials(strings.TrimSpace(string(b)))))
+	}
+
+	conn, err := grpc.DialContext(ctx, opts.Address, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial to teleskop agent %s: %w", opts.Address, err)
+	}
+	return conn, nil
+}
+
+// clientTLSConfig returns a tls.Config that verifies the agent. The client certificate is optional
+// when the agent accepts tokens.
+func clientTLSConfig(opts tlsOptions) (*tls.Config, error) {
+	var certs []tls.Certificate
+	if opts.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load key pair: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+
+	ca, err := ioutil.ReadFile(opts.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("failed to parse CA file: %s", opts.CAFile)
+	}
+
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		RootCAs:      pool,
+		Certificates: certs,
+		ServerName:   opts.ServerName,
+	}, nil
+}
+
+// tokenCredentials sends a bearer token with each request.
+type tokenCredentials string
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return true
+}
+
+// requestIDInterceptor sets requestID to the request_id field of requests that have it.
+func requestIDInterceptor(requestID string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if m, ok := req.(proto.Message); ok && requestID != "" {
+			r := m.ProtoReflect()
+			if fd := r.Descriptor().Fields().ByName("request_id"); fd != nil && r.Get(fd).String() == "" {
+				r.Set(fd, protoreflect.ValueOfString(requestID))
+			}
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// setFlagsFromEnv sets flags from environment variables named like TELESKOPCTL_ADDR.
+func setFlagsFromEnv(fs *flag.FlagSet) error {
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+		name := envPrefix + strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+		if e := fs.Set(f.Name, v); e != nil {
+			err = fmt.Errorf("invalid value of %s: %w", name, e)
+		}
+	})
+	return err
+}
+
+func usage(fs *flag.FlagSet) {
+	out := fs.Output()
+	fmt.Fprintf(out, "Usage: teleskopctl [flags] <command> [flags] [args]\n\nCommands:\n")
+
+	names := make([]string, 0, len(commands))
+	byName := make(map[string]*command, len(commands))
+	for i := range commands {
+		names = append(names, commands[i].name)
+		byName[commands[i].name] = &commands[i]
+	}
+	sort.Strings(names)
+	tw := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	for _, name := range names {
+		cmd := byName[name]
+		fmt.Fprintf(tw, "  %s\t%s\n", strings.TrimSpace(cmd.name+" "+cmd.args), cmd.help)
+	}
+	tw.Flush()
+
+	fmt.Fprintf(out, "\nFlags:\n")
+	fs.PrintDefaults()
+}
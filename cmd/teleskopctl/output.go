@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+func printResponse(w io.Writer, format string, resp proto.Message) error {
+	switch format {
+	case outputJSON:
+		b, err := protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true}.Marshal(resp)
+		if err != nil {
+			return fmt.Errorf("failed to marshal response: %w", err)
+		}
+		_, err = fmt.Fprintf(w, "%s\n", b)
+		return err
+	default:
+		return printTable(w, resp.ProtoReflect())
+	}
+}
+
+// printTable prints the fields of m as a table. A response that wraps a single message
+// is printed as the message, and a response that wraps a list of messages is printed
+// one row per message. Responses without fields print nothing.
+func printTable(w io.Writer, m protoreflect.Message) error {
+	fields := m.Descriptor().Fields()
+	if fields.Len() == 0 {
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	var rows []protoreflect.Message
+	if fields.Len() == 1 && fields.Get(0).Kind() == protoreflect.MessageKind {
+		fd := fields.Get(0)
+		switch {
+		case fd.IsList():
+			list := m.Get(fd).List()
+			for i := 0; i < list.Len(); i++ {
+				rows = append(rows, list.Get(i).Message())
+			}
+		case !fd.IsMap():
+			rows = append(rows, m.Get(fd).Message())
+		}
+		fields = fd.Message().Fields()
+	} else {
+		rows = append(rows, m)
+	}
+
+	header := make([]string, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		header[i] = strings.ToUpper(string(fields.Get(i).Name()))
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		values := make([]string, fields.Len())
+		for i := 0; i < fields.Len(); i++ {
+			values[i] = formatField(row, fields.Get(i))
+		}
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	return tw.Flush()
+}
+
+func formatField(m protoreflect.Message, fd protoreflect.FieldDescriptor) string {
+	if !m.Has(fd) {
+		return "-"
+	}
+	v := m.Get(fd)
+
+	switch {
+	case fd.IsList():
+		list := v.List()
+		values := make([]string, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			values[i] = formatValue(fd, list.Get(i))
+		}
+		return strings.Join(values, ",")
+	case fd.IsMap():
+		var values []string
+		v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			values = append(values, fmt.Sprintf("%s=%s", k.String(), formatValue(fd.MapValue(), v)))
+			return true
+		})
+		return strings.Join(values, ",")
+	}
+	return formatValue(fd, v)
+}
+
+func formatValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return fmt.Sprintf("%d", v.Enum())
+	case protoreflect.MessageKind:
+		b, err := protojson.Marshal(v.Message().Interface())
+		if err != nil {
+			return "?"
+		}
+		return string(b)
+	case protoreflect.Int64Kind:
+		// timestamps are unix time in seconds
+		if strings.HasSuffix(string(fd.Name()), "_time") {
+			return time.Unix(v.Int(), 0).Format(time.RFC3339)
+		}
+	}
+	return v.String()
+}
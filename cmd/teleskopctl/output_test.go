@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+func TestPrintTable(t *testing.T) {
+	tests := []struct {
+		name string
+		resp proto.Message
+		want []string
+	}{
+		{
+			name: "list of messages",
+			resp: &pb.ListVirtualMachineStateResponse{States: []*pb.VirtualMachineState{
+				{Uuid: "0001", Name: "vm1", State: pb.VirtualMachineState_RUNNING},
+				{Uuid: "0002", Name: "vm2", State: pb.VirtualMachineState_SHUTOFF},
+			}},
+			want: []string{
+				"UUID  NAME  STATE",
+				"0001  vm1   RUNNING",
+				"0002  vm2   SHUTOFF",
+			},
+		},
+		{
+			name: "single message",
+			resp: &pb.GetVirtualMachineStateResponse{State: &pb.VirtualMachineState{Uuid: "0001", Name: "vm1", State: pb.VirtualMachineState_PAUSED}},
+			want: []string{
+				"UUID  NAME  STATE",
+				"0001  vm1   PAUSED",
+			},
+		},
+		{
+			name: "scalar fields",
+			resp: &pb.ConnectBlockDeviceResponse{DeviceName: "/dev/dm-0"},
+			want: []string{
+				"DEVICE_NAME  OPERATION_ID",
+				"/dev/dm-0    -",
+			},
+		},
+		{
+			name: "empty response",
+			resp: &pb.AddBridgeResponse{},
+		},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := printResponse(&buf, outputTable, test.resp); err != nil {
+			t.Fatalf("%s: should not be error but: %+v", test.name, err)
+		}
+		var got []string
+		for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+			if line = strings.TrimRight(line, " "); line != "" {
+				got = append(got, line)
+			}
+		}
+		if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+			t.Errorf("%s: want\n%s\nbut got\n%s", test.name, strings.Join(test.want, "\n"), buf.String())
+		}
+	}
+}
+
+func TestPrintJSON(t *testing.T) {
+	var buf bytes.Buffer
+	resp := &pb.ListVirtualMachineStateResponse{States: []*pb.VirtualMachineState{{Uuid: "0001", Name: "vm1", State: pb.VirtualMachineState_RUNNING}}}
+	if err := printResponse(&buf, outputJSON, resp); err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+
+	// protojson randomizes whitespaces, so compare the decoded output
+	var got struct {
+		States []map[string]string `json:"states"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	if len(got.States) != 1 {
+		t.Fatalf("want 1 state, but got %s", buf.String())
+	}
+	for key, want := range map[string]string{"uuid": "0001", "state": "RUNNING"} {
+		if got.States[0][key] != want {
+			t.Errorf("want %s, but got %s", want, got.States[0][key])
+		}
+	}
+}
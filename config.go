@@ -0,0 +1,460 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	envPrefix = "TELESKOP_"
+)
+
+type config struct {
+	ListenAddress        string        `yaml:"listen_address"`
+	MetricsListenAddress string        `yaml:"metrics_listen_address"`
+	SatelitEndpoint      string        `yaml:"satelit_endpoint"`
+	Interface            string        `yaml:"interface"`
+	LibvirtAddress       string        `yaml:"libvirt_address"`
+	StartupTimeout       time.Duration `yaml:"startup_timeout"`
+	ShutdownTimeout      time.Duration `yaml:"shutdown_timeout"`
+	LogLevel             string        `yaml:"log_level"`
+	MaxHeavyOperations   int           `yaml:"max_heavy_operations"`
+	IdempotencyWindow    time.Duration `yaml:"idempotency_window"`
+
+	PayloadLog    payloadLogConfig    `yaml:"payload_log"`
+	DHCP          dhcpConfig          `yaml:"dhcp"`
+	Metadata      metadataConfig      `yaml:"metadata"`
+	Reconcile     reconcileConfig     `yaml:"reconcile"`
+	HealthCheck   healthCheckConfig   `yaml:"health_check"`
+	Tracing       tracingConfig       `yaml:"tracing"`
+	Operations    operationsConfig    `yaml:"operations"`
+	Preflight     preflightConfig     `yaml:"preflight"`
+	SecurityGroup securityGroupConfig `yaml:"security_group"`
+
+	TLS           tlsConfig           `yaml:"tls"`
+	SatelitTLS    tlsConfig           `yaml:"satelit_tls"`
+	Authorization authorizationConfig `yaml:"authorization"`
+}
+
+type dhcpConfig struct {
+	ListenAddress   string `yaml:"listen_address"`
+	InterfacePrefix string `yaml:"interface_prefix"`
+}
+
+type metadataConfig struct {
+	ListenAddress string `yaml:"listen_address"`
+	DatasourceURL string `yaml:"datasource_url"`
+}
+
+type reconcileConfig struct {
+	Interval time.Duration `yaml:"interval"`
+	DryRun   bool          `yaml:"dry_run"`
+	// DeleteStale deletes the bridges and teleskop interfaces that satelit does not list
+	DeleteStale bool `yaml:"delete_stale"`
+}
+
+type healthCheckConfig struct {
+	Interval time.Duration `yaml:"interval"`
+}
+
+type preflightConfig struct {
+	// Only runs the checks and exits, given by the -preflight flag
+	Only bool `yaml:"-"`
+	// Fix applies the safe fixes of failed checks
+	Fix bool `yaml:"fix"`
+}
+
+func defaultConfig() *config {
+	return &config{
+		ListenAddress:        ":5000",
+		MetricsListenAddress: ":5001",
+		SatelitEndpoint:      "127.0.0.1:9263",
+		Interface:            "bond0.1000",
+		LibvirtAddress:       "127.0.0.1:16509",
+		StartupTimeout:       3 * time.Second,
+		ShutdownTimeout:      defaultShutdownTimeout,
+		LogLevel:             "info",
+		MaxHeavyOperations:   defaultMaxHeavyOperations,
+		IdempotencyWindow:    defaultIdempotencyWindow,
+		PayloadLog: payloadLogConfig{
+			Default: payloadLogNone,
+		},
+		DHCP: dhcpConfig{
+			ListenAddress:   "0.0.0.0:67",
+			InterfacePrefix: "dhcp",
+		},
+		Metadata: metadataConfig{
+			ListenAddress: "0.0.0.0:80",
+			DatasourceURL: "http://169.254.169.254/",
+		},
+		Reconcile: reconcileConfig{
+			Interval:    defaultReconcileInterval,
+			DryRun:      false,
+			DeleteStale: false,
+		},
+		HealthCheck: healthCheckConfig{
+			Interval: defaultHealthCheckInterval,
+		},
+		Tracing: tracingConfig{
+			SampleRatio: 1,
+		},
+		Operations: operationsConfig{
+			JournalDir: defaultOperationJournalDir,
+			Retention:  defaultOperationRetention,
+		},
+		SecurityGroup: securityGroupConfig{
+			Backend:        firewallBackendIPTables,
+			StateDir:       defaultSecurityGroupStateDir,
+			VerifyInterval: defaultSecurityGroupVerifyInterval,
+		},
+	}
+}
+
+func defineFlags(fs *flag.FlagSet, c *config, path *string) {
+	fs.StringVar(path, "config", "", "path of teleskop config file")
+	fs.StringVar(&c.SatelitEndpoint, "satelit", c.SatelitEndpoint, "satelit datastore api endpoint")
+	fs.StringVar(&c.Interface, "intf", c.Interface, "teleskop listen interface")
+	fs.StringVar(&c.ListenAddress, "listen", c.ListenAddress, "agent api listen address")
+	fs.StringVar(&c.MetricsListenAddress, "metrics-listen", c.MetricsListenAddress, "metrics listen address")
+	fs.StringVar(&c.LibvirtAddress, "libvirt", c.LibvirtAddress, "libvirtd address")
+	fs.DurationVar(&c.StartupTimeout, "startup-timeout", c.StartupTimeout, "timeout of startup")
+	fs.DurationVar(&c.ShutdownTimeout, "shutdown-timeout", c.ShutdownTimeout, "timeout of graceful shutdown")
+	fs.StringVar(&c.LogLevel, "log-level", c.LogLevel, "log level (debug, info, warn, error)")
+	fs.IntVar(&c.MaxHeavyOperations, "max-heavy-operations", c.MaxHeavyOperations, "max number of concurrent heavy operations such as iSCSI logins")
+	fs.DurationVar(&c.IdempotencyWindow, "idempotency-window", c.IdempotencyWindow, "period to replay the response to retried requests with the same request_id (0 disables)")
+	fs.StringVar(&c.DHCP.ListenAddress, "dhcp-listen", c.DHCP.ListenAddress, "dhcp server listen address")
+	fs.StringVar(&c.DHCP.InterfacePrefix, "dhcp-interface-prefix", c.DHCP.InterfacePrefix, "prefix of interfaces served by dhcp server")
+	fs.StringVar(&c.Metadata.ListenAddress, "metadata-listen", c.Metadata.ListenAddress, "metadata server listen address")
+	fs.StringVar(&c.Metadata.DatasourceURL, "datasource-url", c.Metadata.DatasourceURL, "cloud-init datasource url passed by SMBIOS")
+	fs.DurationVar(&c.Reconcile.Interval, "reconcile-interval", c.Reconcile.Interval, "interval of bridge reconciliation")
+	fs.BoolVar(&c.Reconcile.DryRun, "reconcile-dry-run", c.Reconcile.DryRun, "only report bridge drift without changing anything")
+	fs.BoolVar(&c.Reconcile.DeleteStale, "reconcile-delete-stale", c.Reconcile.DeleteStale, "delete bridges that satelit does not list, unless domains are attached to them")
+	fs.DurationVar(&c.HealthCheck.Interval, "health-check-interval", c.HealthCheck.Interval, "interval of health checks")
+	fs.StringVar(&c.Tracing.OTLPEndpoint, "otlp-endpoint", c.Tracing.OTLPEndpoint, "OTLP/gRPC collector address to export traces")
+	fs.Float64Var(&c.Tracing.SampleRatio, "trace-sample-ratio", c.Tracing.SampleRatio, "ratio of sampled traces")
+	fs.StringVar(&c.Operations.JournalDir, "operation-journal-dir", c.Operations.JournalDir, "directory to record long-running operations")
+	fs.DurationVar(&c.Operations.Retention, "operation-retention", c.Operations.Retention, "retention period of finished operations")
+	fs.StringVar(&c.SecurityGroup.Backend, "security-group-backend", c.SecurityGroup.Backend, "firewall backend of security groups (iptables, nftables)")
+	fs.StringVar(&c.SecurityGroup.StateDir, "security-group-state-dir", c.SecurityGroup.StateDir, "directory to record the desired security groups")
+	fs.DurationVar(&c.SecurityGroup.VerifyInterval, "security-group-verify-interval", c.SecurityGroup.VerifyInterval, "interval to verify and repair the firewall rules of security groups")
+	fs.BoolVar(&c.Preflight.Only, "preflight", c.Preflight.Only, "run preflight checks of the host and exit")
+	fs.BoolVar(&c.Preflight.Fix, "preflight-fix", c.Preflight.Fix, "apply safe fixes of failed preflight checks, e.g. loading kernel modules")
+	fs.StringVar(&c.TLS.CAFile, "tls-ca", c.TLS.CAFile, "CA certificate to verify agent api clients")
+	fs.StringVar(&c.TLS.CertFile, "tls-cert", c.TLS.CertFile, "certificate of agent api")
+	fs.StringVar(&c.TLS.KeyFile, "tls-key", c.TLS.KeyFile, "private key of agent api")
+	fs.StringVar(&c.SatelitTLS.CAFile, "satelit-tls-ca", c.SatelitTLS.CAFile, "CA certificate to verify satelit datastore api")
+	fs.StringVar(&c.SatelitTLS.CertFile, "satelit-tls-cert", c.SatelitTLS.CertFile, "client certificate for satelit datastore api")
+	fs.StringVar(&c.SatelitTLS.KeyFile, "satelit-tls-key", c.SatelitTLS.KeyFile, "client private key for satelit datastore api")
+	fs.StringVar(&c.SatelitTLS.ServerName, "satelit-tls-server-name", c.SatelitTLS.ServerName, "server name to verify satelit datastore api certificate")
+}
+
+// loadConfig builds the configuration from defaults, the config file,
+// environment variables and command line flags, in increasing order of precedence.
+func loadConfig(name string, args []string) (*config, error) {
+	var path string
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	defineFlags(fs, defaultConfig(), &path)
+	if err := setFlagsFromEnv(fs); err != nil {
+		return nil, err
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	c := defaultConfig()
+	if path != "" {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := yaml.UnmarshalStrict(b, c); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+	}
+
+	// flags are defined again with the values from the config file as default,
+	// so that only explicitly given flags and environment variables override them.
+	fs = flag.NewFlagSet(name, flag.ContinueOnError)
+	defineFlags(fs, c, &path)
+	if err := setFlagsFromEnv(fs); err != nil {
+		return nil, err
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return c, nil
+}
+
+// setFlagsFromEnv sets flags from environment variables named like TELESKOP_DHCP_LISTEN.
+func setFlagsFromEnv(fs *flag.FlagSet) error {
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+		name := envPrefix + strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+		if e := fs.Set(f.Name, v); e != nil {
+			err = fmt.Errorf("invalid value of %s: %w", name, e)
+		}
+	})
+	return err
+}
+
+func (c *config) validate() error {
+	for name, addr := range map[string]string{
+		"listen_address":          c.ListenAddress,
+		"metrics_listen_address":  c.MetricsListenAddress,
+		"satelit_endpoint":        c.SatelitEndpoint,
+		"libvirt_address":         c.LibvirtAddress,
+		"dhcp.listen_address":     c.DHCP.ListenAddress,
+		"metadata.listen_address": c.Metadata.ListenAddress,
+	} {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return fmt.Errorf("invalid %s %q: %w", name, addr, err)
+		}
+	}
+	if c.Interface == "" {
+		return fmt.Errorf("interface must be set")
+	}
+	if c.DHCP.InterfacePrefix == "" {
+		return fmt.Errorf("dhcp.interface_prefix must be set")
+	}
+	u, err := url.Parse(c.Metadata.DatasourceURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid metadata.datasource_url %q", c.Metadata.DatasourceURL)
+	}
+	for name, d := range map[string]time.Duration{
+		"startup_timeout":       c.StartupTimeout,
+		"shutdown_timeout":      c.ShutdownTimeout,
+		"reconcile.interval":    c.Reconcile.Interval,
+		"health_check.interval": c.HealthCheck.Interval,
+	} {
+		if d <= 0 {
+			return fmt.Errorf("%s must be positive: %s", name, d)
+		}
+	}
+	if _, err := c.zapLevel(); err != nil {
+		return err
+	}
+	if c.MaxHeavyOperations <= 0 {
+		return fmt.Errorf("max_heavy_operations must be positive: %d", c.MaxHeavyOperations)
+	}
+	if c.IdempotencyWindow < 0 {
+		return fmt.Errorf("idempotency_window must not be negative: %s", c.IdempotencyWindow)
+	}
+	if err := c.PayloadLog.validate(); err != nil {
+		return fmt.Errorf("invalid payload_log: %w", err)
+	}
+	if c.Tracing.OTLPEndpoint != "" {
+		if _, _, err := net.SplitHostPort(c.Tracing.OTLPEndpoint); err != nil {
+			return fmt.Errorf("invalid tracing.otlp_endpoint %q: %w", c.Tracing.OTLPEndpoint, err)
+		}
+	}
+	if err := c.Tracing.validate(); err != nil {
+		return fmt.Errorf("invalid tracing: %w", err)
+	}
+	if err := c.Operations.validate(); err != nil {
+		return fmt.Errorf("invalid operations: %w", err)
+	}
+	if err := c.SecurityGroup.validate(); err != nil {
+		return fmt.Errorf("invalid security_group: %w", err)
+	}
+	if err := c.TLS.validate(); err != nil {
+		return fmt.Errorf("invalid tls: %w", err)
+	}
+	if err := c.SatelitTLS.validate(); err != nil {
+		return fmt.Errorf("invalid satelit_tls: %w", err)
+	}
+	if err := c.Authorization.validate(); err != nil {
+		return fmt.Errorf("invalid authorization: %w", err)
+	}
+	if c.Authorization.Enabled() && !c.TLS.Enabled() {
+		return fmt.Errorf("authorization requires tls")
+	}
+	if c.TLS.Enabled() && !c.Authorization.Enabled() {
+		// otherwise every certificate signed by the CA would be allowed to call every method
+		return fmt.Errorf("tls requires authorization identities")
+	}
+
+	return nil
+}
+
+func (c *config) zapLevel() (zapcore.Level, error) {
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+		return level, fmt.Errorf("invalid log_level %q: %w", c.LogLevel, err)
+	}
+	return level, nil
+}
+
+// listenPort returns the port of the agent api.
+func (c *config) listenPort() (string, error) {
+	_, port, err := net.SplitHostPort(c.ListenAddress)
+	return port, err
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (c *config) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("listen_address", c.ListenAddress)
+	enc.AddString("metrics_listen_address", c.MetricsListenAddress)
+	enc.AddString("satelit_endpoint", c.SatelitEndpoint)
+	enc.AddString("interface", c.Interface)
+	enc.AddString("libvirt_address", c.LibvirtAddress)
+	enc.AddDuration("startup_timeout", c.StartupTimeout)
+	enc.AddDuration("shutdown_timeout", c.ShutdownTimeout)
+	enc.AddString("log_level", c.LogLevel)
+	enc.AddInt("max_heavy_operations", c.MaxHeavyOperations)
+	enc.AddDuration("idempotency_window", c.IdempotencyWindow)
+	enc.AddObject("payload_log", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddString("default", c.PayloadLog.Default)
+		enc.AddInt("methods", len(c.PayloadLog.Methods))
+		return nil
+	}))
+	enc.AddObject("dhcp", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddString("listen_address", c.DHCP.ListenAddress)
+		enc.AddString("interface_prefix", c.DHCP.InterfacePrefix)
+		return nil
+	}))
+	enc.AddObject("metadata", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddString("listen_address", c.Metadata.ListenAddress)
+		enc.AddString("datasource_url", c.Metadata.DatasourceURL)
+		return nil
+	}))
+	enc.AddObject("reconcile", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddDuration("interval", c.Reconcile.Interval)
+		enc.AddBool("dry_run", c.Reconcile.DryRun)
+		enc.AddBool("delete_stale", c.Reconcile.DeleteStale)
+		return nil
+	}))
+	enc.AddObject("health_check", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddDuration("interval", c.HealthCheck.Interval)
+		return nil
+	}))
+	enc.AddObject("tracing", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddString("otlp_endpoint", c.Tracing.OTLPEndpoint)
+		enc.AddFloat64("sample_ratio", c.Tracing.SampleRatio)
+		return nil
+	}))
+	enc.AddObject("operations", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddString("journal_dir", c.Operations.JournalDir)
+		enc.AddDuration("retention", c.Operations.Retention)
+		return nil
+	}))
+	enc.AddObject("preflight", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddBool("fix", c.Preflight.Fix)
+		return nil
+	}))
+	enc.AddObject("security_group", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddString("backend", c.SecurityGroup.Backend)
+		enc.AddString("state_dir", c.SecurityGroup.StateDir)
+		enc.AddDuration("verify_interval", c.SecurityGroup.VerifyInterval)
+		return nil
+	}))
+	enc.AddObject("tls", c.TLS)
+	enc.AddObject("satelit_tls", c.SatelitTLS)
+	enc.AddObject("authorization", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddInt("identities", len(c.Authorization.Identities))
+		enc.AddInt("tokens", len(c.Authorization.Tokens))
+		return nil
+	}))
+	return nil
+}
+
+// configReloader reloads the configuration on SIGHUP and applies
+// the settings that are safe to change at runtime.
+type configReloader struct {
+	name string
+	args []string
+	// current is the config the process started with and the reloadable settings applied since,
+	// so that settings requiring restart are compared with the ones in use
+	current       *config
+	level         zap.AtomicLevel
+	payloadLogger *payloadLogger
+	reconciler    *bridgeReconciler
+	verifier      *securityGroupVerifier
+	logger        *zap.Logger
+}
+
+// Run waits for SIGHUP until ctx is done.
+func (r *configReloader) Run(ctx context.Context) error {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	defer signal.Stop(sigCh)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sigCh:
+			if err := r.reload(); err != nil {
+				r.logger.Warn("failed to reload config", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (r *configReloader) reload() error {
+	next, err := loadConfig(r.name, r.args)
+	if err != nil {
+		return err
+	}
+
+	level, err := next.zapLevel()
+	if err != nil {
+		return err
+	}
+	r.level.SetLevel(level)
+	r.payloadLogger.SetConfig(next.PayloadLog)
+	r.reconciler.SetOptions(next.Reconcile)
+	r.verifier.SetInterval(next.SecurityGroup.VerifyInterval)
+
+	if !reflect.DeepEqual(r.current.withoutReloadable(), next.withoutReloadable()) {
+		r.logger.Warn("some changed settings require restart to take effect")
+	}
+	r.current = r.current.withReloadable(next)
+	r.logger.Info("reloaded config", zap.Object("config", r.current))
+
+	return nil
+}
+
+// withoutReloadable returns a copy of c without the settings that can be changed at runtime.
+func (c *config) withoutReloadable() config {
+	tmp := *c
+	tmp.LogLevel = ""
+	tmp.PayloadLog = payloadLogConfig{}
+	tmp.Reconcile = reconcileConfig{}
+	tmp.SecurityGroup.VerifyInterval = 0
+	return tmp
+}
+
+// withReloadable returns a copy of c with the settings of next that can be changed at runtime.
+func (c *config) withReloadable(next *config) *config {
+	tmp := *c
+	tmp.LogLevel = next.LogLevel
+	tmp.PayloadLog = next.PayloadLog
+	tmp.Reconcile = next.Reconcile
+	tmp.SecurityGroup.VerifyInterval = next.SecurityGroup.VerifyInterval
+	return &tmp
+}
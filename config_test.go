@@ -0,0 +1,170 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "teleskop-config")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %+v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "teleskop.yaml")
+	content := `
+satelit_endpoint: 192.0.2.100:9263
+interface: eth0
+dhcp:
+  interface_prefix: dh
+reconcile:
+  interval: 5m
+`
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write config file: %+v", err)
+	}
+
+	want := defaultConfig()
+	want.SatelitEndpoint = "192.0.2.100:9263"
+	want.Interface = "eth1"
+	want.DHCP.InterfacePrefix = "dh"
+	want.Reconcile.Interval = 5 * time.Minute
+	want.LogLevel = "debug"
+
+	os.Setenv("TELESKOP_INTF", "eth2")
+	os.Setenv("TELESKOP_LOG_LEVEL", "debug")
+	defer os.Unsetenv("TELESKOP_INTF")
+	defer os.Unsetenv("TELESKOP_LOG_LEVEL")
+
+	got, err := loadConfig("teleskop", []string{"-config", path, "-intf", "eth1"})
+	if err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	if diff := deep.Equal(want, got); len(diff) != 0 {
+		t.Fatalf("want %+v, but %+v, diff %q:", want, got, diff)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		modify func(c *config)
+		err    bool
+	}{
+		{
+			modify: func(c *config) {},
+			err:    false,
+		},
+		{
+			modify: func(c *config) { c.ListenAddress = "5000" },
+			err:    true,
+		},
+		{
+			modify: func(c *config) { c.Interface = "" },
+			err:    true,
+		},
+		{
+			modify: func(c *config) { c.Metadata.DatasourceURL = "169.254.169.254" },
+			err:    true,
+		},
+		{
+			modify: func(c *config) { c.Reconcile.Interval = 0 },
+			err:    true,
+		},
+		{
+			modify: func(c *config) { c.LogLevel = "verbose" },
+			err:    true,
+		},
+		{
+			modify: func(c *config) {
+				c.TLS = tlsConfig{CAFile: "ca.pem", CertFile: "agent.pem", KeyFile: "agent-key.pem"}
+				c.Authorization.Identities = map[string]string{"satelit": roleAdmin}
+			},
+			err: false,
+		},
+		{
+			modify: func(c *config) {
+				c.TLS = tlsConfig{CAFile: "ca.pem", CertFile: "agent.pem", KeyFile: "agent-key.pem"}
+			},
+			err: true,
+		},
+		{
+			modify: func(c *config) { c.SecurityGroup.Backend = firewallBackendNFTables },
+			err:    false,
+		},
+		{
+			modify: func(c *config) { c.SecurityGroup.Backend = "ebtables" },
+			err:    true,
+		},
+	}
+	for i, test := range tests {
+		c := defaultConfig()
+		test.modify(c)
+		err := c.validate()
+		if !test.err && err != nil {
+			t.Fatalf("#%d: should not be error but: %+v", i, err)
+		}
+		if test.err && err == nil {
+			t.Fatalf("#%d: should be error but not", i)
+		}
+	}
+}
+
+func TestConfigReloaderReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "teleskop-config")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %+v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "teleskop.yaml")
+	write := func(content string) {
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write config file: %+v", err)
+		}
+	}
+	write("satelit_endpoint: 192.0.2.100:9263\n")
+
+	args := []string{"-config", path}
+	cfg, err := loadConfig("teleskop", args)
+	if err != nil {
+		t.Fatalf("failed to load config: %+v", err)
+	}
+	core, logs := observer.New(zapcore.WarnLevel)
+	r := &configReloader{
+		name:          "teleskop",
+		args:          args,
+		current:       cfg,
+		level:         zap.NewAtomicLevel(),
+		payloadLogger: newPayloadLogger(cfg.PayloadLog),
+		reconciler:    newBridgeReconciler(nil, "bond0", cfg.Reconcile, zap.NewNop()),
+		verifier:      newSecurityGroupVerifier(nil, cfg.SecurityGroup.VerifyInterval, zap.NewNop()),
+		logger:        zap.New(core),
+	}
+
+	write("satelit_endpoint: 192.0.2.200:9263\nlog_level: debug\n")
+	// the second reload still warns, because the changed endpoint is not applied yet
+	for i := 0; i < 2; i++ {
+		if err := r.reload(); err != nil {
+			t.Fatalf("#%d: should not be error but: %+v", i, err)
+		}
+		if got := logs.FilterMessage("some changed settings require restart to take effect").Len(); got != i+1 {
+			t.Errorf("#%d: want %d restart warnings, but got %d", i, i+1, got)
+		}
+	}
+
+	if r.current.SatelitEndpoint != "192.0.2.100:9263" {
+		t.Errorf("want the endpoint in use, but got %s", r.current.SatelitEndpoint)
+	}
+	if r.current.LogLevel != "debug" || r.level.Level() != zapcore.DebugLevel {
+		t.Errorf("want reloaded log level, but got %s (%s)", r.current.LogLevel, r.level.Level())
+	}
+}
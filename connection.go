@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	libvirt "github.com/digitalocean/go-libvirt"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultReconnectMinBackoff = 1 * time.Second
+	defaultReconnectMaxBackoff = 30 * time.Second
+
+	connectionTargetLibvirt = "libvirt"
+	connectionTargetSatelit = "satelit"
+)
+
+type connectFunc func(ctx context.Context, client *libvirt.Libvirt) error
+
+// dialFunc opens the transport to libvirtd. It is replaced in tests.
+type dialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// libvirtConnection holds a connection to libvirtd and reconnects it with backoff when it is lost.
+type libvirtConnection struct {
+	addr       string
+	dialer     dialFunc
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	logger     *zap.Logger
+
+	mu        sync.RWMutex
+	client    *libvirt.Libvirt
+	connected bool
+	closed    bool
+	callbacks []connectFunc
+}
+
+func newLibvirtConnection(addr string, logger *zap.Logger) *libvirtConnection {
+	return &libvirtConnection{
+		addr:       addr,
+		dialer:     (&net.Dialer{}).DialContext,
+		minBackoff: defaultReconnectMinBackoff,
+		maxBackoff: defaultReconnectMaxBackoff,
+		logger:     logger.Named("libvirt"),
+	}
+}
+
+// Connect establishes the first connection to libvirtd.
+func (c *libvirtConnection) Connect(ctx context.Context) error {
+	client, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.setClient(ctx, client)
+	return nil
+}
+
+// Client returns the current libvirt client.
+// It returns an Unavailable error while libvirtd is disconnected.
+func (c *libvirtConnection) Client() (*libvirt.Libvirt, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.connected {
+		return nil, status.Errorf(codes.Unavailable, "libvirtd is disconnected")
+	}
+	return c.client, nil
+}
+
+// Connected reports whether the connection to libvirtd is alive.
+func (c *libvirtConnection) Connected() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.connected
+}
+
+// OnConnect registers fn to be called every time the connection is re-established.
+func (c *libvirtConnection) OnConnect(fn connectFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.callbacks = append(c.callbacks, fn)
+}
+
+// Run watches the connection and reconnects it until ctx is done.
+func (c *libvirtConnection) Run(ctx context.Context) error {
+	for {
+		c.mu.RLock()
+		client := c.client
+		c.mu.RUnlock()
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-client.Disconnected():
+		}
+
+		c.mu.Lock()
+		c.connected = false
+		closed := c.closed
+		c.mu.Unlock()
+		if closed {
+			return nil
+		}
+		connectionUp.WithLabelValues(connectionTargetLibvirt).Set(0)
+		c.logger.Warn("lost connection to libvirtd", zap.String("address", c.addr))
+
+		if err := c.reconnect(ctx); err != nil {
+			return nil
+		}
+	}
+}
+
+// Close closes the connection to libvirtd.
+func (c *libvirtConnection) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	if !c.connected {
+		return nil
+	}
+	c.connected = false
+	connectionUp.WithLabelValues(connectionTargetLibvirt).Set(0)
+	return c.client.Disconnect()
+}
+
+func (c *libvirtConnection) reconnect(ctx context.Context) error {
+	backoff := c.minBackoff
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		client, err := c.dial(ctx)
+		if err == nil {
+			c.logger.Info("reconnected to libvirtd", zap.String("address", c.addr))
+			c.setClient(ctx, client)
+			return nil
+		}
+		c.logger.Warn("failed to reconnect to libvirtd", zap.Duration("backoff", backoff), zap.Error(err))
+
+		backoff *= 2
+		if backoff > c.maxBackoff {
+			backoff = c.maxBackoff
+		}
+	}
+}
+
+func (c *libvirtConnection) dial(ctx context.Context) (*libvirt.Libvirt, error) {
+	conn, err := c.dialer(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial to libvirtd: %w", err)
+	}
+
+	client := libvirt.New(conn)
+	if err := client.Connect(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect to libvirtd: %w", err)
+	}
+
+	return client, nil
+}
+
+func (c *libvirtConnection) setClient(ctx context.Context, client *libvirt.Libvirt) {
+	c.mu.Lock()
+	c.client = client
+	c.connected = true
+	callbacks := make([]connectFunc, len(c.callbacks))
+	copy(callbacks, c.callbacks)
+	c.mu.Unlock()
+	connectionUp.WithLabelValues(connectionTargetLibvirt).Set(1)
+
+	for _, fn := range callbacks {
+		if err := fn(ctx, client); err != nil {
+			c.logger.Warn("failed to run connect callback", zap.Error(err))
+		}
+	}
+}
+
+// watchSatelitConnection records state changes of the connection to satelit until ctx is done.
+// grpc reconnects the connection by itself, so this only observes it.
+func watchSatelitConnection(ctx context.Context, conn *grpc.ClientConn, logger *zap.Logger) error {
+	logger = logger.Named("satelit")
+
+	state := conn.GetState()
+	for {
+		if state == connectivity.Ready || state == connectivity.Idle {
+			connectionUp.WithLabelValues(connectionTargetSatelit).Set(1)
+		} else {
+			connectionUp.WithLabelValues(connectionTargetSatelit).Set(0)
+		}
+
+		if !conn.WaitForStateChange(ctx, state) {
+			return nil
+		}
+		next := conn.GetState()
+		logger.Info("satelit connection state changed", zap.String("from", state.String()), zap.String("to", next.String()))
+		state = next
+	}
+}
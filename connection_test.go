@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	libvirt "github.com/digitalocean/go-libvirt"
+	"github.com/digitalocean/go-libvirt/libvirttest"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeLibvirtd dials mock libvirtd servers, and refuses to dial while it is down.
+type fakeLibvirtd struct {
+	mu    sync.Mutex
+	down  bool
+	conns []net.Conn
+}
+
+func (d *fakeLibvirtd) dial(ctx context.Context, network, address string) (net.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.down {
+		return nil, errors.New("connection refused")
+	}
+	conn, err := libvirttest.New().Dial()
+	if err != nil {
+		return nil, err
+	}
+	d.conns = append(d.conns, conn)
+	return conn, nil
+}
+
+// stop closes the current connection, and refuses new ones until start is called.
+func (d *fakeLibvirtd) stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.down = true
+	d.conns[len(d.conns)-1].Close()
+}
+
+func (d *fakeLibvirtd) start() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.down = false
+}
+
+func TestLibvirtConnectionReconnect(t *testing.T) {
+	d := &fakeLibvirtd{}
+	c := newLibvirtConnection("127.0.0.1:16509", zap.NewNop())
+	c.dialer = d.dial
+	c.minBackoff = 10 * time.Millisecond
+	c.maxBackoff = 10 * time.Millisecond
+
+	if _, err := c.Client(); status.Code(err) != codes.Unavailable {
+		t.Fatalf("want %s before connecting, but got %+v", codes.Unavailable, err)
+	}
+
+	connected := make(chan struct{}, 2)
+	c.OnConnect(func(ctx context.Context, client *libvirt.Libvirt) error {
+		connected <- struct{}{}
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("failed to connect: %+v", err)
+	}
+	<-connected
+	if _, err := c.Client(); err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+
+	done := make(chan error)
+	go func() { done <- c.Run(ctx) }()
+
+	d.stop()
+	waitFor(t, "disconnected", func() bool { return !c.Connected() })
+	if _, err := c.Client(); status.Code(err) != codes.Unavailable {
+		t.Errorf("want %s while disconnected, but got %+v", codes.Unavailable, err)
+	}
+
+	d.start()
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("connect callback should be called on reconnect")
+	}
+	if _, err := c.Client(); err != nil {
+		t.Errorf("should not be error after reconnect but: %+v", err)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("should not be error but: %+v", err)
+	}
+}
+
+func TestLibvirtConnectionConnectFailure(t *testing.T) {
+	d := &fakeLibvirtd{down: true}
+	c := newLibvirtConnection("127.0.0.1:16509", zap.NewNop())
+	c.dialer = d.dial
+
+	if err := c.Connect(context.Background()); err == nil {
+		t.Fatalf("should be error but not")
+	}
+	if _, err := c.Client(); status.Code(err) != codes.Unavailable {
+		t.Errorf("want %s, but got %+v", codes.Unavailable, err)
+	}
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -5,67 +5,163 @@ import (
 	"encoding/binary"
 	"fmt"
 	"net"
-	"os"
 	"strings"
 	"sync"
 
 	pb "github.com/lovi-cloud/satelit/api/satelit_datastore"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
 	"go.universe.tf/netboot/dhcp4"
 )
 
+var tracer = otel.Tracer("github.com/lovi-cloud/teleskop/dhcp")
+
 // Server is DHCP server
 type Server struct {
-	mutex  *sync.Mutex
-	client pb.SatelitDatastoreClient
+	mutex           *sync.Mutex
+	client          pb.SatelitDatastoreClient
+	interfacePrefix string
+	logger          *zap.Logger
+	listening       bool
+
+	conn   *dhcp4.Conn
+	closed bool
+	done   chan struct{}
 }
 
-// NewServer is return new DHCP server
-func NewServer(client pb.SatelitDatastoreClient) *Server {
+// NewServer is return new DHCP server that serves interfaces named with interfacePrefix
+func NewServer(client pb.SatelitDatastoreClient, interfacePrefix string, logger *zap.Logger) *Server {
 	return &Server{
-		mutex:  &sync.Mutex{},
-		client: client,
+		mutex:           &sync.Mutex{},
+		client:          client,
+		interfacePrefix: interfacePrefix,
+		logger:          logger.Named("dhcp"),
 	}
 }
 
-// ListenAndServe listens on the UDP network and serve DHCP server
-func (s *Server) ListenAndServe() error {
-	conn, err := dhcp4.NewConn("0.0.0.0:67")
+// ListenAndServe listens on the UDP network address addr and serve DHCP server
+func (s *Server) ListenAndServe(addr string) error {
+	conn, err := dhcp4.NewConn(addr)
 	if err != nil {
 		return fmt.Errorf("failed to listen: %w", err)
 	}
 	defer conn.Close()
 
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return nil
+	}
+	s.conn = conn
+	s.listening = true
+	done := make(chan struct{})
+	s.done = done
+	s.mutex.Unlock()
+	defer close(done)
+	defer s.setListening(false)
+
 	for {
 		req, intf, err := conn.RecvDHCP()
 		if err != nil {
+			if s.isClosed() {
+				return nil
+			}
 			return fmt.Errorf("failed to receive DHCP request: %w", err)
 		}
-		if !strings.HasPrefix(intf.Name, "dhcp") {
+		if !strings.HasPrefix(intf.Name, s.interfacePrefix) {
 			continue
 		}
 
-		lease, err := s.client.GetDHCPLease(context.Background(), &pb.GetDHCPLeaseRequest{
-			MacAddress: req.HardwareAddr.String(),
-		})
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "%+v\n", err)
-			continue
+		if err := s.serve(conn, req, intf); err != nil {
+			s.logger.Warn("failed to serve DHCP request", zap.String("interface", intf.Name), zap.String("mac_address", req.HardwareAddr.String()), zap.Error(err))
 		}
+	}
+	// return nil
+}
 
-		resp, err := makeResponse(*intf, *req, lease.Lease)
+func (s *Server) serve(conn *dhcp4.Conn, req *dhcp4.Packet, intf *net.Interface) (err error) {
+	ctx, span := tracer.Start(context.Background(), "dhcp.Request", trace.WithAttributes(
+		attribute.String("dhcp.interface", intf.Name),
+		attribute.String("dhcp.mac_address", req.HardwareAddr.String()),
+	))
+	defer func() {
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "%+v\n", err)
-			continue
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
 		}
-		fmt.Fprintf(os.Stderr, "%+v\n", resp)
+		span.End()
+	}()
 
-		err = conn.SendDHCP(resp, intf)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "%+v\n", err)
-			continue
-		}
+	lease, err := s.client.GetDHCPLease(ctx, &pb.GetDHCPLeaseRequest{
+		MacAddress: req.HardwareAddr.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get DHCP lease: %w", err)
 	}
-	// return nil
+
+	resp, err := makeResponse(*intf, *req, lease.Lease)
+	if err != nil {
+		return fmt.Errorf("failed to make DHCP response: %w", err)
+	}
+	s.logger.Debug("sending DHCP response",
+		zap.String("interface", intf.Name),
+		zap.Stringer("type", resp.Type),
+		zap.String("mac_address", resp.HardwareAddr.String()),
+		zap.String("your_addr", resp.YourAddr.String()),
+	)
+
+	if err := conn.SendDHCP(resp, intf); err != nil {
+		return fmt.Errorf("failed to send DHCP response: %w", err)
+	}
+	return nil
+}
+
+// Shutdown closes the UDP connection and waits until the server stops or ctx is done
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mutex.Lock()
+	s.closed = true
+	conn := s.conn
+	done := s.done
+	s.mutex.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+	if err := conn.Close(); err != nil {
+		return fmt.Errorf("failed to close connection: %w", err)
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Listening reports whether the server is listening on the UDP network
+func (s *Server) Listening() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.listening
+}
+
+func (s *Server) isClosed() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.closed
+}
+
+func (s *Server) setListening(listening bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.listening = listening
 }
 
 func makeResponse(intf net.Interface, req dhcp4.Packet, lease *pb.DHCPLease) (*dhcp4.Packet, error) {
@@ -71,6 +71,13 @@ func run() error {
 		Interface:  instanceInterface,
 		IpAddress:  "10.192.0.100",
 		MacAddress: instanceInterfaceMAC,
+		IngressRules: []*pb.SecurityGroupRule{
+			{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 22},
+			{Protocol: pb.SecurityGroupRule_ICMP},
+		},
+		EgressRules: []*pb.SecurityGroupRule{
+			{Protocol: pb.SecurityGroupRule_ANY},
+		},
 	})
 	if err != nil {
 		return fmt.Errorf("failed to add security group: %w", err)
@@ -2,10 +2,12 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 
+	"github.com/lovi-cloud/teleskop/client"
 	"github.com/lovi-cloud/teleskop/example"
 
 	pb "github.com/lovi-cloud/teleskop/protoc/agent"
@@ -33,35 +35,36 @@ func run() error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	client, err := example.SetupClient(ctx, *addr)
+	c, err := example.SetupClient(ctx, *addr)
 	if err != nil {
 		return err
 	}
+	defer c.Close()
 
-	_, err = client.AddVLANInterface(ctx, &pb.AddVLANInterfaceRequest{
+	_, err = c.AddVLANInterface(ctx, &pb.AddVLANInterfaceRequest{
 		VlanId:          vlanID,
 		ParentInterface: parentInterface,
 	})
-	if err != nil {
+	if err != nil && !errors.Is(err, client.ErrAlreadyExists) {
 		return fmt.Errorf("failed to add VLAN interface: %w", err)
 	}
 
-	_, err = client.AddBridge(ctx, &pb.AddBridgeRequest{
+	_, err = c.AddBridge(ctx, &pb.AddBridgeRequest{
 		Name: bridgeName,
 	})
-	if err != nil {
+	if err != nil && !errors.Is(err, client.ErrAlreadyExists) {
 		return fmt.Errorf("failed to add bridge: %w", err)
 	}
 
-	_, err = client.AddInterfaceToBridge(ctx, &pb.AddInterfaceToBridgeRequest{
+	_, err = c.AddInterfaceToBridge(ctx, &pb.AddInterfaceToBridgeRequest{
 		Bridge:    bridgeName,
 		Interface: fmt.Sprintf("%s.%d", parentInterface, vlanID),
 	})
-	if err != nil {
+	if err != nil && !errors.Is(err, client.ErrAlreadyExists) {
 		return fmt.Errorf("failed to add interface to bridge: %w", err)
 	}
 
-	_, err = client.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{})
+	_, err = c.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{})
 	if err != nil {
 		return fmt.Errorf("failed to setup default security group: %w", err)
 	}
@@ -0,0 +1,1551 @@
+package main
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+
+	libvirt "github.com/digitalocean/go-libvirt"
+	"github.com/google/nftables"
+	"github.com/google/nftables/expr"
+	"github.com/vishvananda/netlink"
+	"go.uber.org/zap"
+	"golang.org/x/sync/semaphore"
+	"google.golang.org/grpc"
+
+	dspb "github.com/lovi-cloud/satelit/api/satelit_datastore"
+)
+
+// fakeBackends are in-memory implementations of the dependencies of agent.
+type fakeBackends struct {
+	libvirt   *fakeLibvirt
+	netlink   *fakeNetlink
+	iptables  *fakeIPTables
+	ip6tables *fakeIPTables
+	ipset     *fakeIPSet
+	nftables  *fakeNFTables
+	osbrick   *fakeOSBrick
+	datastore *fakeDatastore
+}
+
+func newTestAgent(t *testing.T) (*agent, *fakeBackends) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "teleskop-operations")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %+v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	operations, err := newOperationManager(operationsConfig{JournalDir: dir, Retention: defaultOperationRetention}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("failed to create operation manager: %+v", err)
+	}
+	securityGroups, err := newSecurityGroupStore(filepath.Join(dir, "security-groups"))
+	if err != nil {
+		t.Fatalf("failed to create security group store: %+v", err)
+	}
+
+	nl := newFakeNetlink()
+	f := &fakeBackends{
+		libvirt:   newFakeLibvirt(nl),
+		netlink:   nl,
+		iptables:  newFakeIPTables(),
+		ip6tables: newFakeIPTables(),
+		nftables:  newFakeNFTables(),
+		osbrick:   &fakeOSBrick{iqn: "iqn.1993-08.org.debian:01:teleskop", volumes: map[string]string{}},
+		datastore: &fakeDatastore{},
+	}
+	f.ipset = newFakeIPSet(f.iptables)
+	a := &agent{
+		libvirt:             f.libvirt,
+		netlink:             f.netlink,
+		osbrick:             f.osbrick,
+		firewall:            newIPTablesBackend(f.newIPTables, f.ipset, zap.NewNop()),
+		securityGroups:      securityGroups,
+		securityGroupIndex:  newSecurityGroupIndex(),
+		datastoreClient:     f.datastore,
+		logger:              zap.NewNop(),
+		domainLocks:         newKeyedMutex(),
+		bridgeLocks:         newKeyedMutex(),
+		remoteGroupLocks:    newKeyedMutex(),
+		securityGroupLocks:  newKeyedMutex(),
+		heavyOperations:     semaphore.NewWeighted(defaultMaxHeavyOperations),
+		operations:          operations,
+		interfaceName:       "bond0.1000",
+		dhcpInterfacePrefix: "dhcp",
+		datasourceURL:       "http://169.254.169.254/",
+	}
+	return a, f
+}
+
+// fakeLibvirt is an in-memory libvirtd. Networks create bridges in netlink like libvirtd does.
+type fakeLibvirt struct {
+	mu       sync.Mutex
+	netlink  *fakeNetlink
+	domains  map[libvirt.UUID]*fakeDomain
+	networks map[string]*fakeNetwork
+	nextID   int32
+
+	// defined records the domain XMLs, and attached and detached record
+	// the devices and the modify flags passed to libvirtd.
+	defined  []string
+	attached []fakeDeviceChange
+	detached []fakeDeviceChange
+
+	// lookupErr is returned by NetworkLookupByName if set
+	lookupErr error
+}
+
+type fakeDomain struct {
+	domain  libvirt.Domain
+	state   libvirt.DomainState
+	devices []string
+}
+
+type fakeNetwork struct {
+	network libvirt.Network
+	active  bool
+}
+
+type fakeDeviceChange struct {
+	xml   string
+	flags libvirt.DomainDeviceModifyFlags
+}
+
+func newFakeLibvirt(nl *fakeNetlink) *fakeLibvirt {
+	return &fakeLibvirt{
+		netlink:  nl,
+		domains:  map[libvirt.UUID]*fakeDomain{},
+		networks: map[string]*fakeNetwork{},
+	}
+}
+
+// addDomain defines a domain in state, and returns its uuid.
+func (l *fakeLibvirt) addDomain(name string, state libvirt.DomainState) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return fmt.Sprintf("%x", l.define(name, state).UUID)
+}
+
+func (l *fakeLibvirt) define(name string, state libvirt.DomainState) libvirt.Domain {
+	l.nextID++
+	var uuid libvirt.UUID
+	copy(uuid[:], fmt.Sprintf("%016d", l.nextID))
+	l.domains[uuid] = &fakeDomain{
+		domain: libvirt.Domain{Name: name, UUID: uuid, ID: l.nextID},
+		state:  state,
+	}
+	return l.domains[uuid].domain
+}
+
+func (l *fakeLibvirt) API() (libvirtAPI, error) {
+	return l, nil
+}
+
+func (l *fakeLibvirt) domain(dom libvirt.Domain) (*fakeDomain, error) {
+	d, ok := l.domains[dom.UUID]
+	if !ok {
+		return nil, fmt.Errorf("Domain not found: no domain with matching uuid '%x'", dom.UUID)
+	}
+	return d, nil
+}
+
+func (l *fakeLibvirt) ConnectListAllDomains(ctx context.Context, needResults int32, flags libvirt.ConnectListAllDomainsFlags) ([]libvirt.Domain, uint32, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var domains []libvirt.Domain
+	for _, d := range l.domains {
+		domains = append(domains, d.domain)
+	}
+	sort.Slice(domains, func(i, j int) bool { return domains[i].Name < domains[j].Name })
+	return domains, uint32(len(domains)), nil
+}
+
+func (l *fakeLibvirt) ConnectListAllNetworks(ctx context.Context, needResults int32, flags libvirt.ConnectListAllNetworksFlags) ([]libvirt.Network, uint32, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var networks []libvirt.Network
+	for _, n := range l.networks {
+		networks = append(networks, n.network)
+	}
+	sort.Slice(networks, func(i, j int) bool { return networks[i].Name < networks[j].Name })
+	return networks, uint32(len(networks)), nil
+}
+
+func (l *fakeLibvirt) DomainAttachDeviceFlags(ctx context.Context, dom libvirt.Domain, xml string, flags uint32) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	d, err := l.domain(dom)
+	if err != nil {
+		return err
+	}
+	d.devices = append(d.devices, xml)
+	l.attached = append(l.attached, fakeDeviceChange{xml: xml, flags: libvirt.DomainDeviceModifyFlags(flags)})
+	return nil
+}
+
+func (l *fakeLibvirt) DomainCreate(ctx context.Context, dom libvirt.Domain) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	d, err := l.domain(dom)
+	if err != nil {
+		return err
+	}
+	if d.state == libvirt.DomainRunning {
+		return fmt.Errorf("Requested operation is not valid: domain is already running")
+	}
+	d.state = libvirt.DomainRunning
+	return nil
+}
+
+func (l *fakeLibvirt) DomainDefineXML(ctx context.Context, desc string) (libvirt.Domain, error) {
+	var v struct {
+		Name string `xml:"name"`
+	}
+	if err := xml.Unmarshal([]byte(desc), &v); err != nil {
+		return libvirt.Domain{}, fmt.Errorf("XML error: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.defined = append(l.defined, desc)
+	return l.define(v.Name, libvirt.DomainShutoff), nil
+}
+
+func (l *fakeLibvirt) DomainDestroy(ctx context.Context, dom libvirt.Domain) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	d, err := l.domain(dom)
+	if err != nil {
+		return err
+	}
+	if d.state != libvirt.DomainRunning {
+		return fmt.Errorf("Requested operation is not valid: domain is not running")
+	}
+	d.state = libvirt.DomainShutoff
+	return nil
+}
+
+func (l *fakeLibvirt) DomainDetachDeviceFlags(ctx context.Context, dom libvirt.Domain, xml string, flags uint32) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	d, err := l.domain(dom)
+	if err != nil {
+		return err
+	}
+	for i, device := range d.devices {
+		if device == xml {
+			d.devices = append(d.devices[:i], d.devices[i+1:]...)
+			break
+		}
+	}
+	l.detached = append(l.detached, fakeDeviceChange{xml: xml, flags: libvirt.DomainDeviceModifyFlags(flags)})
+	return nil
+}
+
+func (l *fakeLibvirt) DomainGetState(ctx context.Context, dom libvirt.Domain, flags uint32) (int32, int32, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	d, err := l.domain(dom)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int32(d.state), 0, nil
+}
+
+func (l *fakeLibvirt) DomainGetXMLDesc(ctx context.Context, dom libvirt.Domain, flags libvirt.DomainXMLFlags) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	d, err := l.domain(dom)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("<domain><name>%s</name><devices>%s</devices></domain>", d.domain.Name, strings.Join(d.devices, "")), nil
+}
+
+func (l *fakeLibvirt) DomainLookupByUUID(ctx context.Context, uuid libvirt.UUID) (libvirt.Domain, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	d, err := l.domain(libvirt.Domain{UUID: uuid})
+	if err != nil {
+		return libvirt.Domain{}, err
+	}
+	return d.domain, nil
+}
+
+func (l *fakeLibvirt) DomainUndefine(ctx context.Context, dom libvirt.Domain) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.domain(dom); err != nil {
+		return err
+	}
+	delete(l.domains, dom.UUID)
+	return nil
+}
+
+func (l *fakeLibvirt) NetworkCreate(ctx context.Context, network libvirt.Network) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	n, ok := l.networks[network.Name]
+	if !ok {
+		return fakeNetworkNotFoundError(network.Name)
+	}
+	n.active = true
+	return l.netlink.LinkAdd(&netlink.Bridge{LinkAttrs: netlink.LinkAttrs{Name: network.Name}})
+}
+
+func (l *fakeLibvirt) NetworkDefineXML(ctx context.Context, desc string) (libvirt.Network, error) {
+	var v struct {
+		Name string `xml:"name"`
+	}
+	if err := xml.Unmarshal([]byte(desc), &v); err != nil {
+		return libvirt.Network{}, fmt.Errorf("XML error: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.networks[v.Name]; ok {
+		return libvirt.Network{}, fmt.Errorf("operation failed: network '%s' already exists", v.Name)
+	}
+	network := libvirt.Network{Name: v.Name}
+	l.networks[v.Name] = &fakeNetwork{network: network}
+	return network, nil
+}
+
+func (l *fakeLibvirt) NetworkDestroy(ctx context.Context, network libvirt.Network) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	n, ok := l.networks[network.Name]
+	if !ok || !n.active {
+		return fmt.Errorf("Requested operation is not valid: network '%s' is not active", network.Name)
+	}
+	n.active = false
+	link, err := l.netlink.LinkByName(network.Name)
+	if err != nil {
+		return err
+	}
+	return l.netlink.LinkDel(link)
+}
+
+func (l *fakeLibvirt) NetworkLookupByName(ctx context.Context, name string) (libvirt.Network, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.lookupErr != nil {
+		return libvirt.Network{}, l.lookupErr
+	}
+	n, ok := l.networks[name]
+	if !ok {
+		return libvirt.Network{}, fakeNetworkNotFoundError(name)
+	}
+	return n.network, nil
+}
+
+func (l *fakeLibvirt) NetworkUndefine(ctx context.Context, network libvirt.Network) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.networks[network.Name]; !ok {
+		return fakeNetworkNotFoundError(network.Name)
+	}
+	delete(l.networks, network.Name)
+	return nil
+}
+
+func fakeNetworkNotFoundError(name string) error {
+	return libvirt.Error{Code: uint32(libvirt.ErrNoNetwork), Message: fmt.Sprintf("Network not found: no network with matching name '%s'", name)}
+}
+
+// fakeNetlink is an in-memory set of links.
+type fakeNetlink struct {
+	mu        sync.Mutex
+	links     map[int]netlink.Link
+	addrs     map[int][]netlink.Addr
+	peers     map[int]int
+	flows     []*netlink.ConntrackFlow
+	nextIndex int
+
+	// lookupErr is returned by LinkByName if set
+	lookupErr error
+	// conntrackErr is returned by ConntrackDeleteFilter if set
+	conntrackErr error
+}
+
+func newFakeNetlink() *fakeNetlink {
+	return &fakeNetlink{
+		links: map[int]netlink.Link{},
+		addrs: map[int][]netlink.Addr{},
+		peers: map[int]int{},
+	}
+}
+
+func (n *fakeNetlink) add(link netlink.Link) {
+	n.nextIndex++
+	link.Attrs().Index = n.nextIndex
+	n.links[n.nextIndex] = link
+}
+
+func (n *fakeNetlink) byName(name string) (netlink.Link, error) {
+	for _, link := range n.links {
+		if link.Attrs().Name == name {
+			return link, nil
+		}
+	}
+	return nil, netlink.LinkNotFoundError{}
+}
+
+func (n *fakeNetlink) byLink(link netlink.Link) (netlink.Link, error) {
+	l, ok := n.links[link.Attrs().Index]
+	if !ok {
+		return nil, netlink.LinkNotFoundError{}
+	}
+	return l, nil
+}
+
+func (n *fakeNetlink) LinkByName(name string) (netlink.Link, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.lookupErr != nil {
+		return nil, n.lookupErr
+	}
+	return n.byName(name)
+}
+
+func (n *fakeNetlink) LinkByIndex(index int) (netlink.Link, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	link, ok := n.links[index]
+	if !ok {
+		return nil, netlink.LinkNotFoundError{}
+	}
+	return link, nil
+}
+
+func (n *fakeNetlink) LinkList() ([]netlink.Link, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var links []netlink.Link
+	for _, link := range n.links {
+		links = append(links, link)
+	}
+	sort.Slice(links, func(i, j int) bool { return links[i].Attrs().Index < links[j].Attrs().Index })
+	return links, nil
+}
+
+func (n *fakeNetlink) LinkAdd(link netlink.Link) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if _, err := n.byName(link.Attrs().Name); err == nil {
+		return syscall.EEXIST
+	}
+	n.add(link)
+	if veth, ok := link.(*netlink.Veth); ok {
+		peer := &netlink.Veth{LinkAttrs: netlink.LinkAttrs{Name: veth.PeerName}, PeerName: veth.Name}
+		n.add(peer)
+		n.peers[veth.Index] = peer.Index
+		n.peers[peer.Index] = veth.Index
+	}
+	return nil
+}
+
+func (n *fakeNetlink) LinkDel(link netlink.Link) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	l, err := n.byLink(link)
+	if err != nil {
+		return err
+	}
+	index := l.Attrs().Index
+	if peer, ok := n.peers[index]; ok {
+		delete(n.links, peer)
+		delete(n.peers, peer)
+		delete(n.peers, index)
+	}
+	delete(n.links, index)
+	return nil
+}
+
+func (n *fakeNetlink) LinkSetUp(link netlink.Link) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	l, err := n.byLink(link)
+	if err != nil {
+		return err
+	}
+	l.Attrs().Flags |= net.FlagUp
+	return nil
+}
+
+func (n *fakeNetlink) LinkSetDown(link netlink.Link) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	l, err := n.byLink(link)
+	if err != nil {
+		return err
+	}
+	l.Attrs().Flags &^= net.FlagUp
+	return nil
+}
+
+func (n *fakeNetlink) LinkSetMaster(link, master netlink.Link) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	l, err := n.byLink(link)
+	if err != nil {
+		return err
+	}
+	m, err := n.byLink(master)
+	if err != nil {
+		return err
+	}
+	l.Attrs().MasterIndex = m.Attrs().Index
+	return nil
+}
+
+func (n *fakeNetlink) LinkSetNoMaster(link netlink.Link) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	l, err := n.byLink(link)
+	if err != nil {
+		return err
+	}
+	l.Attrs().MasterIndex = 0
+	return nil
+}
+
+func (n *fakeNetlink) AddrAdd(link netlink.Link, addr *netlink.Addr) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	l, err := n.byLink(link)
+	if err != nil {
+		return err
+	}
+	n.addrs[l.Attrs().Index] = append(n.addrs[l.Attrs().Index], *addr)
+	return nil
+}
+
+func (n *fakeNetlink) AddrDel(link netlink.Link, addr *netlink.Addr) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	l, err := n.byLink(link)
+	if err != nil {
+		return err
+	}
+	addrs := n.addrs[l.Attrs().Index]
+	for i, a := range addrs {
+		if a.IPNet.String() == addr.IPNet.String() {
+			n.addrs[l.Attrs().Index] = append(addrs[:i], addrs[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot assign requested address")
+}
+
+func (n *fakeNetlink) AddrList(link netlink.Link, family int) ([]netlink.Addr, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	l, err := n.byLink(link)
+	if err != nil {
+		return nil, err
+	}
+	return append([]netlink.Addr(nil), n.addrs[l.Attrs().Index]...), nil
+}
+
+func (n *fakeNetlink) VethPeerIndex(link *netlink.Veth) (int, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	peer, ok := n.peers[link.Attrs().Index]
+	if !ok {
+		return -1, fmt.Errorf("no such device")
+	}
+	return peer, nil
+}
+
+func (n *fakeNetlink) ConntrackDeleteFilter(table netlink.ConntrackTableType, family netlink.InetFamily, filter netlink.CustomConntrackFilter) (uint, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.conntrackErr != nil {
+		return 0, n.conntrackErr
+	}
+	var (
+		kept    []*netlink.ConntrackFlow
+		deleted uint
+	)
+	for _, flow := range n.flows {
+		if flow.FamilyType == uint8(family) && filter.MatchConntrackFlow(flow) {
+			deleted++
+			continue
+		}
+		kept = append(kept, flow)
+	}
+	n.flows = kept
+	return deleted, nil
+}
+
+// newIPTables returns the fake table of family.
+func (f *fakeBackends) newIPTables(family ipFamily) (iptablesAPI, error) {
+	if family == ipFamilyIPv6 {
+		return f.ip6tables, nil
+	}
+	return f.iptables, nil
+}
+
+// fakeIPTables is an in-memory filter table. Rules are recorded as space separated rulespecs.
+type fakeIPTables struct {
+	mu     sync.Mutex
+	chains map[string]map[string][]string
+	// specs are the arguments of the recorded rules, to quote them in listings like iptables -S
+	specs    map[string][]string
+	counters map[string][2]uint64
+	// failRestore fails the transactions that contain a line with it
+	failRestore string
+}
+
+func newFakeIPTables() *fakeIPTables {
+	return &fakeIPTables{
+		chains: map[string]map[string][]string{
+			tableFilter: {
+				chainINPUT:   nil,
+				chainFORWARD: nil,
+				"OUTPUT":     nil,
+			},
+		},
+		specs:    map[string][]string{},
+		counters: map[string][2]uint64{},
+	}
+}
+
+// setCounters sets the packet and byte counters of rule in chain.
+func (i *fakeIPTables) setCounters(chain, rule string, packets, bytes uint64) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.counters[chain+" "+rule] = [2]uint64{packets, bytes}
+}
+
+// format returns rule in chain as printed by iptables -S.
+func (i *fakeIPTables) format(chain, rule string, counters bool) string {
+	spec, ok := i.specs[rule]
+	if !ok {
+		spec = strings.Fields(rule)
+	}
+	args := []string{"-A", chain}
+	for n, arg := range spec {
+		if counters && n == len(spec)-2 && arg == "-j" {
+			c := i.counters[chain+" "+rule]
+			args = append(args, "-c", fmt.Sprint(c[0]), fmt.Sprint(c[1]))
+		}
+		if strings.Contains(arg, " ") {
+			arg = strconv.Quote(arg)
+		}
+		args = append(args, arg)
+	}
+	return strings.Join(args, " ")
+}
+
+func (i *fakeIPTables) rules(table, chain string) []string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return append([]string(nil), i.chains[table][chain]...)
+}
+
+func (i *fakeIPTables) hasChain(table, chain string) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	_, ok := i.chains[table][chain]
+	return ok
+}
+
+// matchesSet returns whether a rule matches set.
+func (i *fakeIPTables) matchesSet(set string) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, chains := range i.chains {
+		for _, rules := range chains {
+			for _, rule := range rules {
+				if strings.Contains(rule, "--match-set "+set+" ") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (i *fakeIPTables) NewChain(ctx context.Context, table, chain string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if _, ok := i.chains[table][chain]; ok {
+		return fmt.Errorf("iptables: Chain already exists")
+	}
+	i.chains[table][chain] = []string{}
+	return nil
+}
+
+func (i *fakeIPTables) ChainExists(ctx context.Context, table, chain string) (bool, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	_, ok := i.chains[table][chain]
+	return ok, nil
+}
+
+func (i *fakeIPTables) ClearChain(ctx context.Context, table, chain string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.chains[table][chain] = []string{}
+	return nil
+}
+
+func (i *fakeIPTables) DeleteChain(ctx context.Context, table, chain string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	rules, ok := i.chains[table][chain]
+	if !ok {
+		return fmt.Errorf("iptables: No chain/target/match by that name")
+	}
+	if len(rules) != 0 {
+		return fmt.Errorf("iptables: Directory not empty")
+	}
+	delete(i.chains[table], chain)
+	return nil
+}
+
+// RenameChain renames the chain and the targets of the rules jumping to it, as iptables -E does.
+func (i *fakeIPTables) RenameChain(ctx context.Context, table, oldChain, newChain string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	rules, ok := i.chains[table][oldChain]
+	if !ok {
+		return fmt.Errorf("iptables: No chain/target/match by that name")
+	}
+	if _, ok := i.chains[table][newChain]; ok {
+		return fmt.Errorf("iptables: File exists")
+	}
+	delete(i.chains[table], oldChain)
+	i.chains[table][newChain] = rules
+	for chain, rules := range i.chains[table] {
+		for n, rule := range rules {
+			if rule == "-j "+oldChain || strings.HasSuffix(rule, " -j "+oldChain) {
+				renamed := strings.TrimSuffix(rule, oldChain) + newChain
+				if spec, ok := i.specs[rule]; ok {
+					spec = append([]string(nil), spec...)
+					spec[len(spec)-1] = newChain
+					i.specs[renamed] = spec
+				}
+				i.chains[table][chain][n] = renamed
+			}
+		}
+	}
+	return nil
+}
+
+func (i *fakeIPTables) List(ctx context.Context, table, chain string) ([]string, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	rules, ok := i.chains[table][chain]
+	if !ok {
+		return nil, fmt.Errorf("iptables: No chain/target/match by that name")
+	}
+	list := []string{"-N " + chain}
+	for _, rule := range rules {
+		list = append(list, i.format(chain, rule, false))
+	}
+	return list, nil
+}
+
+func (i *fakeIPTables) ListWithCounters(ctx context.Context, table, chain string) ([]string, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	rules, ok := i.chains[table][chain]
+	if !ok {
+		return nil, fmt.Errorf("iptables: No chain/target/match by that name")
+	}
+	list := []string{"-N " + chain}
+	for _, rule := range rules {
+		list = append(list, i.format(chain, rule, true))
+	}
+	return list, nil
+}
+
+// ListChains returns the built-in chains first, and then the other chains by name like iptables -S.
+func (i *fakeIPTables) ListChains(ctx context.Context, table string) ([]string, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	chains := []string{chainINPUT, chainFORWARD, "OUTPUT"}
+	var others []string
+	for chain := range i.chains[table] {
+		if chain != chainINPUT && chain != chainFORWARD && chain != "OUTPUT" {
+			others = append(others, chain)
+		}
+	}
+	sort.Strings(others)
+	return append(chains, others...), nil
+}
+
+// Save prints the chains in the order of ListChains, like iptables-save.
+func (i *fakeIPTables) Save(ctx context.Context, table string) (string, error) {
+	chains, err := i.ListChains(ctx, table)
+	if err != nil {
+		return "", err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	lines := []string{"*" + table}
+	for _, chain := range chains {
+		policy := "-"
+		if chain == chainINPUT || chain == chainFORWARD || chain == "OUTPUT" {
+			policy = "ACCEPT"
+		}
+		lines = append(lines, fmt.Sprintf(":%s %s [0:0]", chain, policy))
+	}
+	for _, chain := range chains {
+		for _, rule := range i.chains[table][chain] {
+			lines = append(lines, i.format(chain, rule, false))
+		}
+	}
+	lines = append(lines, "COMMIT")
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// Restore applies rules to a copy of the table, and replaces the table only if every line succeeds.
+func (i *fakeIPTables) Restore(ctx context.Context, table, rules string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	tx := &fakeIPTables{
+		chains:   map[string]map[string][]string{table: {}},
+		specs:    map[string][]string{},
+		counters: i.counters,
+	}
+	for chain, rules := range i.chains[table] {
+		tx.chains[table][chain] = append([]string(nil), rules...)
+	}
+	for rule, spec := range i.specs {
+		tx.specs[rule] = spec
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(rules), "\n") {
+		if i.failRestore != "" && strings.Contains(line, i.failRestore) {
+			return fmt.Errorf("iptables-restore: line failed: %s", line)
+		}
+		if line == "*"+table || line == "COMMIT" {
+			continue
+		}
+		if err := tx.restore(ctx, table, line); err != nil {
+			return fmt.Errorf("iptables-restore: %s: %w", line, err)
+		}
+	}
+
+	i.chains[table] = tx.chains[table]
+	i.specs = tx.specs
+	return nil
+}
+
+func (i *fakeIPTables) restore(ctx context.Context, table, line string) error {
+	args, err := splitIPTablesRule(line)
+	if err != nil {
+		return err
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("invalid line")
+	}
+	command, chain, spec := args[0], args[1], args[2:]
+	switch command {
+	case "-N":
+		return i.NewChain(ctx, table, chain)
+	case "-F":
+		if _, ok := i.chains[table][chain]; !ok {
+			return fmt.Errorf("iptables: No chain/target/match by that name")
+		}
+		return i.ClearChain(ctx, table, chain)
+	case "-X":
+		return i.DeleteChain(ctx, table, chain)
+	case "-E":
+		if len(spec) != 1 {
+			return fmt.Errorf("invalid rename")
+		}
+		return i.RenameChain(ctx, table, chain, spec[0])
+	case "-A":
+		return i.Insert(ctx, table, chain, len(i.chains[table][chain])+1, spec...)
+	case "-I":
+		pos := 1
+		if len(spec) != 0 {
+			if n, err := strconv.Atoi(spec[0]); err == nil {
+				pos, spec = n, spec[1:]
+			}
+		}
+		return i.Insert(ctx, table, chain, pos, spec...)
+	case "-D":
+		return i.Delete(ctx, table, chain, spec...)
+	default:
+		return fmt.Errorf("unsupported command %s", command)
+	}
+}
+
+func (i *fakeIPTables) Exists(ctx context.Context, table, chain string, rulespec ...string) (bool, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	rules, ok := i.chains[table][chain]
+	if !ok {
+		return false, fmt.Errorf("iptables: No chain/target/match by that name")
+	}
+	rule := strings.Join(rulespec, " ")
+	for _, r := range rules {
+		if r == rule {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (i *fakeIPTables) Insert(ctx context.Context, table, chain string, pos int, rulespec ...string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	rules, ok := i.chains[table][chain]
+	if !ok {
+		return fmt.Errorf("iptables: No chain/target/match by that name")
+	}
+	if pos < 1 || pos > len(rules)+1 {
+		return fmt.Errorf("iptables: Index of insertion too big")
+	}
+	rule := strings.Join(rulespec, " ")
+	i.specs[rule] = append([]string(nil), rulespec...)
+	rules = append(rules[:pos-1], append([]string{rule}, rules[pos-1:]...)...)
+	i.chains[table][chain] = rules
+	return nil
+}
+
+func (i *fakeIPTables) AppendUnique(ctx context.Context, table, chain string, rulespec ...string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	rules, ok := i.chains[table][chain]
+	if !ok {
+		return fmt.Errorf("iptables: No chain/target/match by that name")
+	}
+	rule := strings.Join(rulespec, " ")
+	for _, r := range rules {
+		if r == rule {
+			return nil
+		}
+	}
+	i.specs[rule] = append([]string(nil), rulespec...)
+	i.chains[table][chain] = append(rules, rule)
+	return nil
+}
+
+func (i *fakeIPTables) Delete(ctx context.Context, table, chain string, rulespec ...string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	rules, ok := i.chains[table][chain]
+	if !ok {
+		return fmt.Errorf("iptables: No chain/target/match by that name")
+	}
+	rule := strings.Join(rulespec, " ")
+	for n, r := range rules {
+		if r == rule {
+			i.chains[table][chain] = append(rules[:n:n], rules[n+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("iptables: Bad rule (does a matching rule exist in that chain?)")
+}
+
+// fakeIPSet is an in-memory set of ipsets. A set is in use while a rule of iptables matches it.
+type fakeIPSet struct {
+	mu       sync.Mutex
+	sets     map[string][]net.IP
+	iptables *fakeIPTables
+}
+
+func newFakeIPSet(iptables *fakeIPTables) *fakeIPSet {
+	return &fakeIPSet{
+		sets:     map[string][]net.IP{},
+		iptables: iptables,
+	}
+}
+
+func (s *fakeIPSet) members(set string) ([]net.IP, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	members, ok := s.sets[set]
+	return append([]net.IP(nil), members...), ok
+}
+
+func (s *fakeIPSet) Create(ctx context.Context, set string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sets[set]; !ok {
+		s.sets[set] = []net.IP{}
+	}
+	return nil
+}
+
+func (s *fakeIPSet) Replace(ctx context.Context, set, temporary string, members []net.IP) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sets[set] = append([]net.IP{}, members...)
+	return nil
+}
+
+func (s *fakeIPSet) Destroy(ctx context.Context, set string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sets[set]; !ok {
+		return fmt.Errorf("ipset v7.10: The set with the given name does not exist")
+	}
+	if s.iptables.matchesSet(set) {
+		return fmt.Errorf("ipset v7.10: Set cannot be destroyed: it is in use by a kernel component: %w", errIPSetInUse)
+	}
+	delete(s.sets, set)
+	return nil
+}
+
+func (s *fakeIPSet) List(ctx context.Context) (map[string][]net.IP, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sets := map[string][]net.IP{}
+	for set, members := range s.sets {
+		sets[set] = append([]net.IP{}, members...)
+	}
+	return sets, nil
+}
+
+// fakeNFTables is an in-memory bridge family of nftables. The changes queued by a connection are applied
+// to a copy of the state at Flush, which replaces the state only if every change succeeds like a batch.
+type fakeNFTables struct {
+	mu    sync.Mutex
+	state *fakeNFTablesState
+	// failChain fails the batches that change the chain
+	failChain string
+}
+
+// fakeNFTablesState is keyed by the table and the name of the chains and sets.
+type fakeNFTablesState struct {
+	tables map[string]bool
+	chains map[string]*fakeNFTChain
+	sets   map[string][][]byte
+	handle uint64
+}
+
+type fakeNFTChain struct {
+	chain *nftables.Chain
+	rules []*nftables.Rule
+}
+
+func newFakeNFTables() *fakeNFTables {
+	return &fakeNFTables{state: &fakeNFTablesState{
+		tables: map[string]bool{},
+		chains: map[string]*fakeNFTChain{},
+		sets:   map[string][][]byte{},
+	}}
+}
+
+func (n *fakeNFTables) newConn() (nftablesAPI, error) {
+	return &fakeNFTablesConn{fakeNFTables: n}, nil
+}
+
+// rules returns the rules of chain in the table of the agent as decodeNFTRule prints them.
+func (n *fakeNFTables) rules(chain string) []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	c, ok := n.state.chains[fakeNFTKey(nftablesTable, chain)]
+	if !ok {
+		return nil
+	}
+	rules := []string{}
+	for _, rule := range c.rules {
+		rules = append(rules, decodeNFTRule(rule).Rule)
+	}
+	return rules
+}
+
+func (n *fakeNFTables) hasChain(chain string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, ok := n.state.chains[fakeNFTKey(nftablesTable, chain)]
+	return ok
+}
+
+// members returns the members of the set of group.
+func (n *fakeNFTables) members(group string) ([]string, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	elements, ok := n.state.sets[fakeNFTKey(nftablesTable, getRemoteGroupSetName(group))]
+	members := []string{}
+	for _, element := range elements {
+		members = append(members, net.IP(element).String())
+	}
+	return members, ok
+}
+
+func fakeNFTKey(table, name string) string {
+	return table + " " + name
+}
+
+func (s *fakeNFTablesState) clone() *fakeNFTablesState {
+	c := &fakeNFTablesState{
+		tables: map[string]bool{},
+		chains: map[string]*fakeNFTChain{},
+		sets:   map[string][][]byte{},
+		handle: s.handle,
+	}
+	for table := range s.tables {
+		c.tables[table] = true
+	}
+	for key, chain := range s.chains {
+		c.chains[key] = &fakeNFTChain{chain: chain.chain, rules: append([]*nftables.Rule(nil), chain.rules...)}
+	}
+	for key, elements := range s.sets {
+		c.sets[key] = append([][]byte(nil), elements...)
+	}
+	return c
+}
+
+func (s *fakeNFTablesState) chain(c *nftables.Chain) (*fakeNFTChain, error) {
+	chain, ok := s.chains[fakeNFTKey(c.Table.Name, c.Name)]
+	if !ok {
+		return nil, fmt.Errorf("chain %s: %w", c.Name, syscall.ENOENT)
+	}
+	return chain, nil
+}
+
+// add adds rule at the top of its chain if insert is set, or at the bottom. The chains that it jumps to
+// and the sets that it looks up must exist.
+func (s *fakeNFTablesState) add(rule *nftables.Rule, insert bool) error {
+	chain, err := s.chain(rule.Chain)
+	if err != nil {
+		return err
+	}
+	for _, e := range rule.Exprs {
+		switch e := e.(type) {
+		case *expr.Verdict:
+			if e.Chain == "" {
+				continue
+			}
+			if _, ok := s.chains[fakeNFTKey(rule.Table.Name, e.Chain)]; !ok {
+				return fmt.Errorf("chain %s: %w", e.Chain, syscall.ENOENT)
+			}
+		case *expr.Lookup:
+			if _, ok := s.sets[fakeNFTKey(rule.Table.Name, e.SetName)]; !ok {
+				return fmt.Errorf("set %s: %w", e.SetName, syscall.ENOENT)
+			}
+		}
+	}
+
+	s.handle++
+	added := *rule
+	added.Handle = s.handle
+	added.Exprs = append([]expr.Any(nil), rule.Exprs...)
+	if insert {
+		chain.rules = append([]*nftables.Rule{&added}, chain.rules...)
+	} else {
+		chain.rules = append(chain.rules, &added)
+	}
+	return nil
+}
+
+// references returns whether a rule of table jumps to chain or looks up set.
+func (s *fakeNFTablesState) references(table, chain, set string) bool {
+	for key, c := range s.chains {
+		if !strings.HasPrefix(key, table+" ") {
+			continue
+		}
+		for _, rule := range c.rules {
+			for _, e := range rule.Exprs {
+				if v, ok := e.(*expr.Verdict); ok && chain != "" && v.Chain == chain {
+					return true
+				}
+				if l, ok := e.(*expr.Lookup); ok && set != "" && l.SetName == set {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (n *fakeNFTables) ListTablesOfFamily(ctx context.Context, family nftables.TableFamily) ([]*nftables.Table, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var tables []*nftables.Table
+	if family != nftables.TableFamilyBridge {
+		return tables, nil
+	}
+	for table := range n.state.tables {
+		tables = append(tables, &nftables.Table{Name: table, Family: family})
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Name < tables[j].Name })
+	return tables, nil
+}
+
+func (n *fakeNFTables) ListChainsOfTableFamily(ctx context.Context, family nftables.TableFamily) ([]*nftables.Chain, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var chains []*nftables.Chain
+	if family != nftables.TableFamilyBridge {
+		return chains, nil
+	}
+	for _, chain := range n.state.chains {
+		c := *chain.chain
+		chains = append(chains, &c)
+	}
+	return chains, nil
+}
+
+func (n *fakeNFTables) GetRules(ctx context.Context, t *nftables.Table, c *nftables.Chain) ([]*nftables.Rule, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	chain, err := n.state.chain(&nftables.Chain{Name: c.Name, Table: t})
+	if err != nil {
+		return nil, err
+	}
+	return append([]*nftables.Rule(nil), chain.rules...), nil
+}
+
+func (n *fakeNFTables) GetSets(ctx context.Context, t *nftables.Table) ([]*nftables.Set, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if !n.state.tables[t.Name] {
+		return nil, fmt.Errorf("table %s: %w", t.Name, syscall.ENOENT)
+	}
+	var sets []*nftables.Set
+	for key := range n.state.sets {
+		if strings.HasPrefix(key, t.Name+" ") {
+			sets = append(sets, &nftables.Set{Table: t, Name: strings.TrimPrefix(key, t.Name+" "), KeyType: nftables.TypeIPAddr})
+		}
+	}
+	return sets, nil
+}
+
+func (n *fakeNFTables) GetSetElements(ctx context.Context, s *nftables.Set) ([]nftables.SetElement, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	set, ok := n.state.sets[fakeNFTKey(s.Table.Name, s.Name)]
+	if !ok {
+		return nil, fmt.Errorf("set %s: %w", s.Name, syscall.ENOENT)
+	}
+	var elements []nftables.SetElement
+	for _, key := range set {
+		elements = append(elements, nftables.SetElement{Key: key})
+	}
+	return elements, nil
+}
+
+// fakeNFTablesConn queues changes until Flush, like a netlink connection of nftables.
+type fakeNFTablesConn struct {
+	*fakeNFTables
+	changes []fakeNFTablesChange
+}
+
+type fakeNFTablesChange struct {
+	chain string
+	apply func(s *fakeNFTablesState) error
+}
+
+func (c *fakeNFTablesConn) queue(chain string, apply func(s *fakeNFTablesState) error) {
+	c.changes = append(c.changes, fakeNFTablesChange{chain: chain, apply: apply})
+}
+
+func (c *fakeNFTablesConn) AddTable(t *nftables.Table) *nftables.Table {
+	c.queue("", func(s *fakeNFTablesState) error {
+		s.tables[t.Name] = true
+		return nil
+	})
+	return t
+}
+
+func (c *fakeNFTablesConn) AddChain(chain *nftables.Chain) *nftables.Chain {
+	c.queue(chain.Name, func(s *fakeNFTablesState) error {
+		if !s.tables[chain.Table.Name] {
+			return fmt.Errorf("table %s: %w", chain.Table.Name, syscall.ENOENT)
+		}
+		key := fakeNFTKey(chain.Table.Name, chain.Name)
+		if _, ok := s.chains[key]; !ok {
+			s.chains[key] = &fakeNFTChain{chain: chain}
+		}
+		return nil
+	})
+	return chain
+}
+
+func (c *fakeNFTablesConn) FlushChain(chain *nftables.Chain) {
+	c.queue(chain.Name, func(s *fakeNFTablesState) error {
+		fc, err := s.chain(chain)
+		if err != nil {
+			return err
+		}
+		fc.rules = nil
+		return nil
+	})
+}
+
+func (c *fakeNFTablesConn) DelChain(chain *nftables.Chain) {
+	c.queue(chain.Name, func(s *fakeNFTablesState) error {
+		fc, err := s.chain(chain)
+		if err != nil {
+			return err
+		}
+		if len(fc.rules) != 0 || s.references(chain.Table.Name, chain.Name, "") {
+			return fmt.Errorf("chain %s: %w", chain.Name, syscall.EBUSY)
+		}
+		delete(s.chains, fakeNFTKey(chain.Table.Name, chain.Name))
+		return nil
+	})
+}
+
+func (c *fakeNFTablesConn) AddRule(r *nftables.Rule) *nftables.Rule {
+	c.queue(r.Chain.Name, func(s *fakeNFTablesState) error {
+		return s.add(r, false)
+	})
+	return r
+}
+
+func (c *fakeNFTablesConn) InsertRule(r *nftables.Rule) *nftables.Rule {
+	c.queue(r.Chain.Name, func(s *fakeNFTablesState) error {
+		return s.add(r, true)
+	})
+	return r
+}
+
+func (c *fakeNFTablesConn) DelRule(r *nftables.Rule) error {
+	if r.Handle == 0 {
+		return fmt.Errorf("rule's handle cannot be 0")
+	}
+	c.queue(r.Chain.Name, func(s *fakeNFTablesState) error {
+		chain, err := s.chain(r.Chain)
+		if err != nil {
+			return err
+		}
+		for i, rule := range chain.rules {
+			if rule.Handle == r.Handle {
+				chain.rules = append(chain.rules[:i], chain.rules[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("rule %d: %w", r.Handle, syscall.ENOENT)
+	})
+	return nil
+}
+
+func (c *fakeNFTablesConn) AddSet(set *nftables.Set, vals []nftables.SetElement) error {
+	c.queue("", func(s *fakeNFTablesState) error {
+		if !s.tables[set.Table.Name] {
+			return fmt.Errorf("table %s: %w", set.Table.Name, syscall.ENOENT)
+		}
+		key := fakeNFTKey(set.Table.Name, set.Name)
+		if _, ok := s.sets[key]; !ok {
+			s.sets[key] = nil
+		}
+		return nil
+	})
+	return c.SetAddElements(set, vals)
+}
+
+func (c *fakeNFTablesConn) FlushSet(set *nftables.Set) {
+	c.queue("", func(s *fakeNFTablesState) error {
+		key := fakeNFTKey(set.Table.Name, set.Name)
+		if _, ok := s.sets[key]; !ok {
+			return fmt.Errorf("set %s: %w", set.Name, syscall.ENOENT)
+		}
+		s.sets[key] = nil
+		return nil
+	})
+}
+
+func (c *fakeNFTablesConn) SetAddElements(set *nftables.Set, vals []nftables.SetElement) error {
+	if len(vals) == 0 {
+		return nil
+	}
+	c.queue("", func(s *fakeNFTablesState) error {
+		key := fakeNFTKey(set.Table.Name, set.Name)
+		if _, ok := s.sets[key]; !ok {
+			return fmt.Errorf("set %s: %w", set.Name, syscall.ENOENT)
+		}
+		for _, val := range vals {
+			s.sets[key] = append(s.sets[key], val.Key)
+		}
+		return nil
+	})
+	return nil
+}
+
+func (c *fakeNFTablesConn) DelSet(set *nftables.Set) {
+	c.queue("", func(s *fakeNFTablesState) error {
+		key := fakeNFTKey(set.Table.Name, set.Name)
+		if _, ok := s.sets[key]; !ok {
+			return fmt.Errorf("set %s: %w", set.Name, syscall.ENOENT)
+		}
+		if s.references(set.Table.Name, "", set.Name) {
+			return fmt.Errorf("set %s: %w", set.Name, syscall.EBUSY)
+		}
+		delete(s.sets, key)
+		return nil
+	})
+}
+
+func (c *fakeNFTablesConn) Flush(ctx context.Context) error {
+	changes := c.changes
+	c.changes = nil
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tx := c.state.clone()
+	for _, change := range changes {
+		if c.failChain != "" && change.chain == c.failChain {
+			return fmt.Errorf("conn.Receive: chain %s: %w", change.chain, syscall.EINVAL)
+		}
+		if err := change.apply(tx); err != nil {
+			return fmt.Errorf("conn.Receive: %w", err)
+		}
+	}
+	c.state = tx
+	return nil
+}
+
+// fakeOSBrick is an in-memory set of iSCSI volumes.
+type fakeOSBrick struct {
+	mu      sync.Mutex
+	iqn     string
+	volumes map[string]string
+	err     error
+}
+
+func fakeVolumeKey(portals []string, hostLUNID int) string {
+	return fmt.Sprintf("%s/%d", strings.Join(portals, ","), hostLUNID)
+}
+
+func (o *fakeOSBrick) connect(portals []string, hostLUNID int, device string) (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.err != nil {
+		return "", o.err
+	}
+	o.volumes[fakeVolumeKey(portals, hostLUNID)] = device
+	return device, nil
+}
+
+func (o *fakeOSBrick) disconnect(portals []string, hostLUNID int) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.err != nil {
+		return o.err
+	}
+	delete(o.volumes, fakeVolumeKey(portals, hostLUNID))
+	return nil
+}
+
+func (o *fakeOSBrick) GetIQN(ctx context.Context) (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.iqn, o.err
+}
+
+func (o *fakeOSBrick) ConnectSinglePathVolume(ctx context.Context, targetPortalIP string, hostLUNID int) (string, error) {
+	return o.connect([]string{targetPortalIP}, hostLUNID, fmt.Sprintf("/dev/sd%c", 'a'+hostLUNID))
+}
+
+func (o *fakeOSBrick) ConnectMultipathVolume(ctx context.Context, targetPortalIPs []string, hostLUNID int) (string, error) {
+	return o.connect(targetPortalIPs, hostLUNID, fmt.Sprintf("/dev/dm-%d", hostLUNID))
+}
+
+func (o *fakeOSBrick) DisconnectSinglePathVolume(ctx context.Context, targetPortalIP string, hostLUNID int) error {
+	return o.disconnect([]string{targetPortalIP}, hostLUNID)
+}
+
+func (o *fakeOSBrick) DisconnectVolume(ctx context.Context, targetPortalIPs []string, hostLUNID int) error {
+	return o.disconnect(targetPortalIPs, hostLUNID)
+}
+
+// fakeDatastore is a satelit datastore api that serves fixed bridges and pinning groups.
+type fakeDatastore struct {
+	dspb.SatelitDatastoreClient
+
+	mu         sync.Mutex
+	bridges    []*dspb.Bridge
+	pairs      []*dspb.CorePair
+	registered []*dspb.RegisterTeleskopAgentRequest
+}
+
+func (d *fakeDatastore) ListBridge(ctx context.Context, in *dspb.ListBridgeRequest, opts ...grpc.CallOption) (*dspb.ListBridgeResponse, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return &dspb.ListBridgeResponse{Bridges: d.bridges}, nil
+}
+
+func (d *fakeDatastore) RegisterTeleskopAgent(ctx context.Context, in *dspb.RegisterTeleskopAgentRequest, opts ...grpc.CallOption) (*dspb.RegisterTeleskopAgentResponse, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.registered = append(d.registered, in)
+	return &dspb.RegisterTeleskopAgentResponse{}, nil
+}
+
+func (d *fakeDatastore) GetCPUCoreByPinningGroup(ctx context.Context, in *dspb.GetCPUCoreByPinningGroupRequest, opts ...grpc.CallOption) (*dspb.GetCPUCoreByPinningGroupResponse, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return &dspb.GetCPUCoreByPinningGroupResponse{Pairs: d.pairs}, nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"go.uber.org/zap"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+const (
+	firewallBackendIPTables = "iptables"
+	firewallBackendNFTables = "nftables"
+)
+
+// ipFamily is an address family of security groups.
+type ipFamily int
+
+const (
+	ipFamilyIPv4 ipFamily = iota
+	ipFamilyIPv6
+)
+
+// ipFamilies are the families that every security group filters.
+var ipFamilies = []ipFamily{ipFamilyIPv4, ipFamilyIPv6}
+
+func (f ipFamily) String() string {
+	if f == ipFamilyIPv6 {
+		return "ipv6"
+	}
+	return "ipv4"
+}
+
+// ICMPv6 types that IPv6 requires besides the rules of security groups
+const (
+	icmpv6TypeMLDQuery              = 130
+	icmpv6TypeRouterSolicitation    = 133
+	icmpv6TypeRouterAdvertisement   = 134
+	icmpv6TypeNeighborSolicitation  = 135
+	icmpv6TypeNeighborAdvertisement = 136
+	icmpv6TypeMLDv2Report           = 143
+)
+
+var (
+	// ipv6LinkLocalNet is the network of the link-local addresses, which VMs configure by themselves
+	ipv6LinkLocalNet = &net.IPNet{IP: net.ParseIP("fe80::"), Mask: net.CIDRMask(10, 128)}
+	// ipv6UnspecifiedNet is the source of duplicate address detection
+	ipv6UnspecifiedNet = &net.IPNet{IP: net.IPv6unspecified, Mask: net.CIDRMask(128, 128)}
+)
+
+// getIPFamily returns the family of ip.
+func getIPFamily(ip net.IP) ipFamily {
+	if ip.To4() != nil {
+		return ipFamilyIPv4
+	}
+	return ipFamilyIPv6
+}
+
+var (
+	// errRemoteGroupNotFound is returned when a remote group to delete does not exist.
+	errRemoteGroupNotFound = errors.New("remote group does not exist")
+	// errRemoteGroupInUse is returned when a remote group to delete is referred by security group rules.
+	errRemoteGroupInUse = errors.New("remote group is in use")
+)
+
+// firewallBackend applies security groups and remote groups to the firewall of the host.
+// The changes of a call are applied at once, so that a failure leaves neither half-filled
+// chains nor changes of the other security groups.
+type firewallBackend interface {
+	// Name returns the name of the backend in the configuration.
+	Name() string
+	// Check returns an error if the firewall cannot be operated.
+	Check(ctx context.Context) error
+
+	// Setup creates the shared chains and the hooks. It fails if they exist.
+	Setup(ctx context.Context) error
+	// Add creates the chains of intf and the rules that direct its traffic to them.
+	Add(ctx context.Context, intf link) error
+	// Exists returns whether the input and output chains of intf exist.
+	Exists(ctx context.Context, intf link) (bool, error)
+	// Update replaces the rules of the input and output chains of intf.
+	Update(ctx context.Context, intf link) error
+	// UpdateSource replaces the rules of the source chain of intf, which allow its IP/MAC pairs.
+	UpdateSource(ctx context.Context, intf link) error
+	// Remove deletes chains and the rules that direct the traffic of intf to them. Missing ones are skipped,
+	// so that it succeeds for a partially removed security group. It returns the IP addresses allowed by the
+	// source chain, which are none if the chain does not exist.
+	Remove(ctx context.Context, intf link, chains []string) ([]net.IP, error)
+	// Chains returns the chains managed by the agent with their rules, and their interfaces by index. If intf is set,
+	// it returns the chains of the interface, and the rules of the shared chains that match the interface.
+	Chains(ctx context.Context, index *securityGroupIndex, intf string) ([]*pb.IPTablesChain, error)
+
+	// Init migrates the chains left by older versions, and returns the interfaces of the existing security groups.
+	Init(ctx context.Context, records []*pb.AddSecurityGroupRequest) ([]string, error)
+	// Verify repairs the shared chains and the hooks, and returns the interfaces of intfs whose chains differ from them.
+	Verify(ctx context.Context, intfs []link) ([]securityGroupRepair, []string, error)
+	// Repair creates the missing chains and rules of intf, and refills its drifted chains.
+	Repair(ctx context.Context, intf link) ([]securityGroupRepair, error)
+
+	// SetRemoteGroupMembers replaces the members of the set of group at once, creating the set if missing.
+	SetRemoteGroupMembers(ctx context.Context, group string, members []net.IP) error
+	// DeleteRemoteGroup deletes the set of group. It returns errRemoteGroupNotFound or errRemoteGroupInUse.
+	DeleteRemoteGroup(ctx context.Context, group string) error
+	// RemoteGroups returns the members of every remote group.
+	RemoteGroups(ctx context.Context) (map[string][]net.IP, error)
+}
+
+// newFirewallBackend returns the backend of name.
+func newFirewallBackend(name string, logger *zap.Logger) (firewallBackend, error) {
+	switch name {
+	case firewallBackendIPTables:
+		return newIPTablesBackend(newTracedIPTables, hostIPSet{}, logger), nil
+	case firewallBackendNFTables:
+		return newNFTablesBackend(newTracedNFTables, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown firewall backend %q", name)
+	}
+}
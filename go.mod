@@ -6,16 +6,24 @@ require (
 	github.com/coreos/go-iptables v0.4.5
 	github.com/digitalocean/go-libvirt v0.0.0-20200810224808-b9c702499bf7
 	github.com/go-test/deep v1.0.7
-	github.com/golang/protobuf v1.4.2
+	github.com/google/nftables v0.0.0-20220808154552-2eca00135732
+	github.com/golang/protobuf v1.5.2
 	github.com/grpc-ecosystem/go-grpc-middleware v1.2.1
 	github.com/lovi-cloud/go-os-brick v0.2.0
 	github.com/lovi-cloud/satelit v0.0.1
+	github.com/prometheus/client_golang v1.9.0
 	github.com/vishvananda/netlink v1.1.0
+	go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc v0.25.0
+	go.opentelemetry.io/otel v1.0.1
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc v1.0.1
+	go.opentelemetry.io/otel/sdk v1.0.1
+	go.opentelemetry.io/otel/trace v1.0.1
 	go.uber.org/zap v1.16.0
 	go.universe.tf/netboot v0.0.0-20200701170418-ddb47796bc4c
 	golang.org/x/sync v0.0.0-20200625203802-6e8e738ad208
-	google.golang.org/grpc v1.31.1
-	google.golang.org/protobuf v1.25.0
+	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013
+	google.golang.org/grpc v1.41.0
+	google.golang.org/protobuf v1.27.1
 	gopkg.in/yaml.v2 v2.3.0
 )
 
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/lovi-cloud/teleskop/dhcp"
+	"github.com/lovi-cloud/teleskop/metadata"
+)
+
+const (
+	defaultHealthCheckInterval = 10 * time.Second
+	defaultHealthProbeTimeout  = 5 * time.Second
+
+	healthServiceLibvirt  = "teleskop.libvirt"
+	healthServiceSatelit  = "teleskop.satelit"
+	healthServiceDHCP     = "teleskop.dhcp"
+	healthServiceMetadata = "teleskop.metadata"
+	healthServiceIPTables = "teleskop.iptables"
+	healthServiceNFTables = "teleskop.nftables"
+)
+
+// healthProbe checks a dependency of teleskop.
+// The overall status becomes NOT_SERVING when a critical probe fails.
+type healthProbe struct {
+	service  string
+	critical bool
+	check    func(ctx context.Context) error
+}
+
+// healthChecker periodically runs probes and publishes the result to the grpc.health.v1 service.
+type healthChecker struct {
+	server   *health.Server
+	probes   []healthProbe
+	interval time.Duration
+	timeout  time.Duration
+	logger   *zap.Logger
+
+	failing map[string]bool
+}
+
+// newHealthChecker returns a new healthChecker. Every service is NOT_SERVING until the first check,
+// as health.NewServer reports the overall status as SERVING from the start.
+func newHealthChecker(server *health.Server, interval time.Duration, logger *zap.Logger, probes ...healthProbe) *healthChecker {
+	server.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	for _, probe := range probes {
+		server.SetServingStatus(probe.service, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+
+	return &healthChecker{
+		server:   server,
+		probes:   probes,
+		interval: interval,
+		timeout:  defaultHealthProbeTimeout,
+		logger:   logger.Named("health"),
+		failing:  map[string]bool{},
+	}
+}
+
+// Run checks the probes every interval until ctx is done.
+func (h *healthChecker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		h.Check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check runs every probe once and updates the serving status.
+func (h *healthChecker) Check(ctx context.Context) {
+	overall := healthpb.HealthCheckResponse_SERVING
+	for _, probe := range h.probes {
+		status := healthpb.HealthCheckResponse_SERVING
+		err := h.runProbe(ctx, probe)
+		if err != nil {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+			if probe.critical {
+				overall = healthpb.HealthCheckResponse_NOT_SERVING
+			}
+		}
+
+		switch {
+		case err != nil && !h.failing[probe.service]:
+			h.logger.Warn("health check failed", zap.String("service", probe.service), zap.Bool("critical", probe.critical), zap.Error(err))
+		case err == nil && h.failing[probe.service]:
+			h.logger.Info("health check recovered", zap.String("service", probe.service))
+		}
+		h.failing[probe.service] = err != nil
+
+		h.server.SetServingStatus(probe.service, status)
+	}
+
+	h.server.SetServingStatus("", overall)
+}
+
+// runProbe runs the probe with the timeout. A probe that does not return in time, such as the one of
+// a wedged libvirtd, is abandoned and reported as failed, so that it does not stop the other checks.
+func (h *healthChecker) runProbe(ctx context.Context, probe healthProbe) error {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- probe.check(ctx)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("health check timed out: %w", ctx.Err())
+	}
+}
+
+func libvirtProbe(conn *libvirtConnection) healthProbe {
+	return healthProbe{
+		service:  healthServiceLibvirt,
+		critical: true,
+		check: func(ctx context.Context) error {
+			client, err := conn.Client()
+			if err != nil {
+				return err
+			}
+			if _, err := client.ConnectGetLibVersion(); err != nil {
+				return fmt.Errorf("failed to get libvirtd version: %w", err)
+			}
+			return nil
+		},
+	}
+}
+
+func satelitProbe(conn *grpc.ClientConn) healthProbe {
+	return healthProbe{
+		service:  healthServiceSatelit,
+		critical: true,
+		check: func(ctx context.Context) error {
+			switch state := conn.GetState(); state {
+			case connectivity.Ready, connectivity.Idle:
+				return nil
+			default:
+				return fmt.Errorf("satelit connection is %s", state)
+			}
+		},
+	}
+}
+
+func dhcpProbe(server *dhcp.Server) healthProbe {
+	return healthProbe{
+		service:  healthServiceDHCP,
+		critical: false,
+		check: func(ctx context.Context) error {
+			if !server.Listening() {
+				return fmt.Errorf("dhcp server is not listening")
+			}
+			return nil
+		},
+	}
+}
+
+func metadataProbe(server *metadata.Server) healthProbe {
+	return healthProbe{
+		service:  healthServiceMetadata,
+		critical: false,
+		check: func(ctx context.Context) error {
+			if !server.Listening() {
+				return fmt.Errorf("metadata server is not listening")
+			}
+			return nil
+		},
+	}
+}
+
+// firewallProbe checks the firewall backend, as the service named after the backend.
+func firewallProbe(firewall firewallBackend) healthProbe {
+	service := healthServiceIPTables
+	if firewall.Name() == firewallBackendNFTables {
+		service = healthServiceNFTables
+	}
+	return healthProbe{
+		service:  service,
+		critical: true,
+		check:    firewall.Check,
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestHealthCheckerCheck(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	ng := func(ctx context.Context) error { return fmt.Errorf("down") }
+	// wedged ignores ctx like a call to a hung libvirtd
+	release := make(chan struct{})
+	defer close(release)
+	wedged := func(ctx context.Context) error {
+		<-release
+		return nil
+	}
+
+	tests := []struct {
+		name   string
+		probes []healthProbe
+		want   map[string]healthpb.HealthCheckResponse_ServingStatus
+	}{
+		{
+			name: "all probes pass",
+			probes: []healthProbe{
+				{service: healthServiceLibvirt, critical: true, check: ok},
+				{service: healthServiceDHCP, critical: false, check: ok},
+			},
+			want: map[string]healthpb.HealthCheckResponse_ServingStatus{
+				"":                   healthpb.HealthCheckResponse_SERVING,
+				healthServiceLibvirt: healthpb.HealthCheckResponse_SERVING,
+				healthServiceDHCP:    healthpb.HealthCheckResponse_SERVING,
+			},
+		},
+		{
+			name: "non-critical probe fails",
+			probes: []healthProbe{
+				{service: healthServiceLibvirt, critical: true, check: ok},
+				{service: healthServiceDHCP, critical: false, check: ng},
+			},
+			want: map[string]healthpb.HealthCheckResponse_ServingStatus{
+				"":                   healthpb.HealthCheckResponse_SERVING,
+				healthServiceLibvirt: healthpb.HealthCheckResponse_SERVING,
+				healthServiceDHCP:    healthpb.HealthCheckResponse_NOT_SERVING,
+			},
+		},
+		{
+			name: "critical probe fails",
+			probes: []healthProbe{
+				{service: healthServiceLibvirt, critical: true, check: ng},
+				{service: healthServiceDHCP, critical: false, check: ok},
+			},
+			want: map[string]healthpb.HealthCheckResponse_ServingStatus{
+				"":                   healthpb.HealthCheckResponse_NOT_SERVING,
+				healthServiceLibvirt: healthpb.HealthCheckResponse_NOT_SERVING,
+				healthServiceDHCP:    healthpb.HealthCheckResponse_SERVING,
+			},
+		},
+		{
+			name: "critical probe times out",
+			probes: []healthProbe{
+				{service: healthServiceLibvirt, critical: true, check: wedged},
+				{service: healthServiceDHCP, critical: false, check: ok},
+			},
+			want: map[string]healthpb.HealthCheckResponse_ServingStatus{
+				"":                   healthpb.HealthCheckResponse_NOT_SERVING,
+				healthServiceLibvirt: healthpb.HealthCheckResponse_NOT_SERVING,
+				healthServiceDHCP:    healthpb.HealthCheckResponse_SERVING,
+			},
+		},
+	}
+	for _, test := range tests {
+		server := health.NewServer()
+		checker := newHealthChecker(server, defaultHealthCheckInterval, zap.NewNop(), test.probes...)
+		checker.timeout = 50 * time.Millisecond
+		checker.Check(context.Background())
+
+		for service, want := range test.want {
+			resp, err := server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+			if err != nil {
+				t.Fatalf("%s: should not be error for %q but: %+v", test.name, service, err)
+			}
+			if resp.Status != want {
+				t.Fatalf("%s: want %s for %q, but %s", test.name, want, service, resp.Status)
+			}
+		}
+	}
+}
+
+func TestHealthCheckerInitialStatus(t *testing.T) {
+	server := health.NewServer()
+	newHealthChecker(server, defaultHealthCheckInterval, zap.NewNop(),
+		healthProbe{service: healthServiceLibvirt, critical: true, check: func(ctx context.Context) error { return nil }},
+	)
+
+	for _, service := range []string{"", healthServiceLibvirt} {
+		resp, err := server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("should not be error for %q but: %+v", service, err)
+		}
+		if resp.Status != healthpb.HealthCheckResponse_NOT_SERVING {
+			t.Errorf("want %s for %q before the first check, but %s", healthpb.HealthCheckResponse_NOT_SERVING, service, resp.Status)
+		}
+	}
+}
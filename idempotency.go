@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"sync"
+	"time"
+
+	grpc_ctxtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultIdempotencyWindow = 10 * time.Minute
+)
+
+// idempotentRequest is a request that is executed only once for the same request id.
+type idempotentRequest interface {
+	proto.Message
+	GetRequestId() string
+}
+
+// idempotencyCache replays the response of a succeeded request to the retries with the same request id.
+// Failed requests and requests without a response are not cached, so that they can be retried.
+type idempotencyCache struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*idempotencyEntry
+}
+
+type idempotencyEntry struct {
+	digest  []byte
+	done    chan struct{}
+	resp    interface{}
+	err     error
+	expires time.Time
+}
+
+func newIdempotencyCache(window time.Duration) *idempotencyCache {
+	return &idempotencyCache{
+		window:  window,
+		entries: map[string]*idempotencyEntry{},
+	}
+}
+
+// UnaryServerInterceptor returns a new unary server interceptor that deduplicates requests by request id.
+func (c *idempotencyCache) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		r, ok := req.(idempotentRequest)
+		if !ok || r.GetRequestId() == "" {
+			return handler(ctx, req)
+		}
+		tags := grpc_ctxtags.Extract(ctx)
+		tags.Set("grpc.request.request_id", r.GetRequestId())
+
+		digest, err := requestDigest(r)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to calculate request digest: %+v", err)
+		}
+		key := info.FullMethod + "/" + r.GetRequestId()
+
+		for {
+			c.mu.Lock()
+			c.expire(time.Now())
+			e, ok := c.entries[key]
+			if !ok {
+				e = &idempotencyEntry{
+					digest: digest,
+					done:   make(chan struct{}),
+				}
+				c.entries[key] = e
+				c.mu.Unlock()
+
+				resp, err := handler(ctx, req)
+
+				c.mu.Lock()
+				e.resp, e.err = resp, err
+				if err != nil || resp == nil {
+					// failed requests and requests without a response to replay are not cached
+					delete(c.entries, key)
+				} else {
+					e.expires = time.Now().Add(c.window)
+				}
+				close(e.done)
+				c.mu.Unlock()
+				return resp, err
+			}
+			c.mu.Unlock()
+
+			if !bytes.Equal(e.digest, digest) {
+				return nil, status.Errorf(codes.InvalidArgument, "request_id %s is already used by another request", r.GetRequestId())
+			}
+
+			// wait for the first request if it is in progress
+			select {
+			case <-e.done:
+			case <-ctx.Done():
+				return nil, status.Errorf(contextErrorCode(ctx.Err()), "failed to wait for the request with the same request_id: %+v", ctx.Err())
+			}
+			if e.err != nil {
+				if isContextError(e.err) {
+					// the caller of the first request went away, which says nothing about this one,
+					// so run the handler again for this caller
+					continue
+				}
+				return nil, e.err
+			}
+			if e.resp == nil {
+				// the first request was not cached, so run the handler again for this caller
+				continue
+			}
+			tags.Set("grpc.request.replayed", true)
+			return proto.Clone(e.resp.(proto.Message)), nil
+		}
+	}
+}
+
+// expire removes the entries of finished requests that are older than the window.
+func (c *idempotencyCache) expire(now time.Time) {
+	for key, e := range c.entries {
+		if !e.expires.IsZero() && now.After(e.expires) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// isContextError reports whether err is caused by the cancellation or the deadline of a context.
+func isContextError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.Canceled, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// requestDigest returns the digest of r to detect reuse of request ids by different requests.
+func requestDigest(r proto.Message) ([]byte, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(b)
+	return sum[:], nil
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+func TestIdempotencyCache(t *testing.T) {
+	first := &pb.AddBridgeRequest{Name: "br1000", RequestId: "req-1"}
+
+	tests := []struct {
+		name      string
+		req       interface{}
+		err       error
+		want      codes.Code
+		wantCalls int32
+	}{
+		{
+			name:      "retry is replayed",
+			req:       &pb.AddBridgeRequest{Name: "br1000", RequestId: "req-1"},
+			want:      codes.OK,
+			wantCalls: 1,
+		},
+		{
+			name:      "request id is reused by another request",
+			req:       &pb.AddBridgeRequest{Name: "br1001", RequestId: "req-1"},
+			want:      codes.InvalidArgument,
+			wantCalls: 1,
+		},
+		{
+			name:      "different request id is executed",
+			req:       &pb.AddBridgeRequest{Name: "br1000", RequestId: "req-2"},
+			want:      codes.OK,
+			wantCalls: 2,
+		},
+		{
+			name:      "request without request id is executed",
+			req:       &pb.AddBridgeRequest{Name: "br1000"},
+			want:      codes.OK,
+			wantCalls: 2,
+		},
+		{
+			name:      "failed request is executed again",
+			req:       &pb.AddBridgeRequest{Name: "br1000", RequestId: "req-1"},
+			err:       status.Errorf(codes.Internal, "failed to define network"),
+			want:      codes.OK,
+			wantCalls: 2,
+		},
+	}
+	for _, test := range tests {
+		var calls int32
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			n := atomic.AddInt32(&calls, 1)
+			if n == 1 && test.err != nil {
+				return nil, test.err
+			}
+			return &pb.AddBridgeResponse{}, nil
+		}
+		interceptor := newIdempotencyCache(time.Minute).UnaryServerInterceptor()
+		info := &grpc.UnaryServerInfo{FullMethod: "/agent.Agent/AddBridge"}
+
+		_, err := interceptor(context.Background(), first, info, handler)
+		if test.err == nil && err != nil {
+			t.Fatalf("%s: should not be error but: %+v", test.name, err)
+		}
+		_, err = interceptor(context.Background(), test.req, info, handler)
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %s", test.name, test.want, got)
+		}
+		if calls != test.wantCalls {
+			t.Errorf("%s: want %d calls, but got %d", test.name, test.wantCalls, calls)
+		}
+	}
+}
+
+func TestIdempotencyCacheConcurrent(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return &pb.ConnectBlockDeviceResponse{DeviceName: "/dev/dm-0"}, nil
+	}
+	interceptor := newIdempotencyCache(time.Minute).UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/agent.Agent/ConnectBlockDevice"}
+	req := &pb.ConnectBlockDeviceRequest{PortalAddresses: []string{"192.0.2.10"}, HostLunId: 1, RequestId: "req-1"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			resp, err := interceptor(context.Background(), req, info, handler)
+			if err != nil {
+				t.Errorf("should not be error but: %+v", err)
+				return
+			}
+			if got := resp.(*pb.ConnectBlockDeviceResponse).DeviceName; got != "/dev/dm-0" {
+				t.Errorf("want /dev/dm-0, but got %s", got)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("want 1 call, but got %d", calls)
+	}
+}
+
+func TestIdempotencyCacheCanceledFirstRequest(t *testing.T) {
+	var calls int32
+	started := make(chan struct{})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+			<-ctx.Done()
+			return nil, status.Errorf(contextErrorCode(ctx.Err()), "failed to define network: %+v", ctx.Err())
+		}
+		return &pb.AddBridgeResponse{}, nil
+	}
+	interceptor := newIdempotencyCache(time.Minute).UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/agent.Agent/AddBridge"}
+	req := &pb.AddBridgeRequest{Name: "br1000", RequestId: "req-1"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := interceptor(ctx, req, info, handler)
+		firstErr <- err
+	}()
+	<-started
+
+	retryErr := make(chan error, 1)
+	go func() {
+		_, err := interceptor(context.Background(), req, info, handler)
+		retryErr <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	if got := status.Code(<-firstErr); got != codes.Canceled {
+		t.Errorf("want %s for the canceled request, but got %s", codes.Canceled, got)
+	}
+	if err := <-retryErr; err != nil {
+		t.Errorf("waiting retry should not inherit the cancellation but: %+v", err)
+	}
+	if calls != 2 {
+		t.Errorf("want 2 calls, but got %d", calls)
+	}
+}
+
+func TestIdempotencyCacheNilResponse(t *testing.T) {
+	var calls int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+			<-release
+			return nil, nil
+		}
+		return &pb.AddBridgeResponse{}, nil
+	}
+	interceptor := newIdempotencyCache(time.Minute).UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/agent.Agent/AddBridge"}
+	req := &pb.AddBridgeRequest{Name: "br1000", RequestId: "req-1"}
+
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := interceptor(context.Background(), req, info, handler)
+		firstErr <- err
+	}()
+	<-started
+
+	retryErr := make(chan error, 1)
+	go func() {
+		resp, err := interceptor(context.Background(), req, info, handler)
+		if err == nil && resp == nil {
+			err = status.Errorf(codes.Internal, "want response, but got nil")
+		}
+		retryErr <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	if err := <-firstErr; err != nil {
+		t.Errorf("should not be error but: %+v", err)
+	}
+	if err := <-retryErr; err != nil {
+		t.Errorf("waiting retry should run again but: %+v", err)
+	}
+	if calls != 2 {
+		t.Errorf("want 2 calls, but got %d", calls)
+	}
+}
+
+func TestIdempotencyCacheExpire(t *testing.T) {
+	var calls int32
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		return &pb.DeleteBridgeResponse{}, nil
+	}
+	c := newIdempotencyCache(time.Millisecond)
+	interceptor := c.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/agent.Agent/DeleteBridge"}
+	req := &pb.DeleteBridgeRequest{Name: "br1000", RequestId: "req-1"}
+
+	for i := 0; i < 2; i++ {
+		if _, err := interceptor(context.Background(), req, info, handler); err != nil {
+			t.Fatalf("should not be error but: %+v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if calls != 2 {
+		t.Errorf("want 2 calls, but got %d", calls)
+	}
+}
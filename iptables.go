@@ -2,18 +2,24 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"net"
+	"strconv"
+	"strings"
 
 	"github.com/coreos/go-iptables/iptables"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"go.uber.org/zap"
 
 	pb "github.com/lovi-cloud/teleskop/protoc/agent"
 )
 
 const (
 	maxChainNameLength = 29
+	// maxInterfaceNameLength is IFNAMSIZ without the terminating NUL
+	maxInterfaceNameLength = 15
+	chainNameHashLength    = 12
 
 	tableFilter = "filter"
 
@@ -27,6 +33,10 @@ const (
 	chainCallistoOUTPUPrefix  = "callisto-o"
 	chainCallistoSOURCEPrefix = "callisto-s"
 
+	// chains left by older versions, which replaced the chains of an interface by renaming them
+	chainCallistoNEWINPUTPrefix  = "callisto-ni"
+	chainCallistoNEWOUTPUTPrefix = "callisto-no"
+
 	actionACCEPT = "ACCEPT"
 	actionDROP   = "DROP"
 	actionRETURN = "RETURN"
@@ -51,6 +61,9 @@ var (
 	ruleSGFallback = []string{
 		"-m", "comment", "--comment", "Default drop rule for unmatched traffic.", "-j", actionDROP,
 	}
+	ruleSG = []string{
+		"-j", actionACCEPT,
+	}
 	ruleINPUT = []string{
 		"-j", chainCallistoINPUT,
 	}
@@ -59,275 +72,647 @@ var (
 	}
 )
 
-type setupFunction func(ctx context.Context, client *iptables.IPTables) error
-type addFunction func(ctx context.Context, client *iptables.IPTables, intf link) error
+type setupFunction func(tx *iptablesTransaction)
+type addFunction func(tx *iptablesTransaction, intf link, family ipFamily)
 
-type link struct {
-	Name       string
-	IPAddress  net.IP
-	MACAddress net.HardwareAddr
+// jumpRule is a rule in chain that jumps to a chain of an interface.
+type jumpRule struct {
+	chain string
+	rule  []string
+	// position is where the rule is inserted in chain
+	position int
 }
 
-func (a *agent) GetIPTables(ctx context.Context, req *pb.GetIPTablesRequest) (*pb.GetIPTablesResponse, error) {
-	return &pb.GetIPTablesResponse{}, nil
+func (j jumpRule) target() string {
+	return j.rule[len(j.rule)-1]
 }
 
-func (a *agent) SetupDefaultSecurityGroup(ctx context.Context, req *pb.SetupDefaultSecurityGroupRequest) (*pb.SetupDefaultSecurityGroupResponse, error) {
-	client, err := iptables.New()
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create iptables client: %+v", err)
-	}
-
-	if err := setupDefaultSecurityGroup(ctx, client); err != nil {
-		return nil, err
-	}
-
-	return &pb.SetupDefaultSecurityGroupResponse{}, nil
+// withTarget returns j jumping to target instead.
+func (j jumpRule) withTarget(target string) jumpRule {
+	rule := append([]string(nil), j.rule...)
+	rule[len(rule)-1] = target
+	return jumpRule{chain: j.chain, rule: rule, position: j.position}
 }
 
-func (a *agent) AddSecurityGroup(ctx context.Context, req *pb.AddSecurityGroupRequest) (*pb.AddSecurityGroupResponse, error) {
-	client, err := iptables.New()
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create iptables client: %+v", err)
-	}
+// iptablesBackend applies security groups to the filter tables of iptables and ip6tables by iptables-restore,
+// and remote groups to ipsets. Bridged packets are filtered by physdev matches, which requires br_netfilter.
+// Every family has its own chains, which are changed in a transaction per family.
+type iptablesBackend struct {
+	newIPTables func(family ipFamily) (iptablesAPI, error)
+	ipset       ipsetAPI
+	logger      *zap.Logger
+}
 
-	ipAddr := net.ParseIP(req.IpAddress)
-	if ipAddr == nil {
-		return nil, status.Errorf(codes.InvalidArgument, "failed to parse request IP address: %s", "")
+func newIPTablesBackend(newIPTables func(family ipFamily) (iptablesAPI, error), ipset ipsetAPI, logger *zap.Logger) *iptablesBackend {
+	return &iptablesBackend{
+		newIPTables: newIPTables,
+		ipset:       ipset,
+		logger:      logger,
 	}
+}
 
-	macAddr, err := net.ParseMAC(req.MacAddress)
-	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, "failed to parse request MAC address: %+v", err)
+// getIPTablesCommand returns the command that manages the tables of family.
+func getIPTablesCommand(family ipFamily) string {
+	if family == ipFamilyIPv6 {
+		return "ip6tables"
 	}
+	return "iptables"
+}
 
-	err = addSecurityGroup(ctx, client, link{
-		Name:       req.Interface,
-		IPAddress:  ipAddr,
-		MACAddress: macAddr,
-	})
+func (b *iptablesBackend) client(family ipFamily) (iptablesAPI, error) {
+	client, err := b.newIPTables(family)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to add security group: %+v", err)
+		return nil, fmt.Errorf("failed to create %s client: %w", getIPTablesCommand(family), err)
 	}
+	return client, nil
+}
 
-	return &pb.AddSecurityGroupResponse{}, nil
+func (b *iptablesBackend) Name() string {
+	return firewallBackendIPTables
 }
 
-func setupDefaultSecurityGroup(ctx context.Context, client *iptables.IPTables) error {
-	for _, fn := range setupFunctions {
-		if err := fn(ctx, client); err != nil {
-			return err
+func (b *iptablesBackend) Check(ctx context.Context) error {
+	for _, protocol := range []iptables.Protocol{iptables.ProtocolIPv4, iptables.ProtocolIPv6} {
+		client, err := iptables.NewWithProtocol(protocol)
+		if err != nil {
+			return fmt.Errorf("failed to create iptables client: %w", err)
+		}
+		if _, err := client.ListChains(tableFilter); err != nil {
+			return fmt.Errorf("failed to list chains: %w", err)
 		}
 	}
 	return nil
 }
 
-func addSecurityGroup(ctx context.Context, client *iptables.IPTables, intf link) error {
-	for _, fn := range addFunctions {
-		if err := fn(ctx, client, intf); err != nil {
+// Setup creates the shared chains and the hooks of the families whose shared chains are missing, so that
+// it can be retried after a family failed. It fails if the chains of every family exist.
+func (b *iptablesBackend) Setup(ctx context.Context) error {
+	setup := false
+	for _, family := range ipFamilies {
+		client, err := b.client(family)
+		if err != nil {
 			return err
 		}
+		exists, err := client.ChainExists(ctx, tableFilter, chainCallistoSG)
+		if err != nil {
+			return fmt.Errorf("failed to check %s chain: %w", chainCallistoSG, err)
+		}
+		if exists {
+			continue
+		}
+		if err := setupIPTables(ctx, client); err != nil {
+			return fmt.Errorf("failed to set up %s chains: %w", family, err)
+		}
+		setup = true
+	}
+	if !setup {
+		return fmt.Errorf("default security group is already set up")
 	}
 	return nil
 }
 
-func setupChain(ctx context.Context, client *iptables.IPTables, name string) error {
-	if err := client.NewChain(tableFilter, name); err != nil {
-		return status.Errorf(codes.Internal, "failed to create new chain: %+v", err)
+// setupIPTables creates the shared chains and the hooks in a transaction.
+func setupIPTables(ctx context.Context, client iptablesAPI) error {
+	tx := newIPTablesTransaction(tableFilter)
+	for _, fn := range setupFunctions {
+		fn(tx)
+	}
+	return tx.commit(ctx, client)
+}
+
+// Add creates the chains of intf and the jump rules to them in a transaction per family, so that
+// a failure leaves neither half-filled chains nor changes in the shared chains. If a family fails,
+// the chains added to the other families are removed.
+func (b *iptablesBackend) Add(ctx context.Context, intf link) error {
+	if err := b.createRemoteGroups(ctx, intf.IngressRules, intf.EgressRules); err != nil {
+		return fmt.Errorf("failed to create remote groups: %w", err)
+	}
+
+	for i, family := range ipFamilies {
+		if err := b.addFamily(ctx, family, intf); err != nil {
+			for _, added := range ipFamilies[:i] {
+				if _, err := b.removeFamily(ctx, added, intf, getChainNames(intf)); err != nil {
+					b.logger.Warn("failed to remove chains of a security group that failed to be added",
+						zap.String("interface", intf.Name), zap.Stringer("family", added), zap.Error(err))
+				}
+			}
+			return fmt.Errorf("failed to add %s chains: %w", family, err)
+		}
 	}
 	return nil
 }
 
-func setupSGFallbackChain(ctx context.Context, client *iptables.IPTables) error {
-	if err := setupChain(ctx, client, chainCallistoSGFallback); err != nil {
+func (b *iptablesBackend) addFamily(ctx context.Context, family ipFamily, intf link) error {
+	client, err := b.client(family)
+	if err != nil {
 		return err
 	}
 
-	if err := client.AppendUnique(tableFilter, chainCallistoSGFallback, ruleSGFallback...); err != nil {
-		return status.Errorf(codes.Internal, "failed to append new rule: %+v", err)
+	tx := newIPTablesTransaction(tableFilter)
+	for _, fn := range addFunctions {
+		fn(tx, intf, family)
 	}
+	return tx.commit(ctx, client)
+}
 
-	return nil
+// Exists returns whether the IPv4 chains of intf exist. The IPv6 chains of security groups added by older
+// versions are missing until the verifier creates them.
+func (b *iptablesBackend) Exists(ctx context.Context, intf link) (bool, error) {
+	client, err := b.client(ipFamilyIPv4)
+	if err != nil {
+		return false, err
+	}
+
+	for _, chain := range []string{getINPUTChainName(intf), getOUTPUTChainName(intf)} {
+		exists, err := client.ChainExists(ctx, tableFilter, chain)
+		if err != nil {
+			return false, fmt.Errorf("failed to check %s chain: %w", chain, err)
+		}
+		if !exists {
+			return false, nil
+		}
+	}
+	return true, nil
 }
 
-func setupSGChain(ctx context.Context, client *iptables.IPTables) error {
-	if err := setupChain(ctx, client, chainCallistoSG); err != nil {
-		return err
+// Update refills the input and output chains of intf with the new rules in a transaction per family,
+// so that every packet is filtered by either the old or the new rules. Conntrack entries are kept,
+// so established connections are not dropped. Families without the chains are skipped, and if a family
+// fails, the verifier restores the recorded rules of the families updated before it.
+func (b *iptablesBackend) Update(ctx context.Context, intf link) error {
+	if err := b.createRemoteGroups(ctx, intf.IngressRules, intf.EgressRules); err != nil {
+		return fmt.Errorf("failed to create remote groups: %w", err)
 	}
 
-	if err := client.AppendUnique(tableFilter, chainCallistoSG,
-		"-j", actionACCEPT,
-	); err != nil {
-		return status.Errorf(codes.Internal, "failed to append new rule: %+v", err)
+	for _, family := range ipFamilies {
+		client, err := b.client(family)
+		if err != nil {
+			return err
+		}
+		exists, err := client.ChainExists(ctx, tableFilter, getINPUTChainName(intf))
+		if err != nil {
+			return fmt.Errorf("failed to check %s chain: %w", getINPUTChainName(intf), err)
+		}
+		if !exists {
+			continue
+		}
+
+		tx := newIPTablesTransaction(tableFilter)
+		replaceSGChain(tx, getINPUTChainName(intf), getINPUTSGChainRules(intf, family))
+		replaceSGChain(tx, getOUTPUTChainName(intf), getOUTPUTSGChainRules(intf, family))
+		if err := tx.commit(ctx, client); err != nil {
+			return fmt.Errorf("failed to update %s chains: %w", family, err)
+		}
 	}
+	return nil
+}
 
+// UpdateSource refills the source chain of intf in a transaction per family. The input and output chains,
+// and the connections allowed by them, are kept.
+func (b *iptablesBackend) UpdateSource(ctx context.Context, intf link) error {
+	for _, family := range ipFamilies {
+		client, err := b.client(family)
+		if err != nil {
+			return err
+		}
+		exists, err := client.ChainExists(ctx, tableFilter, getSOURCEChainName(intf))
+		if err != nil {
+			return fmt.Errorf("failed to check %s chain: %w", getSOURCEChainName(intf), err)
+		}
+		if !exists {
+			continue
+		}
+
+		tx := newIPTablesTransaction(tableFilter)
+		replaceSGChain(tx, getSOURCEChainName(intf), getSOURCESGChainRules(intf, family))
+		if err := tx.commit(ctx, client); err != nil {
+			return fmt.Errorf("failed to update %s source chain: %w", family, err)
+		}
+	}
 	return nil
 }
 
-func setupINPUT(ctx context.Context, client *iptables.IPTables) error {
-	if err := setupChain(ctx, client, chainCallistoINPUT); err != nil {
-		return err
+// Remove deletes the jump rules of intf and chains in a transaction per family. The chains left by older
+// versions are deleted with them.
+func (b *iptablesBackend) Remove(ctx context.Context, intf link, chains []string) ([]net.IP, error) {
+	var ipAddrs []net.IP
+	for _, family := range ipFamilies {
+		removed, err := b.removeFamily(ctx, family, intf, chains)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove %s chains: %w", family, err)
+		}
+		ipAddrs = append(ipAddrs, removed...)
 	}
+	return ipAddrs, nil
+}
 
-	if err := client.AppendUnique(tableFilter, chainINPUT, ruleINPUT...); err != nil {
-		return status.Errorf(codes.Internal, "failed to append new rule: %+v", err)
+// removeFamily deletes the jump rules of intf and chains of family in a transaction, and returns
+// the IP addresses allowed by the source chain.
+func (b *iptablesBackend) removeFamily(ctx context.Context, family ipFamily, intf link, chains []string) ([]net.IP, error) {
+	client, err := b.client(family)
+	if err != nil {
+		return nil, err
+	}
+	saved, err := saveIPTables(ctx, client)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
-}
+	jumps := getJumpRules(intf)
+	for _, chain := range [][2]string{
+		{getINPUTChainName(intf), getNEWINPUTChainName(intf)},
+		{getOUTPUTChainName(intf), getNEWOUTPUTChainName(intf)},
+	} {
+		for _, jump := range getJumpRulesTo(jumps, chain[0]) {
+			jumps = append(jumps, jump.withTarget(chain[1]))
+		}
+	}
 
-func setupFORWARD(ctx context.Context, client *iptables.IPTables) error {
-	if err := setupChain(ctx, client, chainCallistoFORWARD); err != nil {
-		return err
+	tx := newIPTablesTransaction(tableFilter)
+	for _, jump := range jumps {
+		exists, err := containsRule(ctx, client, saved, jump.chain, jump.rule)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			tx.deleteRule(jump.chain, jump.rule...)
+		}
 	}
 
-	if err := client.AppendUnique(tableFilter, chainFORWARD, ruleFORWARD...); err != nil {
-		return status.Errorf(codes.Internal, "failed to append new rule: %+v", err)
+	var existing []string
+	for _, chain := range append(chains, getNEWINPUTChainName(intf), getNEWOUTPUTChainName(intf)) {
+		if _, ok := saved[chain]; ok {
+			existing = append(existing, chain)
+		}
+	}
+	// all chains are flushed before deleting them, because the output chains jump to the source chain
+	for _, chain := range existing {
+		tx.flushChain(chain)
+	}
+	for _, chain := range existing {
+		tx.deleteChain(chain)
 	}
 
-	return nil
+	if err := tx.commit(ctx, client); err != nil {
+		return nil, err
+	}
+	return getSourceIPAddresses(saved[getSOURCEChainName(intf)]), nil
 }
 
-func addSOURCESGRules(ctx context.Context, client *iptables.IPTables, intf link) error {
-	var err error
+// Chains returns the chains of the filter tables whose names start with callisto-, IPv4 first.
+func (b *iptablesBackend) Chains(ctx context.Context, index *securityGroupIndex, intfName string) ([]*pb.IPTablesChain, error) {
+	var chains []*pb.IPTablesChain
+	for _, family := range ipFamilies {
+		client, err := b.client(family)
+		if err != nil {
+			return nil, err
+		}
+		c, err := listSecurityGroupChains(ctx, client, family, index, intfName)
+		if err != nil {
+			return nil, err
+		}
+		chains = append(chains, c...)
+	}
+	return chains, nil
+}
 
-	chain := getSOURCEChainName(intf)
-	if err := setupChain(ctx, client, chain); err != nil {
-		return err
+// listSecurityGroupChains returns the chains of the filter table of family whose names start with callisto-.
+func listSecurityGroupChains(ctx context.Context, client iptablesAPI, family ipFamily, index *securityGroupIndex, intfName string) ([]*pb.IPTablesChain, error) {
+	names, err := client.ListChains(ctx, tableFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chains: %w", err)
 	}
 
-	rules := [][]string{
-		{"-s", fmt.Sprintf("%s/32", intf.IPAddress), "-m", "mac", "--mac-source", intf.MACAddress.String(), "-m", "comment", "--comment", "Allow traffic from defined IP/MAC pairs.", "-j", actionRETURN},
-		{"-m", "comment", "--comment", "Drop traffic without an IP/MAC allow rule.", "-j", actionDROP},
+	shared := map[string]bool{}
+	for _, chain := range sharedChains {
+		shared[chain] = true
 	}
-	for i, rule := range rules {
-		err = client.Insert(tableFilter, chain, i+1, rule...)
+	intf := link{Name: intfName}
+	own := map[string]bool{
+		getNEWINPUTChainName(intf):  true,
+		getNEWOUTPUTChainName(intf): true,
+	}
+	for _, chain := range index.chainsOf(intfName) {
+		own[chain] = true
+	}
+
+	var chains []*pb.IPTablesChain
+	for _, name := range names {
+		if !strings.HasPrefix(name, "callisto-") {
+			continue
+		}
+		if intfName != "" && !shared[name] && !own[name] {
+			continue
+		}
+
+		lines, err := client.ListWithCounters(ctx, tableFilter, name)
 		if err != nil {
-			client.ClearChain(tableFilter, chain)
-			client.DeleteChain(tableFilter, chain)
-			return status.Errorf(codes.Internal, "failed to append new %s rule: %+v", chain, err)
+			return nil, fmt.Errorf("failed to list %s chain: %w", name, err)
 		}
+		chain := &pb.IPTablesChain{Name: name, Interface: index.lookup(name), Family: family.String()}
+		for _, line := range lines {
+			if !strings.HasPrefix(line, "-A ") {
+				continue
+			}
+			_, rule, err := parseIPTablesRule(line)
+			if err != nil {
+				return nil, err
+			}
+			if intfName != "" && shared[name] && rule.PhysdevIn != intfName && rule.PhysdevOut != intfName {
+				continue
+			}
+			chain.Rules = append(chain.Rules, rule)
+		}
+		if intfName != "" && shared[name] && len(chain.Rules) == 0 {
+			continue
+		}
+		chains = append(chains, chain)
 	}
 
-	return nil
+	return chains, nil
 }
 
-func addINPUTSGRules(ctx context.Context, client *iptables.IPTables, intf link) error {
-	var err error
+// Init migrates the chains that older versions named by truncating long interface names. The interfaces
+// of the security groups are the recorded ones and the ones that the shared chains jump for. Older versions
+// had only IPv4 chains, so the shared IPv6 chains of a host set up by them are set up here, and the IPv6
+// chains of the recorded security groups are created by the verifier.
+func (b *iptablesBackend) Init(ctx context.Context, records []*pb.AddSecurityGroupRequest) ([]string, error) {
+	client, err := b.client(ipFamilyIPv4)
+	if err != nil {
+		return nil, err
+	}
+	saved, err := saveIPTables(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	interfaces, err := getSecurityGroupInterfaces(saved, records)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find security groups: %w", err)
+	}
 
-	chain := getINPUTChainName(intf)
-	if err := setupChain(ctx, client, chain); err != nil {
-		return err
+	if err := b.migrateSecurityGroupChains(ctx, client, saved, interfaces, records); err != nil {
+		return nil, err
 	}
 
-	rules := [][]string{
-		{"-m", "state", "--state", "RELATED,ESTABLISHED", "-m", "comment", "--comment", "Direct packets associated with a known session to the RETURN chain.", "-j", actionRETURN},
-		{"-p", "udp", "-m", "udp", "--sport", "67", "--dport", "68", "-j", actionRETURN},
-		{"-p", "tcp", "-m", "tcp", "-m", "multiport", "--dports", "1:65535", "-j", actionRETURN},
-		{"-p", "udp", "-m", "udp", "-m", "multiport", "--dports", "1:65535", "-j", actionRETURN},
-		{"-p", "icmp", "-j", actionRETURN},
-		{"-m", "comment", "--comment", "Send unmatched traffic to the fallback chain.", "-j", chainCallistoSGFallback},
-	}
-	for i, rule := range rules {
-		err = client.Insert(tableFilter, chain, i+1, rule...)
+	if _, ok := saved[chainCallistoSG]; ok {
+		client, err := b.client(ipFamilyIPv6)
+		if err != nil {
+			return nil, err
+		}
+		exists, err := client.ChainExists(ctx, tableFilter, chainCallistoSG)
 		if err != nil {
-			client.ClearChain(tableFilter, chain)
-			client.DeleteChain(tableFilter, chain)
-			return status.Errorf(codes.Internal, "failed to append new %s rule: %+v", chain, err)
+			return nil, fmt.Errorf("failed to check %s chain: %w", chainCallistoSG, err)
+		}
+		if !exists {
+			if err := setupIPTables(ctx, client); err != nil {
+				return nil, fmt.Errorf("failed to set up %s chains: %w", ipFamilyIPv6, err)
+			}
+			b.logger.Info("set up shared chains of the default security group", zap.Stringer("family", ipFamilyIPv6))
 		}
 	}
+	return interfaces, nil
+}
 
-	return nil
+// getSourceIPAddresses returns the IP addresses allowed by the saved rules of the source chain.
+func getSourceIPAddresses(rules []string) []net.IP {
+	var ipAddrs []net.IP
+	for _, rule := range rules {
+		fields := strings.Fields(rule)
+		for i := 0; i < len(fields)-1; i++ {
+			if fields[i] != "-s" {
+				continue
+			}
+			if ipAddr := parseSourceIPAddress(fields[i+1]); ipAddr != nil {
+				ipAddrs = append(ipAddrs, ipAddr)
+			}
+		}
+	}
+	return ipAddrs
 }
 
-func addOUTPUTSGRules(ctx context.Context, client *iptables.IPTables, intf link) error {
-	var err error
+// parseSourceIPAddress returns the IP address of source, or nil if source is a network or the unspecified
+// address, which are not addresses of the VM.
+func parseSourceIPAddress(source string) net.IP {
+	ipAddr := net.ParseIP(source)
+	if ip, n, err := net.ParseCIDR(source); err == nil {
+		if ones, bits := n.Mask.Size(); ones == bits {
+			ipAddr = ip
+		}
+	}
+	if ipAddr == nil || ipAddr.IsUnspecified() {
+		return nil
+	}
+	return ipAddr
+}
 
-	chain := getOUTPUTChainName(intf)
-	if err := setupChain(ctx, client, chain); err != nil {
-		return err
+func setupSGFallbackChain(tx *iptablesTransaction) {
+	tx.newChain(chainCallistoSGFallback)
+	tx.appendRule(chainCallistoSGFallback, ruleSGFallback...)
+}
+
+func setupSGChain(tx *iptablesTransaction) {
+	tx.newChain(chainCallistoSG)
+	tx.appendRule(chainCallistoSG, ruleSG...)
+}
+
+func setupINPUT(tx *iptablesTransaction) {
+	tx.newChain(chainCallistoINPUT)
+	tx.appendRule(chainINPUT, ruleINPUT...)
+}
+
+func setupFORWARD(tx *iptablesTransaction) {
+	tx.newChain(chainCallistoFORWARD)
+	tx.appendRule(chainFORWARD, ruleFORWARD...)
+}
+
+func addSOURCESGRules(tx *iptablesTransaction, intf link, family ipFamily) {
+	addSGChain(tx, getSOURCEChainName(intf), getSOURCESGChainRules(intf, family))
+}
+
+func addINPUTSGRules(tx *iptablesTransaction, intf link, family ipFamily) {
+	addSGChain(tx, getINPUTChainName(intf), getINPUTSGChainRules(intf, family))
+}
+
+func addOUTPUTSGRules(tx *iptablesTransaction, intf link, family ipFamily) {
+	addSGChain(tx, getOUTPUTChainName(intf), getOUTPUTSGChainRules(intf, family))
+}
+
+// addSGChain creates chain with rules.
+func addSGChain(tx *iptablesTransaction, chain string, rules [][]string) {
+	tx.newChain(chain)
+	for _, rule := range rules {
+		tx.appendRule(chain, rule...)
 	}
+}
 
-	rules := [][]string{
-		{"-p", "udp", "-m", "udp", "--sport", "68", "--dport", "67", "-m", "comment", "--comment", "Allow DHCP client traffic.", "-j", actionRETURN},
-		{"-j", getSOURCEChainName(intf)},
-		{"-p", "udp", "-m", "udp", "--sport", "67", "--dport", "68", "-m", "comment", "--comment", "Prevent DHCP Spoofing by VM.", "-j", actionDROP},
-		{"-m", "state", "--state", "RELATED,ESTABLISHED", "-m", "comment", "--comment", "Direct packets associated with a known session to the RETURN chain.", "-j", actionRETURN},
-		{"-p", "tcp", "-m", "tcp", "-m", "multiport", "--dports", "1:65535", "-j", actionRETURN},
-		{"-p", "udp", "-m", "udp", "-m", "multiport", "--dports", "1:65535", "-j", actionRETURN},
-		{"-p", "icmp", "-j", actionRETURN},
-		{"-j", actionRETURN},
-		{"-m", "comment", "--comment", "Send unmatched traffic to the fallback chain.", "-j", chainCallistoSGFallback},
-	}
-	for i, rule := range rules {
-		err = client.Insert(tableFilter, chain, i+1, rule...)
-		if err != nil {
-			client.ClearChain(tableFilter, chain)
-			client.DeleteChain(tableFilter, chain)
-			return status.Errorf(codes.Internal, "failed to append new %s rule: %+v", chain, err)
-		}
+// replaceSGChain replaces the rules of chain with rules.
+func replaceSGChain(tx *iptablesTransaction, chain string, rules [][]string) {
+	tx.flushChain(chain)
+	for _, rule := range rules {
+		tx.appendRule(chain, rule...)
 	}
+}
 
-	return nil
+func addSGRules(tx *iptablesTransaction, intf link, family ipFamily) {
+	for i, rule := range getSGRules(intf) {
+		tx.insertRule(chainCallistoSG, i+1, rule...)
+	}
 }
 
-func addSGRules(ctx context.Context, client *iptables.IPTables, intf link) error {
-	var err error
+func addINPUTRules(tx *iptablesTransaction, intf link, family ipFamily) {
+	for i, rule := range getINPUTRules(intf) {
+		tx.insertRule(chainCallistoFORWARD, i+1, rule...)
+	}
+}
 
-	chain := chainCallistoSG
-	rules := [][]string{
-		{"-m", "physdev", "--physdev-out", intf.Name, "--physdev-is-bridged", "-m", "comment", "--comment", "Jump to the VM specific chain.", "-j", getINPUTChainName(intf)},
-		{"-m", "physdev", "--physdev-in", intf.Name, "--physdev-is-bridged", "-m", "comment", "--comment", "Jump to the VM specific chain.", "-j", getOUTPUTChainName(intf)},
+func addFORWARDRules(tx *iptablesTransaction, intf link, family ipFamily) {
+	for i, rule := range getFORWARDRules(intf) {
+		tx.insertRule(chainCallistoFORWARD, i+1, rule...)
 	}
-	for i, rule := range rules {
-		err = client.Insert(tableFilter, chain, i+1, rule...)
-		if err != nil {
-			client.ClearChain(tableFilter, chain)
-			client.DeleteChain(tableFilter, chain)
-			return status.Errorf(codes.Internal, "failed to append new %s rule: %+v", chain, err)
+}
+
+// getSOURCESGChainRules returns the rules of the source chain of intf, which only allows the IP/MAC pair of the VM.
+// IPv6 traffic is also allowed from the link-local addresses and the unspecified address of duplicate address
+// detection with the MAC address, because the VM assigns them by itself.
+func getSOURCESGChainRules(intf link, family ipFamily) [][]string {
+	mac := intf.MACAddress.String()
+	var rules [][]string
+	switch family {
+	case ipFamilyIPv4:
+		rules = append(rules, []string{"-s", fmt.Sprintf("%s/32", intf.IPAddress), "-m", "mac", "--mac-source", mac, "-m", "comment", "--comment", "Allow traffic from defined IP/MAC pairs.", "-j", actionRETURN})
+		rules = append(rules, getAllowedAddressPairRules(intf, family)...)
+	case ipFamilyIPv6:
+		if intf.IPv6Address != nil {
+			rules = append(rules, []string{"-s", fmt.Sprintf("%s/128", intf.IPv6Address), "-m", "mac", "--mac-source", mac, "-m", "comment", "--comment", "Allow traffic from defined IP/MAC pairs.", "-j", actionRETURN})
 		}
+		rules = append(rules, getAllowedAddressPairRules(intf, family)...)
+		rules = append(rules,
+			[]string{"-s", ipv6LinkLocalNet.String(), "-m", "mac", "--mac-source", mac, "-m", "comment", "--comment", "Allow link-local traffic from the MAC address of the VM.", "-j", actionRETURN},
+			[]string{"-s", ipv6UnspecifiedNet.String(), "-p", "ipv6-icmp", "-m", "mac", "--mac-source", mac, "-m", "comment", "--comment", "Allow duplicate address detection.", "-j", actionRETURN},
+		)
 	}
-
-	return nil
+	return append(rules, []string{"-m", "comment", "--comment", "Drop traffic without an IP/MAC allow rule.", "-j", actionDROP})
 }
 
-func addINPUTRules(ctx context.Context, client *iptables.IPTables, intf link) error {
-	var err error
+// getAllowedAddressPairRules returns the rules that allow the allowed address pairs of family.
+func getAllowedAddressPairRules(intf link, family ipFamily) [][]string {
+	var rules [][]string
+	for _, pair := range intf.AllowedAddressPairs {
+		if pair.family() == family {
+			rules = append(rules, []string{"-s", pair.IPNet.String(), "-m", "mac", "--mac-source", pair.MACAddress.String(), "-m", "comment", "--comment", "Allow traffic from allowed address pairs.", "-j", actionRETURN})
+		}
+	}
+	return rules
+}
 
-	chain := chainCallistoFORWARD
+// getINPUTSGChainRules returns the rules of the input chain of intf, which filters traffic to the VM.
+func getINPUTSGChainRules(intf link, family ipFamily) [][]string {
 	rules := [][]string{
-		{"-m", "physdev", "--physdev-in", intf.Name, "--physdev-is-bridged", "-m", "comment", "--comment", "Direct incoming traffic from VM to the security group chain.", "-j", getOUTPUTChainName(intf)},
+		{"-m", "state", "--state", "RELATED,ESTABLISHED", "-m", "comment", "--comment", "Direct packets associated with a known session to the RETURN chain.", "-j", actionRETURN},
 	}
-	for i, rule := range rules {
-		err = client.Insert(tableFilter, chain, i+1, rule...)
-		if err != nil {
-			client.ClearChain(tableFilter, chain)
-			client.DeleteChain(tableFilter, chain)
-			return status.Errorf(codes.Internal, "failed to append new %s rule: %+v", chain, err)
+	switch family {
+	case ipFamilyIPv4:
+		rules = append(rules, []string{"-p", "udp", "-m", "udp", "--sport", "67", "--dport", "68", "-j", actionRETURN})
+	case ipFamilyIPv6:
+		rules = append(rules,
+			[]string{"-p", "udp", "-m", "udp", "--sport", "547", "--dport", "546", "-j", actionRETURN},
+			getICMPv6Rule(icmpv6TypeMLDQuery, "Allow multicast listener queries.", actionRETURN),
+			getICMPv6Rule(icmpv6TypeRouterAdvertisement, "Allow router advertisements.", actionRETURN),
+			getICMPv6Rule(icmpv6TypeNeighborSolicitation, "Allow neighbor discovery.", actionRETURN),
+			getICMPv6Rule(icmpv6TypeNeighborAdvertisement, "Allow neighbor discovery.", actionRETURN),
+		)
+	}
+	for _, rule := range intf.IngressRules {
+		if rule.appliesTo(family) {
+			rules = append(rules, rule.rulespec(directionIngress))
 		}
 	}
+	return append(rules, []string{"-m", "comment", "--comment", "Send unmatched traffic to the fallback chain.", "-j", chainCallistoSGFallback})
+}
 
-	return nil
+// getOUTPUTSGChainRules returns the rules of the output chain of intf, which filters traffic from the VM.
+// Router advertisements and DHCPv6 replies from the VM are dropped, so that it cannot take over the network.
+func getOUTPUTSGChainRules(intf link, family ipFamily) [][]string {
+	var rules [][]string
+	switch family {
+	case ipFamilyIPv4:
+		rules = [][]string{
+			{"-p", "udp", "-m", "udp", "--sport", "68", "--dport", "67", "-m", "comment", "--comment", "Allow DHCP client traffic.", "-j", actionRETURN},
+			{"-j", getSOURCEChainName(intf)},
+			{"-p", "udp", "-m", "udp", "--sport", "67", "--dport", "68", "-m", "comment", "--comment", "Prevent DHCP Spoofing by VM.", "-j", actionDROP},
+			{"-m", "state", "--state", "RELATED,ESTABLISHED", "-m", "comment", "--comment", "Direct packets associated with a known session to the RETURN chain.", "-j", actionRETURN},
+		}
+	case ipFamilyIPv6:
+		// DHCPv6 clients send from link-local addresses, which the source chain allows
+		rules = [][]string{
+			{"-j", getSOURCEChainName(intf)},
+			getICMPv6Rule(icmpv6TypeRouterAdvertisement, "Prevent router advertisements by VM.", actionDROP),
+			{"-p", "udp", "-m", "udp", "--sport", "547", "--dport", "546", "-m", "comment", "--comment", "Prevent DHCPv6 Spoofing by VM.", "-j", actionDROP},
+			{"-m", "state", "--state", "RELATED,ESTABLISHED", "-m", "comment", "--comment", "Direct packets associated with a known session to the RETURN chain.", "-j", actionRETURN},
+			{"-p", "udp", "-m", "udp", "--sport", "546", "--dport", "547", "-m", "comment", "--comment", "Allow DHCPv6 client traffic.", "-j", actionRETURN},
+			getICMPv6Rule(icmpv6TypeRouterSolicitation, "Allow router solicitations.", actionRETURN),
+			getICMPv6Rule(icmpv6TypeNeighborSolicitation, "Allow neighbor discovery.", actionRETURN),
+			getICMPv6Rule(icmpv6TypeNeighborAdvertisement, "Allow neighbor discovery.", actionRETURN),
+			getICMPv6Rule(icmpv6TypeMLDv2Report, "Allow multicast listener reports.", actionRETURN),
+		}
+	}
+	for _, rule := range intf.EgressRules {
+		if rule.appliesTo(family) {
+			rules = append(rules, rule.rulespec(directionEgress))
+		}
+	}
+	return append(rules, []string{"-m", "comment", "--comment", "Send unmatched traffic to the fallback chain.", "-j", chainCallistoSGFallback})
 }
 
-func addFORWARDRules(ctx context.Context, client *iptables.IPTables, intf link) error {
-	var err error
+// getICMPv6Rule returns the rule that applies target to the ICMPv6 messages of icmpType.
+func getICMPv6Rule(icmpType int, comment, target string) []string {
+	return []string{"-p", "ipv6-icmp", "-m", "icmp6", "--icmpv6-type", strconv.Itoa(icmpType), "-m", "comment", "--comment", comment, "-j", target}
+}
 
-	chain := chainCallistoFORWARD
-	rules := [][]string{
-		{"-m", "physdev", "--physdev-out", intf.Name, "--physdev-is-bridged", "-m", "comment", "--comment", "Direct traffic from the VM interface to the security group chain.", "-j", chainCallistoSG},
-		{"-m", "physdev", "--physdev-in", intf.Name, "--physdev-is-bridged", "-m", "comment", "--comment", "Direct traffic from the VM interface to the security group chain.", "-j", chainCallistoSG},
+// getJumpRules returns the rules in callisto-sg-chain and callisto-FORWARD that jump to the chains of intf.
+func getJumpRules(intf link) []jumpRule {
+	var jumps []jumpRule
+	for _, rule := range getSGRules(intf) {
+		jumps = append(jumps, jumpRule{chain: chainCallistoSG, rule: rule, position: 1})
 	}
-	for i, rule := range rules {
-		err = client.Insert(tableFilter, chain, i+1, rule...)
-		if err != nil {
-			client.ClearChain(tableFilter, chain)
-			client.DeleteChain(tableFilter, chain)
-			return status.Errorf(codes.Internal, "failed to append new %s rule: %+v", chain, err)
+	for _, rule := range append(getINPUTRules(intf), getFORWARDRules(intf)...) {
+		jumps = append(jumps, jumpRule{chain: chainCallistoFORWARD, rule: rule, position: 1})
+	}
+	return jumps
+}
+
+// getJumpRulesTo returns the rules of jumps that jump to target.
+func getJumpRulesTo(jumps []jumpRule, target string) []jumpRule {
+	var filtered []jumpRule
+	for _, jump := range jumps {
+		if jump.target() == target {
+			filtered = append(filtered, jump)
 		}
 	}
+	return filtered
+}
 
-	return nil
+// getSGRules returns the rules in callisto-sg-chain that jump to the chains of intf.
+func getSGRules(intf link) [][]string {
+	return [][]string{
+		{"-m", "physdev", "--physdev-out", intf.Name, "--physdev-is-bridged", "-m", "comment", "--comment", "Jump to the VM specific chain.", "-j", getINPUTChainName(intf)},
+		{"-m", "physdev", "--physdev-in", intf.Name, "--physdev-is-bridged", "-m", "comment", "--comment", "Jump to the VM specific chain.", "-j", getOUTPUTChainName(intf)},
+	}
+}
+
+// getINPUTRules returns the rule in callisto-FORWARD that directs traffic from intf to its output chain.
+func getINPUTRules(intf link) [][]string {
+	return [][]string{
+		{"-m", "physdev", "--physdev-in", intf.Name, "--physdev-is-bridged", "-m", "comment", "--comment", "Direct incoming traffic from VM to the security group chain.", "-j", getOUTPUTChainName(intf)},
+	}
+}
+
+// getFORWARDRules returns the rules in callisto-FORWARD that direct traffic of intf to callisto-sg-chain.
+func getFORWARDRules(intf link) [][]string {
+	return [][]string{
+		{"-m", "physdev", "--physdev-out", intf.Name, "--physdev-is-bridged", "-m", "comment", "--comment", "Direct traffic from the VM interface to the security group chain.", "-j", chainCallistoSG},
+		{"-m", "physdev", "--physdev-in", intf.Name, "--physdev-is-bridged", "-m", "comment", "--comment", "Direct traffic from the VM interface to the security group chain.", "-j", chainCallistoSG},
+	}
 }
 
 func getINPUTChainName(intf link) string {
@@ -338,14 +723,35 @@ func getOUTPUTChainName(intf link) string {
 	return getValidChainName(chainCallistoOUTPUPrefix, intf.Name)
 }
 
+func getNEWINPUTChainName(intf link) string {
+	return getValidChainName(chainCallistoNEWINPUTPrefix, intf.Name)
+}
+
+func getNEWOUTPUTChainName(intf link) string {
+	return getValidChainName(chainCallistoNEWOUTPUTPrefix, intf.Name)
+}
+
 func getSOURCEChainName(intf link) string {
 	return getValidChainName(chainCallistoSOURCEPrefix, intf.Name)
 }
 
+// getValidChainName returns prefix followed by val if val is not longer than the name of a network interface.
+// A longer val is shortened and followed by its hash, which is longer than any interface name, so that
+// hashed names never collide with the others.
 func getValidChainName(prefix, val string) string {
-	tmp := fmt.Sprintf("%s%s", prefix, val)
-	if len(tmp) > maxChainNameLength {
-		return tmp[:maxChainNameLength]
+	if len(val) <= maxInterfaceNameLength {
+		return prefix + val
+	}
+	sum := sha256.Sum256([]byte(val))
+	hash := hex.EncodeToString(sum[:])[:chainNameHashLength]
+	return prefix + val[:maxChainNameLength-len(prefix)-len(hash)-1] + "-" + hash
+}
+
+// getLegacyChainName returns the name of older versions, which truncated prefix followed by val.
+func getLegacyChainName(prefix, val string) string {
+	name := prefix + val
+	if len(name) > maxChainNameLength {
+		return name[:maxChainNameLength]
 	}
-	return tmp
+	return name
 }
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// iptablesTransaction is a set of changes of a table, applied at once by iptables-restore --noflush.
+// Either all changes are applied or none of them, and packets never see a half-filled chain.
+type iptablesTransaction struct {
+	table string
+	lines []string
+}
+
+func newIPTablesTransaction(table string) *iptablesTransaction {
+	return &iptablesTransaction{table: table}
+}
+
+func (t *iptablesTransaction) add(args ...string) {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		quoted = append(quoted, quoteIPTablesArg(arg))
+	}
+	t.lines = append(t.lines, strings.Join(quoted, " "))
+}
+
+// newChain creates chain. The transaction fails if it already exists.
+func (t *iptablesTransaction) newChain(chain string) {
+	t.add("-N", chain)
+}
+
+func (t *iptablesTransaction) flushChain(chain string) {
+	t.add("-F", chain)
+}
+
+func (t *iptablesTransaction) deleteChain(chain string) {
+	t.add("-X", chain)
+}
+
+// renameChain renames oldChain to newChain. Rules jumping to oldChain jump to newChain.
+func (t *iptablesTransaction) renameChain(oldChain, newChain string) {
+	t.add("-E", oldChain, newChain)
+}
+
+func (t *iptablesTransaction) appendRule(chain string, rulespec ...string) {
+	t.add(append([]string{"-A", chain}, rulespec...)...)
+}
+
+func (t *iptablesTransaction) insertRule(chain string, pos int, rulespec ...string) {
+	t.add(append([]string{"-I", chain, strconv.Itoa(pos)}, rulespec...)...)
+}
+
+func (t *iptablesTransaction) deleteRule(chain string, rulespec ...string) {
+	t.add(append([]string{"-D", chain}, rulespec...)...)
+}
+
+// String returns the input of iptables-restore.
+func (t *iptablesTransaction) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%s\n", t.table)
+	for _, line := range t.lines {
+		fmt.Fprintln(&b, line)
+	}
+	fmt.Fprintln(&b, "COMMIT")
+	return b.String()
+}
+
+// commit applies the changes. An empty transaction is not applied.
+func (t *iptablesTransaction) commit(ctx context.Context, client iptablesAPI) error {
+	if len(t.lines) == 0 {
+		return nil
+	}
+	if err := client.Restore(ctx, t.table, t.String()); err != nil {
+		return fmt.Errorf("failed to apply iptables transaction: %w", err)
+	}
+	return nil
+}
+
+// saveIPTables returns the rules of every chain of the filter table.
+func saveIPTables(ctx context.Context, client iptablesAPI) (map[string][]string, error) {
+	out, err := client.Save(ctx, tableFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save iptables: %w", err)
+	}
+	saved, err := parseIPTablesSave(out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse iptables-save: %w", err)
+	}
+	return saved, nil
+}
+
+// containsRule returns whether spec is a saved rule of chain.
+func containsRule(ctx context.Context, client iptablesAPI, saved map[string][]string, chain string, spec []string) (bool, error) {
+	rules, ok := saved[chain]
+	if !ok {
+		return false, nil
+	}
+	for _, rule := range rules {
+		if sameRule(rule, spec) {
+			return true, nil
+		}
+	}
+	exists, err := client.Exists(ctx, tableFilter, chain, spec...)
+	if err != nil {
+		return false, fmt.Errorf("failed to check %s rule: %w", chain, err)
+	}
+	return exists, nil
+}
+
+// sameRule returns whether rule printed by iptables-save is spec. iptables prints some values differently,
+// e.g. MAC addresses in upper case and ICMP type names as numbers, so callers fall back to iptables -C
+// when they differ.
+func sameRule(rule string, spec []string) bool {
+	args := make([]string, 0, len(spec))
+	for _, arg := range spec {
+		args = append(args, quoteIPTablesArg(arg))
+	}
+	return strings.EqualFold(rule, strings.Join(args, " "))
+}
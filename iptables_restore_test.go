@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestIPTablesTransaction(t *testing.T) {
+	tx := newIPTablesTransaction(tableFilter)
+	tx.newChain("callisto-itap0")
+	tx.appendRule("callisto-itap0", "-m", "comment", "--comment", "Send unmatched traffic to the fallback chain.", "-j", chainCallistoSGFallback)
+	tx.insertRule(chainCallistoSG, 1, "-m", "physdev", "--physdev-out", "tap0", "--physdev-is-bridged", "-j", "callisto-itap0")
+	tx.flushChain("callisto-otap0")
+	tx.deleteRule(chainCallistoFORWARD, "-j", chainCallistoSG)
+	tx.deleteChain("callisto-otap0")
+
+	want := `*filter
+-N callisto-itap0
+-A callisto-itap0 -m comment --comment "Send unmatched traffic to the fallback chain." -j callisto-sg-fallback
+-I callisto-sg-chain 1 -m physdev --physdev-out tap0 --physdev-is-bridged -j callisto-itap0
+-F callisto-otap0
+-D callisto-FORWARD -j callisto-sg-chain
+-X callisto-otap0
+COMMIT
+`
+	if got := tx.String(); got != want {
+		t.Errorf("want %s, but got %s", want, got)
+	}
+}
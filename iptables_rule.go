@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+// parseIPTablesRule parses a rule printed by iptables -S, with or without counters.
+// It returns the chain of the rule.
+func parseIPTablesRule(line string) (string, *pb.IPTablesRule, error) {
+	args, err := splitIPTablesRule(line)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(args) < 2 || args[0] != "-A" {
+		return "", nil, fmt.Errorf("not a rule: %s", line)
+	}
+	chain := args[1]
+
+	rule := &pb.IPTablesRule{}
+	values := map[string]*string{
+		"-p":            &rule.Protocol,
+		"-s":            &rule.Source,
+		"-d":            &rule.Destination,
+		"--sport":       &rule.SourcePorts,
+		"--sports":      &rule.SourcePorts,
+		"--dport":       &rule.DestinationPorts,
+		"--dports":      &rule.DestinationPorts,
+		"--physdev-in":  &rule.PhysdevIn,
+		"--physdev-out": &rule.PhysdevOut,
+		"--mac-source":  &rule.MacSource,
+		"--state":       &rule.State,
+		"--ctstate":     &rule.State,
+		"--icmp-type":   &rule.IcmpType,
+		"--icmpv6-type": &rule.IcmpType,
+		"--comment":     &rule.Comment,
+		"-j":            &rule.Target,
+		"--jump":        &rule.Target,
+		"--match-set":   &rule.MatchSet,
+	}
+
+	var spec []string
+	negate := false
+	for i := 2; i < len(args); i++ {
+		arg := args[i]
+		if arg == "-c" {
+			if i+2 >= len(args) {
+				return "", nil, fmt.Errorf("missing counters: %s", line)
+			}
+			if rule.Packets, err = strconv.ParseUint(args[i+1], 10, 64); err != nil {
+				return "", nil, fmt.Errorf("failed to parse packet counter: %w", err)
+			}
+			if rule.Bytes, err = strconv.ParseUint(args[i+2], 10, 64); err != nil {
+				return "", nil, fmt.Errorf("failed to parse byte counter: %w", err)
+			}
+			i += 2
+			continue
+		}
+		spec = append(spec, quoteIPTablesArg(arg))
+
+		if arg == "!" {
+			negate = true
+			continue
+		}
+		field, ok := values[arg]
+		if !ok || i+1 >= len(args) {
+			negate = false
+			continue
+		}
+		value := args[i+1]
+		spec = append(spec, quoteIPTablesArg(value))
+		i++
+		// --match-set takes the name and the flags
+		if arg == "--match-set" && i+1 < len(args) {
+			value += " " + args[i+1]
+			spec = append(spec, quoteIPTablesArg(args[i+1]))
+			i++
+		}
+		if negate {
+			value = "!" + value
+			negate = false
+		}
+		*field = value
+	}
+	rule.Rule = strings.Join(spec, " ")
+
+	return chain, rule, nil
+}
+
+// parseIPTablesSave parses the output of iptables-save of a table. It returns the rules of every chain,
+// as the rule field of parseIPTablesRule.
+func parseIPTablesSave(out string) (map[string][]string, error) {
+	chains := map[string][]string{}
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, ":"):
+			fields := strings.Fields(line[1:])
+			if len(fields) == 0 {
+				return nil, fmt.Errorf("invalid chain: %s", line)
+			}
+			if _, ok := chains[fields[0]]; !ok {
+				chains[fields[0]] = []string{}
+			}
+		case strings.HasPrefix(line, "-A "):
+			chain, rule, err := parseIPTablesRule(line)
+			if err != nil {
+				return nil, err
+			}
+			chains[chain] = append(chains[chain], rule.Rule)
+		}
+	}
+	return chains, nil
+}
+
+// splitIPTablesRule splits line into arguments. Arguments with spaces are double quoted by iptables.
+func splitIPTablesRule(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg, quoted, escaped := false, false, false
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quoted:
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+			inArg = true
+		case r == ' ' && !quoted:
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote: %s", line)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+func quoteIPTablesArg(arg string) string {
+	if arg == "" || strings.ContainsAny(arg, " \"") {
+		return strconv.Quote(arg)
+	}
+	return arg
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+func TestParseIPTablesRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		wantChain string
+		want      *pb.IPTablesRule
+		wantErr   bool
+	}{
+		{
+			name:      "jump rule with counters",
+			line:      `-A callisto-sg-chain -m physdev --physdev-out tap0 --physdev-is-bridged -m comment --comment "Jump to the VM specific chain." -c 12 3456 -j callisto-itap0`,
+			wantChain: "callisto-sg-chain",
+			want: &pb.IPTablesRule{
+				Rule:       `-m physdev --physdev-out tap0 --physdev-is-bridged -m comment --comment "Jump to the VM specific chain." -j callisto-itap0`,
+				PhysdevOut: "tap0",
+				Comment:    "Jump to the VM specific chain.",
+				Target:     "callisto-itap0",
+				Packets:    12,
+				Bytes:      3456,
+			},
+		},
+		{
+			name:      "counters at the end in nftables mode",
+			line:      `-A callisto-itap0 -s 198.51.100.0/24 -p tcp -m tcp --dport 22 -j RETURN -c 1 60`,
+			wantChain: "callisto-itap0",
+			want: &pb.IPTablesRule{
+				Rule:             `-s 198.51.100.0/24 -p tcp -m tcp --dport 22 -j RETURN`,
+				Protocol:         "tcp",
+				Source:           "198.51.100.0/24",
+				DestinationPorts: "22",
+				Target:           "RETURN",
+				Packets:          1,
+				Bytes:            60,
+			},
+		},
+		{
+			name:      "without counters",
+			line:      `-A callisto-otap0 -m set --match-set callisto-gdb dst -p udp -m udp --sport 68 --dport 67 -j RETURN`,
+			wantChain: "callisto-otap0",
+			want: &pb.IPTablesRule{
+				Rule:             `-m set --match-set callisto-gdb dst -p udp -m udp --sport 68 --dport 67 -j RETURN`,
+				Protocol:         "udp",
+				SourcePorts:      "68",
+				DestinationPorts: "67",
+				MatchSet:         "callisto-gdb dst",
+				Target:           "RETURN",
+			},
+		},
+		{
+			name:      "negated match",
+			line:      `-A callisto-itap0 ! -s 192.0.2.0/24 -p icmp -m icmp --icmp-type 8/0 -m state --state RELATED,ESTABLISHED -j DROP`,
+			wantChain: "callisto-itap0",
+			want: &pb.IPTablesRule{
+				Rule:     `! -s 192.0.2.0/24 -p icmp -m icmp --icmp-type 8/0 -m state --state RELATED,ESTABLISHED -j DROP`,
+				Protocol: "icmp",
+				Source:   "!192.0.2.0/24",
+				IcmpType: "8/0",
+				State:    "RELATED,ESTABLISHED",
+				Target:   "DROP",
+			},
+		},
+		{
+			name:      "escaped quote",
+			line:      `-A callisto-sg-fallback -m comment --comment "say \"hello\"" -j DROP`,
+			wantChain: "callisto-sg-fallback",
+			want: &pb.IPTablesRule{
+				Rule:    `-m comment --comment "say \"hello\"" -j DROP`,
+				Comment: `say "hello"`,
+				Target:  "DROP",
+			},
+		},
+		{
+			name:    "chain",
+			line:    "-N callisto-itap0",
+			wantErr: true,
+		},
+		{
+			name:    "unterminated quote",
+			line:    `-A callisto-itap0 -m comment --comment "oops -j DROP`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid counters",
+			line:    `-A callisto-itap0 -c 1 -j DROP`,
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		chain, got, err := parseIPTablesRule(test.line)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%s: should be error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: should not be error but: %+v", test.name, err)
+			continue
+		}
+		if chain != test.wantChain {
+			t.Errorf("%s: want %s, but got %s", test.name, test.wantChain, chain)
+		}
+		if !proto.Equal(got, test.want) {
+			t.Errorf("%s: want %s, but got %s", test.name, test.want, got)
+		}
+	}
+}
+
+func TestParseIPTablesSave(t *testing.T) {
+	out := `# Generated by iptables-save v1.8.4 on Mon Oct 19 00:00:00 2026
+*filter
+:INPUT ACCEPT [0:0]
+:FORWARD DROP [12:3456]
+:callisto-FORWARD - [0:0]
+:callisto-itap0 - [0:0]
+-A FORWARD -j callisto-FORWARD
+-A callisto-itap0 -m comment --comment "Send unmatched traffic to the fallback chain." -j callisto-sg-fallback
+COMMIT
+# Completed on Mon Oct 19 00:00:00 2026
+`
+	got, err := parseIPTablesSave(out)
+	if err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	want := map[string][]string{
+		"INPUT":            {},
+		"FORWARD":          {"-j callisto-FORWARD"},
+		"callisto-FORWARD": {},
+		"callisto-itap0":   {`-m comment --comment "Send unmatched traffic to the fallback chain." -j callisto-sg-fallback`},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %q, but got %q", want, got)
+	}
+
+	if _, err := parseIPTablesSave(`-A callisto-itap0 -m comment --comment "oops -j DROP`); err == nil {
+		t.Errorf("should be error")
+	}
+}
@@ -0,0 +1,833 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/vishvananda/netlink"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+func TestGetIPTables(t *testing.T) {
+	tests := []struct {
+		name       string
+		intf       string
+		want       codes.Code
+		wantChains []string
+		wantRules  int
+	}{
+		{
+			name: "all chains",
+			want: codes.OK,
+			wantChains: []string{
+				"ipv4/callisto-FORWARD", "ipv4/callisto-INPUT", "ipv4/callisto-itap0", "ipv4/callisto-itap1", "ipv4/callisto-otap0", "ipv4/callisto-otap1",
+				"ipv4/callisto-sg-chain", "ipv4/callisto-sg-fallback", "ipv4/callisto-stap0", "ipv4/callisto-stap1",
+				"ipv6/callisto-FORWARD", "ipv6/callisto-INPUT", "ipv6/callisto-itap0", "ipv6/callisto-itap1", "ipv6/callisto-otap0", "ipv6/callisto-otap1",
+				"ipv6/callisto-sg-chain", "ipv6/callisto-sg-fallback", "ipv6/callisto-stap0", "ipv6/callisto-stap1",
+			},
+			wantRules: 6 + 0 + 4 + 3 + 5 + 5 + 5 + 1 + 2 + 2 +
+				6 + 0 + 7 + 7 + 10 + 10 + 5 + 1 + 4 + 3,
+		},
+		{
+			name: "chains of interface",
+			intf: "tap0",
+			want: codes.OK,
+			wantChains: []string{
+				"ipv4/callisto-FORWARD", "ipv4/callisto-itap0", "ipv4/callisto-otap0", "ipv4/callisto-sg-chain", "ipv4/callisto-stap0",
+				"ipv6/callisto-FORWARD", "ipv6/callisto-itap0", "ipv6/callisto-otap0", "ipv6/callisto-sg-chain", "ipv6/callisto-stap0",
+			},
+			wantRules: 3 + 4 + 5 + 2 + 2 + 3 + 7 + 10 + 2 + 4,
+		},
+		{
+			name: "missing interface",
+			intf: "tap9",
+			want: codes.NotFound,
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		ctx := context.Background()
+		if _, err := a.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{}); err != nil {
+			t.Fatalf("%s: failed to setup default security group: %+v", test.name, err)
+		}
+		for _, req := range []*pb.AddSecurityGroupRequest{
+			{Interface: "tap0", IpAddress: "192.0.2.100", Ipv6Address: "2001:db8::100", MacAddress: "52:54:00:00:00:01", IngressRules: []*pb.SecurityGroupRule{{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 22, RemoteCidr: "198.51.100.0/24"}}},
+			{Interface: "tap1", IpAddress: "192.0.2.101", MacAddress: "52:54:00:00:00:02"},
+		} {
+			if _, err := a.AddSecurityGroup(ctx, req); err != nil {
+				t.Fatalf("%s: failed to add security group: %+v", test.name, err)
+			}
+		}
+		source := f.iptables.rules(tableFilter, "callisto-stap0")[0]
+		f.iptables.setCounters("callisto-stap0", source, 10, 1500)
+
+		resp, err := a.GetIPTables(ctx, &pb.GetIPTablesRequest{Interface: test.intf})
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %+v", test.name, test.want, err)
+			continue
+		}
+		if test.want != codes.OK {
+			continue
+		}
+
+		var names []string
+		rules := map[string][]*pb.IPTablesRule{}
+		n := 0
+		for _, chain := range resp.Chains {
+			name := chain.Family + "/" + chain.Name
+			names = append(names, name)
+			rules[name] = chain.Rules
+			n += len(chain.Rules)
+		}
+		if strings.Join(names, ",") != strings.Join(test.wantChains, ",") {
+			t.Errorf("%s: want %s, but got %s", test.name, strings.Join(test.wantChains, ","), strings.Join(names, ","))
+		}
+		if n != test.wantRules {
+			t.Errorf("%s: want %d rules, but got %d", test.name, test.wantRules, n)
+		}
+
+		got := rules["ipv4/callisto-stap0"][0]
+		want := &pb.IPTablesRule{
+			Rule:      source[:strings.Index(source, "--comment ")] + `--comment "Allow traffic from defined IP/MAC pairs." -j RETURN`,
+			Source:    "192.0.2.100/32",
+			MacSource: "52:54:00:00:00:01",
+			Comment:   "Allow traffic from defined IP/MAC pairs.",
+			Target:    actionRETURN,
+			Packets:   10,
+			Bytes:     1500,
+		}
+		if !proto.Equal(got, want) {
+			t.Errorf("%s: want %s, but got %s", test.name, want, got)
+		}
+		got = rules["ipv4/callisto-itap0"][2]
+		if got.Source != "198.51.100.0/24" || got.Protocol != "tcp" || got.DestinationPorts != "22" || got.Target != actionRETURN {
+			t.Errorf("%s: want the ingress rule, but got %s", test.name, got)
+		}
+		got = rules["ipv6/callisto-stap0"][0]
+		if got.Source != "2001:db8::100/128" || got.MacSource != "52:54:00:00:00:01" || got.Target != actionRETURN {
+			t.Errorf("%s: want the IPv6 source rule, but got %s", test.name, got)
+		}
+		for _, chain := range resp.Chains {
+			want := ""
+			if strings.HasSuffix(chain.Name, "tap0") {
+				want = "tap0"
+			} else if strings.HasSuffix(chain.Name, "tap1") {
+				want = "tap1"
+			}
+			if chain.Interface != want {
+				t.Errorf("%s: want interface %q of %s, but got %q", test.name, want, chain.Name, chain.Interface)
+			}
+		}
+	}
+}
+
+func TestSetupDefaultSecurityGroup(t *testing.T) {
+	a, f := newTestAgent(t)
+	ctx := context.Background()
+
+	if _, err := a.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{}); err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+
+	tests := []struct {
+		chain string
+		want  []string
+	}{
+		{
+			chain: chainCallistoSGFallback,
+			want:  []string{strings.Join(ruleSGFallback, " ")},
+		},
+		{
+			chain: chainCallistoSG,
+			want:  []string{"-j ACCEPT"},
+		},
+		{
+			chain: chainCallistoINPUT,
+			want:  []string{},
+		},
+		{
+			chain: chainCallistoFORWARD,
+			want:  []string{},
+		},
+		{
+			chain: chainINPUT,
+			want:  []string{"-j " + chainCallistoINPUT},
+		},
+		{
+			chain: chainFORWARD,
+			want:  []string{"-j " + chainCallistoFORWARD},
+		},
+	}
+	for _, test := range tests {
+		if !f.iptables.hasChain(tableFilter, test.chain) {
+			t.Errorf("%s: chain should be created", test.chain)
+			continue
+		}
+		got := f.iptables.rules(tableFilter, test.chain)
+		if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+			t.Errorf("%s: want %q, but got %q", test.chain, test.want, got)
+		}
+	}
+
+	// chains are not recreated
+	if _, err := a.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{}); status.Code(err) != codes.Internal {
+		t.Errorf("want %s, but got %+v", codes.Internal, err)
+	}
+}
+
+func TestAddSecurityGroup(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *pb.AddSecurityGroupRequest
+		want codes.Code
+	}{
+		{
+			name: "security group",
+			req:  &pb.AddSecurityGroupRequest{Interface: "tap0", IpAddress: "192.0.2.100", MacAddress: "52:54:00:00:00:01"},
+			want: codes.OK,
+		},
+		{
+			name: "security group with rules",
+			req: &pb.AddSecurityGroupRequest{
+				Interface:  "tap0",
+				IpAddress:  "192.0.2.100",
+				MacAddress: "52:54:00:00:00:01",
+				IngressRules: []*pb.SecurityGroupRule{
+					{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 22},
+					{Protocol: pb.SecurityGroupRule_ICMP, RemoteCidr: "198.51.100.0/24"},
+				},
+				EgressRules: []*pb.SecurityGroupRule{{Protocol: pb.SecurityGroupRule_ANY}},
+			},
+			want: codes.OK,
+		},
+		{
+			name: "invalid ip address",
+			req:  &pb.AddSecurityGroupRequest{Interface: "tap0", IpAddress: "192.0.2", MacAddress: "52:54:00:00:00:01"},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "invalid rule",
+			req: &pb.AddSecurityGroupRequest{
+				Interface:    "tap0",
+				IpAddress:    "192.0.2.100",
+				MacAddress:   "52:54:00:00:00:01",
+				IngressRules: []*pb.SecurityGroupRule{{Protocol: pb.SecurityGroupRule_ICMP, PortRangeMin: 22}},
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "invalid mac address",
+			req:  &pb.AddSecurityGroupRequest{Interface: "tap0", IpAddress: "192.0.2.100", MacAddress: "52:54:00:00:00"},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "ipv6 address as ip address",
+			req:  &pb.AddSecurityGroupRequest{Interface: "tap0", IpAddress: "2001:db8::100", MacAddress: "52:54:00:00:00:01"},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "ipv4 address as ipv6 address",
+			req:  &pb.AddSecurityGroupRequest{Interface: "tap0", IpAddress: "192.0.2.100", Ipv6Address: "192.0.2.100", MacAddress: "52:54:00:00:00:01"},
+			want: codes.InvalidArgument,
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		ctx := context.Background()
+		if _, err := a.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{}); err != nil {
+			t.Fatalf("%s: failed to setup default security group: %+v", test.name, err)
+		}
+
+		_, err := a.AddSecurityGroup(ctx, test.req)
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %+v", test.name, test.want, err)
+			continue
+		}
+		if test.want != codes.OK {
+			if f.iptables.hasChain(tableFilter, "callisto-itap0") {
+				t.Errorf("%s: chain should not be created", test.name)
+			}
+			continue
+		}
+
+		for chain, want := range map[string]int{
+			"callisto-itap0":     3 + len(test.req.IngressRules),
+			"callisto-otap0":     5 + len(test.req.EgressRules),
+			"callisto-stap0":     2,
+			chainCallistoSG:      3,
+			chainCallistoFORWARD: 3,
+		} {
+			if got := len(f.iptables.rules(tableFilter, chain)); got != want {
+				t.Errorf("%s: want %d rules in %s, but got %d", test.name, want, chain, got)
+			}
+		}
+		source := f.iptables.rules(tableFilter, "callisto-stap0")[0]
+		if !strings.Contains(source, "-s 192.0.2.100/32 -m mac --mac-source 52:54:00:00:00:01") {
+			t.Errorf("%s: want IP/MAC pair in source chain, but got %s", test.name, source)
+		}
+	}
+}
+
+func TestAddSecurityGroupWithoutDefault(t *testing.T) {
+	a, _ := newTestAgent(t)
+
+	_, err := a.AddSecurityGroup(context.Background(), &pb.AddSecurityGroupRequest{Interface: "tap0", IpAddress: "192.0.2.100", MacAddress: "52:54:00:00:00:01"})
+	if got := status.Code(err); got != codes.Internal {
+		t.Errorf("want %s, but got %+v", codes.Internal, err)
+	}
+}
+
+func TestAddSecurityGroupIPv6(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *pb.AddSecurityGroupRequest
+		// failRestore is a part of the ip6tables line that fails
+		failRestore string
+		wantSource  []string
+		wantIngress int
+	}{
+		{
+			name: "link-local only",
+			req: &pb.AddSecurityGroupRequest{
+				Interface:    "tap0",
+				IpAddress:    "192.0.2.100",
+				MacAddress:   "52:54:00:00:00:01",
+				IngressRules: []*pb.SecurityGroupRule{{Protocol: pb.SecurityGroupRule_ICMP, IcmpType: "echo-request", RemoteCidr: "0.0.0.0/0"}},
+			},
+			wantSource:  []string{"-s fe80::/10", "-s ::/128 -p ipv6-icmp", "-j DROP"},
+			wantIngress: 7,
+		},
+		{
+			name: "global address",
+			req: &pb.AddSecurityGroupRequest{
+				Interface:    "tap0",
+				IpAddress:    "192.0.2.100",
+				Ipv6Address:  "2001:db8::100",
+				MacAddress:   "52:54:00:00:00:01",
+				IngressRules: []*pb.SecurityGroupRule{{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 22}},
+			},
+			wantSource:  []string{"-s 2001:db8::100/128", "-s fe80::/10", "-s ::/128 -p ipv6-icmp", "-j DROP"},
+			wantIngress: 8,
+		},
+		{
+			name: "ip6tables fails",
+			req: &pb.AddSecurityGroupRequest{
+				Interface:   "tap0",
+				IpAddress:   "192.0.2.100",
+				Ipv6Address: "2001:db8::100",
+				MacAddress:  "52:54:00:00:00:01",
+			},
+			failRestore: "-N callisto-itap0",
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		ctx := context.Background()
+		if _, err := a.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{}); err != nil {
+			t.Fatalf("%s: failed to setup default security group: %+v", test.name, err)
+		}
+
+		f.ip6tables.failRestore = test.failRestore
+		_, err := a.AddSecurityGroup(ctx, test.req)
+		if test.failRestore != "" {
+			if status.Code(err) != codes.Internal {
+				t.Errorf("%s: want %s, but got %+v", test.name, codes.Internal, err)
+			}
+			// the chains of the other family are rolled back
+			if f.iptables.hasChain(tableFilter, "callisto-itap0") {
+				t.Errorf("%s: iptables chain should be removed", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: should not be error but: %+v", test.name, err)
+			continue
+		}
+
+		source := f.ip6tables.rules(tableFilter, "callisto-stap0")
+		if len(source) != len(test.wantSource) {
+			t.Errorf("%s: want %d rules in source chain, but got %q", test.name, len(test.wantSource), source)
+			continue
+		}
+		for i, want := range test.wantSource {
+			if !strings.Contains(source[i], want) {
+				t.Errorf("%s: want %s in source rule %d, but got %s", test.name, want, i, source[i])
+			}
+		}
+		if got := len(f.ip6tables.rules(tableFilter, "callisto-itap0")); got != test.wantIngress {
+			t.Errorf("%s: want %d rules in input chain, but got %d", test.name, test.wantIngress, got)
+		}
+		output := strings.Join(f.ip6tables.rules(tableFilter, "callisto-otap0"), "\n")
+		if !strings.Contains(output, "--icmpv6-type 134 -m comment --comment Prevent router advertisements by VM. -j DROP") {
+			t.Errorf("%s: want router advertisements to be dropped, but got %s", test.name, output)
+		}
+	}
+}
+
+func TestRemoveSecurityGroup(t *testing.T) {
+	tests := []struct {
+		name      string
+		intf      string
+		want      codes.Code
+		wantRules map[string]int
+		wantFlows int
+	}{
+		{
+			name:      "security group",
+			intf:      "tap0",
+			want:      codes.OK,
+			wantRules: map[string]int{"callisto-itap1": 3, chainCallistoSG: 3, chainCallistoFORWARD: 3},
+			wantFlows: 1,
+		},
+		{
+			name:      "missing security group",
+			intf:      "tap9",
+			want:      codes.OK,
+			wantRules: map[string]int{"callisto-itap1": 3, chainCallistoSG: 5, chainCallistoFORWARD: 6},
+			wantFlows: 4,
+		},
+		{
+			name:      "empty interface",
+			want:      codes.InvalidArgument,
+			wantRules: map[string]int{"callisto-itap1": 3, chainCallistoSG: 5, chainCallistoFORWARD: 6},
+			wantFlows: 4,
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		ctx := context.Background()
+		if _, err := a.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{}); err != nil {
+			t.Fatalf("%s: failed to setup default security group: %+v", test.name, err)
+		}
+		for _, req := range []*pb.AddSecurityGroupRequest{
+			{Interface: "tap0", IpAddress: "192.0.2.100", Ipv6Address: "2001:db8::100", MacAddress: "52:54:00:00:00:01"},
+			{Interface: "tap1", IpAddress: "192.0.2.101", MacAddress: "52:54:00:00:00:02"},
+		} {
+			if _, err := a.AddSecurityGroup(ctx, req); err != nil {
+				t.Fatalf("%s: failed to add security group: %+v", test.name, err)
+			}
+		}
+		f.netlink.flows = []*netlink.ConntrackFlow{
+			testConntrackFlow("192.0.2.100", "198.51.100.1"),
+			testConntrackFlow("198.51.100.1", "192.0.2.100"),
+			testConntrackFlow("2001:db8::100", "2001:db8:1::1"),
+			testConntrackFlow("192.0.2.101", "198.51.100.1"),
+		}
+
+		// removing twice is not an error
+		for i := 0; i < 2; i++ {
+			_, err := a.RemoveSecurityGroup(ctx, &pb.RemoveSecurityGroupRequest{Interface: test.intf})
+			if got := status.Code(err); got != test.want {
+				t.Fatalf("%s: want %s, but got %+v", test.name, test.want, err)
+			}
+		}
+
+		removed := test.intf == "tap0"
+		for _, chain := range []string{"callisto-itap0", "callisto-otap0", "callisto-stap0"} {
+			if got := f.iptables.hasChain(tableFilter, chain); got == removed {
+				t.Errorf("%s: want chain %s exists %t, but got %t", test.name, chain, !removed, got)
+			}
+			if got := f.ip6tables.hasChain(tableFilter, chain); got == removed {
+				t.Errorf("%s: want ip6tables chain %s exists %t, but got %t", test.name, chain, !removed, got)
+			}
+		}
+		for chain, want := range test.wantRules {
+			if got := len(f.iptables.rules(tableFilter, chain)); got != want {
+				t.Errorf("%s: want %d rules in %s, but got %d", test.name, want, chain, got)
+			}
+		}
+		for _, rule := range f.iptables.rules(tableFilter, chainCallistoFORWARD) {
+			if removed && strings.Contains(rule, " tap0 ") {
+				t.Errorf("%s: rule of tap0 should be removed, but got %s", test.name, rule)
+			}
+		}
+		if len(f.netlink.flows) != test.wantFlows {
+			t.Errorf("%s: want %d conntrack flows, but got %d", test.name, test.wantFlows, len(f.netlink.flows))
+		}
+	}
+}
+
+func TestRemoveSecurityGroupReusedInterface(t *testing.T) {
+	a, f := newTestAgent(t)
+	ctx := context.Background()
+	if _, err := a.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{}); err != nil {
+		t.Fatalf("failed to setup default security group: %+v", err)
+	}
+
+	req := &pb.AddSecurityGroupRequest{Interface: "tap0", IpAddress: "192.0.2.100", MacAddress: "52:54:00:00:00:01"}
+	if _, err := a.AddSecurityGroup(ctx, req); err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	if _, err := a.RemoveSecurityGroup(ctx, &pb.RemoveSecurityGroupRequest{Interface: "tap0"}); err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+
+	req = &pb.AddSecurityGroupRequest{Interface: "tap0", IpAddress: "192.0.2.200", MacAddress: "52:54:00:00:00:02"}
+	if _, err := a.AddSecurityGroup(ctx, req); err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	source := f.iptables.rules(tableFilter, "callisto-stap0")
+	if len(source) != 2 || !strings.Contains(source[0], "-s 192.0.2.200/32") {
+		t.Errorf("want rules of the new address, but got %q", source)
+	}
+}
+
+func TestUpdateSecurityGroupRules(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        *pb.UpdateSecurityGroupRulesRequest
+		want       codes.Code
+		wantINPUT  []string
+		wantOUTPUT []string
+	}{
+		{
+			name: "replace rules",
+			req: &pb.UpdateSecurityGroupRulesRequest{
+				Interface:    "tap0",
+				IngressRules: []*pb.SecurityGroupRule{{Protocol: pb.SecurityGroupRule_UDP, PortRangeMin: 53, RemoteCidr: "198.51.100.0/24"}},
+			},
+			want:      codes.OK,
+			wantINPUT: []string{"-s 198.51.100.0/24 -p udp -m udp --dport 53 -j RETURN"},
+		},
+		{
+			name: "remove all rules",
+			req:  &pb.UpdateSecurityGroupRulesRequest{Interface: "tap0"},
+			want: codes.OK,
+		},
+		{
+			name: "egress rules",
+			req: &pb.UpdateSecurityGroupRulesRequest{
+				Interface:   "tap0",
+				EgressRules: []*pb.SecurityGroupRule{{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 80, PortRangeMax: 443, RemoteCidr: "192.0.2.0/24"}},
+			},
+			want:       codes.OK,
+			wantOUTPUT: []string{"-d 192.0.2.0/24 -p tcp -m tcp --dport 80:443 -j RETURN"},
+		},
+		{
+			name: "missing security group",
+			req:  &pb.UpdateSecurityGroupRulesRequest{Interface: "tap9"},
+			want: codes.NotFound,
+		},
+		{
+			name: "empty interface",
+			req:  &pb.UpdateSecurityGroupRulesRequest{},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "invalid rule",
+			req: &pb.UpdateSecurityGroupRulesRequest{
+				Interface:    "tap0",
+				IngressRules: []*pb.SecurityGroupRule{{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 443, PortRangeMax: 80}},
+			},
+			want: codes.InvalidArgument,
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		ctx := context.Background()
+		if _, err := a.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{}); err != nil {
+			t.Fatalf("%s: failed to setup default security group: %+v", test.name, err)
+		}
+		add := &pb.AddSecurityGroupRequest{
+			Interface:    "tap0",
+			IpAddress:    "192.0.2.100",
+			MacAddress:   "52:54:00:00:00:01",
+			IngressRules: []*pb.SecurityGroupRule{{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 22}},
+			EgressRules:  []*pb.SecurityGroupRule{{Protocol: pb.SecurityGroupRule_ANY}},
+		}
+		if _, err := a.AddSecurityGroup(ctx, add); err != nil {
+			t.Fatalf("%s: failed to add security group: %+v", test.name, err)
+		}
+		wantSG := f.iptables.rules(tableFilter, chainCallistoSG)
+		wantFORWARD := f.iptables.rules(tableFilter, chainCallistoFORWARD)
+
+		_, err := a.UpdateSecurityGroupRules(ctx, test.req)
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %+v", test.name, test.want, err)
+			continue
+		}
+		if test.want != codes.OK {
+			continue
+		}
+
+		for _, chain := range []string{"callisto-nitap0", "callisto-notap0"} {
+			if f.iptables.hasChain(tableFilter, chain) {
+				t.Errorf("%s: chain %s should be renamed", test.name, chain)
+			}
+		}
+		// user-defined rules are between the fixed rules and the fallback rule
+		for _, c := range []struct {
+			chain       string
+			established int
+			head        int
+			want        []string
+		}{
+			{chain: "callisto-itap0", established: 0, head: 2, want: test.wantINPUT},
+			{chain: "callisto-otap0", established: 3, head: 4, want: test.wantOUTPUT},
+		} {
+			got := f.iptables.rules(tableFilter, c.chain)
+			head := c.head
+			if len(got) != head+len(c.want)+1 {
+				t.Errorf("%s: want %d rules in %s, but got %q", test.name, head+len(c.want)+1, c.chain, got)
+				continue
+			}
+			if !strings.Contains(got[c.established], "RELATED,ESTABLISHED") {
+				t.Errorf("%s: established connections should be allowed in %s, but got %s", test.name, c.chain, got[c.established])
+			}
+			if strings.Join(got[head:len(got)-1], "\n") != strings.Join(c.want, "\n") {
+				t.Errorf("%s: want %q in %s, but got %q", test.name, c.want, c.chain, got[head:len(got)-1])
+			}
+		}
+		// jump rules refer to the same chains, in a different order
+		for chain, want := range map[string][]string{chainCallistoSG: wantSG, chainCallistoFORWARD: wantFORWARD} {
+			got := f.iptables.rules(tableFilter, chain)
+			sort.Strings(got)
+			sort.Strings(want)
+			if strings.Join(got, "\n") != strings.Join(want, "\n") {
+				t.Errorf("%s: want %q in %s, but got %q", test.name, want, chain, got)
+			}
+		}
+	}
+}
+
+func TestSecurityGroupTransactionFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		// failRestore is a part of the line that fails
+		failRestore string
+		call        func(ctx context.Context, a *agent) error
+	}{
+		{
+			name:        "add fails on a jump rule",
+			failRestore: "--physdev-in tap0",
+			call: func(ctx context.Context, a *agent) error {
+				_, err := a.AddSecurityGroup(ctx, &pb.AddSecurityGroupRequest{Interface: "tap0", IpAddress: "192.0.2.100", MacAddress: "52:54:00:00:00:01"})
+				return err
+			},
+		},
+		{
+			name:        "add fails on the last chain",
+			failRestore: "-N callisto-itap0",
+			call: func(ctx context.Context, a *agent) error {
+				_, err := a.AddSecurityGroup(ctx, &pb.AddSecurityGroupRequest{Interface: "tap0", IpAddress: "192.0.2.100", MacAddress: "52:54:00:00:00:01"})
+				return err
+			},
+		},
+		{
+			name:        "update fails on the output chain",
+			failRestore: "-F callisto-otap1",
+			call: func(ctx context.Context, a *agent) error {
+				_, err := a.UpdateSecurityGroupRules(ctx, &pb.UpdateSecurityGroupRulesRequest{
+					Interface:    "tap1",
+					IngressRules: []*pb.SecurityGroupRule{{Protocol: pb.SecurityGroupRule_ICMP, IcmpType: "echo-request"}},
+				})
+				return err
+			},
+		},
+		{
+			name:        "remove fails on a chain",
+			failRestore: "-X callisto-stap1",
+			call: func(ctx context.Context, a *agent) error {
+				_, err := a.RemoveSecurityGroup(ctx, &pb.RemoveSecurityGroupRequest{Interface: "tap1"})
+				return err
+			},
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		ctx := context.Background()
+		if _, err := a.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{}); err != nil {
+			t.Fatalf("%s: failed to setup default security group: %+v", test.name, err)
+		}
+		req := &pb.AddSecurityGroupRequest{
+			Interface:    "tap1",
+			IpAddress:    "192.0.2.101",
+			MacAddress:   "52:54:00:00:00:02",
+			IngressRules: []*pb.SecurityGroupRule{{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 22}},
+		}
+		if _, err := a.AddSecurityGroup(ctx, req); err != nil {
+			t.Fatalf("%s: failed to add security group: %+v", test.name, err)
+		}
+
+		want := savedFakeIPTables(t, f.iptables)
+		f.iptables.failRestore = test.failRestore
+		if err := test.call(ctx, a); status.Code(err) != codes.Internal {
+			t.Errorf("%s: want %s, but got %+v", test.name, codes.Internal, err)
+			continue
+		}
+
+		// neither the other security groups nor the shared chains are changed
+		got := savedFakeIPTables(t, f.iptables)
+		if got, want := formatSavedIPTables(got), formatSavedIPTables(want); got != want {
+			t.Errorf("%s: want %s, but got %s", test.name, want, got)
+		}
+	}
+}
+
+func TestRemoveSecurityGroupLegacyChains(t *testing.T) {
+	a, f := newTestAgent(t)
+	ctx := context.Background()
+	if _, err := a.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{}); err != nil {
+		t.Fatalf("failed to setup default security group: %+v", err)
+	}
+	if _, err := a.AddSecurityGroup(ctx, &pb.AddSecurityGroupRequest{Interface: "tap0", IpAddress: "192.0.2.100", MacAddress: "52:54:00:00:00:01"}); err != nil {
+		t.Fatalf("failed to add security group: %+v", err)
+	}
+
+	// chains and a jump rule left by a failed update of older versions
+	intf := link{Name: "tap0"}
+	for chain, rules := range map[string][][]string{
+		"callisto-nitap0": getINPUTSGChainRules(intf, ipFamilyIPv4),
+		"callisto-notap0": getOUTPUTSGChainRules(intf, ipFamilyIPv4),
+	} {
+		if err := f.iptables.NewChain(ctx, tableFilter, chain); err != nil {
+			t.Fatalf("should not be error but: %+v", err)
+		}
+		for n, rule := range rules {
+			if err := f.iptables.Insert(ctx, tableFilter, chain, n+1, rule...); err != nil {
+				t.Fatalf("should not be error but: %+v", err)
+			}
+		}
+	}
+	left := getSGRules(intf)[0]
+	left[len(left)-1] = "callisto-nitap0"
+	if err := f.iptables.Insert(ctx, tableFilter, chainCallistoSG, 1, left...); err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+
+	if _, err := a.RemoveSecurityGroup(ctx, &pb.RemoveSecurityGroupRequest{Interface: "tap0"}); err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	for _, chain := range []string{"callisto-itap0", "callisto-otap0", "callisto-stap0", "callisto-nitap0", "callisto-notap0"} {
+		if f.iptables.hasChain(tableFilter, chain) {
+			t.Errorf("chain %s should be deleted", chain)
+		}
+	}
+	if got := f.iptables.rules(tableFilter, chainCallistoSG); len(got) != 1 {
+		t.Errorf("want only -j ACCEPT in %s, but got %q", chainCallistoSG, got)
+	}
+}
+
+func TestRemoveSecurityGroupFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		// failRestore is a part of the ip6tables line that fails
+		failRestore  string
+		conntrackErr error
+		want         codes.Code
+		// wantRecorded is whether the record is kept for a retry
+		wantRecorded bool
+	}{
+		{
+			name:         "remove failure",
+			failRestore:  "-X callisto-itap0",
+			want:         codes.Internal,
+			wantRecorded: true,
+		},
+		{
+			name:         "conntrack failure",
+			conntrackErr: errors.New("operation not permitted"),
+			want:         codes.OK,
+			wantRecorded: false,
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		ctx := context.Background()
+		if _, err := a.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{}); err != nil {
+			t.Fatalf("%s: failed to setup default security group: %+v", test.name, err)
+		}
+		req := &pb.AddSecurityGroupRequest{Interface: "tap0", IpAddress: "192.0.2.100", Ipv6Address: "2001:db8::100", MacAddress: "52:54:00:00:00:01"}
+		if _, err := a.AddSecurityGroup(ctx, req); err != nil {
+			t.Fatalf("%s: failed to add security group: %+v", test.name, err)
+		}
+		f.ip6tables.failRestore = test.failRestore
+		f.netlink.conntrackErr = test.conntrackErr
+
+		_, err := a.RemoveSecurityGroup(ctx, &pb.RemoveSecurityGroupRequest{Interface: "tap0"})
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %+v", test.name, test.want, err)
+			continue
+		}
+		record, err := a.securityGroups.Get("tap0")
+		if err != nil {
+			t.Fatalf("%s: failed to get security group record: %+v", test.name, err)
+		}
+		if got := record != nil; got != test.wantRecorded {
+			t.Errorf("%s: want recorded %t, but got %t", test.name, test.wantRecorded, got)
+		}
+
+		// a retry removes the security group
+		f.ip6tables.failRestore = ""
+		if _, err := a.RemoveSecurityGroup(ctx, &pb.RemoveSecurityGroupRequest{Interface: "tap0"}); err != nil {
+			t.Errorf("%s: should not be error on retry but: %+v", test.name, err)
+		}
+		if f.iptables.hasChain(tableFilter, "callisto-itap0") || f.ip6tables.hasChain(tableFilter, "callisto-itap0") {
+			t.Errorf("%s: chains should be removed on retry", test.name)
+		}
+	}
+}
+
+func TestGetValidChainName(t *testing.T) {
+	tests := []struct {
+		prefix string
+		intf   string
+		want   string
+	}{
+		{
+			prefix: chainCallistoINPUTPrefix,
+			intf:   "tap0",
+			want:   "callisto-itap0",
+		},
+		{
+			prefix: chainCallistoINPUTPrefix,
+			intf:   "tap-0123456789ab",
+			want:   "callisto-itap-01-558acca7d478",
+		},
+		{
+			prefix: chainCallistoINPUTPrefix,
+			intf:   "tap-0123456789abcdef",
+			want:   "callisto-itap-01-3ebfa70ae08b",
+		},
+		{
+			prefix: chainCallistoNEWINPUTPrefix,
+			intf:   "tap-0123456789abcdef",
+			want:   "callisto-nitap-0-3ebfa70ae08b",
+		},
+	}
+	for _, test := range tests {
+		if got := getValidChainName(test.prefix, test.intf); got != test.want {
+			t.Errorf("%s: want %s, but got %s", test.intf, test.want, got)
+		}
+	}
+
+	// names that were truncated to the same chain by older versions
+	a := getValidChainName(chainCallistoINPUTPrefix, "tap-0123456789abcdef-0")
+	b := getValidChainName(chainCallistoINPUTPrefix, "tap-0123456789abcdef-1")
+	if a == b {
+		t.Errorf("want different chains, but got %s", a)
+	}
+	if len(a) != maxChainNameLength {
+		t.Errorf("want %d characters, but got %s", maxChainNameLength, a)
+	}
+}
+
+func testConntrackFlow(src, dst string) *netlink.ConntrackFlow {
+	flow := &netlink.ConntrackFlow{FamilyType: netlink.FAMILY_V4}
+	if net.ParseIP(src).To4() == nil {
+		flow.FamilyType = netlink.FAMILY_V6
+	}
+	flow.Forward.SrcIP = net.ParseIP(src)
+	flow.Forward.DstIP = net.ParseIP(dst)
+	flow.Reverse.SrcIP = net.ParseIP(dst)
+	flow.Reverse.DstIP = net.ParseIP(src)
+	return flow
+}
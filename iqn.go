@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 
-	"github.com/lovi-cloud/go-os-brick/osbrick"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -11,7 +10,9 @@ import (
 )
 
 func (a *agent) GetISCSIQualifiedName(ctx context.Context, req *pb.GetISCSIQualifiedNameRequest) (*pb.GetISCSIQualifiedNameResponse, error) {
-	iqn, err := osbrick.GetIQN(ctx)
+	ctx, span := startSpan(ctx, "iscsi.GetIQN")
+	iqn, err := a.osbrick.GetIQN(ctx)
+	endSpan(span, err)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get iqn: %+v", err)
 	}
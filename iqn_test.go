@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+func TestGetISCSIQualifiedName(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{
+			name: "iqn",
+			want: codes.OK,
+		},
+		{
+			name: "missing initiator name",
+			err:  fmt.Errorf("failed to open /etc/iscsi/initiatorname.iscsi"),
+			want: codes.Internal,
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		f.osbrick.err = test.err
+
+		resp, err := a.GetISCSIQualifiedName(context.Background(), &pb.GetISCSIQualifiedNameRequest{})
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %+v", test.name, test.want, err)
+			continue
+		}
+		if test.want == codes.OK && resp.Iqn != f.osbrick.iqn {
+			t.Errorf("%s: want %s, but got %s", test.name, f.osbrick.iqn, resp.Iqn)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultMaxHeavyOperations = 4
+)
+
+// keyedMutex is a set of mutexes identified by keys, such as domain UUIDs and bridge names.
+// A mutex is allocated while it is held or waited for.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedMutexEntry
+}
+
+type keyedMutexEntry struct {
+	ch   chan struct{}
+	refs int
+}
+
+func newKeyedMutex() *keyedMutex {
+	return &keyedMutex{
+		locks: map[string]*keyedMutexEntry{},
+	}
+}
+
+// Lock locks the mutex of key, and returns the function to unlock it.
+// It returns an error when ctx is done before the mutex is locked.
+func (k *keyedMutex) Lock(ctx context.Context, key string) (func(), error) {
+	k.mu.Lock()
+	e, ok := k.locks[key]
+	if !ok {
+		e = &keyedMutexEntry{ch: make(chan struct{}, 1)}
+		k.locks[key] = e
+	}
+	e.refs++
+	k.mu.Unlock()
+
+	select {
+	case e.ch <- struct{}{}:
+		return func() {
+			<-e.ch
+			k.release(key, e)
+		}, nil
+	case <-ctx.Done():
+		k.release(key, e)
+		return nil, ctx.Err()
+	}
+}
+
+func (k *keyedMutex) release(key string, e *keyedMutexEntry) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	e.refs--
+	if e.refs == 0 {
+		delete(k.locks, key)
+	}
+}
+
+// len returns the number of allocated mutexes.
+func (k *keyedMutex) len() int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	return len(k.locks)
+}
+
+// lockDomain serializes operations on the domain identified by uuid.
+func (a *agent) lockDomain(ctx context.Context, uuid string) (func(), error) {
+	key := strings.ToLower(strings.ReplaceAll(uuid, "-", ""))
+	unlock, err := a.domainLocks.Lock(ctx, key)
+	if err != nil {
+		return nil, status.Errorf(contextErrorCode(err), "failed to lock domain uuid=%s: %+v", uuid, err)
+	}
+	return unlock, nil
+}
+
+// lockBridge serializes operations on the bridge or the link identified by name.
+func (a *agent) lockBridge(ctx context.Context, name string) (func(), error) {
+	unlock, err := a.bridgeLocks.Lock(ctx, name)
+	if err != nil {
+		return nil, status.Errorf(contextErrorCode(err), "failed to lock bridge name=%s: %+v", name, err)
+	}
+	return unlock, nil
+}
+
+// lockRemoteGroup serializes operations on the remote group identified by name.
+func (a *agent) lockRemoteGroup(ctx context.Context, name string) (func(), error) {
+	unlock, err := a.remoteGroupLocks.Lock(ctx, name)
+	if err != nil {
+		return nil, status.Errorf(contextErrorCode(err), "failed to lock remote group name=%s: %+v", name, err)
+	}
+	return unlock, nil
+}
+
+// lockSecurityGroup serializes operations on the security group of the interface identified by name.
+func (a *agent) lockSecurityGroup(ctx context.Context, name string) (func(), error) {
+	unlock, err := a.securityGroupLocks.Lock(ctx, name)
+	if err != nil {
+		return nil, status.Errorf(contextErrorCode(err), "failed to lock security group interface=%s: %+v", name, err)
+	}
+	return unlock, nil
+}
+
+// acquireHeavyOperation limits the number of concurrent heavy operations such as iSCSI logins.
+func (a *agent) acquireHeavyOperation(ctx context.Context) (func(), error) {
+	if err := a.heavyOperations.Acquire(ctx, 1); err != nil {
+		return nil, status.Errorf(contextErrorCode(err), "failed to wait for other heavy operations: %+v", err)
+	}
+	return func() { a.heavyOperations.Release(1) }, nil
+}
+
+func contextErrorCode(err error) codes.Code {
+	if err == context.Canceled {
+		return codes.Canceled
+	}
+	return codes.DeadlineExceeded
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+func TestKeyedMutexLock(t *testing.T) {
+	const goroutines = 50
+
+	tests := []struct {
+		name string
+		keys []string
+	}{
+		{
+			name: "same key",
+			keys: []string{"br1000"},
+		},
+		{
+			name: "multiple keys",
+			keys: []string{"br1000", "br1001", "br1002"},
+		},
+	}
+	for _, test := range tests {
+		k := newKeyedMutex()
+		// counters are not protected by anything but k, so the race detector reports a broken lock
+		counters := map[string]*int{}
+		inFlight := map[string]*int32{}
+		for _, key := range test.keys {
+			counters[key] = new(int)
+			inFlight[key] = new(int32)
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < goroutines; i++ {
+			for _, key := range test.keys {
+				wg.Add(1)
+				go func(key string) {
+					defer wg.Done()
+
+					unlock, err := k.Lock(context.Background(), key)
+					if err != nil {
+						t.Errorf("%s: failed to lock: %+v", test.name, err)
+						return
+					}
+					defer unlock()
+
+					if n := atomic.AddInt32(inFlight[key], 1); n != 1 {
+						t.Errorf("%s: %d goroutines hold the lock of %s", test.name, n, key)
+					}
+					c := *counters[key]
+					time.Sleep(time.Microsecond)
+					*counters[key] = c + 1
+					atomic.AddInt32(inFlight[key], -1)
+				}(key)
+			}
+		}
+		wg.Wait()
+
+		for _, key := range test.keys {
+			if *counters[key] != goroutines {
+				t.Errorf("%s: want %d, but got %d (key=%s)", test.name, goroutines, *counters[key], key)
+			}
+		}
+		if n := k.len(); n != 0 {
+			t.Errorf("%s: want all mutexes to be released, but %d remain", test.name, n)
+		}
+	}
+}
+
+func TestKeyedMutexLockTimeout(t *testing.T) {
+	k := newKeyedMutex()
+	unlock, err := k.Lock(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("failed to lock: %+v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := k.Lock(ctx, "a"); err != context.DeadlineExceeded {
+		t.Errorf("want %v, but got %v", context.DeadlineExceeded, err)
+	}
+
+	unlockB, err := k.Lock(ctx, "b")
+	if err == nil {
+		unlockB()
+	}
+
+	unlock()
+	if n := k.len(); n != 0 {
+		t.Errorf("want all mutexes to be released, but %d remain", n)
+	}
+}
+
+func TestAgentLockDomain(t *testing.T) {
+	a := &agent{domainLocks: newKeyedMutex()}
+
+	tests := []struct {
+		name   string
+		locked string
+		uuid   string
+		want   codes.Code
+	}{
+		{
+			name:   "same uuid",
+			locked: "f6d5c9b4-1f73-4a8f-9d8e-3f3b0a1c2d4e",
+			uuid:   "f6d5c9b4-1f73-4a8f-9d8e-3f3b0a1c2d4e",
+			want:   codes.DeadlineExceeded,
+		},
+		{
+			name:   "same uuid in another format",
+			locked: "f6d5c9b4-1f73-4a8f-9d8e-3f3b0a1c2d4e",
+			uuid:   "F6D5C9B41F734A8F9D8E3F3B0A1C2D4E",
+			want:   codes.DeadlineExceeded,
+		},
+		{
+			name:   "different uuid",
+			locked: "f6d5c9b4-1f73-4a8f-9d8e-3f3b0a1c2d4e",
+			uuid:   "0a1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d",
+			want:   codes.OK,
+		},
+	}
+	for _, test := range tests {
+		unlock, err := a.lockDomain(context.Background(), test.locked)
+		if err != nil {
+			t.Fatalf("%s: failed to lock: %+v", test.name, err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		unlock2, err := a.lockDomain(ctx, test.uuid)
+		cancel()
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %s", test.name, test.want, got)
+		}
+		if err == nil {
+			unlock2()
+		}
+		unlock()
+	}
+}
+
+func TestAgentAcquireHeavyOperation(t *testing.T) {
+	const limit = 3
+	a := &agent{heavyOperations: semaphore.NewWeighted(limit)}
+
+	var inFlight, max int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			release, err := a.acquireHeavyOperation(context.Background())
+			if err != nil {
+				t.Errorf("failed to acquire: %+v", err)
+				return
+			}
+			defer release()
+
+			n := atomic.AddInt32(&inFlight, 1)
+			for {
+				m := atomic.LoadInt32(&max)
+				if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&inFlight, -1)
+		}()
+	}
+	wg.Wait()
+
+	if max > limit {
+		t.Errorf("want at most %d concurrent operations, but got %d", limit, max)
+	}
+}
+
+func TestTemplatesConcurrentExecute(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var buff bytes.Buffer
+			if err := networkTmpl.Execute(&buff, &pb.AddBridgeRequest{Name: "br1000"}); err != nil {
+				t.Errorf("failed to execute network template: %+v", err)
+			}
+			if err := attachDiskTmpl.Execute(&buff, &pb.AttachBlockDeviceRequest{SourceDevice: "/dev/sdb", TargetDevice: "vdb"}); err != nil {
+				t.Errorf("failed to execute attach disk template: %+v", err)
+			}
+			if err := detachDiskTmpl.Execute(&buff, &pb.DetachBlockDeviceRequest{SourceDevice: "/dev/sdb", TargetDevice: "vdb"}); err != nil {
+				t.Errorf("failed to execute detach disk template: %+v", err)
+			}
+			if err := interfaceTmpl.Execute(&buff, &pb.AttachInterfaceRequest{Bridge: "br1000", Name: "tap0"}); err != nil {
+				t.Errorf("failed to execute interface template: %+v", err)
+			}
+			if err := domainTmpl.Execute(&buff, &domainParams{
+				AddVirtualMachineRequest: &pb.AddVirtualMachineRequest{Name: "vm0", Vcpus: 1, MemoryKib: 1024},
+				CPUSets:                  []string{},
+				DatasourceURL:            "http://169.254.169.254/",
+			}); err != nil {
+				t.Errorf("failed to execute domain template: %+v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	payloadLogNone     = "none"
+	payloadLogRequest  = "request"
+	payloadLogResponse = "response"
+	payloadLogAll      = "all"
+
+	redactedValue = "[REDACTED]"
+)
+
+// secretFieldPattern matches the names of fields that hold secrets.
+var secretFieldPattern = regexp.MustCompile(`(?i)(password|passwd|secret|token|private_key|user_data|userdata)`)
+
+type payloadLogConfig struct {
+	// Default is the policy of methods that are not listed in Methods.
+	Default string `yaml:"default"`
+	// Methods maps a full method name (e.g. /agent.Agent/AddVirtualMachine) to its policy.
+	Methods map[string]string `yaml:"methods"`
+}
+
+func (c payloadLogConfig) validate() error {
+	if err := validatePayloadLogPolicy(c.Default); err != nil {
+		return fmt.Errorf("invalid default: %w", err)
+	}
+	for method, policy := range c.Methods {
+		if err := validatePayloadLogPolicy(policy); err != nil {
+			return fmt.Errorf("invalid policy of %s: %w", method, err)
+		}
+	}
+	return nil
+}
+
+func validatePayloadLogPolicy(policy string) error {
+	switch policy {
+	case payloadLogNone, payloadLogRequest, payloadLogResponse, payloadLogAll:
+		return nil
+	default:
+		return fmt.Errorf("unknown payload log policy %q", policy)
+	}
+}
+
+// payloadLogger logs request and response payloads of gRPC methods
+// according to per-method policies, with secret fields redacted.
+type payloadLogger struct {
+	mu     sync.RWMutex
+	config payloadLogConfig
+}
+
+func newPayloadLogger(config payloadLogConfig) *payloadLogger {
+	return &payloadLogger{
+		config: config,
+	}
+}
+
+// SetConfig replaces the policies.
+func (p *payloadLogger) SetConfig(config payloadLogConfig) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.config = config
+}
+
+func (p *payloadLogger) policy(fullMethod string) (logRequest, logResponse bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	policy, ok := p.config.Methods[fullMethod]
+	if !ok {
+		policy = p.config.Default
+	}
+	switch policy {
+	case payloadLogRequest:
+		return true, false
+	case payloadLogResponse:
+		return false, true
+	case payloadLogAll:
+		return true, true
+	default:
+		return false, false
+	}
+}
+
+// UnaryServerInterceptor returns a new unary server interceptor that logs the payloads.
+// It must be chained after grpc_zap.UnaryServerInterceptor to log with the request scoped logger.
+func (p *payloadLogger) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		logRequest, logResponse := p.policy(info.FullMethod)
+		logger := ctxzap.Extract(ctx)
+		if logRequest {
+			logger.Info("server request payload logged as grpc.request.content field", payloadField("grpc.request.content", req))
+		}
+		resp, err := handler(ctx, req)
+		if logResponse && err == nil {
+			logger.Info("server response payload logged as grpc.response.content field", payloadField("grpc.response.content", resp))
+		}
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns a new stream server interceptor that logs the payloads.
+// It must be chained after grpc_zap.StreamServerInterceptor to log with the request scoped logger.
+func (p *payloadLogger) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		logRequest, logResponse := p.policy(info.FullMethod)
+		if !logRequest && !logResponse {
+			return handler(srv, stream)
+		}
+		return handler(srv, &loggingServerStream{
+			ServerStream: stream,
+			logger:       ctxzap.Extract(stream.Context()),
+			logRequest:   logRequest,
+			logResponse:  logResponse,
+		})
+	}
+}
+
+type loggingServerStream struct {
+	grpc.ServerStream
+	logger      *zap.Logger
+	logRequest  bool
+	logResponse bool
+}
+
+func (s *loggingServerStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if s.logResponse && err == nil {
+		s.logger.Info("server response payload logged as grpc.response.content field", payloadField("grpc.response.content", m))
+	}
+	return err
+}
+
+func (s *loggingServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if s.logRequest && err == nil {
+		s.logger.Info("server request payload logged as grpc.request.content field", payloadField("grpc.request.content", m))
+	}
+	return err
+}
+
+func payloadField(key string, payload interface{}) zap.Field {
+	msg, ok := payload.(proto.Message)
+	if !ok {
+		return zap.String(key, fmt.Sprintf("payload is not a proto message: %T", payload))
+	}
+	b, err := protojson.Marshal(redact(msg))
+	if err != nil {
+		return zap.String(key, fmt.Sprintf("failed to marshal payload: %+v", err))
+	}
+	return zap.Reflect(key, json.RawMessage(b))
+}
+
+// redact returns a copy of msg whose secret fields are replaced with redactedValue.
+func redact(msg proto.Message) proto.Message {
+	msg = proto.Clone(msg)
+	redactMessage(msg.ProtoReflect())
+	return msg
+}
+
+func redactMessage(m protoreflect.Message) {
+	type update struct {
+		fd protoreflect.FieldDescriptor
+		v  protoreflect.Value
+	}
+	var updates []update
+
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		secret := secretFieldPattern.MatchString(string(fd.Name()))
+		switch {
+		case fd.IsMap():
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				if fd.MapValue().Message() != nil {
+					redactMessage(mv.Message())
+				} else if secret {
+					v.Map().Set(k, redactedScalar(fd.MapValue()))
+				}
+				return true
+			})
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				if fd.Message() != nil {
+					redactMessage(list.Get(i).Message())
+				} else if secret {
+					list.Set(i, redactedScalar(fd))
+				}
+			}
+		case fd.Message() != nil:
+			redactMessage(v.Message())
+		case secret:
+			updates = append(updates, update{fd: fd, v: redactedScalar(fd)})
+		}
+		return true
+	})
+
+	for _, u := range updates {
+		m.Set(u.fd, u.v)
+	}
+}
+
+func redactedScalar(fd protoreflect.FieldDescriptor) protoreflect.Value {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(redactedValue)
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes([]byte(redactedValue))
+	default:
+		return fd.Default()
+	}
+}
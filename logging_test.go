@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// newTestSecretMessage returns a message that has secret fields of every shape next to a plain one.
+func newTestSecretMessage(t *testing.T) proto.Message {
+	t.Helper()
+
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, label descriptorpb.FieldDescriptorProto_Label, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(number),
+			Type:   typ.Enum(),
+			Label:  label.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	str := descriptorpb.FieldDescriptorProto_TYPE_STRING
+	msg := descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("logging_test.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Request"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("name", 1, str, optional, ""),
+					field("user_data", 2, descriptorpb.FieldDescriptorProto_TYPE_BYTES, optional, ""),
+					field("password", 3, str, optional, ""),
+					field("credential", 4, msg, optional, ".test.Credential"),
+					field("api_tokens", 5, str, repeated, ""),
+					field("secrets", 6, msg, repeated, ".test.Request.SecretsEntry"),
+				},
+				NestedType: []*descriptorpb.DescriptorProto{
+					{
+						Name: proto.String("SecretsEntry"),
+						Field: []*descriptorpb.FieldDescriptorProto{
+							field("key", 1, str, optional, ""),
+							field("value", 2, str, optional, ""),
+						},
+						Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+					},
+				},
+			},
+			{
+				Name: proto.String("Credential"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("username", 1, str, optional, ""),
+					field("passwd", 2, str, optional, ""),
+				},
+			},
+		},
+	}
+	fd, err := protodesc.NewFile(file, nil)
+	if err != nil {
+		t.Fatalf("failed to build file descriptor: %+v", err)
+	}
+	md := fd.Messages().ByName("Request")
+	m := dynamicpb.NewMessage(md)
+	m.Set(md.Fields().ByName("name"), protoreflect.ValueOfString("vm1"))
+	m.Set(md.Fields().ByName("user_data"), protoreflect.ValueOfBytes([]byte("#cloud-config\npassword: hunter2")))
+	m.Set(md.Fields().ByName("password"), protoreflect.ValueOfString("hunter2"))
+
+	credential := m.Mutable(md.Fields().ByName("credential")).Message()
+	cd := fd.Messages().ByName("Credential")
+	credential.Set(cd.Fields().ByName("username"), protoreflect.ValueOfString("admin"))
+	credential.Set(cd.Fields().ByName("passwd"), protoreflect.ValueOfString("hunter2"))
+
+	tokens := m.Mutable(md.Fields().ByName("api_tokens")).List()
+	tokens.Append(protoreflect.ValueOfString("hunter2"))
+	secrets := m.Mutable(md.Fields().ByName("secrets")).Map()
+	secrets.Set(protoreflect.ValueOfString("root").MapKey(), protoreflect.ValueOfString("hunter2"))
+
+	return m
+}
+
+func TestPayloadFieldRedaction(t *testing.T) {
+	msg := newTestSecretMessage(t)
+	original := proto.Clone(msg)
+
+	core, logs := observer.New(zap.InfoLevel)
+	zap.New(core).Info("payload", payloadField("grpc.request.content", msg))
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("want 1 log entry, but got %d", len(entries))
+	}
+	raw, ok := entries[0].ContextMap()["grpc.request.content"].(json.RawMessage)
+	if !ok {
+		t.Fatalf("want json payload, but got %T", entries[0].ContextMap()["grpc.request.content"])
+	}
+	logged := string(raw)
+	if strings.Contains(logged, "hunter2") || strings.Contains(logged, "cloud-config") {
+		t.Errorf("secrets should be redacted, but got %s", logged)
+	}
+
+	var got struct {
+		Name       string            `json:"name"`
+		UserData   []byte            `json:"userData"`
+		Password   string            `json:"password"`
+		Credential map[string]string `json:"credential"`
+		APITokens  []string          `json:"apiTokens"`
+		Secrets    map[string]string `json:"secrets"`
+	}
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("failed to unmarshal payload %s: %+v", logged, err)
+	}
+	for name, pair := range map[string][2]string{
+		"name":                {got.Name, "vm1"},
+		"user_data":           {string(got.UserData), redactedValue},
+		"password":            {got.Password, redactedValue},
+		"credential.username": {got.Credential["username"], "admin"},
+		"credential.passwd":   {got.Credential["passwd"], redactedValue},
+		"api_tokens":          {strings.Join(got.APITokens, ","), redactedValue},
+		"secrets":             {got.Secrets["root"], redactedValue},
+	} {
+		if pair[0] != pair[1] {
+			t.Errorf("want %q in %s, but got %q", pair[1], name, pair[0])
+		}
+	}
+
+	// the payload passed to the handler is not modified
+	if !proto.Equal(msg, original) {
+		t.Errorf("payload should not be modified by redaction")
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"net"
 	"os"
 	"strings"
-	"time"
 
 	libvirt "github.com/digitalocean/go-libvirt"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
@@ -22,23 +21,41 @@ import (
 	pb "github.com/lovi-cloud/teleskop/protoc/agent"
 
 	"github.com/vishvananda/netlink"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
 	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
 	"google.golang.org/grpc"
-)
-
-const (
-	listenAddress = ":5000"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 type agent struct {
-	libvirtClient   *libvirt.Libvirt
-	datastoreClient dspb.SatelitDatastoreClient
-	dhcpServer      *dhcp.Server
+	libvirt            libvirtProvider
+	netlink            netlinkAPI
+	osbrick            osbrickAPI
+	preflight          *preflightHost
+	firewall           firewallBackend
+	securityGroups     *securityGroupStore
+	securityGroupIndex *securityGroupIndex
+	datastoreClient    dspb.SatelitDatastoreClient
+	dhcpServer         *dhcp.Server
+	logger             *zap.Logger
+
+	domainLocks        *keyedMutex
+	bridgeLocks        *keyedMutex
+	remoteGroupLocks   *keyedMutex
+	securityGroupLocks *keyedMutex
+	heavyOperations    *semaphore.Weighted
+	operations         *operationManager
 
-	interfaceName string
+	interfaceName       string
+	dhcpInterfacePrefix string
+	datasourceURL       string
 }
 
 func main() {
@@ -48,9 +65,9 @@ func main() {
 	}
 }
 
-func initLogger() (*zap.Logger, error) {
+func initLogger(level zap.AtomicLevel) (*zap.Logger, error) {
 	return zap.Config{
-		Level:    zap.NewAtomicLevelAt(zap.InfoLevel),
+		Level:    level,
 		Encoding: "json",
 		EncoderConfig: zapcore.EncoderConfig{
 			TimeKey:        "Time",
@@ -70,56 +87,92 @@ func initLogger() (*zap.Logger, error) {
 }
 
 func run() error {
-	var (
-		satelitEndpoint   string
-		teleskopInterface string
-	)
-	flag.StringVar(&satelitEndpoint, "satelit", "127.0.0.1:9263", "satelit datastore api endpoint")
-	flag.StringVar(&teleskopInterface, "intf", "bond0.1000", "teleskop listen interface")
-	flag.Parse()
+	cfg, err := loadConfig(os.Args[0], os.Args[1:])
+	if err == flag.ErrHelp {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if cfg.Preflight.Only {
+		return runPreflight(context.Background(), cfg, os.Stdout)
+	}
 
 	links, err := netlink.LinkList()
 	if err != nil {
 		return err
 	}
-	if !isValidLinkName(links, teleskopInterface) {
-		return fmt.Errorf("invalid interface name: intf=%s", teleskopInterface)
+	if !isValidLinkName(links, cfg.Interface) {
+		return fmt.Errorf("invalid interface name: intf=%s", cfg.Interface)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
-
-	logger, err := initLogger()
+	level, err := cfg.zapLevel()
 	if err != nil {
-		return fmt.Errorf("failed to init logger: %w", err)
+		return err
 	}
-
-	var dialer net.Dialer
-	libvirtConn, err := dialer.DialContext(ctx, "tcp", "127.0.0.1:16509")
+	atomicLevel := zap.NewAtomicLevelAt(level)
+	logger, err := initLogger(atomicLevel)
 	if err != nil {
-		return fmt.Errorf("failed to dial to libvirtd: %w", err)
+		return fmt.Errorf("failed to init logger: %w", err)
 	}
+	logger.Info("loaded config", zap.Object("config", cfg))
 
-	libvirtClient := libvirt.New(libvirtConn)
-	if err := libvirtClient.Connect(); err != nil {
-		return fmt.Errorf("failed to connect to libvirtd: %w", err)
-	}
+	preflight := newPreflightHost()
+	logPreflightChecks(context.Background(), cfg, preflight, logger)
+
+	signalCtx, stop := notifyShutdown(logger)
+	defer stop()
+
+	ctx, cancel := context.WithTimeout(signalCtx, cfg.StartupTimeout)
+	defer cancel()
 
-	libvirtVersion, err := libvirtClient.ConnectGetLibVersion()
+	shutdownTracerProvider, err := initTracerProvider(ctx, cfg.Tracing)
 	if err != nil {
-		return fmt.Errorf("failed to get libvirtd versoin: %w", err)
+		return fmt.Errorf("failed to init tracer provider: %w", err)
 	}
-	fmt.Printf("connect to libvirtd version = %d\n", libvirtVersion)
 
-	lis, err := net.Listen("tcp", listenAddress)
+	libvirtConn := newLibvirtConnection(cfg.LibvirtAddress, logger)
+	libvirtConn.OnConnect(func(ctx context.Context, client *libvirt.Libvirt) error {
+		libvirtVersion, err := client.ConnectGetLibVersion()
+		if err != nil {
+			return fmt.Errorf("failed to get libvirtd versoin: %w", err)
+		}
+		logger.Info("connected to libvirtd", zap.Uint64("version", libvirtVersion))
+		return nil
+	})
+	if err := libvirtConn.Connect(ctx); err != nil {
+		return err
+	}
+
+	lis, err := net.Listen("tcp", cfg.ListenAddress)
 	if err != nil {
 		return fmt.Errorf("failed to listen address: %w", err)
 	}
 
+	satelitCreds := grpc.WithInsecure()
+	if cfg.SatelitTLS.Enabled() {
+		reloader, err := newCertificateReloader(cfg.SatelitTLS, logger)
+		if err != nil {
+			return fmt.Errorf("failed to load satelit tls certificate: %w", err)
+		}
+		satelitCreds = grpc.WithTransportCredentials(credentials.NewTLS(reloader.ClientConfig()))
+	} else {
+		logger.Warn("dialing to satelit datastore api without tls")
+	}
 	grpcConn, err := grpc.DialContext(ctx,
-		satelitEndpoint,
-		grpc.WithInsecure(),
+		cfg.SatelitEndpoint,
+		satelitCreds,
 		grpc.WithBlock(),
+		grpc.WithUnaryInterceptor(otelgrpc.UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(otelgrpc.StreamClientInterceptor()),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff: backoff.Config{
+				BaseDelay:  defaultReconnectMinBackoff,
+				Multiplier: backoff.DefaultConfig.Multiplier,
+				Jitter:     backoff.DefaultConfig.Jitter,
+				MaxDelay:   defaultReconnectMaxBackoff,
+			},
+		}),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to dial to satelit datastore api: %w", err)
@@ -130,48 +183,177 @@ func run() error {
 		grpc_zap.WithMessageProducer(grpc_zap.DefaultMessageProducer),
 	}
 	grpc_zap.ReplaceGrpcLoggerV2(logger)
-	grpcServer := grpc.NewServer(
-		grpc_middleware.WithUnaryServerChain(
-			grpc_ctxtags.UnaryServerInterceptor(),
-			grpc_zap.PayloadUnaryServerInterceptor(logger, func(ctx context.Context, fullMethodName string, servingObject interface{}) bool {
-				return true
-			}),
-			grpc_zap.UnaryServerInterceptor(logger, opts...),
-		),
-		grpc_middleware.WithStreamServerChain(
-			grpc_ctxtags.StreamServerInterceptor(),
-			grpc_zap.PayloadStreamServerInterceptor(logger, func(ctx context.Context, fullMethodName string, servingObject interface{}) bool {
-				return true
-			}),
-			grpc_zap.StreamServerInterceptor(logger, opts...),
-		),
-	)
-	dhcpServer := dhcp.NewServer(datastoreClient)
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		otelgrpc.UnaryServerInterceptor(),
+		grpc_ctxtags.UnaryServerInterceptor(),
+		grpc_zap.UnaryServerInterceptor(logger, opts...),
+	}
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		otelgrpc.StreamServerInterceptor(),
+		grpc_ctxtags.StreamServerInterceptor(),
+		grpc_zap.StreamServerInterceptor(logger, opts...),
+	}
+	if cfg.Authorization.Enabled() {
+		authz, err := newAuthorizer(cfg.Authorization)
+		if err != nil {
+			return fmt.Errorf("failed to create authorizer: %w", err)
+		}
+		unaryInterceptors = append(unaryInterceptors, authz.UnaryServerInterceptor())
+		streamInterceptors = append(streamInterceptors, authz.StreamServerInterceptor())
+	}
+	payloadLogger := newPayloadLogger(cfg.PayloadLog)
+	unaryInterceptors = append(unaryInterceptors, payloadLogger.UnaryServerInterceptor())
+	streamInterceptors = append(streamInterceptors, payloadLogger.StreamServerInterceptor())
+	if cfg.IdempotencyWindow > 0 {
+		unaryInterceptors = append(unaryInterceptors, newIdempotencyCache(cfg.IdempotencyWindow).UnaryServerInterceptor())
+	}
+	serverOpts := []grpc.ServerOption{
+		grpc_middleware.WithUnaryServerChain(unaryInterceptors...),
+		grpc_middleware.WithStreamServerChain(streamInterceptors...),
+	}
+	if cfg.TLS.Enabled() {
+		reloader, err := newCertificateReloader(cfg.TLS, logger)
+		if err != nil {
+			return fmt.Errorf("failed to load tls certificate: %w", err)
+		}
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(reloader.ServerConfig(len(cfg.Authorization.Tokens) != 0))))
+	} else {
+		logger.Warn("agent api is serving without tls")
+	}
+	operations, err := newOperationManager(cfg.Operations, logger)
+	if err != nil {
+		return fmt.Errorf("failed to load operations: %w", err)
+	}
+	securityGroups, err := newSecurityGroupStore(cfg.SecurityGroup.StateDir)
+	if err != nil {
+		return fmt.Errorf("failed to open security group records: %w", err)
+	}
+
+	firewall, err := newFirewallBackend(cfg.SecurityGroup.Backend, logger)
+	if err != nil {
+		return err
+	}
+
+	grpcServer := grpc.NewServer(serverOpts...)
+	dhcpServer := dhcp.NewServer(datastoreClient, cfg.DHCP.InterfacePrefix, logger)
 	agentServer := &agent{
-		libvirtClient:   libvirtClient,
-		datastoreClient: datastoreClient,
-		dhcpServer:      dhcpServer,
-		interfaceName:   teleskopInterface,
+		libvirt:             libvirtConn,
+		netlink:             hostNetlink{},
+		osbrick:             hostOSBrick{},
+		preflight:           preflight,
+		firewall:            firewall,
+		securityGroups:      securityGroups,
+		securityGroupIndex:  newSecurityGroupIndex(),
+		datastoreClient:     datastoreClient,
+		dhcpServer:          dhcpServer,
+		logger:              logger,
+		interfaceName:       cfg.Interface,
+		dhcpInterfacePrefix: cfg.DHCP.InterfacePrefix,
+		datasourceURL:       cfg.Metadata.DatasourceURL,
+		domainLocks:         newKeyedMutex(),
+		bridgeLocks:         newKeyedMutex(),
+		remoteGroupLocks:    newKeyedMutex(),
+		securityGroupLocks:  newKeyedMutex(),
+		heavyOperations:     semaphore.NewWeighted(int64(cfg.MaxHeavyOperations)),
+		operations:          operations,
 	}
 	pb.RegisterAgentServer(grpcServer, agentServer)
-	metadataServer := metadata.New(datastoreClient)
+	metadataServer := metadata.New(datastoreClient, logger)
 
-	if err := setup(ctx, teleskopInterface, agentServer); err != nil {
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	healthChecker := newHealthChecker(healthServer, cfg.HealthCheck.Interval, logger,
+		libvirtProbe(libvirtConn),
+		satelitProbe(grpcConn),
+		dhcpProbe(dhcpServer),
+		metadataProbe(metadataServer),
+		firewallProbe(firewall),
+	)
+
+	port, err := cfg.listenPort()
+	if err != nil {
 		return err
 	}
+	if err := setup(ctx, cfg.Interface, port, agentServer); err != nil {
+		return err
+	}
+	if err := agentServer.initSecurityGroupChains(ctx); err != nil {
+		return fmt.Errorf("failed to initialize security group chains: %w", err)
+	}
+
+	reconciler := newBridgeReconciler(agentServer, trimVlanID(cfg.Interface), cfg.Reconcile, logger)
+	verifier := newSecurityGroupVerifier(agentServer, cfg.SecurityGroup.VerifyInterval, logger)
+	reloader := &configReloader{
+		name:          os.Args[0],
+		args:          os.Args[1:],
+		current:       cfg,
+		level:         atomicLevel,
+		payloadLogger: payloadLogger,
+		reconciler:    reconciler,
+		verifier:      verifier,
+		logger:        logger,
+	}
+
+	libvirtConn.OnConnect(func(ctx context.Context, _ *libvirt.Libvirt) error {
+		return reconciler.Reconcile(ctx)
+	})
+
+	shutdowner := &shutdowner{
+		grpcServer:     grpcServer,
+		healthServer:   healthServer,
+		dhcpServer:     dhcpServer,
+		metadataServer: metadataServer,
+		operations:     operations,
+		libvirtConn:    libvirtConn,
+		satelitConn:    grpcConn,
+		shutdownTracer: shutdownTracerProvider,
+		logger:         logger,
+	}
 
-	eg, ctx := errgroup.WithContext(ctx)
+	eg, egCtx := errgroup.WithContext(signalCtx)
 	eg.Go(func() error {
-		fmt.Printf("listening on address %s\n", listenAddress)
+		<-egCtx.Done()
+		logger.Info("shutting down", zap.Duration("timeout", cfg.ShutdownTimeout))
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+		return shutdowner.Shutdown(ctx)
+	})
+	eg.Go(func() error {
+		return libvirtConn.Run(egCtx)
+	})
+	eg.Go(func() error {
+		return watchSatelitConnection(egCtx, grpcConn, logger)
+	})
+	eg.Go(func() error {
+		logger.Info("listening agent api", zap.String("address", cfg.ListenAddress))
 		return grpcServer.Serve(lis)
 	})
 	eg.Go(func() error {
-		fmt.Printf("listening on address %s\n", "0.0.0.0:67")
-		return dhcpServer.ListenAndServe()
+		logger.Info("listening dhcp server", zap.String("address", cfg.DHCP.ListenAddress))
+		return dhcpServer.ListenAndServe(cfg.DHCP.ListenAddress)
+	})
+	eg.Go(func() error {
+		logger.Info("listening metadata server", zap.String("address", cfg.Metadata.ListenAddress))
+		return metadataServer.Serve(egCtx, cfg.Metadata.ListenAddress)
+	})
+	eg.Go(func() error {
+		logger.Info("listening metrics server", zap.String("address", cfg.MetricsListenAddress))
+		return serveMetrics(egCtx, cfg.MetricsListenAddress, atomicLevel)
+	})
+	eg.Go(func() error {
+		return reconciler.Run(egCtx)
+	})
+	eg.Go(func() error {
+		return operations.Run(egCtx)
+	})
+	eg.Go(func() error {
+		return verifier.Run(egCtx)
+	})
+	eg.Go(func() error {
+		return healthChecker.Run(egCtx)
 	})
 	eg.Go(func() error {
-		fmt.Printf("listening on address %s\n", "0.0.0.0:80")
-		return metadataServer.Serve(context.Background(), "0.0.0.0:80")
+		return reloader.Run(egCtx)
 	})
 
 	if err := eg.Wait(); err != nil {
@@ -182,7 +364,7 @@ func run() error {
 	return nil
 }
 
-func setup(ctx context.Context, teleskopInterface string, agentServer *agent) error {
+func setup(ctx context.Context, teleskopInterface, port string, agentServer *agent) error {
 	hostname, err := os.Hostname()
 	if err != nil {
 		return err
@@ -198,7 +380,7 @@ func setup(ctx context.Context, teleskopInterface string, agentServer *agent) er
 
 	for _, addr := range addrs {
 		if ip := addr.IP.To4(); ip != nil {
-			return agentServer.setup(ctx, hostname, fmt.Sprintf("%s:%d", ip.String(), 5000), trimVlanID(teleskopInterface))
+			return agentServer.setup(ctx, hostname, net.JoinHostPort(ip.String(), port), trimVlanID(teleskopInterface))
 		}
 	}
 	return fmt.Errorf("failed to find valid address on interface=%s", teleskopInterface)
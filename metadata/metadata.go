@@ -7,25 +7,54 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
-	"os"
+	"sync"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
 	yaml "gopkg.in/yaml.v2"
 
 	pb "github.com/lovi-cloud/satelit/api/satelit_datastore"
 )
 
+var tracer = otel.Tracer("github.com/lovi-cloud/teleskop/metadata")
+
 // Server is implement metadata server
 type Server struct {
 	client pb.SatelitDatastoreClient
+	logger *zap.Logger
+
+	mutex     sync.Mutex
+	listening bool
+	srv       *http.Server
+	closed    bool
 }
 
 // New create a instance of gRPC server
-func New(client pb.SatelitDatastoreClient) *Server {
+func New(client pb.SatelitDatastoreClient, logger *zap.Logger) *Server {
 	return &Server{
 		client: client,
+		logger: logger.Named("metadata"),
 	}
 }
 
+// Listening reports whether the server is listening on the TCP network
+func (s *Server) Listening() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.listening
+}
+
+func (s *Server) setListening(listening bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.listening = listening
+}
+
 // Serve is
 func (s *Server) Serve(ctx context.Context, addr string) error {
 	l, err := net.Listen("tcp", addr)
@@ -38,32 +67,69 @@ func (s *Server) Serve(ctx context.Context, addr string) error {
 	mux.Handle("/meta-data", s.loggingHandler(s.metadataHandler()))
 	mux.Handle("/user-data", s.loggingHandler(s.userdataHandler()))
 
-	srv := http.Server{
+	srv := &http.Server{
 		Handler: mux,
 	}
 
-	idleConnsClosed := make(chan struct{})
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		l.Close()
+		return nil
+	}
+	s.srv = srv
+	s.mutex.Unlock()
+
+	stop := make(chan struct{})
+	defer close(stop)
 	go func() {
-		<-ctx.Done()
-		if err := srv.Shutdown(context.Background()); err != nil {
-			fmt.Fprintf(os.Stderr, "failed to shutdown: %+v", err)
+		select {
+		case <-ctx.Done():
+			if err := s.Shutdown(context.Background()); err != nil {
+				s.logger.Warn("failed to shutdown", zap.Error(err))
+			}
+		case <-stop:
 		}
-		close(idleConnsClosed)
 	}()
 
+	s.setListening(true)
+	defer s.setListening(false)
+
 	if err := srv.Serve(l); err != http.ErrServerClosed {
 		return fmt.Errorf("failed to serve: %w", err)
 	}
-
-	<-idleConnsClosed
 	return nil
 }
 
+// Shutdown gracefully shuts down the server until ctx is done
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mutex.Lock()
+	s.closed = true
+	srv := s.srv
+	s.mutex.Unlock()
+
+	if srv == nil {
+		return nil
+	}
+	return srv.Shutdown(ctx)
+}
+
 func (s *Server) loggingHandler(handler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracer.Start(r.Context(), "metadata "+r.URL.Path, trace.WithAttributes(
+			attribute.String("http.method", r.Method),
+			attribute.String("http.url", r.URL.String()),
+			attribute.String("net.peer.addr", r.RemoteAddr),
+		))
+		defer span.End()
+
 		rec := httptest.NewRecorder()
-		handler.ServeHTTP(rec, r)
-		fmt.Printf("http request: url=%s, remote=%s, code=%d\n", r.URL.String(), r.RemoteAddr, rec.Code)
+		handler.ServeHTTP(rec, r.WithContext(ctx))
+		span.SetAttributes(attribute.Int("http.status_code", rec.Code))
+		if rec.Code >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(rec.Code))
+		}
+		s.logger.Info("http request", zap.String("url", r.URL.String()), zap.String("remote", r.RemoteAddr), zap.Int("code", rec.Code))
 		w.WriteHeader(rec.Code)
 		io.Copy(w, rec.Body)
 	})
@@ -74,7 +140,7 @@ func (s *Server) metadataHandler() http.Handler {
 		addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr)
 		if err != nil {
 			msg := fmt.Sprintf("failed to parse request remote address: %+v", err)
-			fmt.Fprintf(os.Stderr, "%s\n", msg)
+			s.logger.Warn(msg)
 			w.WriteHeader(http.StatusBadRequest)
 			w.Write([]byte(msg))
 			return
@@ -84,7 +150,7 @@ func (s *Server) metadataHandler() http.Handler {
 		})
 		if err != nil {
 			msg := fmt.Sprintf("failed to get hostname by address: %+v", err)
-			fmt.Fprintf(os.Stderr, "%s\n", msg)
+			s.logger.Warn(msg)
 			w.WriteHeader(http.StatusInternalServerError)
 			w.Write([]byte(msg))
 			return
@@ -99,7 +165,7 @@ func (s *Server) userdataHandler() http.Handler {
 		addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr)
 		if err != nil {
 			msg := fmt.Sprintf("failed to parse request remote address: %+v", err)
-			fmt.Fprintf(os.Stderr, "%s\n", msg)
+			s.logger.Warn(msg)
 			w.WriteHeader(http.StatusBadRequest)
 			w.Write([]byte(msg))
 			return
@@ -110,7 +176,7 @@ func (s *Server) userdataHandler() http.Handler {
 		})
 		if err != nil {
 			msg := fmt.Sprintf("failed to get hostname by address: %+v", err)
-			fmt.Fprintf(os.Stderr, "%s\n", msg)
+			s.logger.Warn(msg)
 			w.WriteHeader(http.StatusInternalServerError)
 			w.Write([]byte(msg))
 			return
@@ -125,7 +191,7 @@ func (s *Server) userdataHandler() http.Handler {
 		out, err := yaml.Marshal(config)
 		if err != nil {
 			msg := fmt.Sprintf("failed to parse user-data: %+v", err)
-			fmt.Fprintf(os.Stderr, "%s\n", msg)
+			s.logger.Warn(msg)
 			w.WriteHeader(http.StatusInternalServerError)
 			w.Write([]byte(msg))
 			return
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+const (
+	metricsNamespace = "teleskop"
+)
+
+var (
+	connectionUp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "connection_up",
+		Help:      "Whether the connection to a dependency is established (1) or not (0).",
+	}, []string{"target"})
+	reconcileRunsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "reconcile",
+		Name:      "runs_total",
+		Help:      "Number of bridge reconcile runs, partitioned by result.",
+	}, []string{"result"})
+	reconcileDriftTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "reconcile",
+		Name:      "drift_total",
+		Help:      "Number of drift events detected by the bridge reconciler.",
+	}, []string{"resource", "action", "dry_run"})
+	reconcileLastSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "reconcile",
+		Name:      "last_success_timestamp_seconds",
+		Help:      "Unix timestamp of the last successful bridge reconcile run.",
+	})
+	securityGroupVerifyRunsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "security_group_verify",
+		Name:      "runs_total",
+		Help:      "Number of security group verify runs, partitioned by result.",
+	}, []string{"result"})
+	securityGroupRepairsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "security_group_verify",
+		Name:      "repairs_total",
+		Help:      "Number of iptables chains and rules repaired by the security group verifier.",
+	}, []string{"resource", "action"})
+	securityGroupVerifyLastSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "security_group_verify",
+		Name:      "last_success_timestamp_seconds",
+		Help:      "Unix timestamp of the last successful security group verify run.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		connectionUp,
+		reconcileRunsTotal,
+		reconcileDriftTotal,
+		reconcileLastSuccess,
+		securityGroupVerifyRunsTotal,
+		securityGroupRepairsTotal,
+		securityGroupVerifyLastSuccess,
+	)
+}
+
+// serveMetrics serves prometheus metrics on addr until ctx is done.
+func serveMetrics(ctx context.Context, addr string, level zap.AtomicLevel) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	// GET returns the current log level, and PUT {"level":"debug"} changes it
+	mux.Handle("/log/level", level)
+
+	srv := http.Server{
+		Handler: mux,
+	}
+
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	if err := srv.Serve(l); err != http.ErrServerClosed {
+		return fmt.Errorf("failed to serve: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,1121 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/google/nftables"
+	"github.com/google/nftables/binaryutil"
+	"github.com/google/nftables/expr"
+	"go.uber.org/zap"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+const (
+	// nftablesTable is the bridge family table of the nftables backend. Its chains are named like
+	// the iptables chains, and callisto-FORWARD is the base chain of the forward hook.
+	nftablesTable = "callisto"
+
+	// nftablesUserDataComment is the type of the comment in the user data of a rule, as nft stores it.
+	nftablesUserDataComment = 0
+
+	// offsets of the fields in the IPv4 header, the IPv6 header and the transport header
+	nftablesOffsetIPSource        = 12
+	nftablesOffsetIPDestination   = 16
+	nftablesOffsetIPv6Source      = 8
+	nftablesOffsetIPv6Destination = 24
+	nftablesOffsetSourcePort      = 0
+	nftablesOffsetDestPort        = 2
+	nftablesOffsetICMPType        = 0
+	nftablesOffsetICMPCode        = 1
+	// offset of the source address in the ethernet header
+	nftablesOffsetEtherSource = 6
+)
+
+var (
+	// ETH_P_IP and ETH_P_IPV6
+	nftablesProtocolIPv4 = []byte{0x08, 0x00}
+	nftablesProtocolIPv6 = []byte{0x86, 0xdd}
+
+	nftablesProtocolNames = map[byte]string{
+		syscall.IPPROTO_ICMP:   "icmp",
+		syscall.IPPROTO_TCP:    "tcp",
+		syscall.IPPROTO_UDP:    "udp",
+		syscall.IPPROTO_ICMPV6: "ipv6-icmp",
+	}
+)
+
+// nftRule is a rule of a chain of the nftables backend.
+type nftRule struct {
+	exprs   []expr.Any
+	comment string
+}
+
+func newNFTRule(comment string, exprs ...[]expr.Any) nftRule {
+	r := nftRule{comment: comment}
+	for _, e := range exprs {
+		r.exprs = append(r.exprs, e...)
+	}
+	return r
+}
+
+// build returns r in chain.
+func (r nftRule) build(chain *nftables.Chain) *nftables.Rule {
+	rule := &nftables.Rule{Table: chain.Table, Chain: chain, Exprs: r.exprs}
+	if r.comment != "" {
+		rule.UserData = append([]byte{nftablesUserDataComment, byte(len(r.comment) + 1)}, append([]byte(r.comment), 0)...)
+	}
+	return rule
+}
+
+// String returns r as decodeNFTRule prints it.
+func (r nftRule) String() string {
+	return decodeNFTRule(r.build(nftChain(""))).Rule
+}
+
+// nftJumpRule is a rule in chain that jumps to a chain of an interface.
+type nftJumpRule struct {
+	chain string
+	rule  nftRule
+}
+
+// nftChainRules is a chain of an interface with its rules.
+type nftChainRules struct {
+	name  string
+	rules []nftRule
+}
+
+// nftSecurityGroupDiff is the difference between the desired security group of an interface and nftables.
+type nftSecurityGroupDiff struct {
+	missingChains []nftChainRules
+	driftedChains []nftChainRules
+	missingJumps  []nftJumpRule
+}
+
+func (d nftSecurityGroupDiff) empty() bool {
+	return len(d.missingChains) == 0 && len(d.driftedChains) == 0 && len(d.missingJumps) == 0
+}
+
+// nftablesBackend applies security groups to a bridge family table by netlink, and remote groups to
+// the sets of the table. The changes of a call are sent in a batch, which the kernel applies atomically.
+type nftablesBackend struct {
+	newConn func() (nftablesAPI, error)
+	logger  *zap.Logger
+}
+
+func newNFTablesBackend(newConn func() (nftablesAPI, error), logger *zap.Logger) *nftablesBackend {
+	return &nftablesBackend{
+		newConn: newConn,
+		logger:  logger,
+	}
+}
+
+func (b *nftablesBackend) Name() string {
+	return firewallBackendNFTables
+}
+
+func (b *nftablesBackend) Check(ctx context.Context) error {
+	conn, err := b.newConn()
+	if err != nil {
+		return fmt.Errorf("failed to create nftables connection: %w", err)
+	}
+	if _, err := conn.ListTablesOfFamily(ctx, nftables.TableFamilyBridge); err != nil {
+		return fmt.Errorf("failed to list tables: %w", err)
+	}
+	return nil
+}
+
+// Setup creates the table, the shared chains and the base chain in a batch. It fails if the chains exist.
+func (b *nftablesBackend) Setup(ctx context.Context) error {
+	conn, err := b.newConn()
+	if err != nil {
+		return fmt.Errorf("failed to create nftables connection: %w", err)
+	}
+	ruleset, err := listNFTables(ctx, conn)
+	if err != nil {
+		return err
+	}
+	for _, chain := range sharedChains {
+		if _, ok := ruleset[chain]; ok {
+			return fmt.Errorf("chain %s already exists", chain)
+		}
+	}
+
+	conn.AddTable(nftTable())
+	for _, chain := range getNFTSharedChains() {
+		addNFTChain(conn, nftChain(chain.name), chain.rules)
+	}
+	conn.AddChain(nftBaseChain())
+	return commitNFTables(ctx, conn)
+}
+
+// Add creates the chains of intf and the jump rules to them in a batch.
+func (b *nftablesBackend) Add(ctx context.Context, intf link) error {
+	conn, err := b.newConn()
+	if err != nil {
+		return fmt.Errorf("failed to create nftables connection: %w", err)
+	}
+	if err := addNFTRemoteGroups(conn, intf.IngressRules, intf.EgressRules); err != nil {
+		return err
+	}
+
+	for _, chain := range getNFTSecurityGroupChains(intf) {
+		addNFTChain(conn, nftChain(chain.name), chain.rules)
+	}
+	insertNFTJumpRules(conn, getNFTJumpRules(intf))
+	return commitNFTables(ctx, conn)
+}
+
+func (b *nftablesBackend) Exists(ctx context.Context, intf link) (bool, error) {
+	conn, err := b.newConn()
+	if err != nil {
+		return false, fmt.Errorf("failed to create nftables connection: %w", err)
+	}
+	chains, err := listNFTChains(ctx, conn)
+	if err != nil {
+		return false, err
+	}
+	for _, chain := range []string{getINPUTChainName(intf), getOUTPUTChainName(intf)} {
+		if _, ok := chains[chain]; !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Update refills the input and output chains of intf with the new rules in a batch, so that every packet
+// is filtered by either the old or the new rules.
+func (b *nftablesBackend) Update(ctx context.Context, intf link) error {
+	conn, err := b.newConn()
+	if err != nil {
+		return fmt.Errorf("failed to create nftables connection: %w", err)
+	}
+	if err := addNFTRemoteGroups(conn, intf.IngressRules, intf.EgressRules); err != nil {
+		return err
+	}
+
+	replaceNFTChain(conn, nftChain(getINPUTChainName(intf)), getNFTINPUTChainRules(intf))
+	replaceNFTChain(conn, nftChain(getOUTPUTChainName(intf)), getNFTOUTPUTChainRules(intf))
+	return commitNFTables(ctx, conn)
+}
+
+// UpdateSource refills the source chain of intf in a batch. The input and output chains, and the connections
+// allowed by them, are kept.
+func (b *nftablesBackend) UpdateSource(ctx context.Context, intf link) error {
+	conn, err := b.newConn()
+	if err != nil {
+		return fmt.Errorf("failed to create nftables connection: %w", err)
+	}
+	replaceNFTChain(conn, nftChain(getSOURCEChainName(intf)), getNFTSOURCEChainRules(intf))
+	return commitNFTables(ctx, conn)
+}
+
+// Remove deletes the jump rules of intf and chains in a batch.
+func (b *nftablesBackend) Remove(ctx context.Context, intf link, chains []string) ([]net.IP, error) {
+	conn, err := b.newConn()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create nftables connection: %w", err)
+	}
+	ruleset, err := listNFTables(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, jump := range getNFTJumpRules(intf) {
+		want := jump.rule.String()
+		for _, rule := range ruleset[jump.chain] {
+			if decodeNFTRule(rule).Rule != want {
+				continue
+			}
+			if err := conn.DelRule(rule); err != nil {
+				return nil, fmt.Errorf("failed to delete %s rule: %w", jump.chain, err)
+			}
+		}
+	}
+
+	var existing []string
+	for _, chain := range chains {
+		if _, ok := ruleset[chain]; ok {
+			existing = append(existing, chain)
+		}
+	}
+	// all chains are flushed before deleting them, because the output chains jump to the source chain
+	for _, chain := range existing {
+		conn.FlushChain(nftChain(chain))
+	}
+	for _, chain := range existing {
+		conn.DelChain(nftChain(chain))
+	}
+
+	if err := commitNFTables(ctx, conn); err != nil {
+		return nil, err
+	}
+	var ipAddrs []net.IP
+	for _, rule := range ruleset[getSOURCEChainName(intf)] {
+		if ipAddr := parseSourceIPAddress(decodeNFTRule(rule).Source); ipAddr != nil {
+			ipAddrs = append(ipAddrs, ipAddr)
+		}
+	}
+	return ipAddrs, nil
+}
+
+// Chains returns the chains of the table. The iifname and oifname of the rules are returned as physdev_in and physdev_out.
+func (b *nftablesBackend) Chains(ctx context.Context, index *securityGroupIndex, intfName string) ([]*pb.IPTablesChain, error) {
+	conn, err := b.newConn()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create nftables connection: %w", err)
+	}
+	ruleset, err := listNFTables(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	shared := map[string]bool{}
+	for _, chain := range sharedChains {
+		shared[chain] = true
+	}
+	own := map[string]bool{}
+	for _, chain := range index.chainsOf(intfName) {
+		own[chain] = true
+	}
+
+	names := make([]string, 0, len(ruleset))
+	for name := range ruleset {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var chains []*pb.IPTablesChain
+	for _, name := range names {
+		if intfName != "" && !shared[name] && !own[name] {
+			continue
+		}
+
+		chain := &pb.IPTablesChain{Name: name, Interface: index.lookup(name)}
+		for _, r := range ruleset[name] {
+			rule := decodeNFTRule(r)
+			if intfName != "" && shared[name] && rule.PhysdevIn != intfName && rule.PhysdevOut != intfName {
+				continue
+			}
+			chain.Rules = append(chain.Rules, rule)
+		}
+		if intfName != "" && shared[name] && len(chain.Rules) == 0 {
+			continue
+		}
+		chains = append(chains, chain)
+	}
+
+	return chains, nil
+}
+
+// Init returns the recorded interfaces and the interfaces that the shared chains jump for.
+// Older versions did not support nftables, so there is nothing to migrate.
+func (b *nftablesBackend) Init(ctx context.Context, records []*pb.AddSecurityGroupRequest) ([]string, error) {
+	conn, err := b.newConn()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create nftables connection: %w", err)
+	}
+	ruleset, err := listNFTables(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	found := map[string]bool{}
+	for _, record := range records {
+		found[record.Interface] = true
+	}
+	for _, chain := range []string{chainCallistoSG, chainCallistoFORWARD} {
+		for _, r := range ruleset[chain] {
+			rule := decodeNFTRule(r)
+			for _, intf := range []string{rule.PhysdevIn, rule.PhysdevOut} {
+				if intf != "" {
+					found[intf] = true
+				}
+			}
+		}
+	}
+
+	interfaces := make([]string, 0, len(found))
+	for intf := range found {
+		interfaces = append(interfaces, intf)
+	}
+	sort.Strings(interfaces)
+	return interfaces, nil
+}
+
+// Verify restores the table, the shared chains, their default rules and the base chain in a batch,
+// and compares the chains and jump rules of intfs with the table.
+func (b *nftablesBackend) Verify(ctx context.Context, intfs []link) ([]securityGroupRepair, []string, error) {
+	conn, err := b.newConn()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create nftables connection: %w", err)
+	}
+	ruleset, err := listNFTables(ctx, conn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var repairs []securityGroupRepair
+	if len(ruleset) == 0 {
+		// the table is deleted or not set up, so it is created first in the batch
+		conn.AddTable(nftTable())
+	}
+	for _, chain := range getNFTSharedChains() {
+		rules, ok := ruleset[chain.name]
+		if !ok {
+			conn.AddChain(nftChain(chain.name))
+			repairs = append(repairs, securityGroupRepair{repairResourceChain, driftActionCreate, []zap.Field{zap.String("chain", chain.name)}})
+		}
+		for _, rule := range chain.rules {
+			if containsNFTRule(rules, rule) {
+				continue
+			}
+			conn.AddRule(rule.build(nftChain(chain.name)))
+			repairs = append(repairs, securityGroupRepair{repairResourceRule, driftActionCreate, []zap.Field{zap.String("chain", chain.name)}})
+		}
+	}
+	if _, ok := ruleset[chainCallistoFORWARD]; !ok {
+		conn.AddChain(nftBaseChain())
+		repairs = append(repairs, securityGroupRepair{repairResourceHook, driftActionCreate, []zap.Field{zap.String("chain", chainCallistoFORWARD)}})
+	}
+
+	if err := commitNFTables(ctx, conn); err != nil {
+		return nil, nil, err
+	}
+
+	var drifted []string
+	for _, intf := range intfs {
+		if !diffNFTSecurityGroup(ruleset, intf).empty() {
+			drifted = append(drifted, intf.Name)
+		}
+	}
+	return repairs, drifted, nil
+}
+
+// Repair creates the missing chains and jump rules of intf, and refills the drifted chains in a batch.
+func (b *nftablesBackend) Repair(ctx context.Context, intf link) ([]securityGroupRepair, error) {
+	conn, err := b.newConn()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create nftables connection: %w", err)
+	}
+	ruleset, err := listNFTables(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	diff := diffNFTSecurityGroup(ruleset, intf)
+	if diff.empty() {
+		return nil, nil
+	}
+	if err := addNFTRemoteGroups(conn, intf.IngressRules, intf.EgressRules); err != nil {
+		return nil, err
+	}
+
+	var repairs []securityGroupRepair
+	for _, chain := range diff.missingChains {
+		addNFTChain(conn, nftChain(chain.name), chain.rules)
+		repairs = append(repairs, securityGroupRepair{repairResourceChain, driftActionCreate, []zap.Field{zap.String("interface", intf.Name), zap.String("chain", chain.name)}})
+	}
+	for _, chain := range diff.driftedChains {
+		replaceNFTChain(conn, nftChain(chain.name), chain.rules)
+		repairs = append(repairs, securityGroupRepair{repairResourceChain, driftActionUpdate, []zap.Field{zap.String("interface", intf.Name), zap.String("chain", chain.name)}})
+	}
+	insertNFTJumpRules(conn, diff.missingJumps)
+	for _, jump := range diff.missingJumps {
+		repairs = append(repairs, securityGroupRepair{repairResourceJump, driftActionCreate, []zap.Field{zap.String("interface", intf.Name), zap.String("chain", jump.chain), zap.String("target", getNFTJumpTarget(jump.rule))}})
+	}
+
+	if err := commitNFTables(ctx, conn); err != nil {
+		return nil, err
+	}
+	return repairs, nil
+}
+
+// SetRemoteGroupMembers flushes the set of group and adds members in a batch, creating the table and the set if missing.
+func (b *nftablesBackend) SetRemoteGroupMembers(ctx context.Context, group string, members []net.IP) error {
+	conn, err := b.newConn()
+	if err != nil {
+		return fmt.Errorf("failed to create nftables connection: %w", err)
+	}
+
+	conn.AddTable(nftTable())
+	set := nftRemoteGroupSet(group)
+	if err := conn.AddSet(set, nil); err != nil {
+		return fmt.Errorf("failed to create set of remote group %s: %w", group, err)
+	}
+	conn.FlushSet(set)
+	if len(members) != 0 {
+		elements := make([]nftables.SetElement, 0, len(members))
+		for _, member := range members {
+			elements = append(elements, nftables.SetElement{Key: member.To4()})
+		}
+		if err := conn.SetAddElements(set, elements); err != nil {
+			return fmt.Errorf("failed to add members of remote group %s: %w", group, err)
+		}
+	}
+	return commitNFTables(ctx, conn)
+}
+
+func (b *nftablesBackend) DeleteRemoteGroup(ctx context.Context, group string) error {
+	conn, err := b.newConn()
+	if err != nil {
+		return fmt.Errorf("failed to create nftables connection: %w", err)
+	}
+	sets, err := listNFTSets(ctx, conn)
+	if err != nil {
+		return err
+	}
+	set, ok := sets[getRemoteGroupSetName(group)]
+	if !ok {
+		return errRemoteGroupNotFound
+	}
+
+	conn.DelSet(set)
+	if err := commitNFTables(ctx, conn); err != nil {
+		// the kernel refuses to delete a set referred by rules
+		if errors.Is(err, syscall.EBUSY) {
+			return errRemoteGroupInUse
+		}
+		return err
+	}
+	return nil
+}
+
+func (b *nftablesBackend) RemoteGroups(ctx context.Context) (map[string][]net.IP, error) {
+	conn, err := b.newConn()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create nftables connection: %w", err)
+	}
+	sets, err := listNFTSets(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := map[string][]net.IP{}
+	for name, set := range sets {
+		if !strings.HasPrefix(name, remoteGroupSetPrefix) {
+			continue
+		}
+		elements, err := conn.GetSetElements(ctx, set)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list elements of %s set: %w", name, err)
+		}
+		members := []net.IP{}
+		for _, element := range elements {
+			members = append(members, net.IP(append([]byte(nil), element.Key...)))
+		}
+		groups[strings.TrimPrefix(name, remoteGroupSetPrefix)] = members
+	}
+	return groups, nil
+}
+
+func nftTable() *nftables.Table {
+	return &nftables.Table{Name: nftablesTable, Family: nftables.TableFamilyBridge}
+}
+
+func nftChain(name string) *nftables.Chain {
+	return &nftables.Chain{Name: name, Table: nftTable()}
+}
+
+// nftBaseChain returns callisto-FORWARD, the base chain of the forward hook. Its policy is accept like
+// the FORWARD chain that the iptables backend hooks to, so that only the traffic of the VMs is filtered.
+func nftBaseChain() *nftables.Chain {
+	policy := nftables.ChainPolicyAccept
+	return &nftables.Chain{
+		Name:     chainCallistoFORWARD,
+		Table:    nftTable(),
+		Type:     nftables.ChainTypeFilter,
+		Hooknum:  nftables.ChainHookForward,
+		Priority: nftables.ChainPriorityFilter,
+		Policy:   &policy,
+	}
+}
+
+func nftRemoteGroupSet(group string) *nftables.Set {
+	return &nftables.Set{Table: nftTable(), Name: getRemoteGroupSetName(group), KeyType: nftables.TypeIPAddr}
+}
+
+// commitNFTables sends the queued changes in a batch. An empty batch is not sent.
+func commitNFTables(ctx context.Context, conn nftablesAPI) error {
+	if err := conn.Flush(ctx); err != nil {
+		return fmt.Errorf("failed to apply nftables batch: %w", err)
+	}
+	return nil
+}
+
+// listNFTChains returns the chains of the table. It is empty if the table does not exist.
+func listNFTChains(ctx context.Context, conn nftablesAPI) (map[string]*nftables.Chain, error) {
+	list, err := conn.ListChainsOfTableFamily(ctx, nftables.TableFamilyBridge)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chains: %w", err)
+	}
+	chains := map[string]*nftables.Chain{}
+	for _, chain := range list {
+		if chain.Table != nil && chain.Table.Name == nftablesTable {
+			chains[chain.Name] = chain
+		}
+	}
+	return chains, nil
+}
+
+// listNFTables returns the rules of every chain of the table. It is empty if the table does not exist.
+func listNFTables(ctx context.Context, conn nftablesAPI) (map[string][]*nftables.Rule, error) {
+	chains, err := listNFTChains(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	ruleset := map[string][]*nftables.Rule{}
+	for name := range chains {
+		rules, err := conn.GetRules(ctx, nftTable(), nftChain(name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s chain: %w", name, err)
+		}
+		ruleset[name] = rules
+	}
+	return ruleset, nil
+}
+
+// listNFTSets returns the sets of the table. It is empty if the table does not exist.
+func listNFTSets(ctx context.Context, conn nftablesAPI) (map[string]*nftables.Set, error) {
+	tables, err := conn.ListTablesOfFamily(ctx, nftables.TableFamilyBridge)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	sets := map[string]*nftables.Set{}
+	for _, table := range tables {
+		if table.Name != nftablesTable {
+			continue
+		}
+		list, err := conn.GetSets(ctx, nftTable())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list sets: %w", err)
+		}
+		for _, set := range list {
+			sets[set.Name] = set
+		}
+	}
+	return sets, nil
+}
+
+// addNFTRemoteGroups creates the empty sets of the remote groups referred by rules if missing,
+// so that rules can be added before the members of their groups are set.
+func addNFTRemoteGroups(conn nftablesAPI, rules ...[]securityGroupRule) error {
+	for _, group := range getRemoteGroups(rules...) {
+		if err := conn.AddSet(nftRemoteGroupSet(group), nil); err != nil {
+			return fmt.Errorf("failed to create remote group %s: %w", group, err)
+		}
+	}
+	return nil
+}
+
+// addNFTChain creates chain with rules.
+func addNFTChain(conn nftablesAPI, chain *nftables.Chain, rules []nftRule) {
+	conn.AddChain(chain)
+	for _, rule := range rules {
+		conn.AddRule(rule.build(chain))
+	}
+}
+
+// replaceNFTChain replaces the rules of chain with rules.
+func replaceNFTChain(conn nftablesAPI, chain *nftables.Chain, rules []nftRule) {
+	conn.FlushChain(chain)
+	for _, rule := range rules {
+		conn.AddRule(rule.build(chain))
+	}
+}
+
+// insertNFTJumpRules inserts jumps at the top of their chains in the order of jumps.
+func insertNFTJumpRules(conn nftablesAPI, jumps []nftJumpRule) {
+	for i := len(jumps) - 1; i >= 0; i-- {
+		conn.InsertRule(jumps[i].rule.build(nftChain(jumps[i].chain)))
+	}
+}
+
+// containsNFTRule returns whether want is one of rules.
+func containsNFTRule(rules []*nftables.Rule, want nftRule) bool {
+	s := want.String()
+	for _, rule := range rules {
+		if decodeNFTRule(rule).Rule == s {
+			return true
+		}
+	}
+	return false
+}
+
+// diffNFTSecurityGroup compares the chains and jump rules of intf with ruleset.
+func diffNFTSecurityGroup(ruleset map[string][]*nftables.Rule, intf link) nftSecurityGroupDiff {
+	var diff nftSecurityGroupDiff
+	for _, chain := range getNFTSecurityGroupChains(intf) {
+		rules, ok := ruleset[chain.name]
+		if !ok {
+			diff.missingChains = append(diff.missingChains, chain)
+			continue
+		}
+		if !nftRulesEqual(rules, chain.rules) {
+			diff.driftedChains = append(diff.driftedChains, chain)
+		}
+	}
+	for _, jump := range getNFTJumpRules(intf) {
+		if !containsNFTRule(ruleset[jump.chain], jump.rule) {
+			diff.missingJumps = append(diff.missingJumps, jump)
+		}
+	}
+	return diff
+}
+
+// nftRulesEqual returns whether rules are want, ignoring the counters.
+func nftRulesEqual(rules []*nftables.Rule, want []nftRule) bool {
+	if len(rules) != len(want) {
+		return false
+	}
+	for i, rule := range rules {
+		if decodeNFTRule(rule).Rule != want[i].String() {
+			return false
+		}
+	}
+	return true
+}
+
+// getNFTSharedChains returns the shared chains with their default rules.
+func getNFTSharedChains() []nftChainRules {
+	return []nftChainRules{
+		{name: chainCallistoSGFallback, rules: []nftRule{
+			newNFTRule("Default drop rule for unmatched traffic.", nftVerdict(expr.VerdictDrop, "")),
+		}},
+		{name: chainCallistoSG, rules: []nftRule{
+			newNFTRule("", nftVerdict(expr.VerdictAccept, "")),
+		}},
+	}
+}
+
+// getNFTSecurityGroupChains returns the chains of intf in the order they are created.
+func getNFTSecurityGroupChains(intf link) []nftChainRules {
+	return []nftChainRules{
+		{name: getSOURCEChainName(intf), rules: getNFTSOURCEChainRules(intf)},
+		{name: getOUTPUTChainName(intf), rules: getNFTOUTPUTChainRules(intf)},
+		{name: getINPUTChainName(intf), rules: getNFTINPUTChainRules(intf)},
+	}
+}
+
+// getNFTSOURCEChainRules returns the rules of the source chain of intf, which only allows the IP/MAC pairs of the VM,
+// and the link-local and unspecified IPv6 addresses with the MAC address like the iptables backend.
+func getNFTSOURCEChainRules(intf link) []nftRule {
+	source := &net.IPNet{IP: intf.IPAddress.To4(), Mask: net.CIDRMask(32, 32)}
+	rules := []nftRule{
+		newNFTRule("Allow traffic from defined IP/MAC pairs.",
+			nftProtocol(ipFamilyIPv4), nftIPNet(nftablesOffsetIPSource, source), nftEtherSource(intf.MACAddress), nftVerdict(expr.VerdictReturn, "")),
+	}
+	if intf.IPv6Address != nil {
+		source := &net.IPNet{IP: intf.IPv6Address, Mask: net.CIDRMask(128, 128)}
+		rules = append(rules, newNFTRule("Allow traffic from defined IP/MAC pairs.",
+			nftProtocol(ipFamilyIPv6), nftIPNet(nftablesOffsetIPv6Source, source), nftEtherSource(intf.MACAddress), nftVerdict(expr.VerdictReturn, "")))
+	}
+	for _, pair := range intf.AllowedAddressPairs {
+		offset := uint32(nftablesOffsetIPSource)
+		if pair.family() == ipFamilyIPv6 {
+			offset = nftablesOffsetIPv6Source
+		}
+		rules = append(rules, newNFTRule("Allow traffic from allowed address pairs.",
+			nftProtocol(pair.family()), nftIPNet(offset, pair.IPNet), nftEtherSource(pair.MACAddress), nftVerdict(expr.VerdictReturn, "")))
+	}
+	return append(rules,
+		newNFTRule("Allow link-local traffic from the MAC address of the VM.",
+			nftProtocol(ipFamilyIPv6), nftIPNet(nftablesOffsetIPv6Source, ipv6LinkLocalNet), nftEtherSource(intf.MACAddress), nftVerdict(expr.VerdictReturn, "")),
+		newNFTRule("Allow duplicate address detection.",
+			nftProtocol(ipFamilyIPv6), nftIPNet(nftablesOffsetIPv6Source, ipv6UnspecifiedNet), nftL4Proto(syscall.IPPROTO_ICMPV6), nftEtherSource(intf.MACAddress), nftVerdict(expr.VerdictReturn, "")),
+		newNFTRule("Drop traffic without an IP/MAC allow rule.", nftVerdict(expr.VerdictDrop, "")),
+	)
+}
+
+// getNFTINPUTChainRules returns the rules of the input chain of intf, which filters traffic to the VM.
+func getNFTINPUTChainRules(intf link) []nftRule {
+	rules := []nftRule{
+		newNFTRule("Direct packets associated with a known session to the RETURN chain.", nftEstablished(), nftVerdict(expr.VerdictReturn, "")),
+		newNFTRule("", nftProtocol(ipFamilyIPv4), nftL4Proto(syscall.IPPROTO_UDP), nftPorts(nftablesOffsetSourcePort, 67, 67), nftPorts(nftablesOffsetDestPort, 68, 68), nftVerdict(expr.VerdictReturn, "")),
+		newNFTRule("", nftProtocol(ipFamilyIPv6), nftL4Proto(syscall.IPPROTO_UDP), nftPorts(nftablesOffsetSourcePort, 547, 547), nftPorts(nftablesOffsetDestPort, 546, 546), nftVerdict(expr.VerdictReturn, "")),
+		newNFTICMPv6Rule(icmpv6TypeMLDQuery, "Allow multicast listener queries.", expr.VerdictReturn),
+		newNFTICMPv6Rule(icmpv6TypeRouterAdvertisement, "Allow router advertisements.", expr.VerdictReturn),
+		newNFTICMPv6Rule(icmpv6TypeNeighborSolicitation, "Allow neighbor discovery.", expr.VerdictReturn),
+		newNFTICMPv6Rule(icmpv6TypeNeighborAdvertisement, "Allow neighbor discovery.", expr.VerdictReturn),
+	}
+	for _, rule := range intf.IngressRules {
+		rules = append(rules, rule.nftRule(directionIngress))
+	}
+	return append(rules, newNFTRule("Send unmatched traffic to the fallback chain.", nftVerdict(expr.VerdictJump, chainCallistoSGFallback)))
+}
+
+// getNFTOUTPUTChainRules returns the rules of the output chain of intf, which filters traffic from the VM.
+func getNFTOUTPUTChainRules(intf link) []nftRule {
+	rules := []nftRule{
+		newNFTRule("Allow DHCP client traffic.", nftProtocol(ipFamilyIPv4), nftL4Proto(syscall.IPPROTO_UDP), nftPorts(nftablesOffsetSourcePort, 68, 68), nftPorts(nftablesOffsetDestPort, 67, 67), nftVerdict(expr.VerdictReturn, "")),
+		newNFTRule("", nftVerdict(expr.VerdictJump, getSOURCEChainName(intf))),
+		newNFTRule("Prevent DHCP Spoofing by VM.", nftProtocol(ipFamilyIPv4), nftL4Proto(syscall.IPPROTO_UDP), nftPorts(nftablesOffsetSourcePort, 67, 67), nftPorts(nftablesOffsetDestPort, 68, 68), nftVerdict(expr.VerdictDrop, "")),
+		newNFTICMPv6Rule(icmpv6TypeRouterAdvertisement, "Prevent router advertisements by VM.", expr.VerdictDrop),
+		newNFTRule("Prevent DHCPv6 Spoofing by VM.", nftProtocol(ipFamilyIPv6), nftL4Proto(syscall.IPPROTO_UDP), nftPorts(nftablesOffsetSourcePort, 547, 547), nftPorts(nftablesOffsetDestPort, 546, 546), nftVerdict(expr.VerdictDrop, "")),
+		newNFTRule("Direct packets associated with a known session to the RETURN chain.", nftEstablished(), nftVerdict(expr.VerdictReturn, "")),
+		newNFTRule("Allow DHCPv6 client traffic.", nftProtocol(ipFamilyIPv6), nftL4Proto(syscall.IPPROTO_UDP), nftPorts(nftablesOffsetSourcePort, 546, 546), nftPorts(nftablesOffsetDestPort, 547, 547), nftVerdict(expr.VerdictReturn, "")),
+		newNFTICMPv6Rule(icmpv6TypeRouterSolicitation, "Allow router solicitations.", expr.VerdictReturn),
+		newNFTICMPv6Rule(icmpv6TypeNeighborSolicitation, "Allow neighbor discovery.", expr.VerdictReturn),
+		newNFTICMPv6Rule(icmpv6TypeNeighborAdvertisement, "Allow neighbor discovery.", expr.VerdictReturn),
+		newNFTICMPv6Rule(icmpv6TypeMLDv2Report, "Allow multicast listener reports.", expr.VerdictReturn),
+	}
+	for _, rule := range intf.EgressRules {
+		rules = append(rules, rule.nftRule(directionEgress))
+	}
+	return append(rules, newNFTRule("Send unmatched traffic to the fallback chain.", nftVerdict(expr.VerdictJump, chainCallistoSGFallback)))
+}
+
+// newNFTICMPv6Rule returns the rule that applies kind to the ICMPv6 messages of icmpType.
+func newNFTICMPv6Rule(icmpType int, comment string, kind expr.VerdictKind) nftRule {
+	return newNFTRule(comment, nftProtocol(ipFamilyIPv6), nftL4Proto(syscall.IPPROTO_ICMPV6), nftICMP(icmpType, -1), nftVerdict(kind, ""))
+}
+
+// getNFTJumpRules returns the rules in callisto-sg-chain and callisto-FORWARD that jump to the chains of intf,
+// in the same order as the iptables backend. Only IPv4 and IPv6 enter the chains, as br_netfilter passes only
+// them to iptables and ip6tables.
+func getNFTJumpRules(intf link) []nftJumpRule {
+	jumps := []nftJumpRule{
+		{chain: chainCallistoSG, rule: newNFTRule("Jump to the VM specific chain.", nftOIFName(intf.Name), nftVerdict(expr.VerdictJump, getINPUTChainName(intf)))},
+		{chain: chainCallistoSG, rule: newNFTRule("Jump to the VM specific chain.", nftIIFName(intf.Name), nftVerdict(expr.VerdictJump, getOUTPUTChainName(intf)))},
+	}
+	for _, family := range ipFamilies {
+		jumps = append(jumps,
+			nftJumpRule{chain: chainCallistoFORWARD, rule: newNFTRule("Direct traffic from the VM interface to the security group chain.", nftProtocol(family), nftOIFName(intf.Name), nftVerdict(expr.VerdictJump, chainCallistoSG))},
+			nftJumpRule{chain: chainCallistoFORWARD, rule: newNFTRule("Direct traffic from the VM interface to the security group chain.", nftProtocol(family), nftIIFName(intf.Name), nftVerdict(expr.VerdictJump, chainCallistoSG))},
+			nftJumpRule{chain: chainCallistoFORWARD, rule: newNFTRule("Direct incoming traffic from VM to the security group chain.", nftProtocol(family), nftIIFName(intf.Name), nftVerdict(expr.VerdictJump, getOUTPUTChainName(intf)))},
+		)
+	}
+	return jumps
+}
+
+func getNFTJumpTarget(rule nftRule) string {
+	if verdict, ok := rule.exprs[len(rule.exprs)-1].(*expr.Verdict); ok {
+		return verdict.Chain
+	}
+	return ""
+}
+
+// The expressions below load a value into register 1 and compare it, like nft does.
+
+func nftMeta(key expr.MetaKey, data []byte) []expr.Any {
+	return []expr.Any{
+		&expr.Meta{Key: key, Register: 1},
+		&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: data},
+	}
+}
+
+// nftIIFName matches the bridge port that the packet enters by, like --physdev-in.
+func nftIIFName(name string) []expr.Any {
+	return nftMeta(expr.MetaKeyIIFNAME, []byte(name+"\x00"))
+}
+
+// nftOIFName matches the bridge port that the packet leaves by, like --physdev-out.
+func nftOIFName(name string) []expr.Any {
+	return nftMeta(expr.MetaKeyOIFNAME, []byte(name+"\x00"))
+}
+
+// nftProtocol matches the ethernet protocol of family.
+func nftProtocol(family ipFamily) []expr.Any {
+	if family == ipFamilyIPv6 {
+		return nftMeta(expr.MetaKeyPROTOCOL, nftablesProtocolIPv6)
+	}
+	return nftMeta(expr.MetaKeyPROTOCOL, nftablesProtocolIPv4)
+}
+
+func nftL4Proto(protocol byte) []expr.Any {
+	return nftMeta(expr.MetaKeyL4PROTO, []byte{protocol})
+}
+
+func nftPayload(base expr.PayloadBase, offset, length uint32) *expr.Payload {
+	return &expr.Payload{DestRegister: 1, Base: base, Offset: offset, Len: length}
+}
+
+// nftPorts matches the port at offset of the transport header between min and max.
+func nftPorts(offset uint32, min, max uint16) []expr.Any {
+	load := nftPayload(expr.PayloadBaseTransportHeader, offset, 2)
+	if min == max {
+		return []expr.Any{load, &expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: binaryutil.BigEndian.PutUint16(min)}}
+	}
+	return []expr.Any{load, &expr.Range{
+		Op:       expr.CmpOpEq,
+		Register: 1,
+		FromData: binaryutil.BigEndian.PutUint16(min),
+		ToData:   binaryutil.BigEndian.PutUint16(max),
+	}}
+}
+
+// nftIPNet matches the IPv4 or IPv6 address at offset of the network header in n.
+func nftIPNet(offset uint32, n *net.IPNet) []expr.Any {
+	ip := n.IP.To4()
+	if ip == nil {
+		ip = n.IP.To16()
+	}
+	mask := n.Mask
+	if len(mask) > len(ip) {
+		mask = mask[len(mask)-len(ip):]
+	}
+	load := nftPayload(expr.PayloadBaseNetworkHeader, offset, uint32(len(ip)))
+	if ones, bits := mask.Size(); ones == bits {
+		return []expr.Any{load, &expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: ip}}
+	}
+	return []expr.Any{
+		load,
+		&expr.Bitwise{SourceRegister: 1, DestRegister: 1, Len: uint32(len(ip)), Mask: []byte(mask), Xor: make([]byte, len(ip))},
+		&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: ip.Mask(mask)},
+	}
+}
+
+// nftSet matches the IPv4 address at offset of the network header in set.
+func nftSet(offset uint32, set string) []expr.Any {
+	return []expr.Any{
+		nftPayload(expr.PayloadBaseNetworkHeader, offset, net.IPv4len),
+		&expr.Lookup{SourceRegister: 1, SetName: set},
+	}
+}
+
+func nftEtherSource(mac net.HardwareAddr) []expr.Any {
+	return []expr.Any{
+		nftPayload(expr.PayloadBaseLLHeader, nftablesOffsetEtherSource, uint32(len(mac))),
+		&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: mac},
+	}
+}
+
+// nftICMP matches the ICMP type, and the code unless it is negative.
+func nftICMP(icmpType, icmpCode int) []expr.Any {
+	exprs := []expr.Any{
+		nftPayload(expr.PayloadBaseTransportHeader, nftablesOffsetICMPType, 1),
+		&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{byte(icmpType)}},
+	}
+	if icmpCode >= 0 {
+		exprs = append(exprs,
+			nftPayload(expr.PayloadBaseTransportHeader, nftablesOffsetICMPCode, 1),
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{byte(icmpCode)}},
+		)
+	}
+	return exprs
+}
+
+// nftEstablished matches the packets of established and related connections.
+func nftEstablished() []expr.Any {
+	return []expr.Any{
+		&expr.Ct{Register: 1, Key: expr.CtKeySTATE},
+		&expr.Bitwise{
+			SourceRegister: 1,
+			DestRegister:   1,
+			Len:            4,
+			Mask:           binaryutil.NativeEndian.PutUint32(expr.CtStateBitESTABLISHED | expr.CtStateBitRELATED),
+			Xor:            make([]byte, 4),
+		},
+		&expr.Cmp{Op: expr.CmpOpNeq, Register: 1, Data: make([]byte, 4)},
+	}
+}
+
+// nftVerdict counts the packets and bytes of the rule and applies the verdict.
+func nftVerdict(kind expr.VerdictKind, chain string) []expr.Any {
+	return []expr.Any{&expr.Counter{}, &expr.Verdict{Kind: kind, Chain: chain}}
+}
+
+// decodeNFTRule returns rule in the fields of an iptables rule. The rule field is the rule as nft prints it.
+// Rules that were not added by the agent may be printed partially.
+func decodeNFTRule(rule *nftables.Rule) *pb.IPTablesRule {
+	r := &pb.IPTablesRule{}
+	var tokens []string
+	var load expr.Any
+	var mask []byte
+	for _, e := range rule.Exprs {
+		switch e := e.(type) {
+		case *expr.Meta, *expr.Payload, *expr.Ct:
+			load, mask = e, nil
+		case *expr.Bitwise:
+			mask = e.Mask
+		case *expr.Cmp:
+			tokens = append(tokens, decodeNFTMatch(r, load, mask, e.Op, e.Data, nil))
+			load, mask = nil, nil
+		case *expr.Range:
+			tokens = append(tokens, decodeNFTMatch(r, load, mask, e.Op, e.FromData, e.ToData))
+			load, mask = nil, nil
+		case *expr.Lookup:
+			field, flag := "saddr", "src"
+			if p, ok := load.(*expr.Payload); ok && p.Offset == nftablesOffsetIPDestination {
+				field, flag = "daddr", "dst"
+			}
+			r.MatchSet = e.SetName + " " + flag
+			op := ""
+			if e.Invert {
+				op = "!= "
+			}
+			tokens = append(tokens, fmt.Sprintf("ip %s %s@%s", field, op, e.SetName))
+			load, mask = nil, nil
+		case *expr.Counter:
+			r.Packets, r.Bytes = e.Packets, e.Bytes
+		case *expr.Verdict:
+			switch e.Kind {
+			case expr.VerdictReturn:
+				r.Target = actionRETURN
+				tokens = append(tokens, "return")
+			case expr.VerdictDrop:
+				r.Target = actionDROP
+				tokens = append(tokens, "drop")
+			case expr.VerdictAccept:
+				r.Target = actionACCEPT
+				tokens = append(tokens, "accept")
+			case expr.VerdictJump:
+				r.Target = e.Chain
+				tokens = append(tokens, "jump "+e.Chain)
+			case expr.VerdictGoto:
+				r.Target = e.Chain
+				tokens = append(tokens, "goto "+e.Chain)
+			default:
+				tokens = append(tokens, fmt.Sprintf("verdict %d", e.Kind))
+			}
+		default:
+			tokens = append(tokens, fmt.Sprintf("%T", e))
+		}
+	}
+	if comment := decodeNFTComment(rule.UserData); comment != "" {
+		r.Comment = comment
+		tokens = append(tokens, "comment "+strconv.Quote(comment))
+	}
+	r.Rule = strings.Join(tokens, " ")
+	return r
+}
+
+// decodeNFTMatch returns the comparison of the value loaded by load, and sets it to the field of r.
+func decodeNFTMatch(r *pb.IPTablesRule, load expr.Any, mask []byte, op expr.CmpOp, data, to []byte) string {
+	prefix, negate := "", ""
+	if op == expr.CmpOpNeq {
+		prefix, negate = "!= ", "!"
+	}
+
+	switch l := load.(type) {
+	case *expr.Meta:
+		switch l.Key {
+		case expr.MetaKeyIIFNAME:
+			r.PhysdevIn = negate + nftString(data)
+			return "iifname " + prefix + strconv.Quote(nftString(data))
+		case expr.MetaKeyOIFNAME:
+			r.PhysdevOut = negate + nftString(data)
+			return "oifname " + prefix + strconv.Quote(nftString(data))
+		case expr.MetaKeyPROTOCOL:
+			if bytes.Equal(data, nftablesProtocolIPv4) {
+				return "meta protocol " + prefix + "ip"
+			}
+			if bytes.Equal(data, nftablesProtocolIPv6) {
+				return "meta protocol " + prefix + "ip6"
+			}
+			return fmt.Sprintf("meta protocol %s0x%x", prefix, data)
+		case expr.MetaKeyL4PROTO:
+			if len(data) == 1 {
+				name, ok := nftablesProtocolNames[data[0]]
+				if !ok {
+					name = strconv.Itoa(int(data[0]))
+				}
+				r.Protocol = negate + name
+				return "meta l4proto " + prefix + name
+			}
+		}
+	case *expr.Payload:
+		switch {
+		case l.Base == expr.PayloadBaseLLHeader && l.Offset == nftablesOffsetEtherSource:
+			r.MacSource = negate + net.HardwareAddr(data).String()
+			return "ether saddr " + prefix + net.HardwareAddr(data).String()
+		case l.Base == expr.PayloadBaseNetworkHeader && (len(data) == net.IPv4len || len(data) == net.IPv6len):
+			n := &net.IPNet{IP: net.IP(data), Mask: net.CIDRMask(len(data)*8, len(data)*8)}
+			if len(mask) == len(data) {
+				n.Mask = net.IPMask(mask)
+			}
+			family := "ip"
+			if len(data) == net.IPv6len {
+				family = "ip6"
+			}
+			if l.Offset == nftablesOffsetIPDestination || l.Offset == nftablesOffsetIPv6Destination {
+				r.Destination = negate + n.String()
+				return family + " daddr " + prefix + n.String()
+			}
+			r.Source = negate + n.String()
+			return family + " saddr " + prefix + n.String()
+		case l.Base == expr.PayloadBaseTransportHeader && l.Len == 2:
+			protocol := r.Protocol
+			if protocol == "" {
+				protocol = "th"
+			}
+			// iptables prints a port range as min:max, and nft as min-max
+			ports := strconv.Itoa(int(binary.BigEndian.Uint16(data)))
+			printed := ports
+			if to != nil {
+				ports = fmt.Sprintf("%d:%d", binary.BigEndian.Uint16(data), binary.BigEndian.Uint16(to))
+				printed = fmt.Sprintf("%d-%d", binary.BigEndian.Uint16(data), binary.BigEndian.Uint16(to))
+			}
+			if l.Offset == nftablesOffsetSourcePort {
+				r.SourcePorts = negate + ports
+				return protocol + " sport " + prefix + printed
+			}
+			r.DestinationPorts = negate + ports
+			return protocol + " dport " + prefix + printed
+		case l.Base == expr.PayloadBaseTransportHeader && l.Len == 1 && len(data) == 1:
+			protocol := "icmp"
+			if r.Protocol == nftablesProtocolNames[syscall.IPPROTO_ICMPV6] {
+				protocol = "icmpv6"
+			}
+			if l.Offset == nftablesOffsetICMPCode {
+				r.IcmpType += "/" + strconv.Itoa(int(data[0]))
+				return protocol + " code " + prefix + strconv.Itoa(int(data[0]))
+			}
+			r.IcmpType = negate + strconv.Itoa(int(data[0]))
+			return protocol + " type " + prefix + strconv.Itoa(int(data[0]))
+		}
+	case *expr.Ct:
+		if l.Key == expr.CtKeySTATE && len(mask) == 4 {
+			bits := binaryutil.NativeEndian.Uint32(mask)
+			var states, names []string
+			for _, state := range []struct {
+				bit  uint32
+				name string
+			}{
+				{bit: expr.CtStateBitRELATED, name: "RELATED"},
+				{bit: expr.CtStateBitESTABLISHED, name: "ESTABLISHED"},
+				{bit: expr.CtStateBitNEW, name: "NEW"},
+				{bit: expr.CtStateBitINVALID, name: "INVALID"},
+			} {
+				if bits&state.bit != 0 {
+					states = append(states, state.name)
+					names = append([]string{strings.ToLower(state.name)}, names...)
+				}
+			}
+			r.State = strings.Join(states, ",")
+			return "ct state " + strings.Join(names, ",")
+		}
+	}
+	return fmt.Sprintf("%T %s0x%x", load, prefix, data)
+}
+
+// decodeNFTComment returns the comment in the user data of a rule.
+func decodeNFTComment(data []byte) string {
+	for len(data) >= 2 {
+		typ, length := data[0], int(data[1])
+		if len(data) < 2+length {
+			return ""
+		}
+		if typ == nftablesUserDataComment {
+			return nftString(data[2 : 2+length])
+		}
+		data = data[2+length:]
+	}
+	return ""
+}
+
+// nftString returns data up to the terminating NUL.
+func nftString(data []byte) string {
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return string(data[:i])
+	}
+	return string(data)
+}
@@ -0,0 +1,487 @@
+package main
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/vishvananda/netlink"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+func newNFTablesTestAgent(t *testing.T) (*agent, *fakeBackends) {
+	t.Helper()
+	a, f := newTestAgent(t)
+	a.firewall = newNFTablesBackend(f.nftables.newConn, zap.NewNop())
+	return a, f
+}
+
+func TestNFTablesAddSecurityGroup(t *testing.T) {
+	a, f := newNFTablesTestAgent(t)
+	ctx := context.Background()
+	if _, err := a.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{}); err != nil {
+		t.Fatalf("failed to setup default security group: %+v", err)
+	}
+	if _, err := a.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{}); status.Code(err) != codes.Internal {
+		t.Errorf("want %s for the second setup, but got %+v", codes.Internal, err)
+	}
+	_, err := a.AddSecurityGroup(ctx, &pb.AddSecurityGroupRequest{
+		Interface:   "tap0",
+		IpAddress:   "192.0.2.100",
+		Ipv6Address: "2001:db8::100",
+		MacAddress:  "52:54:00:00:00:01",
+		IngressRules: []*pb.SecurityGroupRule{
+			{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 22, RemoteCidr: "198.51.100.0/24"},
+			{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 8000, PortRangeMax: 8080, RemoteGroup: "web"},
+			{Protocol: pb.SecurityGroupRule_ICMP, IcmpType: "echo-request"},
+			{Protocol: pb.SecurityGroupRule_ICMP, IcmpType: "3/4"},
+			{Protocol: pb.SecurityGroupRule_ICMPV6, IcmpType: "echo-request"},
+		},
+		EgressRules: []*pb.SecurityGroupRule{
+			{Protocol: pb.SecurityGroupRule_UDP, PortRangeMin: 53, RemoteCidr: "203.0.113.53/32"},
+			{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 443, RemoteCidr: "2001:db8:1::/48"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+
+	want := map[string][]string{
+		chainCallistoFORWARD: {
+			`meta protocol ip oifname "tap0" jump callisto-sg-chain comment "Direct traffic from the VM interface to the security group chain."`,
+			`meta protocol ip iifname "tap0" jump callisto-sg-chain comment "Direct traffic from the VM interface to the security group chain."`,
+			`meta protocol ip iifname "tap0" jump callisto-otap0 comment "Direct incoming traffic from VM to the security group chain."`,
+			`meta protocol ip6 oifname "tap0" jump callisto-sg-chain comment "Direct traffic from the VM interface to the security group chain."`,
+			`meta protocol ip6 iifname "tap0" jump callisto-sg-chain comment "Direct traffic from the VM interface to the security group chain."`,
+			`meta protocol ip6 iifname "tap0" jump callisto-otap0 comment "Direct incoming traffic from VM to the security group chain."`,
+		},
+		chainCallistoSG: {
+			`oifname "tap0" jump callisto-itap0 comment "Jump to the VM specific chain."`,
+			`iifname "tap0" jump callisto-otap0 comment "Jump to the VM specific chain."`,
+			`accept`,
+		},
+		chainCallistoSGFallback: {
+			`drop comment "Default drop rule for unmatched traffic."`,
+		},
+		"callisto-stap0": {
+			`meta protocol ip ip saddr 192.0.2.100/32 ether saddr 52:54:00:00:00:01 return comment "Allow traffic from defined IP/MAC pairs."`,
+			`meta protocol ip6 ip6 saddr 2001:db8::100/128 ether saddr 52:54:00:00:00:01 return comment "Allow traffic from defined IP/MAC pairs."`,
+			`meta protocol ip6 ip6 saddr fe80::/10 ether saddr 52:54:00:00:00:01 return comment "Allow link-local traffic from the MAC address of the VM."`,
+			`meta protocol ip6 ip6 saddr ::/128 meta l4proto ipv6-icmp ether saddr 52:54:00:00:00:01 return comment "Allow duplicate address detection."`,
+			`drop comment "Drop traffic without an IP/MAC allow rule."`,
+		},
+		"callisto-otap0": {
+			`meta protocol ip meta l4proto udp udp sport 68 udp dport 67 return comment "Allow DHCP client traffic."`,
+			`jump callisto-stap0`,
+			`meta protocol ip meta l4proto udp udp sport 67 udp dport 68 drop comment "Prevent DHCP Spoofing by VM."`,
+			`meta protocol ip6 meta l4proto ipv6-icmp icmpv6 type 134 drop comment "Prevent router advertisements by VM."`,
+			`meta protocol ip6 meta l4proto udp udp sport 547 udp dport 546 drop comment "Prevent DHCPv6 Spoofing by VM."`,
+			`ct state established,related return comment "Direct packets associated with a known session to the RETURN chain."`,
+			`meta protocol ip6 meta l4proto udp udp sport 546 udp dport 547 return comment "Allow DHCPv6 client traffic."`,
+			`meta protocol ip6 meta l4proto ipv6-icmp icmpv6 type 133 return comment "Allow router solicitations."`,
+			`meta protocol ip6 meta l4proto ipv6-icmp icmpv6 type 135 return comment "Allow neighbor discovery."`,
+			`meta protocol ip6 meta l4proto ipv6-icmp icmpv6 type 136 return comment "Allow neighbor discovery."`,
+			`meta protocol ip6 meta l4proto ipv6-icmp icmpv6 type 143 return comment "Allow multicast listener reports."`,
+			`meta protocol ip ip daddr 203.0.113.53/32 meta l4proto udp udp dport 53 return`,
+			`meta protocol ip6 ip6 daddr 2001:db8:1::/48 meta l4proto tcp tcp dport 443 return`,
+			`jump callisto-sg-fallback comment "Send unmatched traffic to the fallback chain."`,
+		},
+		"callisto-itap0": {
+			`ct state established,related return comment "Direct packets associated with a known session to the RETURN chain."`,
+			`meta protocol ip meta l4proto udp udp sport 67 udp dport 68 return`,
+			`meta protocol ip6 meta l4proto udp udp sport 547 udp dport 546 return`,
+			`meta protocol ip6 meta l4proto ipv6-icmp icmpv6 type 130 return comment "Allow multicast listener queries."`,
+			`meta protocol ip6 meta l4proto ipv6-icmp icmpv6 type 134 return comment "Allow router advertisements."`,
+			`meta protocol ip6 meta l4proto ipv6-icmp icmpv6 type 135 return comment "Allow neighbor discovery."`,
+			`meta protocol ip6 meta l4proto ipv6-icmp icmpv6 type 136 return comment "Allow neighbor discovery."`,
+			`meta protocol ip ip saddr 198.51.100.0/24 meta l4proto tcp tcp dport 22 return`,
+			`meta protocol ip ip saddr @callisto-gweb meta l4proto tcp tcp dport 8000-8080 return`,
+			`meta protocol ip meta l4proto icmp icmp type 8 return`,
+			`meta protocol ip meta l4proto icmp icmp type 3 icmp code 4 return`,
+			`meta protocol ip6 meta l4proto ipv6-icmp icmpv6 type 128 return`,
+			`jump callisto-sg-fallback comment "Send unmatched traffic to the fallback chain."`,
+		},
+	}
+	if got, want := formatSavedIPTables(savedFakeNFTables(f.nftables)), formatSavedIPTables(want); got != want {
+		t.Errorf("want %s, but got %s", want, got)
+	}
+	if members, ok := f.nftables.members("web"); !ok || len(members) != 0 {
+		t.Errorf("want empty set of web, but got %q (exists=%t)", members, ok)
+	}
+
+	resp, err := a.GetIPTables(ctx, &pb.GetIPTablesRequest{Interface: "tap0"})
+	if err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	for _, chain := range resp.Chains {
+		if chain.Name != "callisto-itap0" {
+			continue
+		}
+		rule := chain.Rules[7]
+		if rule.Source != "198.51.100.0/24" || rule.Protocol != "tcp" || rule.DestinationPorts != "22" || rule.Target != actionRETURN {
+			t.Errorf("want tcp rule from 198.51.100.0/24 to 22, but got %+v", rule)
+		}
+		if rule := chain.Rules[8]; rule.MatchSet != "callisto-gweb src" || rule.DestinationPorts != "8000:8080" {
+			t.Errorf("want rule of callisto-gweb to 8000:8080, but got %+v", rule)
+		}
+		if rule := chain.Rules[11]; rule.Protocol != "ipv6-icmp" || rule.IcmpType != "128" {
+			t.Errorf("want ICMPv6 rule of type 128, but got %+v", rule)
+		}
+		if chain.Interface != "tap0" {
+			t.Errorf("want %s, but got %s", "tap0", chain.Interface)
+		}
+	}
+}
+
+func TestNFTablesUpdateSecurityGroupRules(t *testing.T) {
+	tests := []struct {
+		name string
+		intf string
+		want codes.Code
+	}{
+		{
+			name: "security group",
+			intf: "tap0",
+			want: codes.OK,
+		},
+		{
+			name: "missing security group",
+			intf: "tap9",
+			want: codes.NotFound,
+		},
+	}
+	for _, test := range tests {
+		a, f := newNFTablesTestAgent(t)
+		ctx := context.Background()
+		setupVerifierTestSecurityGroups(t, a)
+
+		_, err := a.UpdateSecurityGroupRules(ctx, &pb.UpdateSecurityGroupRulesRequest{
+			Interface:    test.intf,
+			IngressRules: []*pb.SecurityGroupRule{{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 443}},
+		})
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %+v", test.name, test.want, err)
+			continue
+		}
+		if test.want != codes.OK {
+			continue
+		}
+
+		got := f.nftables.rules("callisto-itap0")
+		want := `meta l4proto tcp tcp dport 443 return`
+		if len(got) != 9 || got[7] != want {
+			t.Errorf("%s: want %q after the default rules, but got %q", test.name, want, got)
+		}
+		// the egress rule allowing any traffic is removed
+		if got := f.nftables.rules("callisto-otap0"); len(got) != 12 {
+			t.Errorf("%s: want 12 rules in callisto-otap0, but got %q", test.name, got)
+		}
+	}
+}
+
+func TestNFTablesAllowedAddressPairs(t *testing.T) {
+	a, f := newNFTablesTestAgent(t)
+	ctx := context.Background()
+	setupVerifierTestSecurityGroups(t, a)
+	input := f.nftables.rules("callisto-itap0")
+
+	_, err := a.AddAllowedAddressPairs(ctx, &pb.AddAllowedAddressPairsRequest{
+		Interface: "tap0",
+		AllowedAddressPairs: []*pb.AllowedAddressPair{
+			{IpAddress: "192.0.2.200"},
+			{IpAddress: "2001:db8:1::/64", MacAddress: "52:54:00:00:00:09"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+
+	want := []string{
+		`meta protocol ip ip saddr 192.0.2.100/32 ether saddr 52:54:00:00:00:01 return comment "Allow traffic from defined IP/MAC pairs."`,
+		`meta protocol ip ip saddr 192.0.2.200/32 ether saddr 52:54:00:00:00:01 return comment "Allow traffic from allowed address pairs."`,
+		`meta protocol ip6 ip6 saddr 2001:db8:1::/64 ether saddr 52:54:00:00:00:09 return comment "Allow traffic from allowed address pairs."`,
+		`meta protocol ip6 ip6 saddr fe80::/10 ether saddr 52:54:00:00:00:01 return comment "Allow link-local traffic from the MAC address of the VM."`,
+		`meta protocol ip6 ip6 saddr ::/128 meta l4proto ipv6-icmp ether saddr 52:54:00:00:00:01 return comment "Allow duplicate address detection."`,
+		`drop comment "Drop traffic without an IP/MAC allow rule."`,
+	}
+	if got := f.nftables.rules("callisto-stap0"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("want %q, but got %q", want, got)
+	}
+	if got := f.nftables.rules("callisto-itap0"); strings.Join(got, "\n") != strings.Join(input, "\n") {
+		t.Errorf("want %q in the input chain, but got %q", input, got)
+	}
+
+	if _, err := a.RemoveAllowedAddressPairs(ctx, &pb.RemoveAllowedAddressPairsRequest{
+		Interface:           "tap0",
+		AllowedAddressPairs: []*pb.AllowedAddressPair{{IpAddress: "192.0.2.200/32"}},
+	}); err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	want = append(want[:1], want[2:]...)
+	if got := f.nftables.rules("callisto-stap0"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("want %q, but got %q", want, got)
+	}
+}
+
+func TestNFTablesRemoveSecurityGroup(t *testing.T) {
+	tests := []struct {
+		name string
+		intf string
+		// partial deletes the source chain before the removal
+		partial   bool
+		wantFlows int
+	}{
+		{
+			name:      "security group",
+			intf:      "tap0",
+			wantFlows: 1,
+		},
+		{
+			name:      "partially removed security group",
+			intf:      "tap0",
+			partial:   true,
+			wantFlows: 3,
+		},
+		{
+			name:      "missing security group",
+			intf:      "tap9",
+			wantFlows: 3,
+		},
+	}
+	for _, test := range tests {
+		a, f := newNFTablesTestAgent(t)
+		ctx := context.Background()
+		setupVerifierTestSecurityGroups(t, a)
+		if test.partial {
+			conn, _ := f.nftables.newConn()
+			conn.FlushChain(nftChain("callisto-otap0"))
+			conn.FlushChain(nftChain("callisto-stap0"))
+			conn.DelChain(nftChain("callisto-stap0"))
+			if err := conn.Flush(ctx); err != nil {
+				t.Fatalf("%s: should not be error but: %+v", test.name, err)
+			}
+		}
+		f.netlink.flows = []*netlink.ConntrackFlow{
+			testConntrackFlow("192.0.2.100", "198.51.100.1"),
+			testConntrackFlow("198.51.100.1", "192.0.2.100"),
+			testConntrackFlow("192.0.2.101", "198.51.100.1"),
+		}
+
+		if _, err := a.RemoveSecurityGroup(ctx, &pb.RemoveSecurityGroupRequest{Interface: test.intf}); err != nil {
+			t.Errorf("%s: should not be error but: %+v", test.name, err)
+			continue
+		}
+
+		for _, chain := range getChainNames(link{Name: test.intf}) {
+			if f.nftables.hasChain(chain) {
+				t.Errorf("%s: want %s deleted, but it exists", test.name, chain)
+			}
+		}
+		for _, chain := range []string{chainCallistoSG, chainCallistoFORWARD} {
+			for _, rule := range f.nftables.rules(chain) {
+				if strings.Contains(rule, `"`+test.intf+`"`) {
+					t.Errorf("%s: want jump rules of %s deleted, but got %s in %s", test.name, test.intf, rule, chain)
+				}
+			}
+		}
+		if !f.nftables.hasChain("callisto-itap1") || !strings.Contains(strings.Join(f.nftables.rules(chainCallistoSG), "\n"), `"tap1"`) {
+			t.Errorf("%s: want security group of tap1 kept, but got %q", test.name, f.nftables.rules(chainCallistoSG))
+		}
+		if len(f.netlink.flows) != test.wantFlows {
+			t.Errorf("%s: want %d conntrack flows, but got %d", test.name, test.wantFlows, len(f.netlink.flows))
+		}
+	}
+}
+
+func TestNFTablesSecurityGroupBatchFailure(t *testing.T) {
+	a, f := newNFTablesTestAgent(t)
+	ctx := context.Background()
+	setupVerifierTestSecurityGroups(t, a)
+	want := formatSavedIPTables(savedFakeNFTables(f.nftables))
+
+	f.nftables.failChain = "callisto-otap2"
+	_, err := a.AddSecurityGroup(ctx, &pb.AddSecurityGroupRequest{Interface: "tap2", IpAddress: "192.0.2.102", MacAddress: "52:54:00:00:00:03"})
+	if got := status.Code(err); got != codes.Internal {
+		t.Errorf("want %s, but got %+v", codes.Internal, err)
+	}
+	if got := formatSavedIPTables(savedFakeNFTables(f.nftables)); got != want {
+		t.Errorf("want %s, but got %s", want, got)
+	}
+	if chain := a.securityGroupIndex.lookup("callisto-itap2"); chain != "" {
+		t.Errorf("want callisto-itap2 not indexed, but got %s", chain)
+	}
+}
+
+func TestNFTablesSecurityGroupVerifierVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(conn nftablesAPI, f *fakeNFTables)
+	}{
+		{
+			name:   "no drift",
+			mutate: func(conn nftablesAPI, f *fakeNFTables) {},
+		},
+		{
+			name: "base chain deleted",
+			mutate: func(conn nftablesAPI, f *fakeNFTables) {
+				conn.FlushChain(nftBaseChain())
+				conn.DelChain(nftBaseChain())
+			},
+		},
+		{
+			name: "jump rule deleted",
+			mutate: func(conn nftablesAPI, f *fakeNFTables) {
+				rules, _ := conn.GetRules(context.Background(), nftTable(), nftChain(chainCallistoSG))
+				conn.DelRule(rules[0])
+			},
+		},
+		{
+			name: "ingress rule deleted",
+			mutate: func(conn nftablesAPI, f *fakeNFTables) {
+				rules, _ := conn.GetRules(context.Background(), nftTable(), nftChain("callisto-itap0"))
+				conn.DelRule(rules[2])
+			},
+		},
+		{
+			name: "fallback rule deleted",
+			mutate: func(conn nftablesAPI, f *fakeNFTables) {
+				rules, _ := conn.GetRules(context.Background(), nftTable(), nftChain(chainCallistoSGFallback))
+				conn.DelRule(rules[0])
+			},
+		},
+		{
+			name: "table deleted",
+			mutate: func(conn nftablesAPI, f *fakeNFTables) {
+				f.state = newFakeNFTables().state
+			},
+		},
+	}
+	for _, test := range tests {
+		a, f := newNFTablesTestAgent(t)
+		ctx := context.Background()
+		setupVerifierTestSecurityGroups(t, a)
+		want := formatSavedIPTables(savedFakeNFTables(f.nftables))
+
+		conn, _ := f.nftables.newConn()
+		test.mutate(conn, f.nftables)
+		if err := conn.Flush(ctx); err != nil {
+			t.Fatalf("%s: failed to mutate nftables: %+v", test.name, err)
+		}
+
+		v := newSecurityGroupVerifier(a, defaultSecurityGroupVerifyInterval, a.logger)
+		if err := v.Verify(ctx); err != nil {
+			t.Errorf("%s: should not be error but: %+v", test.name, err)
+			continue
+		}
+		if got := formatSavedIPTables(savedFakeNFTables(f.nftables)); got != want {
+			t.Errorf("%s: want %s, but got %s", test.name, want, got)
+		}
+		if rules := f.nftables.rules(chainCallistoSG); len(rules) == 0 || rules[len(rules)-1] != "accept" {
+			t.Errorf("%s: want accept at the end of %s, but got %q", test.name, chainCallistoSG, rules)
+		}
+	}
+}
+
+func TestNFTablesRemoteGroups(t *testing.T) {
+	a, f := newNFTablesTestAgent(t)
+	ctx := context.Background()
+	for _, members := range [][]string{{"192.0.2.1", "192.0.2.2"}, {"192.0.2.3"}} {
+		if _, err := a.SetRemoteGroupMembers(ctx, &pb.SetRemoteGroupMembersRequest{Name: "db-clients", IpAddresses: members}); err != nil {
+			t.Fatalf("failed to set members: %+v", err)
+		}
+	}
+	if members, _ := f.nftables.members("db-clients"); strings.Join(members, ",") != "192.0.2.3" {
+		t.Errorf("want %s, but got %q", "192.0.2.3", members)
+	}
+
+	resp, err := a.ListRemoteGroups(ctx, &pb.ListRemoteGroupsRequest{})
+	if err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	if len(resp.RemoteGroups) != 1 || resp.RemoteGroups[0].Name != "db-clients" || !net.ParseIP(resp.RemoteGroups[0].IpAddresses[0]).Equal(net.ParseIP("192.0.2.3")) {
+		t.Errorf("want db-clients with 192.0.2.3, but got %+v", resp.RemoteGroups)
+	}
+
+	if _, err := a.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{}); err != nil {
+		t.Fatalf("failed to setup default security group: %+v", err)
+	}
+	_, err = a.AddSecurityGroup(ctx, &pb.AddSecurityGroupRequest{
+		Interface:    "tap0",
+		IpAddress:    "192.0.2.100",
+		MacAddress:   "52:54:00:00:00:01",
+		IngressRules: []*pb.SecurityGroupRule{{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 5432, RemoteGroup: "db-clients"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to add security group: %+v", err)
+	}
+
+	for _, test := range []struct {
+		name  string
+		group string
+		want  codes.Code
+	}{
+		{name: "remote group in use", group: "db-clients", want: codes.FailedPrecondition},
+		{name: "missing remote group", group: "web", want: codes.NotFound},
+	} {
+		_, err := a.DeleteRemoteGroup(ctx, &pb.DeleteRemoteGroupRequest{Name: test.group})
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %+v", test.name, test.want, err)
+		}
+	}
+
+	if _, err := a.RemoveSecurityGroup(ctx, &pb.RemoveSecurityGroupRequest{Interface: "tap0"}); err != nil {
+		t.Fatalf("failed to remove security group: %+v", err)
+	}
+	if _, err := a.DeleteRemoteGroup(ctx, &pb.DeleteRemoteGroupRequest{Name: "db-clients"}); err != nil {
+		t.Errorf("should not be error but: %+v", err)
+	}
+	if _, ok := f.nftables.members("db-clients"); ok {
+		t.Errorf("want set of db-clients deleted, but it exists")
+	}
+}
+
+func TestNFTablesInitSecurityGroupChains(t *testing.T) {
+	a, _ := newNFTablesTestAgent(t)
+	ctx := context.Background()
+	setupVerifierTestSecurityGroups(t, a)
+	// tap1 was added before the records were introduced
+	if err := a.securityGroups.Delete("tap1"); err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+
+	a.securityGroupIndex = newSecurityGroupIndex()
+	if err := a.initSecurityGroupChains(ctx); err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	for _, intf := range []string{"tap0", "tap1"} {
+		chain := getINPUTChainName(link{Name: intf})
+		if got := a.securityGroupIndex.lookup(chain); got != intf {
+			t.Errorf("want %s, but got %q", intf, got)
+		}
+	}
+}
+
+// savedFakeNFTables returns the rules of every chain of the table of the agent.
+func savedFakeNFTables(f *fakeNFTables) map[string][]string {
+	f.mu.Lock()
+	var chains []string
+	for key := range f.state.chains {
+		if strings.HasPrefix(key, nftablesTable+" ") {
+			chains = append(chains, strings.TrimPrefix(key, nftablesTable+" "))
+		}
+	}
+	f.mu.Unlock()
+	sort.Strings(chains)
+
+	saved := map[string][]string{}
+	for _, chain := range chains {
+		saved[chain] = f.rules(chain)
+	}
+	return saved
+}
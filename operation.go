@@ -0,0 +1,500 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+const (
+	defaultOperationJournalDir = "/var/lib/teleskop/operations"
+	defaultOperationRetention  = 24 * time.Hour
+	operationPruneInterval     = time.Minute
+
+	operationJournalExt = ".json"
+)
+
+type operationsConfig struct {
+	// JournalDir is the directory that operations are recorded to, to survive restarts.
+	JournalDir string `yaml:"journal_dir"`
+	// Retention is how long finished operations are kept.
+	Retention time.Duration `yaml:"retention"`
+}
+
+func (c operationsConfig) validate() error {
+	if c.JournalDir == "" {
+		return fmt.Errorf("journal_dir must be set")
+	}
+	if c.Retention <= 0 {
+		return fmt.Errorf("retention must be positive: %s", c.Retention)
+	}
+	return nil
+}
+
+// operationFunc is the body of an operation. It returns the response of the method.
+type operationFunc func(ctx context.Context, progress *operationProgress) (proto.Message, error)
+
+// operationManager runs long-running operations in background,
+// and records them to the journal so that their results survive restarts.
+type operationManager struct {
+	journalDir    string
+	retention     time.Duration
+	pruneInterval time.Duration
+	logger        *zap.Logger
+	// now returns the current time. It is replaced in tests.
+	now func() time.Time
+
+	mu         sync.Mutex
+	operations map[string]*operation
+	closed     bool
+	wg         sync.WaitGroup
+}
+
+type operation struct {
+	mu        sync.Mutex
+	proto     *pb.Operation
+	cancel    context.CancelFunc
+	cancelled bool
+	done      chan struct{}
+}
+
+// newOperationManager loads operations from the journal.
+// Operations that were running when teleskop stopped are recorded as aborted.
+func newOperationManager(c operationsConfig, logger *zap.Logger) (*operationManager, error) {
+	m := &operationManager{
+		journalDir:    c.JournalDir,
+		retention:     c.Retention,
+		pruneInterval: operationPruneInterval,
+		logger:        logger,
+		now:           time.Now,
+		operations:    map[string]*operation{},
+	}
+	if err := os.MkdirAll(m.journalDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create operation journal directory: %w", err)
+	}
+
+	files, err := ioutil.ReadDir(m.journalDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read operation journal directory: %w", err)
+	}
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != operationJournalExt {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(m.journalDir, f.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read operation journal: %w", err)
+		}
+		p := &pb.Operation{}
+		if err := protojson.Unmarshal(b, p); err != nil {
+			logger.Warn("ignored broken operation journal", zap.String("file", f.Name()), zap.Error(err))
+			continue
+		}
+		if !operationDone(p) {
+			p.State = pb.Operation_FAILED
+			p.ErrorCode = int32(codes.Aborted)
+			p.ErrorMessage = "teleskop was stopped while the operation was running"
+			p.UpdateTime = m.now().Unix()
+			if err := m.save(p); err != nil {
+				return nil, err
+			}
+			logger.Warn("aborted operation", zap.String("id", p.Id), zap.String("method", p.Method))
+		}
+
+		done := make(chan struct{})
+		close(done)
+		m.operations[p.Id] = &operation{proto: p, done: done}
+	}
+	m.prune()
+
+	return m, nil
+}
+
+// Start starts fn as the operation of method in background.
+// The operation keeps running after ctx is done, but inherits the trace of ctx.
+func (m *operationManager) Start(ctx context.Context, method string, fn operationFunc) (*pb.Operation, error) {
+	id, err := newOperationID()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate operation id: %+v", err)
+	}
+	now := m.now().Unix()
+	opCtx, cancel := context.WithCancel(trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx)))
+	op := &operation{
+		proto: &pb.Operation{
+			Id:         id,
+			Method:     method,
+			State:      pb.Operation_PENDING,
+			CreateTime: now,
+			UpdateTime: now,
+		},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		cancel()
+		return nil, status.Errorf(codes.Unavailable, "teleskop is shutting down")
+	}
+	m.operations[id] = op
+	m.wg.Add(1)
+	m.mu.Unlock()
+
+	snapshot := op.snapshot()
+	if err := m.save(snapshot); err != nil {
+		m.mu.Lock()
+		delete(m.operations, id)
+		m.mu.Unlock()
+		m.wg.Done()
+		cancel()
+		return nil, status.Errorf(codes.Internal, "failed to record operation: %+v", err)
+	}
+	m.prune()
+
+	go m.run(opCtx, op, fn)
+	m.logger.Info("started operation", zap.String("id", id), zap.String("method", method))
+
+	return snapshot, nil
+}
+
+func (m *operationManager) run(ctx context.Context, op *operation, fn operationFunc) {
+	defer m.wg.Done()
+	defer op.cancel()
+
+	m.update(op, func(p *pb.Operation) {
+		p.State = pb.Operation_RUNNING
+	})
+
+	result, err := fn(ctx, &operationProgress{manager: m, operation: op})
+
+	m.update(op, func(p *pb.Operation) {
+		switch {
+		case err == nil:
+			p.State = pb.Operation_SUCCEEDED
+			p.ProgressPercent = 100
+			setOperationResult(p, result)
+		case op.cancelled:
+			p.State = pb.Operation_CANCELLED
+			p.ErrorCode = int32(codes.Canceled)
+			p.ErrorMessage = err.Error()
+		case ctx.Err() != nil:
+			// canceled by shutdown
+			p.State = pb.Operation_FAILED
+			p.ErrorCode = int32(codes.Aborted)
+			p.ErrorMessage = err.Error()
+		default:
+			st := status.Convert(err)
+			p.State = pb.Operation_FAILED
+			p.ErrorCode = int32(st.Code())
+			p.ErrorMessage = st.Message()
+		}
+	})
+	close(op.done)
+
+	p := op.snapshot()
+	m.logger.Info("finished operation", zap.String("id", p.Id), zap.String("method", p.Method), zap.String("state", p.State.String()))
+}
+
+// update modifies the operation and records it to the journal.
+func (m *operationManager) update(op *operation, fn func(p *pb.Operation)) {
+	op.mu.Lock()
+	fn(op.proto)
+	op.proto.UpdateTime = m.now().Unix()
+	p := proto.Clone(op.proto).(*pb.Operation)
+	op.mu.Unlock()
+
+	if err := m.save(p); err != nil {
+		m.logger.Warn("failed to record operation", zap.String("id", p.Id), zap.Error(err))
+	}
+}
+
+func (m *operationManager) get(id string) (*operation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	op, ok := m.operations[id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "operation id=%s is not found", id)
+	}
+	return op, nil
+}
+
+// Get returns the current state of the operation.
+func (m *operationManager) Get(id string) (*pb.Operation, error) {
+	op, err := m.get(id)
+	if err != nil {
+		return nil, err
+	}
+	return op.snapshot(), nil
+}
+
+// List returns the operations of method in order of creation. It returns all operations if method is empty.
+func (m *operationManager) List(method string) []*pb.Operation {
+	m.mu.Lock()
+	ops := make([]*operation, 0, len(m.operations))
+	for _, op := range m.operations {
+		ops = append(ops, op)
+	}
+	m.mu.Unlock()
+
+	var result []*pb.Operation
+	for _, op := range ops {
+		p := op.snapshot()
+		if method != "" && p.Method != method {
+			continue
+		}
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CreateTime != result[j].CreateTime {
+			return result[i].CreateTime < result[j].CreateTime
+		}
+		return result[i].Id < result[j].Id
+	})
+	return result
+}
+
+// Cancel requests the operation to stop. The operation is recorded as cancelled when it returns.
+func (m *operationManager) Cancel(id string) error {
+	op, err := m.get(id)
+	if err != nil {
+		return err
+	}
+
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	if operationDone(op.proto) {
+		return status.Errorf(codes.FailedPrecondition, "operation id=%s is already done", id)
+	}
+	op.cancelled = true
+	op.cancel()
+	return nil
+}
+
+// Wait waits for the operation to be done until ctx is done, and returns its latest state.
+func (m *operationManager) Wait(ctx context.Context, id string) (*pb.Operation, error) {
+	op, err := m.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-op.done:
+	case <-ctx.Done():
+	}
+	return op.snapshot(), nil
+}
+
+// Shutdown waits for the running operations until ctx is done, and then cancels them.
+func (m *operationManager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	m.closed = true
+	m.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	var aborted int
+	m.mu.Lock()
+	for _, op := range m.operations {
+		if op.cancel != nil && !op.isDone() {
+			op.cancel()
+			aborted++
+		}
+	}
+	m.mu.Unlock()
+	return fmt.Errorf("aborted %d running operations", aborted)
+}
+
+// Run prunes the finished operations every prune interval until ctx is done,
+// so that they expire even when no operations are started.
+func (m *operationManager) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			m.prune()
+		}
+	}
+}
+
+// prune removes the operations that finished before the retention period.
+func (m *operationManager) prune() {
+	expiry := m.now().Add(-m.retention).Unix()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, op := range m.operations {
+		p := op.snapshot()
+		if !operationDone(p) || p.UpdateTime >= expiry {
+			continue
+		}
+		if err := os.Remove(m.journalPath(id)); err != nil && !os.IsNotExist(err) {
+			m.logger.Warn("failed to remove operation journal", zap.String("id", id), zap.Error(err))
+			continue
+		}
+		delete(m.operations, id)
+	}
+}
+
+// save writes p to the journal atomically.
+func (m *operationManager) save(p *pb.Operation) error {
+	b, err := protojson.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to marshal operation: %w", err)
+	}
+
+	f, err := ioutil.TempFile(m.journalDir, "."+p.Id)
+	if err != nil {
+		return fmt.Errorf("failed to create operation journal: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write operation journal: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to sync operation journal: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close operation journal: %w", err)
+	}
+	if err := os.Rename(f.Name(), m.journalPath(p.Id)); err != nil {
+		return fmt.Errorf("failed to rename operation journal: %w", err)
+	}
+	return nil
+}
+
+func (m *operationManager) journalPath(id string) string {
+	return filepath.Join(m.journalDir, id+operationJournalExt)
+}
+
+func (op *operation) snapshot() *pb.Operation {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+
+	return proto.Clone(op.proto).(*pb.Operation)
+}
+
+func (op *operation) isDone() bool {
+	select {
+	case <-op.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// operationProgress reports the progress of a running operation.
+// A nil operationProgress ignores reports, so that methods can share the code with synchronous calls.
+type operationProgress struct {
+	manager   *operationManager
+	operation *operation
+}
+
+// Report records the progress of the operation.
+func (p *operationProgress) Report(percent uint32, message string) {
+	if p == nil {
+		return
+	}
+	p.manager.update(p.operation, func(o *pb.Operation) {
+		o.ProgressPercent = percent
+		o.ProgressMessage = message
+	})
+}
+
+func operationDone(p *pb.Operation) bool {
+	switch p.State {
+	case pb.Operation_SUCCEEDED, pb.Operation_FAILED, pb.Operation_CANCELLED:
+		return true
+	default:
+		return false
+	}
+}
+
+func setOperationResult(p *pb.Operation, result proto.Message) {
+	switch r := result.(type) {
+	case *pb.ConnectBlockDeviceResponse:
+		p.Result = &pb.Operation_ConnectBlockDevice{ConnectBlockDevice: r}
+	case *pb.DisconnectBlockDeviceResponse:
+		p.Result = &pb.Operation_DisconnectBlockDevice{DisconnectBlockDevice: r}
+	}
+}
+
+func newOperationID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (a *agent) GetOperation(ctx context.Context, req *pb.GetOperationRequest) (*pb.GetOperationResponse, error) {
+	op, err := a.operations.Get(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetOperationResponse{
+		Operation: op,
+	}, nil
+}
+
+func (a *agent) ListOperations(ctx context.Context, req *pb.ListOperationsRequest) (*pb.ListOperationsResponse, error) {
+	return &pb.ListOperationsResponse{
+		Operations: a.operations.List(req.Method),
+	}, nil
+}
+
+func (a *agent) CancelOperation(ctx context.Context, req *pb.CancelOperationRequest) (*pb.CancelOperationResponse, error) {
+	if err := a.operations.Cancel(req.Id); err != nil {
+		return nil, err
+	}
+	return &pb.CancelOperationResponse{}, nil
+}
+
+func (a *agent) WaitOperation(ctx context.Context, req *pb.WaitOperationRequest) (*pb.WaitOperationResponse, error) {
+	if req.TimeoutSeconds != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(req.TimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+
+	op, err := a.operations.Wait(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.WaitOperationResponse{
+		Operation: op,
+	}, nil
+}
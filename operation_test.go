@@ -0,0 +1,349 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+func newTestOperationManager(t *testing.T) (*operationManager, string) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "teleskop-operations")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %+v", err)
+	}
+	m, err := newOperationManager(operationsConfig{JournalDir: dir, Retention: time.Hour}, zap.NewNop())
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("failed to create operation manager: %+v", err)
+	}
+	return m, dir
+}
+
+func TestOperationManager(t *testing.T) {
+	tests := []struct {
+		name      string
+		fn        operationFunc
+		cancel    bool
+		wantState pb.Operation_State
+		wantCode  codes.Code
+	}{
+		{
+			name: "succeeded",
+			fn: func(ctx context.Context, progress *operationProgress) (proto.Message, error) {
+				progress.Report(50, "connecting volume")
+				return &pb.ConnectBlockDeviceResponse{DeviceName: "/dev/sdb"}, nil
+			},
+			wantState: pb.Operation_SUCCEEDED,
+			wantCode:  codes.OK,
+		},
+		{
+			name: "failed",
+			fn: func(ctx context.Context, progress *operationProgress) (proto.Message, error) {
+				return nil, status.Errorf(codes.Internal, "failed to connect block device")
+			},
+			wantState: pb.Operation_FAILED,
+			wantCode:  codes.Internal,
+		},
+		{
+			name: "cancelled",
+			fn: func(ctx context.Context, progress *operationProgress) (proto.Message, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+			cancel:    true,
+			wantState: pb.Operation_CANCELLED,
+			wantCode:  codes.Canceled,
+		},
+	}
+	for _, test := range tests {
+		m, dir := newTestOperationManager(t)
+		defer os.RemoveAll(dir)
+
+		op, err := m.Start(context.Background(), "/agent.Agent/ConnectBlockDevice", test.fn)
+		if err != nil {
+			t.Fatalf("%s: failed to start operation: %+v", test.name, err)
+		}
+		if test.cancel {
+			if err := m.Cancel(op.Id); err != nil {
+				t.Fatalf("%s: failed to cancel operation: %+v", test.name, err)
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		got, err := m.Wait(ctx, op.Id)
+		cancel()
+		if err != nil {
+			t.Fatalf("%s: failed to wait operation: %+v", test.name, err)
+		}
+		if got.State != test.wantState || codes.Code(got.ErrorCode) != test.wantCode {
+			t.Errorf("%s: want %s (%s), but got %s (%s)", test.name, test.wantState, test.wantCode, got.State, codes.Code(got.ErrorCode))
+		}
+		if test.wantState == pb.Operation_SUCCEEDED && got.GetConnectBlockDevice().GetDeviceName() != "/dev/sdb" {
+			t.Errorf("%s: want result, but got %+v", test.name, got.Result)
+		}
+
+		// the final state is recorded to the journal
+		restored, err := newOperationManager(operationsConfig{JournalDir: dir, Retention: time.Hour}, zap.NewNop())
+		if err != nil {
+			t.Fatalf("%s: failed to restore operations: %+v", test.name, err)
+		}
+		r, err := restored.Get(op.Id)
+		if err != nil {
+			t.Fatalf("%s: failed to get restored operation: %+v", test.name, err)
+		}
+		if !proto.Equal(got, r) {
+			t.Errorf("%s: want %+v, but got %+v", test.name, got, r)
+		}
+		if err := restored.Cancel(op.Id); status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("%s: want %s, but got %+v", test.name, codes.FailedPrecondition, err)
+		}
+	}
+}
+
+func TestOperationManagerRestoreRunning(t *testing.T) {
+	dir, err := ioutil.TempDir("", "teleskop-operations")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %+v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	for _, p := range []*pb.Operation{
+		{Id: "running", Method: "/agent.Agent/ConnectBlockDevice", State: pb.Operation_RUNNING, CreateTime: now.Unix(), UpdateTime: now.Unix()},
+		{Id: "expired", Method: "/agent.Agent/ConnectBlockDevice", State: pb.Operation_SUCCEEDED, CreateTime: now.Add(-2 * time.Hour).Unix(), UpdateTime: now.Add(-2 * time.Hour).Unix()},
+	} {
+		b, err := protojson.Marshal(p)
+		if err != nil {
+			t.Fatalf("failed to marshal operation: %+v", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, p.Id+operationJournalExt), b, 0600); err != nil {
+			t.Fatalf("failed to write operation journal: %+v", err)
+		}
+	}
+
+	m, err := newOperationManager(operationsConfig{JournalDir: dir, Retention: time.Hour}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("failed to create operation manager: %+v", err)
+	}
+
+	got, err := m.Get("running")
+	if err != nil {
+		t.Fatalf("failed to get operation: %+v", err)
+	}
+	if got.State != pb.Operation_FAILED || codes.Code(got.ErrorCode) != codes.Aborted {
+		t.Errorf("want %s (%s), but got %s (%s)", pb.Operation_FAILED, codes.Aborted, got.State, codes.Code(got.ErrorCode))
+	}
+
+	if _, err := m.Get("expired"); status.Code(err) != codes.NotFound {
+		t.Errorf("want %s, but got %+v", codes.NotFound, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "expired"+operationJournalExt)); !os.IsNotExist(err) {
+		t.Errorf("want expired journal to be removed, but got %+v", err)
+	}
+}
+
+func TestOperationManagerShutdown(t *testing.T) {
+	m, dir := newTestOperationManager(t)
+	defer os.RemoveAll(dir)
+
+	op, err := m.Start(context.Background(), "/agent.Agent/DisconnectBlockDevice", func(ctx context.Context, progress *operationProgress) (proto.Message, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("failed to start operation: %+v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := m.Shutdown(ctx); err == nil {
+		t.Errorf("should be error but not")
+	}
+	if _, err := m.Start(context.Background(), "/agent.Agent/DisconnectBlockDevice", nil); status.Code(err) != codes.Unavailable {
+		t.Errorf("want %s, but got %+v", codes.Unavailable, err)
+	}
+
+	got, err := m.Wait(context.Background(), op.Id)
+	if err != nil {
+		t.Fatalf("failed to wait operation: %+v", err)
+	}
+	if got.State != pb.Operation_FAILED || codes.Code(got.ErrorCode) != codes.Aborted {
+		t.Errorf("want %s (%s), but got %s (%s)", pb.Operation_FAILED, codes.Aborted, got.State, codes.Code(got.ErrorCode))
+	}
+}
+
+func TestOperationManagerRun(t *testing.T) {
+	m, dir := newTestOperationManager(t)
+	defer os.RemoveAll(dir)
+
+	var mu sync.Mutex
+	now := time.Now()
+	m.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	m.pruneInterval = 10 * time.Millisecond
+
+	op, err := m.Start(context.Background(), "/agent.Agent/DisconnectBlockDevice", func(ctx context.Context, progress *operationProgress) (proto.Message, error) {
+		return &pb.DisconnectBlockDeviceResponse{}, nil
+	})
+	if err != nil {
+		t.Fatalf("failed to start operation: %+v", err)
+	}
+	if _, err := m.Wait(context.Background(), op.Id); err != nil {
+		t.Fatalf("failed to wait operation: %+v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- m.Run(ctx) }()
+
+	// the operation is kept within the retention period
+	time.Sleep(5 * m.pruneInterval)
+	if _, err := m.Get(op.Id); err != nil {
+		t.Errorf("should not be error within the retention period but: %+v", err)
+	}
+
+	mu.Lock()
+	now = now.Add(m.retention + time.Second)
+	mu.Unlock()
+	waitFor(t, "pruned operation", func() bool {
+		_, err := m.Get(op.Id)
+		return status.Code(err) == codes.NotFound
+	})
+	if _, err := os.Stat(filepath.Join(dir, op.Id+operationJournalExt)); !os.IsNotExist(err) {
+		t.Errorf("want pruned journal to be removed, but got %+v", err)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("should not be error but: %+v", err)
+	}
+}
+
+func TestOperationRPC(t *testing.T) {
+	a, _ := newTestAgent(t)
+	ctx := context.Background()
+
+	release := make(chan struct{})
+	op, err := a.operations.Start(ctx, "/agent.Agent/ConnectBlockDevice", func(ctx context.Context, progress *operationProgress) (proto.Message, error) {
+		select {
+		case <-release:
+			return &pb.ConnectBlockDeviceResponse{DeviceName: "/dev/sdb"}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+	if err != nil {
+		t.Fatalf("failed to start operation: %+v", err)
+	}
+	other, err := a.operations.Start(ctx, "/agent.Agent/DisconnectBlockDevice", func(ctx context.Context, progress *operationProgress) (proto.Message, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("failed to start operation: %+v", err)
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{
+			name: "get operation",
+			call: func() error {
+				resp, err := a.GetOperation(ctx, &pb.GetOperationRequest{Id: op.Id})
+				if err == nil && resp.Operation.Id != op.Id {
+					t.Errorf("want %s, but got %s", op.Id, resp.Operation.Id)
+				}
+				return err
+			},
+			want: codes.OK,
+		},
+		{
+			name: "get missing operation",
+			call: func() error {
+				_, err := a.GetOperation(ctx, &pb.GetOperationRequest{Id: "missing"})
+				return err
+			},
+			want: codes.NotFound,
+		},
+		{
+			name: "list operations by method",
+			call: func() error {
+				resp, err := a.ListOperations(ctx, &pb.ListOperationsRequest{Method: "/agent.Agent/DisconnectBlockDevice"})
+				if err == nil && (len(resp.Operations) != 1 || resp.Operations[0].Id != other.Id) {
+					t.Errorf("want only %s, but got %+v", other.Id, resp.Operations)
+				}
+				return err
+			},
+			want: codes.OK,
+		},
+		{
+			name: "wait running operation with timeout",
+			call: func() error {
+				resp, err := a.WaitOperation(ctx, &pb.WaitOperationRequest{Id: op.Id, TimeoutSeconds: 1})
+				if err == nil && resp.Operation.State != pb.Operation_RUNNING {
+					t.Errorf("want %s, but got %s", pb.Operation_RUNNING, resp.Operation.State)
+				}
+				return err
+			},
+			want: codes.OK,
+		},
+		{
+			name: "cancel operation",
+			call: func() error {
+				if _, err := a.CancelOperation(ctx, &pb.CancelOperationRequest{Id: other.Id}); err != nil {
+					return err
+				}
+				resp, err := a.WaitOperation(ctx, &pb.WaitOperationRequest{Id: other.Id, TimeoutSeconds: 5})
+				if err == nil && resp.Operation.State != pb.Operation_CANCELLED {
+					t.Errorf("want %s, but got %s", pb.Operation_CANCELLED, resp.Operation.State)
+				}
+				return err
+			},
+			want: codes.OK,
+		},
+		{
+			name: "cancel missing operation",
+			call: func() error {
+				_, err := a.CancelOperation(ctx, &pb.CancelOperationRequest{Id: "missing"})
+				return err
+			},
+			want: codes.NotFound,
+		},
+		{
+			name: "wait succeeded operation",
+			call: func() error {
+				close(release)
+				resp, err := a.WaitOperation(ctx, &pb.WaitOperationRequest{Id: op.Id, TimeoutSeconds: 5})
+				if err == nil && resp.Operation.GetConnectBlockDevice().GetDeviceName() != "/dev/sdb" {
+					t.Errorf("want result, but got %+v", resp.Operation)
+				}
+				return err
+			},
+			want: codes.OK,
+		},
+	}
+	for _, test := range tests {
+		if got := status.Code(test.call()); got != test.want {
+			t.Errorf("%s: want %s, but got %s", test.name, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/vishvananda/netlink"
+	"go.uber.org/zap"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+// capabilities required by the agent, see capability(7).
+var requiredCapabilities = []struct {
+	name string
+	bit  uint
+}{
+	{name: "CAP_NET_BIND_SERVICE", bit: 10},
+	{name: "CAP_NET_ADMIN", bit: 12},
+	{name: "CAP_NET_RAW", bit: 13},
+	{name: "CAP_SYS_ADMIN", bit: 21},
+}
+
+// preflightCheck checks a requirement of the host.
+type preflightCheck struct {
+	name        string
+	remediation string
+	check       func(ctx context.Context) error
+	// fix is nil if the failure can not be fixed safely by the agent
+	fix func(ctx context.Context) error
+}
+
+// preflightHost is the host inspected by preflight checks.
+// Files are read under root, so that tests can use a fake host.
+type preflightHost struct {
+	root     string
+	netlink  netlinkAPI
+	lookPath func(file string) (string, error)
+	command  func(ctx context.Context, name string, args ...string) error
+}
+
+func newPreflightHost() *preflightHost {
+	return &preflightHost{
+		root:     "/",
+		netlink:  hostNetlink{},
+		lookPath: exec.LookPath,
+		command: func(ctx context.Context, name string, args ...string) error {
+			out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+			if err != nil {
+				return fmt.Errorf("failed to execute %s: %w (output: %s)", name, err, strings.TrimSpace(string(out)))
+			}
+			return nil
+		},
+	}
+}
+
+func (h *preflightHost) path(elem ...string) string {
+	return filepath.Join(append([]string{h.root}, elem...)...)
+}
+
+// checks returns the checks of the host with the firewall backend. The order matters when fixing,
+// e.g. the sysctl of br_netfilter exists after the module is loaded.
+func (h *preflightHost) checks(metadataAddress net.IP, firewall string) []preflightCheck {
+	checks := append(h.firewallChecks(firewall), []preflightCheck{
+		h.kernelModuleCheck("8021q", "VLAN interfaces"),
+		{
+			name:        "device /dev/kvm",
+			remediation: "enable virtualization in the BIOS and load kvm_intel or kvm_amd",
+			check: func(ctx context.Context) error {
+				if _, err := os.Stat(h.path("dev", "kvm")); err != nil {
+					return fmt.Errorf("/dev/kvm is not available: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			name:        "process iscsid",
+			remediation: "systemctl enable --now iscsid",
+			check:       h.checkISCSId,
+		},
+		{
+			name:        "capabilities",
+			remediation: "run teleskop as root, or grant the capabilities by AmbientCapabilities of the systemd unit",
+			check:       h.checkCapabilities,
+		},
+		h.binaryCheck("iscsiadm", "install open-iscsi"),
+		h.binaryCheck("multipath", "install multipath-tools"),
+	}...)
+	if metadataAddress != nil {
+		checks = append(checks, h.loopbackAddressCheck(metadataAddress))
+	}
+	return checks
+}
+
+// firewallChecks returns the checks of the firewall backend. iptables and ip6tables see bridged packets
+// by br_netfilter, while nftables filters them in a bridge table, which tracks connections by nf_conntrack_bridge.
+func (h *preflightHost) firewallChecks(firewall string) []preflightCheck {
+	if firewall == firewallBackendNFTables {
+		return []preflightCheck{
+			h.kernelModuleCheck("nf_tables", "security groups"),
+			h.kernelModuleCheck("nf_conntrack_bridge", "security groups track connections of bridged packets"),
+		}
+	}
+	return []preflightCheck{
+		h.kernelModuleCheck("br_netfilter", "security groups filter bridged packets"),
+		h.sysctlCheck("net.bridge.bridge-nf-call-iptables", "1"),
+		h.sysctlCheck("net.bridge.bridge-nf-call-ip6tables", "1"),
+		h.binaryCheck("iptables", "install iptables"),
+		h.binaryCheck("ip6tables", "install iptables"),
+		h.binaryCheck("ipset", "install ipset"),
+	}
+}
+
+func (h *preflightHost) kernelModuleCheck(module, usage string) preflightCheck {
+	return preflightCheck{
+		name:        "kernel module " + module,
+		remediation: fmt.Sprintf("modprobe %s, and add it to /etc/modules-load.d", module),
+		check: func(ctx context.Context) error {
+			if _, err := os.Stat(h.path("sys", "module", module)); err != nil {
+				return fmt.Errorf("%s is not loaded, it is required by %s", module, usage)
+			}
+			return nil
+		},
+		fix: func(ctx context.Context) error {
+			return h.command(ctx, "modprobe", module)
+		},
+	}
+}
+
+func (h *preflightHost) sysctlCheck(key, want string) preflightCheck {
+	p := h.path(append([]string{"proc", "sys"}, strings.Split(key, ".")...)...)
+	return preflightCheck{
+		name:        "sysctl " + key,
+		remediation: fmt.Sprintf("sysctl -w %s=%s, and add it to /etc/sysctl.d", key, want),
+		check: func(ctx context.Context) error {
+			b, err := ioutil.ReadFile(p)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", key, err)
+			}
+			if got := strings.TrimSpace(string(b)); got != want {
+				return fmt.Errorf("%s is %s, but want %s", key, got, want)
+			}
+			return nil
+		},
+		fix: func(ctx context.Context) error {
+			if err := ioutil.WriteFile(p, []byte(want), 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", key, err)
+			}
+			return nil
+		},
+	}
+}
+
+func (h *preflightHost) binaryCheck(name, remediation string) preflightCheck {
+	return preflightCheck{
+		name:        "binary " + name,
+		remediation: remediation,
+		check: func(ctx context.Context) error {
+			if _, err := h.lookPath(name); err != nil {
+				return fmt.Errorf("%s is not found in PATH: %w", name, err)
+			}
+			return nil
+		},
+	}
+}
+
+// loopbackAddressCheck checks that addr is on lo, so that the metadata server can listen on it.
+func (h *preflightHost) loopbackAddressCheck(addr net.IP) preflightCheck {
+	want := &net.IPNet{IP: addr, Mask: net.CIDRMask(8*len(addr), 8*len(addr))}
+	if ip4 := addr.To4(); ip4 != nil {
+		want = &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}
+	}
+	return preflightCheck{
+		name:        "address " + want.String() + " on lo",
+		remediation: fmt.Sprintf("ip addr add %s dev lo", want),
+		check: func(ctx context.Context) error {
+			lo, err := h.netlink.LinkByName("lo")
+			if err != nil {
+				return fmt.Errorf("failed to get lo: %w", err)
+			}
+			addrs, err := h.netlink.AddrList(lo, netlink.FAMILY_ALL)
+			if err != nil {
+				return fmt.Errorf("failed to list addresses of lo: %w", err)
+			}
+			for _, a := range addrs {
+				if a.IP.Equal(want.IP) {
+					return nil
+				}
+			}
+			return fmt.Errorf("%s is not on lo, it is required by the metadata server", want)
+		},
+		fix: func(ctx context.Context) error {
+			lo, err := h.netlink.LinkByName("lo")
+			if err != nil {
+				return fmt.Errorf("failed to get lo: %w", err)
+			}
+			if err := h.netlink.AddrAdd(lo, &netlink.Addr{IPNet: want}); err != nil {
+				return fmt.Errorf("failed to add address to lo: %w", err)
+			}
+			return nil
+		},
+	}
+}
+
+func (h *preflightHost) checkISCSId(ctx context.Context) error {
+	dirs, err := ioutil.ReadDir(h.path("proc"))
+	if err != nil {
+		return fmt.Errorf("failed to list processes: %w", err)
+	}
+	for _, dir := range dirs {
+		if _, err := strconv.Atoi(dir.Name()); err != nil {
+			continue
+		}
+		b, err := ioutil.ReadFile(h.path("proc", dir.Name(), "comm"))
+		if err != nil {
+			// the process has exited
+			continue
+		}
+		if strings.TrimSpace(string(b)) == "iscsid" {
+			return nil
+		}
+	}
+	return fmt.Errorf("iscsid is not running")
+}
+
+func (h *preflightHost) checkCapabilities(ctx context.Context) error {
+	f, err := os.Open(h.path("proc", "self", "status"))
+	if err != nil {
+		return fmt.Errorf("failed to read process status: %w", err)
+	}
+	defer f.Close()
+
+	var effective uint64
+	found := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		v := strings.TrimPrefix(scanner.Text(), "CapEff:")
+		if v == scanner.Text() {
+			continue
+		}
+		effective, err = strconv.ParseUint(strings.TrimSpace(v), 16, 64)
+		if err != nil {
+			return fmt.Errorf("failed to parse CapEff: %w", err)
+		}
+		found = true
+		break
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read process status: %w", err)
+	}
+	if !found {
+		return fmt.Errorf("CapEff is not found in process status")
+	}
+
+	var missing []string
+	for _, c := range requiredCapabilities {
+		if effective&(1<<c.bit) == 0 {
+			missing = append(missing, c.name)
+		}
+	}
+	if len(missing) != 0 {
+		return fmt.Errorf("missing %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// runPreflightChecks runs checks in order. If fix is true, the fixes of failed checks are applied
+// and the checks are run again.
+func runPreflightChecks(ctx context.Context, checks []preflightCheck, fix bool) []*pb.PreflightCheck {
+	results := make([]*pb.PreflightCheck, 0, len(checks))
+	for _, c := range checks {
+		result := &pb.PreflightCheck{Name: c.name, Result: pb.PreflightCheck_PASSED}
+		err := c.check(ctx)
+		if err != nil && fix && c.fix != nil {
+			if fixErr := c.fix(ctx); fixErr != nil {
+				err = fmt.Errorf("%v (failed to fix: %v)", err, fixErr)
+			} else if err = c.check(ctx); err == nil {
+				result.Result = pb.PreflightCheck_FIXED
+			}
+		}
+		if err != nil {
+			result.Result = pb.PreflightCheck_FAILED
+			result.Message = err.Error()
+			result.Remediation = c.remediation
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func preflightPassed(results []*pb.PreflightCheck) bool {
+	for _, r := range results {
+		if r.Result == pb.PreflightCheck_FAILED {
+			return false
+		}
+	}
+	return true
+}
+
+// metadataAddress returns the address of the metadata server in datasourceURL, or nil if it is a hostname.
+func metadataAddress(datasourceURL string) net.IP {
+	u, err := url.Parse(datasourceURL)
+	if err != nil {
+		return nil
+	}
+	return net.ParseIP(u.Hostname())
+}
+
+// RunPreflightChecks checks the requirements of the host.
+func (a *agent) RunPreflightChecks(ctx context.Context, req *pb.RunPreflightChecksRequest) (*pb.RunPreflightChecksResponse, error) {
+	results := runPreflightChecks(ctx, a.preflight.checks(metadataAddress(a.datasourceURL), a.firewall.Name()), req.Fix)
+	for _, r := range results {
+		if r.Result == pb.PreflightCheck_FIXED {
+			a.logger.Info("fixed preflight check", zap.String("check", r.Name))
+		}
+	}
+
+	return &pb.RunPreflightChecksResponse{
+		Checks: results,
+	}, nil
+}
+
+// printPreflightChecks prints results for the --preflight mode.
+func printPreflightChecks(w io.Writer, results []*pb.PreflightCheck) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tRESULT\tMESSAGE\tREMEDIATION")
+	for _, r := range results {
+		message, remediation := r.Message, r.Remediation
+		if message == "" {
+			message = "-"
+		}
+		if remediation == "" {
+			remediation = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Name, r.Result, message, remediation)
+	}
+	return tw.Flush()
+}
+
+// runPreflight runs the checks in the --preflight mode, and returns an error if a check fails.
+func runPreflight(ctx context.Context, cfg *config, w io.Writer) error {
+	results := runPreflightChecks(ctx, newPreflightHost().checks(metadataAddress(cfg.Metadata.DatasourceURL), cfg.SecurityGroup.Backend), cfg.Preflight.Fix)
+	if err := printPreflightChecks(w, results); err != nil {
+		return err
+	}
+	if !preflightPassed(results) {
+		return fmt.Errorf("preflight checks failed")
+	}
+	return nil
+}
+
+// logPreflightChecks runs the checks at startup. Failures are logged, but do not stop the agent,
+// because some requirements are only needed by a part of the api.
+func logPreflightChecks(ctx context.Context, cfg *config, host *preflightHost, logger *zap.Logger) {
+	results := runPreflightChecks(ctx, host.checks(metadataAddress(cfg.Metadata.DatasourceURL), cfg.SecurityGroup.Backend), cfg.Preflight.Fix)
+	for _, r := range results {
+		switch r.Result {
+		case pb.PreflightCheck_FAILED:
+			logger.Warn("preflight check failed",
+				zap.String("check", r.Name),
+				zap.String("message", r.Message),
+				zap.String("remediation", r.Remediation))
+		case pb.PreflightCheck_FIXED:
+			logger.Info("fixed preflight check", zap.String("check", r.Name))
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vishvananda/netlink"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+// fakeHost is a host that satisfies every preflight check. Tests break it by removing files.
+type fakeHost struct {
+	*preflightHost
+
+	binaries map[string]bool
+}
+
+func newFakeHost(t *testing.T, nl *fakeNetlink) *fakeHost {
+	t.Helper()
+
+	root, err := ioutil.TempDir("", "teleskop-preflight")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %+v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(root) })
+
+	h := &fakeHost{
+		binaries: map[string]bool{"iptables": true, "ip6tables": true, "ipset": true, "iscsiadm": true, "multipath": true},
+	}
+	h.preflightHost = &preflightHost{
+		root:    root,
+		netlink: nl,
+		lookPath: func(file string) (string, error) {
+			if !h.binaries[file] {
+				return "", fmt.Errorf("executable file not found in $PATH")
+			}
+			return "/usr/sbin/" + file, nil
+		},
+		command: func(ctx context.Context, name string, args ...string) error {
+			if name == "modprobe" {
+				return os.MkdirAll(h.path("sys", "module", args[0]), 0755)
+			}
+			return nil
+		},
+	}
+
+	files := map[string]string{
+		"proc/sys/net/bridge/bridge-nf-call-iptables":  "1\n",
+		"proc/sys/net/bridge/bridge-nf-call-ip6tables": "1\n",
+		"proc/self/status": "Name:\tteleskop\nCapEff:\t000001ffffffffff\n",
+		"proc/1/comm":      "systemd\n",
+		"proc/42/comm":     "iscsid\n",
+		"dev/kvm":          "",
+	}
+	for name, content := range files {
+		h.writeFile(t, name, content)
+	}
+	for _, dir := range []string{"sys/module/br_netfilter", "sys/module/8021q"} {
+		if err := os.MkdirAll(h.path(dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %+v", dir, err)
+		}
+	}
+
+	lo := &netlink.Device{LinkAttrs: netlink.LinkAttrs{Name: "lo"}}
+	nl.add(lo)
+	nl.addrs[lo.Index] = []netlink.Addr{{IPNet: &net.IPNet{IP: net.ParseIP("169.254.169.254").To4(), Mask: net.CIDRMask(32, 32)}}}
+
+	return h
+}
+
+func (h *fakeHost) writeFile(t *testing.T, name, content string) {
+	t.Helper()
+
+	p := h.path(name)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatalf("failed to create dir of %s: %+v", name, err)
+	}
+	if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %+v", name, err)
+	}
+}
+
+func (h *fakeHost) remove(t *testing.T, name string) {
+	t.Helper()
+
+	if err := os.RemoveAll(h.path(name)); err != nil {
+		t.Fatalf("failed to remove %s: %+v", name, err)
+	}
+}
+
+func TestRunPreflightChecks(t *testing.T) {
+	tests := []struct {
+		name       string
+		breakHost  func(t *testing.T, h *fakeHost, nl *fakeNetlink)
+		nftables   bool
+		fix        bool
+		wantFailed []string
+		wantFixed  []string
+	}{
+		{
+			name:      "healthy host",
+			breakHost: func(t *testing.T, h *fakeHost, nl *fakeNetlink) {},
+		},
+		{
+			name: "missing br_netfilter",
+			breakHost: func(t *testing.T, h *fakeHost, nl *fakeNetlink) {
+				h.remove(t, "sys/module/br_netfilter")
+				h.remove(t, "proc/sys/net/bridge")
+			},
+			wantFailed: []string{"kernel module br_netfilter", "sysctl net.bridge.bridge-nf-call-iptables", "sysctl net.bridge.bridge-nf-call-ip6tables"},
+		},
+		{
+			name: "bridge-nf-call-iptables disabled",
+			breakHost: func(t *testing.T, h *fakeHost, nl *fakeNetlink) {
+				h.writeFile(t, "proc/sys/net/bridge/bridge-nf-call-iptables", "0\n")
+			},
+			wantFailed: []string{"sysctl net.bridge.bridge-nf-call-iptables"},
+		},
+		{
+			name: "fix bridge-nf-call-iptables",
+			breakHost: func(t *testing.T, h *fakeHost, nl *fakeNetlink) {
+				h.writeFile(t, "proc/sys/net/bridge/bridge-nf-call-iptables", "0\n")
+			},
+			fix:       true,
+			wantFixed: []string{"sysctl net.bridge.bridge-nf-call-iptables"},
+		},
+		{
+			name: "missing ip6tables",
+			breakHost: func(t *testing.T, h *fakeHost, nl *fakeNetlink) {
+				delete(h.binaries, "ip6tables")
+			},
+			wantFailed: []string{"binary ip6tables"},
+		},
+		{
+			name: "fix kernel module",
+			breakHost: func(t *testing.T, h *fakeHost, nl *fakeNetlink) {
+				h.remove(t, "sys/module/8021q")
+			},
+			fix:       true,
+			wantFixed: []string{"kernel module 8021q"},
+		},
+		{
+			name: "nftables backend",
+			breakHost: func(t *testing.T, h *fakeHost, nl *fakeNetlink) {
+				h.remove(t, "sys/module/br_netfilter")
+				h.remove(t, "proc/sys/net/bridge")
+				delete(h.binaries, "iptables")
+				delete(h.binaries, "ip6tables")
+				delete(h.binaries, "ipset")
+			},
+			nftables:  true,
+			fix:       true,
+			wantFixed: []string{"kernel module nf_tables", "kernel module nf_conntrack_bridge"},
+		},
+		{
+			name: "missing metadata address",
+			breakHost: func(t *testing.T, h *fakeHost, nl *fakeNetlink) {
+				lo, _ := nl.byName("lo")
+				nl.addrs[lo.Attrs().Index] = nil
+			},
+			wantFailed: []string{"address 169.254.169.254/32 on lo"},
+		},
+		{
+			name: "fix metadata address",
+			breakHost: func(t *testing.T, h *fakeHost, nl *fakeNetlink) {
+				lo, _ := nl.byName("lo")
+				nl.addrs[lo.Attrs().Index] = nil
+			},
+			fix:       true,
+			wantFixed: []string{"address 169.254.169.254/32 on lo"},
+		},
+		{
+			name: "unfixable failures",
+			breakHost: func(t *testing.T, h *fakeHost, nl *fakeNetlink) {
+				h.remove(t, "dev/kvm")
+				h.remove(t, "proc/42")
+				h.writeFile(t, "proc/self/status", "Name:\tteleskop\nCapEff:\t0000000000000000\n")
+				delete(h.binaries, "iscsiadm")
+			},
+			fix:        true,
+			wantFailed: []string{"device /dev/kvm", "process iscsid", "capabilities", "binary iscsiadm"},
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		if test.nftables {
+			a.firewall = newNFTablesBackend(f.nftables.newConn, a.logger)
+		}
+		h := newFakeHost(t, f.netlink)
+		a.preflight = h.preflightHost
+		test.breakHost(t, h, f.netlink)
+
+		resp, err := a.RunPreflightChecks(context.Background(), &pb.RunPreflightChecksRequest{Fix: test.fix})
+		if err != nil {
+			t.Fatalf("%s: should not be error but: %+v", test.name, err)
+		}
+
+		var failed, fixed []string
+		for _, c := range resp.Checks {
+			switch c.Result {
+			case pb.PreflightCheck_FAILED:
+				failed = append(failed, c.Name)
+				if c.Message == "" || c.Remediation == "" {
+					t.Errorf("%s: failed check %s should have message and remediation", test.name, c.Name)
+				}
+			case pb.PreflightCheck_FIXED:
+				fixed = append(fixed, c.Name)
+			}
+		}
+		if strings.Join(failed, ",") != strings.Join(test.wantFailed, ",") {
+			t.Errorf("%s: want failed %s, but got %s", test.name, strings.Join(test.wantFailed, ","), strings.Join(failed, ","))
+		}
+		if strings.Join(fixed, ",") != strings.Join(test.wantFixed, ",") {
+			t.Errorf("%s: want fixed %s, but got %s", test.name, strings.Join(test.wantFixed, ","), strings.Join(fixed, ","))
+		}
+		if preflightPassed(resp.Checks) != (len(test.wantFailed) == 0) {
+			t.Errorf("%s: want passed %t, but got %t", test.name, len(test.wantFailed) == 0, preflightPassed(resp.Checks))
+		}
+	}
+}
+
+func TestRunPreflightChecksMissingCapabilities(t *testing.T) {
+	a, f := newTestAgent(t)
+	h := newFakeHost(t, f.netlink)
+	a.preflight = h.preflightHost
+	// CAP_NET_BIND_SERVICE and CAP_NET_RAW only
+	h.writeFile(t, "proc/self/status", "CapEff:\t0000000000002400\n")
+
+	resp, err := a.RunPreflightChecks(context.Background(), &pb.RunPreflightChecksRequest{})
+	if err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	for _, c := range resp.Checks {
+		if c.Name != "capabilities" {
+			continue
+		}
+		want := "missing CAP_NET_ADMIN, CAP_SYS_ADMIN"
+		if c.Message != want {
+			t.Errorf("want %s, but got %s", want, c.Message)
+		}
+	}
+}
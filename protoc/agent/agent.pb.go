@@ -88,6 +88,169 @@ func (VirtualMachineState_State) EnumDescriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{0, 0}
 }
 
+type Operation_State int32
+
+const (
+	Operation_PENDING   Operation_State = 0
+	Operation_RUNNING   Operation_State = 1
+	Operation_SUCCEEDED Operation_State = 2
+	Operation_FAILED    Operation_State = 3
+	Operation_CANCELLED Operation_State = 4
+)
+
+// Enum value maps for Operation_State.
+var (
+	Operation_State_name = map[int32]string{
+		0: "PENDING",
+		1: "RUNNING",
+		2: "SUCCEEDED",
+		3: "FAILED",
+		4: "CANCELLED",
+	}
+	Operation_State_value = map[string]int32{
+		"PENDING":   0,
+		"RUNNING":   1,
+		"SUCCEEDED": 2,
+		"FAILED":    3,
+		"CANCELLED": 4,
+	}
+)
+
+func (x Operation_State) Enum() *Operation_State {
+	p := new(Operation_State)
+	*p = x
+	return p
+}
+
+func (x Operation_State) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Operation_State) Descriptor() protoreflect.EnumDescriptor {
+	return file_agent_proto_enumTypes[1].Descriptor()
+}
+
+func (Operation_State) Type() protoreflect.EnumType {
+	return &file_agent_proto_enumTypes[1]
+}
+
+func (x Operation_State) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Operation_State.Descriptor instead.
+func (Operation_State) EnumDescriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{1, 0}
+}
+
+type SecurityGroupRule_Protocol int32
+
+const (
+	SecurityGroupRule_ANY    SecurityGroupRule_Protocol = 0
+	SecurityGroupRule_TCP    SecurityGroupRule_Protocol = 1
+	SecurityGroupRule_UDP    SecurityGroupRule_Protocol = 2
+	SecurityGroupRule_ICMP   SecurityGroupRule_Protocol = 3
+	SecurityGroupRule_ICMPV6 SecurityGroupRule_Protocol = 4
+)
+
+// Enum value maps for SecurityGroupRule_Protocol.
+var (
+	SecurityGroupRule_Protocol_name = map[int32]string{
+		0: "ANY",
+		1: "TCP",
+		2: "UDP",
+		3: "ICMP",
+		4: "ICMPV6",
+	}
+	SecurityGroupRule_Protocol_value = map[string]int32{
+		"ANY":    0,
+		"TCP":    1,
+		"UDP":    2,
+		"ICMP":   3,
+		"ICMPV6": 4,
+	}
+)
+
+func (x SecurityGroupRule_Protocol) Enum() *SecurityGroupRule_Protocol {
+	p := new(SecurityGroupRule_Protocol)
+	*p = x
+	return p
+}
+
+func (x SecurityGroupRule_Protocol) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SecurityGroupRule_Protocol) Descriptor() protoreflect.EnumDescriptor {
+	return file_agent_proto_enumTypes[2].Descriptor()
+}
+
+func (SecurityGroupRule_Protocol) Type() protoreflect.EnumType {
+	return &file_agent_proto_enumTypes[2]
+}
+
+func (x SecurityGroupRule_Protocol) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SecurityGroupRule_Protocol.Descriptor instead.
+func (SecurityGroupRule_Protocol) EnumDescriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{2, 0}
+}
+
+type PreflightCheck_Result int32
+
+const (
+	PreflightCheck_UNKNOWN PreflightCheck_Result = 0
+	PreflightCheck_PASSED  PreflightCheck_Result = 1
+	PreflightCheck_FAILED  PreflightCheck_Result = 2
+	// failed, but fixed by the agent
+	PreflightCheck_FIXED PreflightCheck_Result = 3
+)
+
+// Enum value maps for PreflightCheck_Result.
+var (
+	PreflightCheck_Result_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "PASSED",
+		2: "FAILED",
+		3: "FIXED",
+	}
+	PreflightCheck_Result_value = map[string]int32{
+		"UNKNOWN": 0,
+		"PASSED":  1,
+		"FAILED":  2,
+		"FIXED":   3,
+	}
+)
+
+func (x PreflightCheck_Result) Enum() *PreflightCheck_Result {
+	p := new(PreflightCheck_Result)
+	*p = x
+	return p
+}
+
+func (x PreflightCheck_Result) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PreflightCheck_Result) Descriptor() protoreflect.EnumDescriptor {
+	return file_agent_proto_enumTypes[3].Descriptor()
+}
+
+func (PreflightCheck_Result) Type() protoreflect.EnumType {
+	return &file_agent_proto_enumTypes[3]
+}
+
+func (x PreflightCheck_Result) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PreflightCheck_Result.Descriptor instead.
+func (PreflightCheck_Result) EnumDescriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{6, 0}
+}
+
 type VirtualMachineState struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -151,14 +314,31 @@ func (x *VirtualMachineState) GetState() VirtualMachineState_State {
 	return VirtualMachineState_NO_STATE
 }
 
-type GetISCSIQualifiedNameRequest struct {
+// Operation is a long-running operation started by an async request.
+type Operation struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-}
 
-func (x *GetISCSIQualifiedNameRequest) Reset() {
-	*x = GetISCSIQualifiedNameRequest{}
+	Id              string          `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Method          string          `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	State           Operation_State `protobuf:"varint,3,opt,name=state,proto3,enum=agent.Operation_State" json:"state,omitempty"`
+	ProgressPercent uint32          `protobuf:"varint,4,opt,name=progress_percent,json=progressPercent,proto3" json:"progress_percent,omitempty"`
+	ProgressMessage string          `protobuf:"bytes,5,opt,name=progress_message,json=progressMessage,proto3" json:"progress_message,omitempty"`
+	// gRPC status code and message of the error if failed or cancelled
+	ErrorCode    int32  `protobuf:"varint,6,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	ErrorMessage string `protobuf:"bytes,7,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	// unix time in seconds
+	CreateTime int64 `protobuf:"varint,8,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	UpdateTime int64 `protobuf:"varint,9,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	// Types that are assignable to Result:
+	//	*Operation_ConnectBlockDevice
+	//	*Operation_DisconnectBlockDevice
+	Result isOperation_Result `protobuf_oneof:"result"`
+}
+
+func (x *Operation) Reset() {
+	*x = Operation{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -166,13 +346,13 @@ func (x *GetISCSIQualifiedNameRequest) Reset() {
 	}
 }
 
-func (x *GetISCSIQualifiedNameRequest) String() string {
+func (x *Operation) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetISCSIQualifiedNameRequest) ProtoMessage() {}
+func (*Operation) ProtoMessage() {}
 
-func (x *GetISCSIQualifiedNameRequest) ProtoReflect() protoreflect.Message {
+func (x *Operation) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -184,19 +364,133 @@ func (x *GetISCSIQualifiedNameRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetISCSIQualifiedNameRequest.ProtoReflect.Descriptor instead.
-func (*GetISCSIQualifiedNameRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Operation.ProtoReflect.Descriptor instead.
+func (*Operation) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{1}
 }
 
-type GetIPTablesRequest struct {
+func (x *Operation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Operation) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *Operation) GetState() Operation_State {
+	if x != nil {
+		return x.State
+	}
+	return Operation_PENDING
+}
+
+func (x *Operation) GetProgressPercent() uint32 {
+	if x != nil {
+		return x.ProgressPercent
+	}
+	return 0
+}
+
+func (x *Operation) GetProgressMessage() string {
+	if x != nil {
+		return x.ProgressMessage
+	}
+	return ""
+}
+
+func (x *Operation) GetErrorCode() int32 {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return 0
+}
+
+func (x *Operation) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *Operation) GetCreateTime() int64 {
+	if x != nil {
+		return x.CreateTime
+	}
+	return 0
+}
+
+func (x *Operation) GetUpdateTime() int64 {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return 0
+}
+
+func (m *Operation) GetResult() isOperation_Result {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (x *Operation) GetConnectBlockDevice() *ConnectBlockDeviceResponse {
+	if x, ok := x.GetResult().(*Operation_ConnectBlockDevice); ok {
+		return x.ConnectBlockDevice
+	}
+	return nil
+}
+
+func (x *Operation) GetDisconnectBlockDevice() *DisconnectBlockDeviceResponse {
+	if x, ok := x.GetResult().(*Operation_DisconnectBlockDevice); ok {
+		return x.DisconnectBlockDevice
+	}
+	return nil
+}
+
+type isOperation_Result interface {
+	isOperation_Result()
+}
+
+type Operation_ConnectBlockDevice struct {
+	ConnectBlockDevice *ConnectBlockDeviceResponse `protobuf:"bytes,10,opt,name=connect_block_device,json=connectBlockDevice,proto3,oneof"`
+}
+
+type Operation_DisconnectBlockDevice struct {
+	DisconnectBlockDevice *DisconnectBlockDeviceResponse `protobuf:"bytes,11,opt,name=disconnect_block_device,json=disconnectBlockDevice,proto3,oneof"`
+}
+
+func (*Operation_ConnectBlockDevice) isOperation_Result() {}
+
+func (*Operation_DisconnectBlockDevice) isOperation_Result() {}
+
+// SecurityGroupRule allows traffic to (ingress) or from (egress) a virtual machine.
+type SecurityGroupRule struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-}
 
-func (x *GetIPTablesRequest) Reset() {
-	*x = GetIPTablesRequest{}
+	Protocol SecurityGroupRule_Protocol `protobuf:"varint,1,opt,name=protocol,proto3,enum=agent.SecurityGroupRule_Protocol" json:"protocol,omitempty"`
+	// destination port range of TCP and UDP, all ports if both are 0
+	PortRangeMin uint32 `protobuf:"varint,2,opt,name=port_range_min,json=portRangeMin,proto3" json:"port_range_min,omitempty"`
+	PortRangeMax uint32 `protobuf:"varint,3,opt,name=port_range_max,json=portRangeMax,proto3" json:"port_range_max,omitempty"`
+	// address of the other side in CIDR notation, any address if empty. The rule applies only to the
+	// family of the address, and a rule without an address applies to both IPv4 and IPv6.
+	RemoteCidr string `protobuf:"bytes,4,opt,name=remote_cidr,json=remoteCidr,proto3" json:"remote_cidr,omitempty"`
+	// ICMP or ICMPv6 type as "type", "type/code" or a name such as "echo-request", all types if empty
+	IcmpType string `protobuf:"bytes,5,opt,name=icmp_type,json=icmpType,proto3" json:"icmp_type,omitempty"`
+	// name of the remote group whose members are the other side, exclusive with remote_cidr.
+	// Remote groups are IPv4 only, so the rule applies only to IPv4.
+	RemoteGroup string `protobuf:"bytes,6,opt,name=remote_group,json=remoteGroup,proto3" json:"remote_group,omitempty"`
+}
+
+func (x *SecurityGroupRule) Reset() {
+	*x = SecurityGroupRule{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -204,13 +498,13 @@ func (x *GetIPTablesRequest) Reset() {
 	}
 }
 
-func (x *GetIPTablesRequest) String() string {
+func (x *SecurityGroupRule) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetIPTablesRequest) ProtoMessage() {}
+func (*SecurityGroupRule) ProtoMessage() {}
 
-func (x *GetIPTablesRequest) ProtoReflect() protoreflect.Message {
+func (x *SecurityGroupRule) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -222,19 +516,65 @@ func (x *GetIPTablesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetIPTablesRequest.ProtoReflect.Descriptor instead.
-func (*GetIPTablesRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SecurityGroupRule.ProtoReflect.Descriptor instead.
+func (*SecurityGroupRule) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{2}
 }
 
-type SetupDefaultSecurityGroupRequest struct {
+func (x *SecurityGroupRule) GetProtocol() SecurityGroupRule_Protocol {
+	if x != nil {
+		return x.Protocol
+	}
+	return SecurityGroupRule_ANY
+}
+
+func (x *SecurityGroupRule) GetPortRangeMin() uint32 {
+	if x != nil {
+		return x.PortRangeMin
+	}
+	return 0
+}
+
+func (x *SecurityGroupRule) GetPortRangeMax() uint32 {
+	if x != nil {
+		return x.PortRangeMax
+	}
+	return 0
+}
+
+func (x *SecurityGroupRule) GetRemoteCidr() string {
+	if x != nil {
+		return x.RemoteCidr
+	}
+	return ""
+}
+
+func (x *SecurityGroupRule) GetIcmpType() string {
+	if x != nil {
+		return x.IcmpType
+	}
+	return ""
+}
+
+func (x *SecurityGroupRule) GetRemoteGroup() string {
+	if x != nil {
+		return x.RemoteGroup
+	}
+	return ""
+}
+
+// RemoteGroup is a named set of IP addresses referred by security group rules.
+type RemoteGroup struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Name        string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	IpAddresses []string `protobuf:"bytes,2,rep,name=ip_addresses,json=ipAddresses,proto3" json:"ip_addresses,omitempty"`
 }
 
-func (x *SetupDefaultSecurityGroupRequest) Reset() {
-	*x = SetupDefaultSecurityGroupRequest{}
+func (x *RemoteGroup) Reset() {
+	*x = RemoteGroup{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -242,13 +582,13 @@ func (x *SetupDefaultSecurityGroupRequest) Reset() {
 	}
 }
 
-func (x *SetupDefaultSecurityGroupRequest) String() string {
+func (x *RemoteGroup) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetupDefaultSecurityGroupRequest) ProtoMessage() {}
+func (*RemoteGroup) ProtoMessage() {}
 
-func (x *SetupDefaultSecurityGroupRequest) ProtoReflect() protoreflect.Message {
+func (x *RemoteGroup) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -260,23 +600,41 @@ func (x *SetupDefaultSecurityGroupRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetupDefaultSecurityGroupRequest.ProtoReflect.Descriptor instead.
-func (*SetupDefaultSecurityGroupRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use RemoteGroup.ProtoReflect.Descriptor instead.
+func (*RemoteGroup) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{3}
 }
 
-type AddSecurityGroupRequest struct {
+func (x *RemoteGroup) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RemoteGroup) GetIpAddresses() []string {
+	if x != nil {
+		return x.IpAddresses
+	}
+	return nil
+}
+
+// IPTablesChain is a chain managed by the agent.
+type IPTablesChain struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Interface  string `protobuf:"bytes,1,opt,name=interface,proto3" json:"interface,omitempty"`
-	IpAddress  string `protobuf:"bytes,2,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
-	MacAddress string `protobuf:"bytes,3,opt,name=mac_address,json=macAddress,proto3" json:"mac_address,omitempty"`
+	Name  string          `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Rules []*IPTablesRule `protobuf:"bytes,2,rep,name=rules,proto3" json:"rules,omitempty"`
+	// interface secured by the chain, or empty for the shared chains
+	Interface string `protobuf:"bytes,3,opt,name=interface,proto3" json:"interface,omitempty"`
+	// address family of the chain, "ipv4" or "ipv6", or empty if the chain filters both
+	Family string `protobuf:"bytes,4,opt,name=family,proto3" json:"family,omitempty"`
 }
 
-func (x *AddSecurityGroupRequest) Reset() {
-	*x = AddSecurityGroupRequest{}
+func (x *IPTablesChain) Reset() {
+	*x = IPTablesChain{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -284,13 +642,13 @@ func (x *AddSecurityGroupRequest) Reset() {
 	}
 }
 
-func (x *AddSecurityGroupRequest) String() string {
+func (x *IPTablesChain) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddSecurityGroupRequest) ProtoMessage() {}
+func (*IPTablesChain) ProtoMessage() {}
 
-func (x *AddSecurityGroupRequest) ProtoReflect() protoreflect.Message {
+func (x *IPTablesChain) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -302,40 +660,67 @@ func (x *AddSecurityGroupRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddSecurityGroupRequest.ProtoReflect.Descriptor instead.
-func (*AddSecurityGroupRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use IPTablesChain.ProtoReflect.Descriptor instead.
+func (*IPTablesChain) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *AddSecurityGroupRequest) GetInterface() string {
+func (x *IPTablesChain) GetName() string {
 	if x != nil {
-		return x.Interface
+		return x.Name
 	}
 	return ""
 }
 
-func (x *AddSecurityGroupRequest) GetIpAddress() string {
+func (x *IPTablesChain) GetRules() []*IPTablesRule {
 	if x != nil {
-		return x.IpAddress
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *IPTablesChain) GetInterface() string {
+	if x != nil {
+		return x.Interface
 	}
 	return ""
 }
 
-func (x *AddSecurityGroupRequest) GetMacAddress() string {
+func (x *IPTablesChain) GetFamily() string {
 	if x != nil {
-		return x.MacAddress
+		return x.Family
 	}
 	return ""
 }
 
-type GetInterfaceNameRequest struct {
+// IPTablesRule is a rule of a chain. Values of negated matches are prefixed by "!".
+type IPTablesRule struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-}
 
-func (x *GetInterfaceNameRequest) Reset() {
-	*x = GetInterfaceNameRequest{}
+	// rulespec without the chain and the counters
+	Rule             string `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	Protocol         string `protobuf:"bytes,2,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Source           string `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	Destination      string `protobuf:"bytes,4,opt,name=destination,proto3" json:"destination,omitempty"`
+	SourcePorts      string `protobuf:"bytes,5,opt,name=source_ports,json=sourcePorts,proto3" json:"source_ports,omitempty"`
+	DestinationPorts string `protobuf:"bytes,6,opt,name=destination_ports,json=destinationPorts,proto3" json:"destination_ports,omitempty"`
+	PhysdevIn        string `protobuf:"bytes,7,opt,name=physdev_in,json=physdevIn,proto3" json:"physdev_in,omitempty"`
+	PhysdevOut       string `protobuf:"bytes,8,opt,name=physdev_out,json=physdevOut,proto3" json:"physdev_out,omitempty"`
+	MacSource        string `protobuf:"bytes,9,opt,name=mac_source,json=macSource,proto3" json:"mac_source,omitempty"`
+	State            string `protobuf:"bytes,10,opt,name=state,proto3" json:"state,omitempty"`
+	IcmpType         string `protobuf:"bytes,11,opt,name=icmp_type,json=icmpType,proto3" json:"icmp_type,omitempty"`
+	// set name and flags such as "callisto-gdb src"
+	MatchSet string `protobuf:"bytes,12,opt,name=match_set,json=matchSet,proto3" json:"match_set,omitempty"`
+	Comment  string `protobuf:"bytes,13,opt,name=comment,proto3" json:"comment,omitempty"`
+	Target   string `protobuf:"bytes,14,opt,name=target,proto3" json:"target,omitempty"`
+	Packets  uint64 `protobuf:"varint,15,opt,name=packets,proto3" json:"packets,omitempty"`
+	Bytes    uint64 `protobuf:"varint,16,opt,name=bytes,proto3" json:"bytes,omitempty"`
+}
+
+func (x *IPTablesRule) Reset() {
+	*x = IPTablesRule{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -343,13 +728,13 @@ func (x *GetInterfaceNameRequest) Reset() {
 	}
 }
 
-func (x *GetInterfaceNameRequest) String() string {
+func (x *IPTablesRule) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetInterfaceNameRequest) ProtoMessage() {}
+func (*IPTablesRule) ProtoMessage() {}
 
-func (x *GetInterfaceNameRequest) ProtoReflect() protoreflect.Message {
+func (x *IPTablesRule) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -361,37 +746,153 @@ func (x *GetInterfaceNameRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetInterfaceNameRequest.ProtoReflect.Descriptor instead.
-func (*GetInterfaceNameRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use IPTablesRule.ProtoReflect.Descriptor instead.
+func (*IPTablesRule) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{5}
 }
 
-type AddBridgeRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *IPTablesRule) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
 
-	Name         string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	MetadataCidr string `protobuf:"bytes,2,opt,name=metadata_cidr,json=metadataCidr,proto3" json:"metadata_cidr,omitempty"`
-	InternalOnly bool   `protobuf:"varint,3,opt,name=internal_only,json=internalOnly,proto3" json:"internal_only,omitempty"`
+func (x *IPTablesRule) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
 }
 
-func (x *AddBridgeRequest) Reset() {
-	*x = AddBridgeRequest{}
-	if protoimpl.UnsafeEnabled {
+func (x *IPTablesRule) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *IPTablesRule) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *IPTablesRule) GetSourcePorts() string {
+	if x != nil {
+		return x.SourcePorts
+	}
+	return ""
+}
+
+func (x *IPTablesRule) GetDestinationPorts() string {
+	if x != nil {
+		return x.DestinationPorts
+	}
+	return ""
+}
+
+func (x *IPTablesRule) GetPhysdevIn() string {
+	if x != nil {
+		return x.PhysdevIn
+	}
+	return ""
+}
+
+func (x *IPTablesRule) GetPhysdevOut() string {
+	if x != nil {
+		return x.PhysdevOut
+	}
+	return ""
+}
+
+func (x *IPTablesRule) GetMacSource() string {
+	if x != nil {
+		return x.MacSource
+	}
+	return ""
+}
+
+func (x *IPTablesRule) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *IPTablesRule) GetIcmpType() string {
+	if x != nil {
+		return x.IcmpType
+	}
+	return ""
+}
+
+func (x *IPTablesRule) GetMatchSet() string {
+	if x != nil {
+		return x.MatchSet
+	}
+	return ""
+}
+
+func (x *IPTablesRule) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *IPTablesRule) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *IPTablesRule) GetPackets() uint64 {
+	if x != nil {
+		return x.Packets
+	}
+	return 0
+}
+
+func (x *IPTablesRule) GetBytes() uint64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+// PreflightCheck is the result of a check of the host requirements.
+type PreflightCheck struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name   string                `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Result PreflightCheck_Result `protobuf:"varint,2,opt,name=result,proto3,enum=agent.PreflightCheck_Result" json:"result,omitempty"`
+	// reason of the failure
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	// how to fix the failure by hand
+	Remediation string `protobuf:"bytes,4,opt,name=remediation,proto3" json:"remediation,omitempty"`
+}
+
+func (x *PreflightCheck) Reset() {
+	*x = PreflightCheck{}
+	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *AddBridgeRequest) String() string {
+func (x *PreflightCheck) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddBridgeRequest) ProtoMessage() {}
+func (*PreflightCheck) ProtoMessage() {}
 
-func (x *AddBridgeRequest) ProtoReflect() protoreflect.Message {
+func (x *PreflightCheck) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -403,43 +904,47 @@ func (x *AddBridgeRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddBridgeRequest.ProtoReflect.Descriptor instead.
-func (*AddBridgeRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use PreflightCheck.ProtoReflect.Descriptor instead.
+func (*PreflightCheck) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *AddBridgeRequest) GetName() string {
+func (x *PreflightCheck) GetName() string {
 	if x != nil {
 		return x.Name
 	}
 	return ""
 }
 
-func (x *AddBridgeRequest) GetMetadataCidr() string {
+func (x *PreflightCheck) GetResult() PreflightCheck_Result {
 	if x != nil {
-		return x.MetadataCidr
+		return x.Result
+	}
+	return PreflightCheck_UNKNOWN
+}
+
+func (x *PreflightCheck) GetMessage() string {
+	if x != nil {
+		return x.Message
 	}
 	return ""
 }
 
-func (x *AddBridgeRequest) GetInternalOnly() bool {
+func (x *PreflightCheck) GetRemediation() string {
 	if x != nil {
-		return x.InternalOnly
+		return x.Remediation
 	}
-	return false
+	return ""
 }
 
-type AddVLANInterfaceRequest struct {
+type GetISCSIQualifiedNameRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	VlanId          uint32 `protobuf:"varint,1,opt,name=vlan_id,json=vlanId,proto3" json:"vlan_id,omitempty"`
-	ParentInterface string `protobuf:"bytes,2,opt,name=parent_interface,json=parentInterface,proto3" json:"parent_interface,omitempty"`
 }
 
-func (x *AddVLANInterfaceRequest) Reset() {
-	*x = AddVLANInterfaceRequest{}
+func (x *GetISCSIQualifiedNameRequest) Reset() {
+	*x = GetISCSIQualifiedNameRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -447,13 +952,13 @@ func (x *AddVLANInterfaceRequest) Reset() {
 	}
 }
 
-func (x *AddVLANInterfaceRequest) String() string {
+func (x *GetISCSIQualifiedNameRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddVLANInterfaceRequest) ProtoMessage() {}
+func (*GetISCSIQualifiedNameRequest) ProtoMessage() {}
 
-func (x *AddVLANInterfaceRequest) ProtoReflect() protoreflect.Message {
+func (x *GetISCSIQualifiedNameRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -465,36 +970,22 @@ func (x *AddVLANInterfaceRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddVLANInterfaceRequest.ProtoReflect.Descriptor instead.
-func (*AddVLANInterfaceRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetISCSIQualifiedNameRequest.ProtoReflect.Descriptor instead.
+func (*GetISCSIQualifiedNameRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *AddVLANInterfaceRequest) GetVlanId() uint32 {
-	if x != nil {
-		return x.VlanId
-	}
-	return 0
-}
-
-func (x *AddVLANInterfaceRequest) GetParentInterface() string {
-	if x != nil {
-		return x.ParentInterface
-	}
-	return ""
-}
-
-type AddInterfaceToBridgeRequest struct {
+type GetIPTablesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Bridge    string `protobuf:"bytes,1,opt,name=bridge,proto3" json:"bridge,omitempty"`
-	Interface string `protobuf:"bytes,2,opt,name=interface,proto3" json:"interface,omitempty"`
+	// only the chains and rules of the interface if set
+	Interface string `protobuf:"bytes,1,opt,name=interface,proto3" json:"interface,omitempty"`
 }
 
-func (x *AddInterfaceToBridgeRequest) Reset() {
-	*x = AddInterfaceToBridgeRequest{}
+func (x *GetIPTablesRequest) Reset() {
+	*x = GetIPTablesRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -502,13 +993,13 @@ func (x *AddInterfaceToBridgeRequest) Reset() {
 	}
 }
 
-func (x *AddInterfaceToBridgeRequest) String() string {
+func (x *GetIPTablesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddInterfaceToBridgeRequest) ProtoMessage() {}
+func (*GetIPTablesRequest) ProtoMessage() {}
 
-func (x *AddInterfaceToBridgeRequest) ProtoReflect() protoreflect.Message {
+func (x *GetIPTablesRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -520,43 +1011,28 @@ func (x *AddInterfaceToBridgeRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddInterfaceToBridgeRequest.ProtoReflect.Descriptor instead.
-func (*AddInterfaceToBridgeRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetIPTablesRequest.ProtoReflect.Descriptor instead.
+func (*GetIPTablesRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *AddInterfaceToBridgeRequest) GetBridge() string {
-	if x != nil {
-		return x.Bridge
-	}
-	return ""
-}
-
-func (x *AddInterfaceToBridgeRequest) GetInterface() string {
+func (x *GetIPTablesRequest) GetInterface() string {
 	if x != nil {
 		return x.Interface
 	}
 	return ""
 }
 
-type AddVirtualMachineRequest struct {
+type SetupDefaultSecurityGroupRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name             string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Vcpus            uint32 `protobuf:"varint,2,opt,name=vcpus,proto3" json:"vcpus,omitempty"`
-	MemoryKib        uint64 `protobuf:"varint,3,opt,name=memory_kib,json=memoryKib,proto3" json:"memory_kib,omitempty"`
-	BootDevice       string `protobuf:"bytes,4,opt,name=boot_device,json=bootDevice,proto3" json:"boot_device,omitempty"`
-	ReadBytesSec     uint32 `protobuf:"varint,5,opt,name=read_bytes_sec,json=readBytesSec,proto3" json:"read_bytes_sec,omitempty"`
-	WriteBytesSec    uint32 `protobuf:"varint,6,opt,name=write_bytes_sec,json=writeBytesSec,proto3" json:"write_bytes_sec,omitempty"`
-	ReadIopsSec      uint32 `protobuf:"varint,7,opt,name=read_iops_sec,json=readIopsSec,proto3" json:"read_iops_sec,omitempty"`
-	WriteIopsSec     uint32 `protobuf:"varint,8,opt,name=write_iops_sec,json=writeIopsSec,proto3" json:"write_iops_sec,omitempty"`
-	PinningGroupName string `protobuf:"bytes,9,opt,name=pinning_group_name,json=pinningGroupName,proto3" json:"pinning_group_name,omitempty"`
+	RequestId string `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *AddVirtualMachineRequest) Reset() {
-	*x = AddVirtualMachineRequest{}
+func (x *SetupDefaultSecurityGroupRequest) Reset() {
+	*x = SetupDefaultSecurityGroupRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -564,13 +1040,13 @@ func (x *AddVirtualMachineRequest) Reset() {
 	}
 }
 
-func (x *AddVirtualMachineRequest) String() string {
+func (x *SetupDefaultSecurityGroupRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddVirtualMachineRequest) ProtoMessage() {}
+func (*SetupDefaultSecurityGroupRequest) ProtoMessage() {}
 
-func (x *AddVirtualMachineRequest) ProtoReflect() protoreflect.Message {
+func (x *SetupDefaultSecurityGroupRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -582,100 +1058,155 @@ func (x *AddVirtualMachineRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddVirtualMachineRequest.ProtoReflect.Descriptor instead.
-func (*AddVirtualMachineRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetupDefaultSecurityGroupRequest.ProtoReflect.Descriptor instead.
+func (*SetupDefaultSecurityGroupRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *AddVirtualMachineRequest) GetName() string {
+func (x *SetupDefaultSecurityGroupRequest) GetRequestId() string {
 	if x != nil {
-		return x.Name
+		return x.RequestId
 	}
 	return ""
 }
 
-func (x *AddVirtualMachineRequest) GetVcpus() uint32 {
-	if x != nil {
-		return x.Vcpus
+type AddSecurityGroupRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Interface  string `protobuf:"bytes,1,opt,name=interface,proto3" json:"interface,omitempty"`
+	IpAddress  string `protobuf:"bytes,2,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	MacAddress string `protobuf:"bytes,3,opt,name=mac_address,json=macAddress,proto3" json:"mac_address,omitempty"`
+	RequestId  string `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// traffic not allowed by the rules is dropped, except for DHCP, DHCPv6, neighbor discovery
+	// and established connections
+	IngressRules []*SecurityGroupRule `protobuf:"bytes,5,rep,name=ingress_rules,json=ingressRules,proto3" json:"ingress_rules,omitempty"`
+	EgressRules  []*SecurityGroupRule `protobuf:"bytes,6,rep,name=egress_rules,json=egressRules,proto3" json:"egress_rules,omitempty"`
+	// IPv6 address of the VM, optional. IPv6 traffic from the VM is allowed only from it and the
+	// link-local addresses, and router advertisements and DHCPv6 replies from the VM are dropped.
+	Ipv6Address string `protobuf:"bytes,7,opt,name=ipv6_address,json=ipv6Address,proto3" json:"ipv6_address,omitempty"`
+	// additional addresses that the VM sends traffic from, e.g. virtual IPs of keepalived
+	AllowedAddressPairs []*AllowedAddressPair `protobuf:"bytes,8,rep,name=allowed_address_pairs,json=allowedAddressPairs,proto3" json:"allowed_address_pairs,omitempty"`
+}
+
+func (x *AddSecurityGroupRequest) Reset() {
+	*x = AddSecurityGroupRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return 0
 }
 
-func (x *AddVirtualMachineRequest) GetMemoryKib() uint64 {
+func (x *AddSecurityGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddSecurityGroupRequest) ProtoMessage() {}
+
+func (x *AddSecurityGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddSecurityGroupRequest.ProtoReflect.Descriptor instead.
+func (*AddSecurityGroupRequest) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *AddSecurityGroupRequest) GetInterface() string {
 	if x != nil {
-		return x.MemoryKib
+		return x.Interface
 	}
-	return 0
+	return ""
 }
 
-func (x *AddVirtualMachineRequest) GetBootDevice() string {
+func (x *AddSecurityGroupRequest) GetIpAddress() string {
 	if x != nil {
-		return x.BootDevice
+		return x.IpAddress
 	}
 	return ""
 }
 
-func (x *AddVirtualMachineRequest) GetReadBytesSec() uint32 {
+func (x *AddSecurityGroupRequest) GetMacAddress() string {
 	if x != nil {
-		return x.ReadBytesSec
+		return x.MacAddress
 	}
-	return 0
+	return ""
 }
 
-func (x *AddVirtualMachineRequest) GetWriteBytesSec() uint32 {
+func (x *AddSecurityGroupRequest) GetRequestId() string {
 	if x != nil {
-		return x.WriteBytesSec
+		return x.RequestId
 	}
-	return 0
+	return ""
 }
 
-func (x *AddVirtualMachineRequest) GetReadIopsSec() uint32 {
+func (x *AddSecurityGroupRequest) GetIngressRules() []*SecurityGroupRule {
 	if x != nil {
-		return x.ReadIopsSec
+		return x.IngressRules
 	}
-	return 0
+	return nil
 }
 
-func (x *AddVirtualMachineRequest) GetWriteIopsSec() uint32 {
+func (x *AddSecurityGroupRequest) GetEgressRules() []*SecurityGroupRule {
 	if x != nil {
-		return x.WriteIopsSec
+		return x.EgressRules
 	}
-	return 0
+	return nil
 }
 
-func (x *AddVirtualMachineRequest) GetPinningGroupName() string {
+func (x *AddSecurityGroupRequest) GetIpv6Address() string {
 	if x != nil {
-		return x.PinningGroupName
+		return x.Ipv6Address
 	}
 	return ""
 }
 
-type ConnectBlockDeviceRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+func (x *AddSecurityGroupRequest) GetAllowedAddressPairs() []*AllowedAddressPair {
+	if x != nil {
+		return x.AllowedAddressPairs
+	}
+	return nil
+}
+
+// AllowedAddressPair allows traffic from an address or a network with a MAC address.
+type AllowedAddressPair struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	PortalAddresses []string `protobuf:"bytes,1,rep,name=portal_addresses,json=portalAddresses,proto3" json:"portal_addresses,omitempty"`
-	HostLunId       uint32   `protobuf:"varint,2,opt,name=host_lun_id,json=hostLunId,proto3" json:"host_lun_id,omitempty"`
+	// IPv4 or IPv6 address, or a network in CIDR notation
+	IpAddress string `protobuf:"bytes,1,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	// the MAC address of the interface if empty
+	MacAddress string `protobuf:"bytes,2,opt,name=mac_address,json=macAddress,proto3" json:"mac_address,omitempty"`
 }
 
-func (x *ConnectBlockDeviceRequest) Reset() {
-	*x = ConnectBlockDeviceRequest{}
+func (x *AllowedAddressPair) Reset() {
+	*x = AllowedAddressPair{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_agent_proto_msgTypes[10]
+		mi := &file_agent_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ConnectBlockDeviceRequest) String() string {
+func (x *AllowedAddressPair) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ConnectBlockDeviceRequest) ProtoMessage() {}
+func (*AllowedAddressPair) ProtoMessage() {}
 
-func (x *ConnectBlockDeviceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_proto_msgTypes[10]
+func (x *AllowedAddressPair) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -686,56 +1217,51 @@ func (x *ConnectBlockDeviceRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ConnectBlockDeviceRequest.ProtoReflect.Descriptor instead.
-func (*ConnectBlockDeviceRequest) Descriptor() ([]byte, []int) {
-	return file_agent_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use AllowedAddressPair.ProtoReflect.Descriptor instead.
+func (*AllowedAddressPair) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *ConnectBlockDeviceRequest) GetPortalAddresses() []string {
+func (x *AllowedAddressPair) GetIpAddress() string {
 	if x != nil {
-		return x.PortalAddresses
+		return x.IpAddress
 	}
-	return nil
+	return ""
 }
 
-func (x *ConnectBlockDeviceRequest) GetHostLunId() uint32 {
+func (x *AllowedAddressPair) GetMacAddress() string {
 	if x != nil {
-		return x.HostLunId
+		return x.MacAddress
 	}
-	return 0
+	return ""
 }
 
-type AttachBlockDeviceRequest struct {
+type RemoveSecurityGroupRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Uuid          string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
-	SourceDevice  string `protobuf:"bytes,2,opt,name=source_device,json=sourceDevice,proto3" json:"source_device,omitempty"`
-	TargetDevice  string `protobuf:"bytes,3,opt,name=target_device,json=targetDevice,proto3" json:"target_device,omitempty"`
-	ReadBytesSec  uint32 `protobuf:"varint,4,opt,name=read_bytes_sec,json=readBytesSec,proto3" json:"read_bytes_sec,omitempty"`
-	WriteBytesSec uint32 `protobuf:"varint,5,opt,name=write_bytes_sec,json=writeBytesSec,proto3" json:"write_bytes_sec,omitempty"`
-	ReadIopsSec   uint32 `protobuf:"varint,6,opt,name=read_iops_sec,json=readIopsSec,proto3" json:"read_iops_sec,omitempty"`
-	WriteIopsSec  uint32 `protobuf:"varint,7,opt,name=write_iops_sec,json=writeIopsSec,proto3" json:"write_iops_sec,omitempty"`
+	Interface string `protobuf:"bytes,1,opt,name=interface,proto3" json:"interface,omitempty"`
+	RequestId string `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *AttachBlockDeviceRequest) Reset() {
-	*x = AttachBlockDeviceRequest{}
+func (x *RemoveSecurityGroupRequest) Reset() {
+	*x = RemoveSecurityGroupRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_agent_proto_msgTypes[11]
+		mi := &file_agent_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *AttachBlockDeviceRequest) String() string {
+func (x *RemoveSecurityGroupRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AttachBlockDeviceRequest) ProtoMessage() {}
+func (*RemoveSecurityGroupRequest) ProtoMessage() {}
 
-func (x *AttachBlockDeviceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_proto_msgTypes[11]
+func (x *RemoveSecurityGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -746,90 +1272,54 @@ func (x *AttachBlockDeviceRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AttachBlockDeviceRequest.ProtoReflect.Descriptor instead.
-func (*AttachBlockDeviceRequest) Descriptor() ([]byte, []int) {
-	return file_agent_proto_rawDescGZIP(), []int{11}
-}
-
-func (x *AttachBlockDeviceRequest) GetUuid() string {
-	if x != nil {
-		return x.Uuid
-	}
-	return ""
+// Deprecated: Use RemoveSecurityGroupRequest.ProtoReflect.Descriptor instead.
+func (*RemoveSecurityGroupRequest) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *AttachBlockDeviceRequest) GetSourceDevice() string {
+func (x *RemoveSecurityGroupRequest) GetInterface() string {
 	if x != nil {
-		return x.SourceDevice
+		return x.Interface
 	}
 	return ""
 }
 
-func (x *AttachBlockDeviceRequest) GetTargetDevice() string {
+func (x *RemoveSecurityGroupRequest) GetRequestId() string {
 	if x != nil {
-		return x.TargetDevice
+		return x.RequestId
 	}
 	return ""
 }
 
-func (x *AttachBlockDeviceRequest) GetReadBytesSec() uint32 {
-	if x != nil {
-		return x.ReadBytesSec
-	}
-	return 0
-}
-
-func (x *AttachBlockDeviceRequest) GetWriteBytesSec() uint32 {
-	if x != nil {
-		return x.WriteBytesSec
-	}
-	return 0
-}
-
-func (x *AttachBlockDeviceRequest) GetReadIopsSec() uint32 {
-	if x != nil {
-		return x.ReadIopsSec
-	}
-	return 0
-}
-
-func (x *AttachBlockDeviceRequest) GetWriteIopsSec() uint32 {
-	if x != nil {
-		return x.WriteIopsSec
-	}
-	return 0
-}
-
-type AttachInterfaceRequest struct {
+// UpdateSecurityGroupRulesRequest replaces the rules of the security group of interface.
+type UpdateSecurityGroupRulesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Uuid            string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
-	Bridge          string `protobuf:"bytes,2,opt,name=bridge,proto3" json:"bridge,omitempty"`
-	InboundAverage  uint32 `protobuf:"varint,3,opt,name=inbound_average,json=inboundAverage,proto3" json:"inbound_average,omitempty"`
-	OutboundAverage uint32 `protobuf:"varint,4,opt,name=outbound_average,json=outboundAverage,proto3" json:"outbound_average,omitempty"`
-	Name            string `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
-	MacAddress      string `protobuf:"bytes,6,opt,name=mac_address,json=macAddress,proto3" json:"mac_address,omitempty"`
+	Interface    string               `protobuf:"bytes,1,opt,name=interface,proto3" json:"interface,omitempty"`
+	IngressRules []*SecurityGroupRule `protobuf:"bytes,2,rep,name=ingress_rules,json=ingressRules,proto3" json:"ingress_rules,omitempty"`
+	EgressRules  []*SecurityGroupRule `protobuf:"bytes,3,rep,name=egress_rules,json=egressRules,proto3" json:"egress_rules,omitempty"`
+	RequestId    string               `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *AttachInterfaceRequest) Reset() {
-	*x = AttachInterfaceRequest{}
+func (x *UpdateSecurityGroupRulesRequest) Reset() {
+	*x = UpdateSecurityGroupRulesRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_agent_proto_msgTypes[12]
+		mi := &file_agent_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *AttachInterfaceRequest) String() string {
+func (x *UpdateSecurityGroupRulesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AttachInterfaceRequest) ProtoMessage() {}
+func (*UpdateSecurityGroupRulesRequest) ProtoMessage() {}
 
-func (x *AttachInterfaceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_proto_msgTypes[12]
+func (x *UpdateSecurityGroupRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -840,78 +1330,68 @@ func (x *AttachInterfaceRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AttachInterfaceRequest.ProtoReflect.Descriptor instead.
-func (*AttachInterfaceRequest) Descriptor() ([]byte, []int) {
-	return file_agent_proto_rawDescGZIP(), []int{12}
-}
-
-func (x *AttachInterfaceRequest) GetUuid() string {
-	if x != nil {
-		return x.Uuid
-	}
-	return ""
+// Deprecated: Use UpdateSecurityGroupRulesRequest.ProtoReflect.Descriptor instead.
+func (*UpdateSecurityGroupRulesRequest) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *AttachInterfaceRequest) GetBridge() string {
+func (x *UpdateSecurityGroupRulesRequest) GetInterface() string {
 	if x != nil {
-		return x.Bridge
+		return x.Interface
 	}
 	return ""
 }
 
-func (x *AttachInterfaceRequest) GetInboundAverage() uint32 {
-	if x != nil {
-		return x.InboundAverage
-	}
-	return 0
-}
-
-func (x *AttachInterfaceRequest) GetOutboundAverage() uint32 {
+func (x *UpdateSecurityGroupRulesRequest) GetIngressRules() []*SecurityGroupRule {
 	if x != nil {
-		return x.OutboundAverage
+		return x.IngressRules
 	}
-	return 0
+	return nil
 }
 
-func (x *AttachInterfaceRequest) GetName() string {
+func (x *UpdateSecurityGroupRulesRequest) GetEgressRules() []*SecurityGroupRule {
 	if x != nil {
-		return x.Name
+		return x.EgressRules
 	}
-	return ""
+	return nil
 }
 
-func (x *AttachInterfaceRequest) GetMacAddress() string {
+func (x *UpdateSecurityGroupRulesRequest) GetRequestId() string {
 	if x != nil {
-		return x.MacAddress
+		return x.RequestId
 	}
 	return ""
 }
 
-type StartVirtualMachineRequest struct {
+// AddAllowedAddressPairsRequest adds pairs to the security group of interface. Pairs that are already
+// allowed are skipped.
+type AddAllowedAddressPairsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Uuid string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Interface           string                `protobuf:"bytes,1,opt,name=interface,proto3" json:"interface,omitempty"`
+	AllowedAddressPairs []*AllowedAddressPair `protobuf:"bytes,2,rep,name=allowed_address_pairs,json=allowedAddressPairs,proto3" json:"allowed_address_pairs,omitempty"`
+	RequestId           string                `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *StartVirtualMachineRequest) Reset() {
-	*x = StartVirtualMachineRequest{}
+func (x *AddAllowedAddressPairsRequest) Reset() {
+	*x = AddAllowedAddressPairsRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_agent_proto_msgTypes[13]
+		mi := &file_agent_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *StartVirtualMachineRequest) String() string {
+func (x *AddAllowedAddressPairsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StartVirtualMachineRequest) ProtoMessage() {}
+func (*AddAllowedAddressPairsRequest) ProtoMessage() {}
 
-func (x *StartVirtualMachineRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_proto_msgTypes[13]
+func (x *AddAllowedAddressPairsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -922,43 +1402,61 @@ func (x *StartVirtualMachineRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StartVirtualMachineRequest.ProtoReflect.Descriptor instead.
-func (*StartVirtualMachineRequest) Descriptor() ([]byte, []int) {
-	return file_agent_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use AddAllowedAddressPairsRequest.ProtoReflect.Descriptor instead.
+func (*AddAllowedAddressPairsRequest) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *StartVirtualMachineRequest) GetUuid() string {
+func (x *AddAllowedAddressPairsRequest) GetInterface() string {
 	if x != nil {
-		return x.Uuid
+		return x.Interface
 	}
 	return ""
 }
 
-type GetVirtualMachineStateRequest struct {
+func (x *AddAllowedAddressPairsRequest) GetAllowedAddressPairs() []*AllowedAddressPair {
+	if x != nil {
+		return x.AllowedAddressPairs
+	}
+	return nil
+}
+
+func (x *AddAllowedAddressPairsRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+// RemoveAllowedAddressPairsRequest removes pairs from the security group of interface. Pairs that are
+// not allowed are skipped.
+type RemoveAllowedAddressPairsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Uuid string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Interface           string                `protobuf:"bytes,1,opt,name=interface,proto3" json:"interface,omitempty"`
+	AllowedAddressPairs []*AllowedAddressPair `protobuf:"bytes,2,rep,name=allowed_address_pairs,json=allowedAddressPairs,proto3" json:"allowed_address_pairs,omitempty"`
+	RequestId           string                `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *GetVirtualMachineStateRequest) Reset() {
-	*x = GetVirtualMachineStateRequest{}
+func (x *RemoveAllowedAddressPairsRequest) Reset() {
+	*x = RemoveAllowedAddressPairsRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_agent_proto_msgTypes[14]
+		mi := &file_agent_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GetVirtualMachineStateRequest) String() string {
+func (x *RemoveAllowedAddressPairsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetVirtualMachineStateRequest) ProtoMessage() {}
+func (*RemoveAllowedAddressPairsRequest) ProtoMessage() {}
 
-func (x *GetVirtualMachineStateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_proto_msgTypes[14]
+func (x *RemoveAllowedAddressPairsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -969,66 +1467,45 @@ func (x *GetVirtualMachineStateRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetVirtualMachineStateRequest.ProtoReflect.Descriptor instead.
-func (*GetVirtualMachineStateRequest) Descriptor() ([]byte, []int) {
-	return file_agent_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use RemoveAllowedAddressPairsRequest.ProtoReflect.Descriptor instead.
+func (*RemoveAllowedAddressPairsRequest) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *GetVirtualMachineStateRequest) GetUuid() string {
+func (x *RemoveAllowedAddressPairsRequest) GetInterface() string {
 	if x != nil {
-		return x.Uuid
+		return x.Interface
 	}
 	return ""
 }
 
-type ListVirtualMachineStateRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-}
-
-func (x *ListVirtualMachineStateRequest) Reset() {
-	*x = ListVirtualMachineStateRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_agent_proto_msgTypes[15]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *RemoveAllowedAddressPairsRequest) GetAllowedAddressPairs() []*AllowedAddressPair {
+	if x != nil {
+		return x.AllowedAddressPairs
 	}
+	return nil
 }
 
-func (x *ListVirtualMachineStateRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*ListVirtualMachineStateRequest) ProtoMessage() {}
-
-func (x *ListVirtualMachineStateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_agent_proto_msgTypes[15]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *RemoveAllowedAddressPairsRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use ListVirtualMachineStateRequest.ProtoReflect.Descriptor instead.
-func (*ListVirtualMachineStateRequest) Descriptor() ([]byte, []int) {
-	return file_agent_proto_rawDescGZIP(), []int{15}
+	return ""
 }
 
-type DeleteBridgeRequest struct {
+// SetRemoteGroupMembersRequest replaces the members of the remote group, creating it if missing.
+type SetRemoteGroupMembersRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Name        string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	IpAddresses []string `protobuf:"bytes,2,rep,name=ip_addresses,json=ipAddresses,proto3" json:"ip_addresses,omitempty"`
+	RequestId   string   `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *DeleteBridgeRequest) Reset() {
-	*x = DeleteBridgeRequest{}
+func (x *SetRemoteGroupMembersRequest) Reset() {
+	*x = SetRemoteGroupMembersRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1036,13 +1513,13 @@ func (x *DeleteBridgeRequest) Reset() {
 	}
 }
 
-func (x *DeleteBridgeRequest) String() string {
+func (x *SetRemoteGroupMembersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteBridgeRequest) ProtoMessage() {}
+func (*SetRemoteGroupMembersRequest) ProtoMessage() {}
 
-func (x *DeleteBridgeRequest) ProtoReflect() protoreflect.Message {
+func (x *SetRemoteGroupMembersRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1054,29 +1531,43 @@ func (x *DeleteBridgeRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteBridgeRequest.ProtoReflect.Descriptor instead.
-func (*DeleteBridgeRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetRemoteGroupMembersRequest.ProtoReflect.Descriptor instead.
+func (*SetRemoteGroupMembersRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *DeleteBridgeRequest) GetName() string {
+func (x *SetRemoteGroupMembersRequest) GetName() string {
 	if x != nil {
 		return x.Name
 	}
 	return ""
 }
 
-type DeleteVLANInterfaceRequest struct {
+func (x *SetRemoteGroupMembersRequest) GetIpAddresses() []string {
+	if x != nil {
+		return x.IpAddresses
+	}
+	return nil
+}
+
+func (x *SetRemoteGroupMembersRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type DeleteRemoteGroupRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	VlanId          uint32 `protobuf:"varint,1,opt,name=vlan_id,json=vlanId,proto3" json:"vlan_id,omitempty"`
-	ParentInterface string `protobuf:"bytes,2,opt,name=parent_interface,json=parentInterface,proto3" json:"parent_interface,omitempty"`
+	Name      string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	RequestId string `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *DeleteVLANInterfaceRequest) Reset() {
-	*x = DeleteVLANInterfaceRequest{}
+func (x *DeleteRemoteGroupRequest) Reset() {
+	*x = DeleteRemoteGroupRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1084,13 +1575,13 @@ func (x *DeleteVLANInterfaceRequest) Reset() {
 	}
 }
 
-func (x *DeleteVLANInterfaceRequest) String() string {
+func (x *DeleteRemoteGroupRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteVLANInterfaceRequest) ProtoMessage() {}
+func (*DeleteRemoteGroupRequest) ProtoMessage() {}
 
-func (x *DeleteVLANInterfaceRequest) ProtoReflect() protoreflect.Message {
+func (x *DeleteRemoteGroupRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1102,36 +1593,33 @@ func (x *DeleteVLANInterfaceRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteVLANInterfaceRequest.ProtoReflect.Descriptor instead.
-func (*DeleteVLANInterfaceRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeleteRemoteGroupRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRemoteGroupRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *DeleteVLANInterfaceRequest) GetVlanId() uint32 {
+func (x *DeleteRemoteGroupRequest) GetName() string {
 	if x != nil {
-		return x.VlanId
+		return x.Name
 	}
-	return 0
+	return ""
 }
 
-func (x *DeleteVLANInterfaceRequest) GetParentInterface() string {
+func (x *DeleteRemoteGroupRequest) GetRequestId() string {
 	if x != nil {
-		return x.ParentInterface
+		return x.RequestId
 	}
 	return ""
 }
 
-type DeleteInterfaceFromBridgeRequest struct {
+type ListRemoteGroupsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	Bridge    string `protobuf:"bytes,1,opt,name=bridge,proto3" json:"bridge,omitempty"`
-	Interface string `protobuf:"bytes,2,opt,name=interface,proto3" json:"interface,omitempty"`
 }
 
-func (x *DeleteInterfaceFromBridgeRequest) Reset() {
-	*x = DeleteInterfaceFromBridgeRequest{}
+func (x *ListRemoteGroupsRequest) Reset() {
+	*x = ListRemoteGroupsRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1139,13 +1627,13 @@ func (x *DeleteInterfaceFromBridgeRequest) Reset() {
 	}
 }
 
-func (x *DeleteInterfaceFromBridgeRequest) String() string {
+func (x *ListRemoteGroupsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteInterfaceFromBridgeRequest) ProtoMessage() {}
+func (*ListRemoteGroupsRequest) ProtoMessage() {}
 
-func (x *DeleteInterfaceFromBridgeRequest) ProtoReflect() protoreflect.Message {
+func (x *ListRemoteGroupsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1157,35 +1645,19 @@ func (x *DeleteInterfaceFromBridgeRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteInterfaceFromBridgeRequest.ProtoReflect.Descriptor instead.
-func (*DeleteInterfaceFromBridgeRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListRemoteGroupsRequest.ProtoReflect.Descriptor instead.
+func (*ListRemoteGroupsRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *DeleteInterfaceFromBridgeRequest) GetBridge() string {
-	if x != nil {
-		return x.Bridge
-	}
-	return ""
-}
-
-func (x *DeleteInterfaceFromBridgeRequest) GetInterface() string {
-	if x != nil {
-		return x.Interface
-	}
-	return ""
-}
-
-type DeleteVirtualMachineRequest struct {
+type GetInterfaceNameRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	Uuid string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
 }
 
-func (x *DeleteVirtualMachineRequest) Reset() {
-	*x = DeleteVirtualMachineRequest{}
+func (x *GetInterfaceNameRequest) Reset() {
+	*x = GetInterfaceNameRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1193,13 +1665,13 @@ func (x *DeleteVirtualMachineRequest) Reset() {
 	}
 }
 
-func (x *DeleteVirtualMachineRequest) String() string {
+func (x *GetInterfaceNameRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteVirtualMachineRequest) ProtoMessage() {}
+func (*GetInterfaceNameRequest) ProtoMessage() {}
 
-func (x *DeleteVirtualMachineRequest) ProtoReflect() protoreflect.Message {
+func (x *GetInterfaceNameRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1211,29 +1683,24 @@ func (x *DeleteVirtualMachineRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteVirtualMachineRequest.ProtoReflect.Descriptor instead.
-func (*DeleteVirtualMachineRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetInterfaceNameRequest.ProtoReflect.Descriptor instead.
+func (*GetInterfaceNameRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *DeleteVirtualMachineRequest) GetUuid() string {
-	if x != nil {
-		return x.Uuid
-	}
-	return ""
-}
-
-type DisconnectBlockDeviceRequest struct {
+type AddBridgeRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	PortalAddresses []string `protobuf:"bytes,1,rep,name=portal_addresses,json=portalAddresses,proto3" json:"portal_addresses,omitempty"`
-	HostLunId       uint32   `protobuf:"varint,2,opt,name=host_lun_id,json=hostLunId,proto3" json:"host_lun_id,omitempty"`
+	Name         string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	MetadataCidr string `protobuf:"bytes,2,opt,name=metadata_cidr,json=metadataCidr,proto3" json:"metadata_cidr,omitempty"`
+	InternalOnly bool   `protobuf:"varint,3,opt,name=internal_only,json=internalOnly,proto3" json:"internal_only,omitempty"`
+	RequestId    string `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *DisconnectBlockDeviceRequest) Reset() {
-	*x = DisconnectBlockDeviceRequest{}
+func (x *AddBridgeRequest) Reset() {
+	*x = AddBridgeRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1241,13 +1708,13 @@ func (x *DisconnectBlockDeviceRequest) Reset() {
 	}
 }
 
-func (x *DisconnectBlockDeviceRequest) String() string {
+func (x *AddBridgeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DisconnectBlockDeviceRequest) ProtoMessage() {}
+func (*AddBridgeRequest) ProtoMessage() {}
 
-func (x *DisconnectBlockDeviceRequest) ProtoReflect() protoreflect.Message {
+func (x *AddBridgeRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1259,35 +1726,51 @@ func (x *DisconnectBlockDeviceRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DisconnectBlockDeviceRequest.ProtoReflect.Descriptor instead.
-func (*DisconnectBlockDeviceRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use AddBridgeRequest.ProtoReflect.Descriptor instead.
+func (*AddBridgeRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *DisconnectBlockDeviceRequest) GetPortalAddresses() []string {
+func (x *AddBridgeRequest) GetName() string {
 	if x != nil {
-		return x.PortalAddresses
+		return x.Name
 	}
-	return nil
+	return ""
 }
 
-func (x *DisconnectBlockDeviceRequest) GetHostLunId() uint32 {
+func (x *AddBridgeRequest) GetMetadataCidr() string {
 	if x != nil {
-		return x.HostLunId
+		return x.MetadataCidr
 	}
-	return 0
+	return ""
 }
 
-type StopVirtualMachineRequest struct {
+func (x *AddBridgeRequest) GetInternalOnly() bool {
+	if x != nil {
+		return x.InternalOnly
+	}
+	return false
+}
+
+func (x *AddBridgeRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type AddVLANInterfaceRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Uuid string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	VlanId          uint32 `protobuf:"varint,1,opt,name=vlan_id,json=vlanId,proto3" json:"vlan_id,omitempty"`
+	ParentInterface string `protobuf:"bytes,2,opt,name=parent_interface,json=parentInterface,proto3" json:"parent_interface,omitempty"`
+	RequestId       string `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *StopVirtualMachineRequest) Reset() {
-	*x = StopVirtualMachineRequest{}
+func (x *AddVLANInterfaceRequest) Reset() {
+	*x = AddVLANInterfaceRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1295,13 +1778,13 @@ func (x *StopVirtualMachineRequest) Reset() {
 	}
 }
 
-func (x *StopVirtualMachineRequest) String() string {
+func (x *AddVLANInterfaceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StopVirtualMachineRequest) ProtoMessage() {}
+func (*AddVLANInterfaceRequest) ProtoMessage() {}
 
-func (x *StopVirtualMachineRequest) ProtoReflect() protoreflect.Message {
+func (x *AddVLANInterfaceRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1313,30 +1796,44 @@ func (x *StopVirtualMachineRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StopVirtualMachineRequest.ProtoReflect.Descriptor instead.
-func (*StopVirtualMachineRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use AddVLANInterfaceRequest.ProtoReflect.Descriptor instead.
+func (*AddVLANInterfaceRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *StopVirtualMachineRequest) GetUuid() string {
+func (x *AddVLANInterfaceRequest) GetVlanId() uint32 {
 	if x != nil {
-		return x.Uuid
+		return x.VlanId
+	}
+	return 0
+}
+
+func (x *AddVLANInterfaceRequest) GetParentInterface() string {
+	if x != nil {
+		return x.ParentInterface
 	}
 	return ""
 }
 
-type DetachBlockDeviceRequest struct {
+func (x *AddVLANInterfaceRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type AddInterfaceToBridgeRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Uuid         string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
-	SourceDevice string `protobuf:"bytes,2,opt,name=source_device,json=sourceDevice,proto3" json:"source_device,omitempty"`
-	TargetDevice string `protobuf:"bytes,3,opt,name=target_device,json=targetDevice,proto3" json:"target_device,omitempty"`
+	Bridge    string `protobuf:"bytes,1,opt,name=bridge,proto3" json:"bridge,omitempty"`
+	Interface string `protobuf:"bytes,2,opt,name=interface,proto3" json:"interface,omitempty"`
+	RequestId string `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *DetachBlockDeviceRequest) Reset() {
-	*x = DetachBlockDeviceRequest{}
+func (x *AddInterfaceToBridgeRequest) Reset() {
+	*x = AddInterfaceToBridgeRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1344,13 +1841,13 @@ func (x *DetachBlockDeviceRequest) Reset() {
 	}
 }
 
-func (x *DetachBlockDeviceRequest) String() string {
+func (x *AddInterfaceToBridgeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DetachBlockDeviceRequest) ProtoMessage() {}
+func (*AddInterfaceToBridgeRequest) ProtoMessage() {}
 
-func (x *DetachBlockDeviceRequest) ProtoReflect() protoreflect.Message {
+func (x *AddInterfaceToBridgeRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1362,47 +1859,51 @@ func (x *DetachBlockDeviceRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DetachBlockDeviceRequest.ProtoReflect.Descriptor instead.
-func (*DetachBlockDeviceRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use AddInterfaceToBridgeRequest.ProtoReflect.Descriptor instead.
+func (*AddInterfaceToBridgeRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *DetachBlockDeviceRequest) GetUuid() string {
+func (x *AddInterfaceToBridgeRequest) GetBridge() string {
 	if x != nil {
-		return x.Uuid
+		return x.Bridge
 	}
 	return ""
 }
 
-func (x *DetachBlockDeviceRequest) GetSourceDevice() string {
+func (x *AddInterfaceToBridgeRequest) GetInterface() string {
 	if x != nil {
-		return x.SourceDevice
+		return x.Interface
 	}
 	return ""
 }
 
-func (x *DetachBlockDeviceRequest) GetTargetDevice() string {
+func (x *AddInterfaceToBridgeRequest) GetRequestId() string {
 	if x != nil {
-		return x.TargetDevice
+		return x.RequestId
 	}
 	return ""
 }
 
-type DetachInterfaceRequest struct {
+type AddVirtualMachineRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Uuid            string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
-	Bridge          string `protobuf:"bytes,2,opt,name=bridge,proto3" json:"bridge,omitempty"`
-	InboundAverage  uint32 `protobuf:"varint,3,opt,name=inbound_average,json=inboundAverage,proto3" json:"inbound_average,omitempty"`
-	OutboundAverage uint32 `protobuf:"varint,4,opt,name=outbound_average,json=outboundAverage,proto3" json:"outbound_average,omitempty"`
-	Name            string `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
-	MacAddress      string `protobuf:"bytes,6,opt,name=mac_address,json=macAddress,proto3" json:"mac_address,omitempty"`
+	Name             string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Vcpus            uint32 `protobuf:"varint,2,opt,name=vcpus,proto3" json:"vcpus,omitempty"`
+	MemoryKib        uint64 `protobuf:"varint,3,opt,name=memory_kib,json=memoryKib,proto3" json:"memory_kib,omitempty"`
+	BootDevice       string `protobuf:"bytes,4,opt,name=boot_device,json=bootDevice,proto3" json:"boot_device,omitempty"`
+	ReadBytesSec     uint32 `protobuf:"varint,5,opt,name=read_bytes_sec,json=readBytesSec,proto3" json:"read_bytes_sec,omitempty"`
+	WriteBytesSec    uint32 `protobuf:"varint,6,opt,name=write_bytes_sec,json=writeBytesSec,proto3" json:"write_bytes_sec,omitempty"`
+	ReadIopsSec      uint32 `protobuf:"varint,7,opt,name=read_iops_sec,json=readIopsSec,proto3" json:"read_iops_sec,omitempty"`
+	WriteIopsSec     uint32 `protobuf:"varint,8,opt,name=write_iops_sec,json=writeIopsSec,proto3" json:"write_iops_sec,omitempty"`
+	PinningGroupName string `protobuf:"bytes,9,opt,name=pinning_group_name,json=pinningGroupName,proto3" json:"pinning_group_name,omitempty"`
+	RequestId        string `protobuf:"bytes,10,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *DetachInterfaceRequest) Reset() {
-	*x = DetachInterfaceRequest{}
+func (x *AddVirtualMachineRequest) Reset() {
+	*x = AddVirtualMachineRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[23]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1410,13 +1911,13 @@ func (x *DetachInterfaceRequest) Reset() {
 	}
 }
 
-func (x *DetachInterfaceRequest) String() string {
+func (x *AddVirtualMachineRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DetachInterfaceRequest) ProtoMessage() {}
+func (*AddVirtualMachineRequest) ProtoMessage() {}
 
-func (x *DetachInterfaceRequest) ProtoReflect() protoreflect.Message {
+func (x *AddVirtualMachineRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[23]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1428,64 +1929,95 @@ func (x *DetachInterfaceRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DetachInterfaceRequest.ProtoReflect.Descriptor instead.
-func (*DetachInterfaceRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use AddVirtualMachineRequest.ProtoReflect.Descriptor instead.
+func (*AddVirtualMachineRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *DetachInterfaceRequest) GetUuid() string {
+func (x *AddVirtualMachineRequest) GetName() string {
 	if x != nil {
-		return x.Uuid
+		return x.Name
 	}
 	return ""
 }
 
-func (x *DetachInterfaceRequest) GetBridge() string {
+func (x *AddVirtualMachineRequest) GetVcpus() uint32 {
 	if x != nil {
-		return x.Bridge
+		return x.Vcpus
+	}
+	return 0
+}
+
+func (x *AddVirtualMachineRequest) GetMemoryKib() uint64 {
+	if x != nil {
+		return x.MemoryKib
+	}
+	return 0
+}
+
+func (x *AddVirtualMachineRequest) GetBootDevice() string {
+	if x != nil {
+		return x.BootDevice
 	}
 	return ""
 }
 
-func (x *DetachInterfaceRequest) GetInboundAverage() uint32 {
+func (x *AddVirtualMachineRequest) GetReadBytesSec() uint32 {
 	if x != nil {
-		return x.InboundAverage
+		return x.ReadBytesSec
 	}
 	return 0
 }
 
-func (x *DetachInterfaceRequest) GetOutboundAverage() uint32 {
+func (x *AddVirtualMachineRequest) GetWriteBytesSec() uint32 {
 	if x != nil {
-		return x.OutboundAverage
+		return x.WriteBytesSec
 	}
 	return 0
 }
 
-func (x *DetachInterfaceRequest) GetName() string {
+func (x *AddVirtualMachineRequest) GetReadIopsSec() uint32 {
 	if x != nil {
-		return x.Name
+		return x.ReadIopsSec
+	}
+	return 0
+}
+
+func (x *AddVirtualMachineRequest) GetWriteIopsSec() uint32 {
+	if x != nil {
+		return x.WriteIopsSec
+	}
+	return 0
+}
+
+func (x *AddVirtualMachineRequest) GetPinningGroupName() string {
+	if x != nil {
+		return x.PinningGroupName
 	}
 	return ""
 }
 
-func (x *DetachInterfaceRequest) GetMacAddress() string {
+func (x *AddVirtualMachineRequest) GetRequestId() string {
 	if x != nil {
-		return x.MacAddress
+		return x.RequestId
 	}
 	return ""
 }
 
-// response
-type GetISCSIQualifiedNameResponse struct {
+type ConnectBlockDeviceRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Iqn string `protobuf:"bytes,1,opt,name=iqn,proto3" json:"iqn,omitempty"`
+	PortalAddresses []string `protobuf:"bytes,1,rep,name=portal_addresses,json=portalAddresses,proto3" json:"portal_addresses,omitempty"`
+	HostLunId       uint32   `protobuf:"varint,2,opt,name=host_lun_id,json=hostLunId,proto3" json:"host_lun_id,omitempty"`
+	// return an operation id immediately instead of waiting for the result
+	Async     bool   `protobuf:"varint,3,opt,name=async,proto3" json:"async,omitempty"`
+	RequestId string `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *GetISCSIQualifiedNameResponse) Reset() {
-	*x = GetISCSIQualifiedNameResponse{}
+func (x *ConnectBlockDeviceRequest) Reset() {
+	*x = ConnectBlockDeviceRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[24]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1493,13 +2025,13 @@ func (x *GetISCSIQualifiedNameResponse) Reset() {
 	}
 }
 
-func (x *GetISCSIQualifiedNameResponse) String() string {
+func (x *ConnectBlockDeviceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetISCSIQualifiedNameResponse) ProtoMessage() {}
+func (*ConnectBlockDeviceRequest) ProtoMessage() {}
 
-func (x *GetISCSIQualifiedNameResponse) ProtoReflect() protoreflect.Message {
+func (x *ConnectBlockDeviceRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[24]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1511,26 +2043,56 @@ func (x *GetISCSIQualifiedNameResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetISCSIQualifiedNameResponse.ProtoReflect.Descriptor instead.
-func (*GetISCSIQualifiedNameResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ConnectBlockDeviceRequest.ProtoReflect.Descriptor instead.
+func (*ConnectBlockDeviceRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *GetISCSIQualifiedNameResponse) GetIqn() string {
+func (x *ConnectBlockDeviceRequest) GetPortalAddresses() []string {
 	if x != nil {
-		return x.Iqn
+		return x.PortalAddresses
+	}
+	return nil
+}
+
+func (x *ConnectBlockDeviceRequest) GetHostLunId() uint32 {
+	if x != nil {
+		return x.HostLunId
+	}
+	return 0
+}
+
+func (x *ConnectBlockDeviceRequest) GetAsync() bool {
+	if x != nil {
+		return x.Async
+	}
+	return false
+}
+
+func (x *ConnectBlockDeviceRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
 	}
 	return ""
 }
 
-type GetIPTablesResponse struct {
+type AttachBlockDeviceRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Uuid          string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	SourceDevice  string `protobuf:"bytes,2,opt,name=source_device,json=sourceDevice,proto3" json:"source_device,omitempty"`
+	TargetDevice  string `protobuf:"bytes,3,opt,name=target_device,json=targetDevice,proto3" json:"target_device,omitempty"`
+	ReadBytesSec  uint32 `protobuf:"varint,4,opt,name=read_bytes_sec,json=readBytesSec,proto3" json:"read_bytes_sec,omitempty"`
+	WriteBytesSec uint32 `protobuf:"varint,5,opt,name=write_bytes_sec,json=writeBytesSec,proto3" json:"write_bytes_sec,omitempty"`
+	ReadIopsSec   uint32 `protobuf:"varint,6,opt,name=read_iops_sec,json=readIopsSec,proto3" json:"read_iops_sec,omitempty"`
+	WriteIopsSec  uint32 `protobuf:"varint,7,opt,name=write_iops_sec,json=writeIopsSec,proto3" json:"write_iops_sec,omitempty"`
+	RequestId     string `protobuf:"bytes,8,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *GetIPTablesResponse) Reset() {
-	*x = GetIPTablesResponse{}
+func (x *AttachBlockDeviceRequest) Reset() {
+	*x = AttachBlockDeviceRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[25]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1538,13 +2100,13 @@ func (x *GetIPTablesResponse) Reset() {
 	}
 }
 
-func (x *GetIPTablesResponse) String() string {
+func (x *AttachBlockDeviceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetIPTablesResponse) ProtoMessage() {}
+func (*AttachBlockDeviceRequest) ProtoMessage() {}
 
-func (x *GetIPTablesResponse) ProtoReflect() protoreflect.Message {
+func (x *AttachBlockDeviceRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[25]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1556,19 +2118,83 @@ func (x *GetIPTablesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetIPTablesResponse.ProtoReflect.Descriptor instead.
-func (*GetIPTablesResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use AttachBlockDeviceRequest.ProtoReflect.Descriptor instead.
+func (*AttachBlockDeviceRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{25}
 }
 
-type SetupDefaultSecurityGroupResponse struct {
+func (x *AttachBlockDeviceRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *AttachBlockDeviceRequest) GetSourceDevice() string {
+	if x != nil {
+		return x.SourceDevice
+	}
+	return ""
+}
+
+func (x *AttachBlockDeviceRequest) GetTargetDevice() string {
+	if x != nil {
+		return x.TargetDevice
+	}
+	return ""
+}
+
+func (x *AttachBlockDeviceRequest) GetReadBytesSec() uint32 {
+	if x != nil {
+		return x.ReadBytesSec
+	}
+	return 0
+}
+
+func (x *AttachBlockDeviceRequest) GetWriteBytesSec() uint32 {
+	if x != nil {
+		return x.WriteBytesSec
+	}
+	return 0
+}
+
+func (x *AttachBlockDeviceRequest) GetReadIopsSec() uint32 {
+	if x != nil {
+		return x.ReadIopsSec
+	}
+	return 0
+}
+
+func (x *AttachBlockDeviceRequest) GetWriteIopsSec() uint32 {
+	if x != nil {
+		return x.WriteIopsSec
+	}
+	return 0
+}
+
+func (x *AttachBlockDeviceRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type AttachInterfaceRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Uuid            string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Bridge          string `protobuf:"bytes,2,opt,name=bridge,proto3" json:"bridge,omitempty"`
+	InboundAverage  uint32 `protobuf:"varint,3,opt,name=inbound_average,json=inboundAverage,proto3" json:"inbound_average,omitempty"`
+	OutboundAverage uint32 `protobuf:"varint,4,opt,name=outbound_average,json=outboundAverage,proto3" json:"outbound_average,omitempty"`
+	Name            string `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	MacAddress      string `protobuf:"bytes,6,opt,name=mac_address,json=macAddress,proto3" json:"mac_address,omitempty"`
+	RequestId       string `protobuf:"bytes,7,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *SetupDefaultSecurityGroupResponse) Reset() {
-	*x = SetupDefaultSecurityGroupResponse{}
+func (x *AttachInterfaceRequest) Reset() {
+	*x = AttachInterfaceRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[26]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1576,13 +2202,13 @@ func (x *SetupDefaultSecurityGroupResponse) Reset() {
 	}
 }
 
-func (x *SetupDefaultSecurityGroupResponse) String() string {
+func (x *AttachInterfaceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetupDefaultSecurityGroupResponse) ProtoMessage() {}
+func (*AttachInterfaceRequest) ProtoMessage() {}
 
-func (x *SetupDefaultSecurityGroupResponse) ProtoReflect() protoreflect.Message {
+func (x *AttachInterfaceRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[26]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1594,19 +2220,71 @@ func (x *SetupDefaultSecurityGroupResponse) ProtoReflect() protoreflect.Message
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetupDefaultSecurityGroupResponse.ProtoReflect.Descriptor instead.
-func (*SetupDefaultSecurityGroupResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use AttachInterfaceRequest.ProtoReflect.Descriptor instead.
+func (*AttachInterfaceRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{26}
 }
 
-type AddSecurityGroupResponse struct {
+func (x *AttachInterfaceRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *AttachInterfaceRequest) GetBridge() string {
+	if x != nil {
+		return x.Bridge
+	}
+	return ""
+}
+
+func (x *AttachInterfaceRequest) GetInboundAverage() uint32 {
+	if x != nil {
+		return x.InboundAverage
+	}
+	return 0
+}
+
+func (x *AttachInterfaceRequest) GetOutboundAverage() uint32 {
+	if x != nil {
+		return x.OutboundAverage
+	}
+	return 0
+}
+
+func (x *AttachInterfaceRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AttachInterfaceRequest) GetMacAddress() string {
+	if x != nil {
+		return x.MacAddress
+	}
+	return ""
+}
+
+func (x *AttachInterfaceRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type StartVirtualMachineRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Uuid      string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	RequestId string `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *AddSecurityGroupResponse) Reset() {
-	*x = AddSecurityGroupResponse{}
+func (x *StartVirtualMachineRequest) Reset() {
+	*x = StartVirtualMachineRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[27]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1614,13 +2292,13 @@ func (x *AddSecurityGroupResponse) Reset() {
 	}
 }
 
-func (x *AddSecurityGroupResponse) String() string {
+func (x *StartVirtualMachineRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddSecurityGroupResponse) ProtoMessage() {}
+func (*StartVirtualMachineRequest) ProtoMessage() {}
 
-func (x *AddSecurityGroupResponse) ProtoReflect() protoreflect.Message {
+func (x *StartVirtualMachineRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[27]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1632,21 +2310,35 @@ func (x *AddSecurityGroupResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddSecurityGroupResponse.ProtoReflect.Descriptor instead.
-func (*AddSecurityGroupResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use StartVirtualMachineRequest.ProtoReflect.Descriptor instead.
+func (*StartVirtualMachineRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{27}
 }
 
-type GetInterfaceNameResponse struct {
+func (x *StartVirtualMachineRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *StartVirtualMachineRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type GetVirtualMachineStateRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	InterfaceName string `protobuf:"bytes,1,opt,name=interface_name,json=interfaceName,proto3" json:"interface_name,omitempty"`
+	Uuid string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
 }
 
-func (x *GetInterfaceNameResponse) Reset() {
-	*x = GetInterfaceNameResponse{}
+func (x *GetVirtualMachineStateRequest) Reset() {
+	*x = GetVirtualMachineStateRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[28]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1654,13 +2346,13 @@ func (x *GetInterfaceNameResponse) Reset() {
 	}
 }
 
-func (x *GetInterfaceNameResponse) String() string {
+func (x *GetVirtualMachineStateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetInterfaceNameResponse) ProtoMessage() {}
+func (*GetVirtualMachineStateRequest) ProtoMessage() {}
 
-func (x *GetInterfaceNameResponse) ProtoReflect() protoreflect.Message {
+func (x *GetVirtualMachineStateRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[28]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1672,26 +2364,26 @@ func (x *GetInterfaceNameResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetInterfaceNameResponse.ProtoReflect.Descriptor instead.
-func (*GetInterfaceNameResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetVirtualMachineStateRequest.ProtoReflect.Descriptor instead.
+func (*GetVirtualMachineStateRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *GetInterfaceNameResponse) GetInterfaceName() string {
+func (x *GetVirtualMachineStateRequest) GetUuid() string {
 	if x != nil {
-		return x.InterfaceName
+		return x.Uuid
 	}
 	return ""
 }
 
-type AddBridgeResponse struct {
+type ListVirtualMachineStateRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 }
 
-func (x *AddBridgeResponse) Reset() {
-	*x = AddBridgeResponse{}
+func (x *ListVirtualMachineStateRequest) Reset() {
+	*x = ListVirtualMachineStateRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[29]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1699,13 +2391,13 @@ func (x *AddBridgeResponse) Reset() {
 	}
 }
 
-func (x *AddBridgeResponse) String() string {
+func (x *ListVirtualMachineStateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddBridgeResponse) ProtoMessage() {}
+func (*ListVirtualMachineStateRequest) ProtoMessage() {}
 
-func (x *AddBridgeResponse) ProtoReflect() protoreflect.Message {
+func (x *ListVirtualMachineStateRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[29]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1717,19 +2409,22 @@ func (x *AddBridgeResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddBridgeResponse.ProtoReflect.Descriptor instead.
-func (*AddBridgeResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListVirtualMachineStateRequest.ProtoReflect.Descriptor instead.
+func (*ListVirtualMachineStateRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{29}
 }
 
-type AddVLANInterfaceResponse struct {
+type DeleteBridgeRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Name      string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	RequestId string `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *AddVLANInterfaceResponse) Reset() {
-	*x = AddVLANInterfaceResponse{}
+func (x *DeleteBridgeRequest) Reset() {
+	*x = DeleteBridgeRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[30]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1737,13 +2432,13 @@ func (x *AddVLANInterfaceResponse) Reset() {
 	}
 }
 
-func (x *AddVLANInterfaceResponse) String() string {
+func (x *DeleteBridgeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddVLANInterfaceResponse) ProtoMessage() {}
+func (*DeleteBridgeRequest) ProtoMessage() {}
 
-func (x *AddVLANInterfaceResponse) ProtoReflect() protoreflect.Message {
+func (x *DeleteBridgeRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[30]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1755,19 +2450,37 @@ func (x *AddVLANInterfaceResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddVLANInterfaceResponse.ProtoReflect.Descriptor instead.
-func (*AddVLANInterfaceResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeleteBridgeRequest.ProtoReflect.Descriptor instead.
+func (*DeleteBridgeRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{30}
 }
 
-type AddInterfaceToBridgeResponse struct {
+func (x *DeleteBridgeRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DeleteBridgeRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type DeleteVLANInterfaceRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	VlanId          uint32 `protobuf:"varint,1,opt,name=vlan_id,json=vlanId,proto3" json:"vlan_id,omitempty"`
+	ParentInterface string `protobuf:"bytes,2,opt,name=parent_interface,json=parentInterface,proto3" json:"parent_interface,omitempty"`
+	RequestId       string `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *AddInterfaceToBridgeResponse) Reset() {
-	*x = AddInterfaceToBridgeResponse{}
+func (x *DeleteVLANInterfaceRequest) Reset() {
+	*x = DeleteVLANInterfaceRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[31]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1775,13 +2488,13 @@ func (x *AddInterfaceToBridgeResponse) Reset() {
 	}
 }
 
-func (x *AddInterfaceToBridgeResponse) String() string {
+func (x *DeleteVLANInterfaceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddInterfaceToBridgeResponse) ProtoMessage() {}
+func (*DeleteVLANInterfaceRequest) ProtoMessage() {}
 
-func (x *AddInterfaceToBridgeResponse) ProtoReflect() protoreflect.Message {
+func (x *DeleteVLANInterfaceRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[31]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1793,22 +2506,44 @@ func (x *AddInterfaceToBridgeResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddInterfaceToBridgeResponse.ProtoReflect.Descriptor instead.
-func (*AddInterfaceToBridgeResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeleteVLANInterfaceRequest.ProtoReflect.Descriptor instead.
+func (*DeleteVLANInterfaceRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{31}
 }
 
-type AddVirtualMachineResponse struct {
+func (x *DeleteVLANInterfaceRequest) GetVlanId() uint32 {
+	if x != nil {
+		return x.VlanId
+	}
+	return 0
+}
+
+func (x *DeleteVLANInterfaceRequest) GetParentInterface() string {
+	if x != nil {
+		return x.ParentInterface
+	}
+	return ""
+}
+
+func (x *DeleteVLANInterfaceRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type DeleteInterfaceFromBridgeRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Uuid string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
-	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Bridge    string `protobuf:"bytes,1,opt,name=bridge,proto3" json:"bridge,omitempty"`
+	Interface string `protobuf:"bytes,2,opt,name=interface,proto3" json:"interface,omitempty"`
+	RequestId string `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *AddVirtualMachineResponse) Reset() {
-	*x = AddVirtualMachineResponse{}
+func (x *DeleteInterfaceFromBridgeRequest) Reset() {
+	*x = DeleteInterfaceFromBridgeRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1816,13 +2551,13 @@ func (x *AddVirtualMachineResponse) Reset() {
 	}
 }
 
-func (x *AddVirtualMachineResponse) String() string {
+func (x *DeleteInterfaceFromBridgeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddVirtualMachineResponse) ProtoMessage() {}
+func (*DeleteInterfaceFromBridgeRequest) ProtoMessage() {}
 
-func (x *AddVirtualMachineResponse) ProtoReflect() protoreflect.Message {
+func (x *DeleteInterfaceFromBridgeRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1834,35 +2569,43 @@ func (x *AddVirtualMachineResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddVirtualMachineResponse.ProtoReflect.Descriptor instead.
-func (*AddVirtualMachineResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeleteInterfaceFromBridgeRequest.ProtoReflect.Descriptor instead.
+func (*DeleteInterfaceFromBridgeRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *AddVirtualMachineResponse) GetUuid() string {
+func (x *DeleteInterfaceFromBridgeRequest) GetBridge() string {
 	if x != nil {
-		return x.Uuid
+		return x.Bridge
 	}
 	return ""
 }
 
-func (x *AddVirtualMachineResponse) GetName() string {
+func (x *DeleteInterfaceFromBridgeRequest) GetInterface() string {
 	if x != nil {
-		return x.Name
+		return x.Interface
 	}
 	return ""
 }
 
-type ConnectBlockDeviceResponse struct {
+func (x *DeleteInterfaceFromBridgeRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type DeleteVirtualMachineRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	DeviceName string `protobuf:"bytes,1,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
+	Uuid      string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	RequestId string `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *ConnectBlockDeviceResponse) Reset() {
-	*x = ConnectBlockDeviceResponse{}
+func (x *DeleteVirtualMachineRequest) Reset() {
+	*x = DeleteVirtualMachineRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[33]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1870,13 +2613,13 @@ func (x *ConnectBlockDeviceResponse) Reset() {
 	}
 }
 
-func (x *ConnectBlockDeviceResponse) String() string {
+func (x *DeleteVirtualMachineRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ConnectBlockDeviceResponse) ProtoMessage() {}
+func (*DeleteVirtualMachineRequest) ProtoMessage() {}
 
-func (x *ConnectBlockDeviceResponse) ProtoReflect() protoreflect.Message {
+func (x *DeleteVirtualMachineRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[33]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1888,29 +2631,39 @@ func (x *ConnectBlockDeviceResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ConnectBlockDeviceResponse.ProtoReflect.Descriptor instead.
-func (*ConnectBlockDeviceResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeleteVirtualMachineRequest.ProtoReflect.Descriptor instead.
+func (*DeleteVirtualMachineRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *ConnectBlockDeviceResponse) GetDeviceName() string {
+func (x *DeleteVirtualMachineRequest) GetUuid() string {
 	if x != nil {
-		return x.DeviceName
+		return x.Uuid
 	}
 	return ""
 }
 
-type StartVirtualMachineResponse struct {
+func (x *DeleteVirtualMachineRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type DisconnectBlockDeviceRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Uuid string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
-	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	PortalAddresses []string `protobuf:"bytes,1,rep,name=portal_addresses,json=portalAddresses,proto3" json:"portal_addresses,omitempty"`
+	HostLunId       uint32   `protobuf:"varint,2,opt,name=host_lun_id,json=hostLunId,proto3" json:"host_lun_id,omitempty"`
+	// return an operation id immediately instead of waiting for the result
+	Async     bool   `protobuf:"varint,3,opt,name=async,proto3" json:"async,omitempty"`
+	RequestId string `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *StartVirtualMachineResponse) Reset() {
-	*x = StartVirtualMachineResponse{}
+func (x *DisconnectBlockDeviceRequest) Reset() {
+	*x = DisconnectBlockDeviceRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[34]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1918,13 +2671,13 @@ func (x *StartVirtualMachineResponse) Reset() {
 	}
 }
 
-func (x *StartVirtualMachineResponse) String() string {
+func (x *DisconnectBlockDeviceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StartVirtualMachineResponse) ProtoMessage() {}
+func (*DisconnectBlockDeviceRequest) ProtoMessage() {}
 
-func (x *StartVirtualMachineResponse) ProtoReflect() protoreflect.Message {
+func (x *DisconnectBlockDeviceRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[34]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1936,35 +2689,50 @@ func (x *StartVirtualMachineResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StartVirtualMachineResponse.ProtoReflect.Descriptor instead.
-func (*StartVirtualMachineResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use DisconnectBlockDeviceRequest.ProtoReflect.Descriptor instead.
+func (*DisconnectBlockDeviceRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{34}
 }
 
-func (x *StartVirtualMachineResponse) GetUuid() string {
+func (x *DisconnectBlockDeviceRequest) GetPortalAddresses() []string {
 	if x != nil {
-		return x.Uuid
+		return x.PortalAddresses
 	}
-	return ""
+	return nil
 }
 
-func (x *StartVirtualMachineResponse) GetName() string {
+func (x *DisconnectBlockDeviceRequest) GetHostLunId() uint32 {
 	if x != nil {
-		return x.Name
+		return x.HostLunId
+	}
+	return 0
+}
+
+func (x *DisconnectBlockDeviceRequest) GetAsync() bool {
+	if x != nil {
+		return x.Async
+	}
+	return false
+}
+
+func (x *DisconnectBlockDeviceRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
 	}
 	return ""
 }
 
-type GetVirtualMachineStateResponse struct {
+type StopVirtualMachineRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	State *VirtualMachineState `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Uuid      string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	RequestId string `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *GetVirtualMachineStateResponse) Reset() {
-	*x = GetVirtualMachineStateResponse{}
+func (x *StopVirtualMachineRequest) Reset() {
+	*x = StopVirtualMachineRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[35]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1972,13 +2740,13 @@ func (x *GetVirtualMachineStateResponse) Reset() {
 	}
 }
 
-func (x *GetVirtualMachineStateResponse) String() string {
+func (x *StopVirtualMachineRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetVirtualMachineStateResponse) ProtoMessage() {}
+func (*StopVirtualMachineRequest) ProtoMessage() {}
 
-func (x *GetVirtualMachineStateResponse) ProtoReflect() protoreflect.Message {
+func (x *StopVirtualMachineRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[35]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1990,28 +2758,38 @@ func (x *GetVirtualMachineStateResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetVirtualMachineStateResponse.ProtoReflect.Descriptor instead.
-func (*GetVirtualMachineStateResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use StopVirtualMachineRequest.ProtoReflect.Descriptor instead.
+func (*StopVirtualMachineRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{35}
 }
 
-func (x *GetVirtualMachineStateResponse) GetState() *VirtualMachineState {
+func (x *StopVirtualMachineRequest) GetUuid() string {
 	if x != nil {
-		return x.State
+		return x.Uuid
 	}
-	return nil
+	return ""
 }
 
-type ListVirtualMachineStateResponse struct {
+func (x *StopVirtualMachineRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type DetachBlockDeviceRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	States []*VirtualMachineState `protobuf:"bytes,1,rep,name=states,proto3" json:"states,omitempty"`
+	Uuid         string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	SourceDevice string `protobuf:"bytes,2,opt,name=source_device,json=sourceDevice,proto3" json:"source_device,omitempty"`
+	TargetDevice string `protobuf:"bytes,3,opt,name=target_device,json=targetDevice,proto3" json:"target_device,omitempty"`
+	RequestId    string `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *ListVirtualMachineStateResponse) Reset() {
-	*x = ListVirtualMachineStateResponse{}
+func (x *DetachBlockDeviceRequest) Reset() {
+	*x = DetachBlockDeviceRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[36]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2019,13 +2797,13 @@ func (x *ListVirtualMachineStateResponse) Reset() {
 	}
 }
 
-func (x *ListVirtualMachineStateResponse) String() string {
+func (x *DetachBlockDeviceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListVirtualMachineStateResponse) ProtoMessage() {}
+func (*DetachBlockDeviceRequest) ProtoMessage() {}
 
-func (x *ListVirtualMachineStateResponse) ProtoReflect() protoreflect.Message {
+func (x *DetachBlockDeviceRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[36]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2037,29 +2815,55 @@ func (x *ListVirtualMachineStateResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListVirtualMachineStateResponse.ProtoReflect.Descriptor instead.
-func (*ListVirtualMachineStateResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use DetachBlockDeviceRequest.ProtoReflect.Descriptor instead.
+func (*DetachBlockDeviceRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{36}
 }
 
-func (x *ListVirtualMachineStateResponse) GetStates() []*VirtualMachineState {
+func (x *DetachBlockDeviceRequest) GetUuid() string {
 	if x != nil {
-		return x.States
+		return x.Uuid
 	}
-	return nil
+	return ""
 }
 
-type AttachBlockDeviceResponse struct {
+func (x *DetachBlockDeviceRequest) GetSourceDevice() string {
+	if x != nil {
+		return x.SourceDevice
+	}
+	return ""
+}
+
+func (x *DetachBlockDeviceRequest) GetTargetDevice() string {
+	if x != nil {
+		return x.TargetDevice
+	}
+	return ""
+}
+
+func (x *DetachBlockDeviceRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type DetachInterfaceRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Uuid string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
-	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Uuid            string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Bridge          string `protobuf:"bytes,2,opt,name=bridge,proto3" json:"bridge,omitempty"`
+	InboundAverage  uint32 `protobuf:"varint,3,opt,name=inbound_average,json=inboundAverage,proto3" json:"inbound_average,omitempty"`
+	OutboundAverage uint32 `protobuf:"varint,4,opt,name=outbound_average,json=outboundAverage,proto3" json:"outbound_average,omitempty"`
+	Name            string `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	MacAddress      string `protobuf:"bytes,6,opt,name=mac_address,json=macAddress,proto3" json:"mac_address,omitempty"`
+	RequestId       string `protobuf:"bytes,7,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *AttachBlockDeviceResponse) Reset() {
-	*x = AttachBlockDeviceResponse{}
+func (x *DetachInterfaceRequest) Reset() {
+	*x = DetachInterfaceRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[37]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2067,13 +2871,13 @@ func (x *AttachBlockDeviceResponse) Reset() {
 	}
 }
 
-func (x *AttachBlockDeviceResponse) String() string {
+func (x *DetachInterfaceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AttachBlockDeviceResponse) ProtoMessage() {}
+func (*DetachInterfaceRequest) ProtoMessage() {}
 
-func (x *AttachBlockDeviceResponse) ProtoReflect() protoreflect.Message {
+func (x *DetachInterfaceRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[37]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2085,36 +2889,70 @@ func (x *AttachBlockDeviceResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AttachBlockDeviceResponse.ProtoReflect.Descriptor instead.
-func (*AttachBlockDeviceResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use DetachInterfaceRequest.ProtoReflect.Descriptor instead.
+func (*DetachInterfaceRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *AttachBlockDeviceResponse) GetUuid() string {
+func (x *DetachInterfaceRequest) GetUuid() string {
 	if x != nil {
 		return x.Uuid
 	}
 	return ""
 }
 
-func (x *AttachBlockDeviceResponse) GetName() string {
+func (x *DetachInterfaceRequest) GetBridge() string {
 	if x != nil {
-		return x.Name
+		return x.Bridge
 	}
 	return ""
 }
 
-type AttachInterfaceResponse struct {
+func (x *DetachInterfaceRequest) GetInboundAverage() uint32 {
+	if x != nil {
+		return x.InboundAverage
+	}
+	return 0
+}
+
+func (x *DetachInterfaceRequest) GetOutboundAverage() uint32 {
+	if x != nil {
+		return x.OutboundAverage
+	}
+	return 0
+}
+
+func (x *DetachInterfaceRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DetachInterfaceRequest) GetMacAddress() string {
+	if x != nil {
+		return x.MacAddress
+	}
+	return ""
+}
+
+func (x *DetachInterfaceRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type GetOperationRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Uuid string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
-	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 }
 
-func (x *AttachInterfaceResponse) Reset() {
-	*x = AttachInterfaceResponse{}
+func (x *GetOperationRequest) Reset() {
+	*x = GetOperationRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[38]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2122,13 +2960,13 @@ func (x *AttachInterfaceResponse) Reset() {
 	}
 }
 
-func (x *AttachInterfaceResponse) String() string {
+func (x *GetOperationRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AttachInterfaceResponse) ProtoMessage() {}
+func (*GetOperationRequest) ProtoMessage() {}
 
-func (x *AttachInterfaceResponse) ProtoReflect() protoreflect.Message {
+func (x *GetOperationRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[38]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2140,33 +2978,29 @@ func (x *AttachInterfaceResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AttachInterfaceResponse.ProtoReflect.Descriptor instead.
-func (*AttachInterfaceResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetOperationRequest.ProtoReflect.Descriptor instead.
+func (*GetOperationRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *AttachInterfaceResponse) GetUuid() string {
-	if x != nil {
-		return x.Uuid
-	}
-	return ""
-}
-
-func (x *AttachInterfaceResponse) GetName() string {
+func (x *GetOperationRequest) GetId() string {
 	if x != nil {
-		return x.Name
+		return x.Id
 	}
 	return ""
 }
 
-type DeleteBridgeResponse struct {
+type ListOperationsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// full method name like /agent.Agent/ConnectBlockDevice, or all if empty
+	Method string `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
 }
 
-func (x *DeleteBridgeResponse) Reset() {
-	*x = DeleteBridgeResponse{}
+func (x *ListOperationsRequest) Reset() {
+	*x = ListOperationsRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[39]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2174,13 +3008,13 @@ func (x *DeleteBridgeResponse) Reset() {
 	}
 }
 
-func (x *DeleteBridgeResponse) String() string {
+func (x *ListOperationsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteBridgeResponse) ProtoMessage() {}
+func (*ListOperationsRequest) ProtoMessage() {}
 
-func (x *DeleteBridgeResponse) ProtoReflect() protoreflect.Message {
+func (x *ListOperationsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[39]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2192,19 +3026,29 @@ func (x *DeleteBridgeResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteBridgeResponse.ProtoReflect.Descriptor instead.
-func (*DeleteBridgeResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListOperationsRequest.ProtoReflect.Descriptor instead.
+func (*ListOperationsRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{39}
 }
 
-type DeleteVLANInterfaceResponse struct {
+func (x *ListOperationsRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+type CancelOperationRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Id        string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RequestId string `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *DeleteVLANInterfaceResponse) Reset() {
-	*x = DeleteVLANInterfaceResponse{}
+func (x *CancelOperationRequest) Reset() {
+	*x = CancelOperationRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[40]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2212,13 +3056,13 @@ func (x *DeleteVLANInterfaceResponse) Reset() {
 	}
 }
 
-func (x *DeleteVLANInterfaceResponse) String() string {
+func (x *CancelOperationRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteVLANInterfaceResponse) ProtoMessage() {}
+func (*CancelOperationRequest) ProtoMessage() {}
 
-func (x *DeleteVLANInterfaceResponse) ProtoReflect() protoreflect.Message {
+func (x *CancelOperationRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[40]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2230,19 +3074,37 @@ func (x *DeleteVLANInterfaceResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteVLANInterfaceResponse.ProtoReflect.Descriptor instead.
-func (*DeleteVLANInterfaceResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use CancelOperationRequest.ProtoReflect.Descriptor instead.
+func (*CancelOperationRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{40}
 }
 
-type DeleteInterfaceFromBridgeResponse struct {
+func (x *CancelOperationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CancelOperationRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type WaitOperationRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// wait until the request deadline if 0
+	TimeoutSeconds uint32 `protobuf:"varint,2,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`
 }
 
-func (x *DeleteInterfaceFromBridgeResponse) Reset() {
-	*x = DeleteInterfaceFromBridgeResponse{}
+func (x *WaitOperationRequest) Reset() {
+	*x = WaitOperationRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[41]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2250,13 +3112,13 @@ func (x *DeleteInterfaceFromBridgeResponse) Reset() {
 	}
 }
 
-func (x *DeleteInterfaceFromBridgeResponse) String() string {
+func (x *WaitOperationRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteInterfaceFromBridgeResponse) ProtoMessage() {}
+func (*WaitOperationRequest) ProtoMessage() {}
 
-func (x *DeleteInterfaceFromBridgeResponse) ProtoReflect() protoreflect.Message {
+func (x *WaitOperationRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[41]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2268,19 +3130,37 @@ func (x *DeleteInterfaceFromBridgeResponse) ProtoReflect() protoreflect.Message
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteInterfaceFromBridgeResponse.ProtoReflect.Descriptor instead.
-func (*DeleteInterfaceFromBridgeResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use WaitOperationRequest.ProtoReflect.Descriptor instead.
+func (*WaitOperationRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{41}
 }
 
-type DeleteVirtualMachineResponse struct {
+func (x *WaitOperationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *WaitOperationRequest) GetTimeoutSeconds() uint32 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+type RunPreflightChecksRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// apply the safe fixes of failed checks, e.g. loading kernel modules
+	Fix       bool   `protobuf:"varint,1,opt,name=fix,proto3" json:"fix,omitempty"`
+	RequestId string `protobuf:"bytes,2,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 }
 
-func (x *DeleteVirtualMachineResponse) Reset() {
-	*x = DeleteVirtualMachineResponse{}
+func (x *RunPreflightChecksRequest) Reset() {
+	*x = RunPreflightChecksRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[42]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2288,13 +3168,13 @@ func (x *DeleteVirtualMachineResponse) Reset() {
 	}
 }
 
-func (x *DeleteVirtualMachineResponse) String() string {
+func (x *RunPreflightChecksRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteVirtualMachineResponse) ProtoMessage() {}
+func (*RunPreflightChecksRequest) ProtoMessage() {}
 
-func (x *DeleteVirtualMachineResponse) ProtoReflect() protoreflect.Message {
+func (x *RunPreflightChecksRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[42]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2306,19 +3186,36 @@ func (x *DeleteVirtualMachineResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteVirtualMachineResponse.ProtoReflect.Descriptor instead.
-func (*DeleteVirtualMachineResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use RunPreflightChecksRequest.ProtoReflect.Descriptor instead.
+func (*RunPreflightChecksRequest) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{42}
 }
 
-type DisconnectBlockDeviceResponse struct {
+func (x *RunPreflightChecksRequest) GetFix() bool {
+	if x != nil {
+		return x.Fix
+	}
+	return false
+}
+
+func (x *RunPreflightChecksRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+// response
+type GetISCSIQualifiedNameResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Iqn string `protobuf:"bytes,1,opt,name=iqn,proto3" json:"iqn,omitempty"`
 }
 
-func (x *DisconnectBlockDeviceResponse) Reset() {
-	*x = DisconnectBlockDeviceResponse{}
+func (x *GetISCSIQualifiedNameResponse) Reset() {
+	*x = GetISCSIQualifiedNameResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[43]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2326,13 +3223,13 @@ func (x *DisconnectBlockDeviceResponse) Reset() {
 	}
 }
 
-func (x *DisconnectBlockDeviceResponse) String() string {
+func (x *GetISCSIQualifiedNameResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DisconnectBlockDeviceResponse) ProtoMessage() {}
+func (*GetISCSIQualifiedNameResponse) ProtoMessage() {}
 
-func (x *DisconnectBlockDeviceResponse) ProtoReflect() protoreflect.Message {
+func (x *GetISCSIQualifiedNameResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[43]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2344,19 +3241,28 @@ func (x *DisconnectBlockDeviceResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DisconnectBlockDeviceResponse.ProtoReflect.Descriptor instead.
-func (*DisconnectBlockDeviceResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetISCSIQualifiedNameResponse.ProtoReflect.Descriptor instead.
+func (*GetISCSIQualifiedNameResponse) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{43}
 }
 
-type StopVirtualMachineResponse struct {
+func (x *GetISCSIQualifiedNameResponse) GetIqn() string {
+	if x != nil {
+		return x.Iqn
+	}
+	return ""
+}
+
+type GetIPTablesResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Chains []*IPTablesChain `protobuf:"bytes,1,rep,name=chains,proto3" json:"chains,omitempty"`
 }
 
-func (x *StopVirtualMachineResponse) Reset() {
-	*x = StopVirtualMachineResponse{}
+func (x *GetIPTablesResponse) Reset() {
+	*x = GetIPTablesResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[44]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2364,13 +3270,13 @@ func (x *StopVirtualMachineResponse) Reset() {
 	}
 }
 
-func (x *StopVirtualMachineResponse) String() string {
+func (x *GetIPTablesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StopVirtualMachineResponse) ProtoMessage() {}
+func (*GetIPTablesResponse) ProtoMessage() {}
 
-func (x *StopVirtualMachineResponse) ProtoReflect() protoreflect.Message {
+func (x *GetIPTablesResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[44]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2382,19 +3288,26 @@ func (x *StopVirtualMachineResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StopVirtualMachineResponse.ProtoReflect.Descriptor instead.
-func (*StopVirtualMachineResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetIPTablesResponse.ProtoReflect.Descriptor instead.
+func (*GetIPTablesResponse) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{44}
 }
 
-type DetachBlockDeviceResponse struct {
+func (x *GetIPTablesResponse) GetChains() []*IPTablesChain {
+	if x != nil {
+		return x.Chains
+	}
+	return nil
+}
+
+type SetupDefaultSecurityGroupResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 }
 
-func (x *DetachBlockDeviceResponse) Reset() {
-	*x = DetachBlockDeviceResponse{}
+func (x *SetupDefaultSecurityGroupResponse) Reset() {
+	*x = SetupDefaultSecurityGroupResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[45]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2402,13 +3315,13 @@ func (x *DetachBlockDeviceResponse) Reset() {
 	}
 }
 
-func (x *DetachBlockDeviceResponse) String() string {
+func (x *SetupDefaultSecurityGroupResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DetachBlockDeviceResponse) ProtoMessage() {}
+func (*SetupDefaultSecurityGroupResponse) ProtoMessage() {}
 
-func (x *DetachBlockDeviceResponse) ProtoReflect() protoreflect.Message {
+func (x *SetupDefaultSecurityGroupResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[45]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2420,19 +3333,19 @@ func (x *DetachBlockDeviceResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DetachBlockDeviceResponse.ProtoReflect.Descriptor instead.
-func (*DetachBlockDeviceResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetupDefaultSecurityGroupResponse.ProtoReflect.Descriptor instead.
+func (*SetupDefaultSecurityGroupResponse) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{45}
 }
 
-type DetachInterfaceResponse struct {
+type AddSecurityGroupResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 }
 
-func (x *DetachInterfaceResponse) Reset() {
-	*x = DetachInterfaceResponse{}
+func (x *AddSecurityGroupResponse) Reset() {
+	*x = AddSecurityGroupResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_agent_proto_msgTypes[46]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2440,13 +3353,13 @@ func (x *DetachInterfaceResponse) Reset() {
 	}
 }
 
-func (x *DetachInterfaceResponse) String() string {
+func (x *AddSecurityGroupResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DetachInterfaceResponse) ProtoMessage() {}
+func (*AddSecurityGroupResponse) ProtoMessage() {}
 
-func (x *DetachInterfaceResponse) ProtoReflect() protoreflect.Message {
+func (x *AddSecurityGroupResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_agent_proto_msgTypes[46]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2458,383 +3371,2090 @@ func (x *DetachInterfaceResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DetachInterfaceResponse.ProtoReflect.Descriptor instead.
-func (*DetachInterfaceResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use AddSecurityGroupResponse.ProtoReflect.Descriptor instead.
+func (*AddSecurityGroupResponse) Descriptor() ([]byte, []int) {
 	return file_agent_proto_rawDescGZIP(), []int{46}
 }
 
-var File_agent_proto protoreflect.FileDescriptor
-
-var file_agent_proto_rawDesc = []byte{
-	0x0a, 0x0b, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x61,
-	0x67, 0x65, 0x6e, 0x74, 0x22, 0xec, 0x01, 0x0a, 0x13, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c,
-	0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04,
-	0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64,
-	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x36, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x0e, 0x32, 0x20, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x56, 0x69, 0x72, 0x74,
-	0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x2e,
-	0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x75, 0x0a, 0x05,
-	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0c, 0x0a, 0x08, 0x4e, 0x4f, 0x5f, 0x53, 0x54, 0x41, 0x54,
-	0x45, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x52, 0x55, 0x4e, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x01,
-	0x12, 0x0b, 0x0a, 0x07, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0a, 0x0a,
-	0x06, 0x50, 0x41, 0x55, 0x53, 0x45, 0x44, 0x10, 0x03, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x48, 0x55,
-	0x54, 0x44, 0x4f, 0x57, 0x4e, 0x10, 0x04, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x48, 0x55, 0x54, 0x4f,
-	0x46, 0x46, 0x10, 0x05, 0x12, 0x0b, 0x0a, 0x07, 0x43, 0x52, 0x41, 0x53, 0x48, 0x45, 0x44, 0x10,
-	0x06, 0x12, 0x10, 0x0a, 0x0c, 0x50, 0x4d, 0x5f, 0x53, 0x55, 0x53, 0x50, 0x45, 0x4e, 0x44, 0x45,
-	0x44, 0x10, 0x07, 0x22, 0x1e, 0x0a, 0x1c, 0x47, 0x65, 0x74, 0x49, 0x53, 0x43, 0x53, 0x49, 0x51,
-	0x75, 0x61, 0x6c, 0x69, 0x66, 0x69, 0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x22, 0x14, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x49, 0x50, 0x54, 0x61, 0x62, 0x6c,
-	0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x22, 0x0a, 0x20, 0x53, 0x65, 0x74,
-	0x75, 0x70, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74,
-	0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x77, 0x0a,
-	0x17, 0x41, 0x64, 0x64, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75,
-	0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x74, 0x65,
-	0x72, 0x66, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x74,
-	0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x70, 0x5f, 0x61, 0x64, 0x64,
-	0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x70, 0x41, 0x64,
-	0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x61, 0x63, 0x5f, 0x61, 0x64, 0x64,
-	0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x61, 0x63, 0x41,
-	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0x19, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x74,
-	0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x22, 0x70, 0x0a, 0x10, 0x41, 0x64, 0x64, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x65, 0x74,
-	0x61, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x63, 0x69, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0c, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x43, 0x69, 0x64, 0x72, 0x12, 0x23,
-	0x0a, 0x0d, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4f,
-	0x6e, 0x6c, 0x79, 0x22, 0x5d, 0x0a, 0x17, 0x41, 0x64, 0x64, 0x56, 0x4c, 0x41, 0x4e, 0x49, 0x6e,
-	0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17,
-	0x0a, 0x07, 0x76, 0x6c, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
-	0x06, 0x76, 0x6c, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x61, 0x72, 0x65, 0x6e,
-	0x74, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0f, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61,
-	0x63, 0x65, 0x22, 0x53, 0x0a, 0x1b, 0x41, 0x64, 0x64, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61,
-	0x63, 0x65, 0x54, 0x6f, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x06, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x74,
-	0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x6e,
-	0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x22, 0xca, 0x02, 0x0a, 0x18, 0x41, 0x64, 0x64, 0x56,
-	0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x63, 0x70, 0x75,
-	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x76, 0x63, 0x70, 0x75, 0x73, 0x12, 0x1d,
-	0x0a, 0x0a, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x6b, 0x69, 0x62, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x09, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x4b, 0x69, 0x62, 0x12, 0x1f, 0x0a,
-	0x0b, 0x62, 0x6f, 0x6f, 0x74, 0x5f, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0a, 0x62, 0x6f, 0x6f, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x24,
-	0x0a, 0x0e, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x73, 0x65, 0x63,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x72, 0x65, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65,
-	0x73, 0x53, 0x65, 0x63, 0x12, 0x26, 0x0a, 0x0f, 0x77, 0x72, 0x69, 0x74, 0x65, 0x5f, 0x62, 0x79,
-	0x74, 0x65, 0x73, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x77,
-	0x72, 0x69, 0x74, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x53, 0x65, 0x63, 0x12, 0x22, 0x0a, 0x0d,
-	0x72, 0x65, 0x61, 0x64, 0x5f, 0x69, 0x6f, 0x70, 0x73, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x07, 0x20,
-	0x01, 0x28, 0x0d, 0x52, 0x0b, 0x72, 0x65, 0x61, 0x64, 0x49, 0x6f, 0x70, 0x73, 0x53, 0x65, 0x63,
-	0x12, 0x24, 0x0a, 0x0e, 0x77, 0x72, 0x69, 0x74, 0x65, 0x5f, 0x69, 0x6f, 0x70, 0x73, 0x5f, 0x73,
-	0x65, 0x63, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x77, 0x72, 0x69, 0x74, 0x65, 0x49,
-	0x6f, 0x70, 0x73, 0x53, 0x65, 0x63, 0x12, 0x2c, 0x0a, 0x12, 0x70, 0x69, 0x6e, 0x6e, 0x69, 0x6e,
-	0x67, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x09, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x10, 0x70, 0x69, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x47, 0x72, 0x6f, 0x75, 0x70,
-	0x4e, 0x61, 0x6d, 0x65, 0x22, 0x66, 0x0a, 0x19, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x42,
-	0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x6f, 0x72, 0x74, 0x61, 0x6c, 0x5f, 0x61, 0x64, 0x64, 0x72,
-	0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x6f, 0x72,
-	0x74, 0x61, 0x6c, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0b,
-	0x68, 0x6f, 0x73, 0x74, 0x5f, 0x6c, 0x75, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0d, 0x52, 0x09, 0x68, 0x6f, 0x73, 0x74, 0x4c, 0x75, 0x6e, 0x49, 0x64, 0x22, 0x90, 0x02, 0x0a,
-	0x18, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69,
-	0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x23, 0x0a,
-	0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x44, 0x65, 0x76, 0x69,
-	0x63, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x64, 0x65, 0x76,
-	0x69, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x74, 0x61, 0x72, 0x67, 0x65,
-	0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x24, 0x0a, 0x0e, 0x72, 0x65, 0x61, 0x64, 0x5f,
-	0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52,
-	0x0c, 0x72, 0x65, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x53, 0x65, 0x63, 0x12, 0x26, 0x0a,
-	0x0f, 0x77, 0x72, 0x69, 0x74, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x73, 0x65, 0x63,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x77, 0x72, 0x69, 0x74, 0x65, 0x42, 0x79, 0x74,
-	0x65, 0x73, 0x53, 0x65, 0x63, 0x12, 0x22, 0x0a, 0x0d, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x69, 0x6f,
-	0x70, 0x73, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x72, 0x65,
-	0x61, 0x64, 0x49, 0x6f, 0x70, 0x73, 0x53, 0x65, 0x63, 0x12, 0x24, 0x0a, 0x0e, 0x77, 0x72, 0x69,
-	0x74, 0x65, 0x5f, 0x69, 0x6f, 0x70, 0x73, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x07, 0x20, 0x01, 0x28,
-	0x0d, 0x52, 0x0c, 0x77, 0x72, 0x69, 0x74, 0x65, 0x49, 0x6f, 0x70, 0x73, 0x53, 0x65, 0x63, 0x22,
-	0xcd, 0x01, 0x0a, 0x16, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66,
-	0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75,
-	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x16,
-	0x0a, 0x06, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
-	0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x6e, 0x62, 0x6f, 0x75, 0x6e,
-	0x64, 0x5f, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52,
-	0x0e, 0x69, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x41, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x12,
-	0x29, 0x0a, 0x10, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x61, 0x76, 0x65, 0x72,
-	0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x6f, 0x75, 0x74, 0x62, 0x6f,
-	0x75, 0x6e, 0x64, 0x41, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1f,
-	0x0a, 0x0b, 0x6d, 0x61, 0x63, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x06, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x61, 0x63, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22,
-	0x30, 0x0a, 0x1a, 0x53, 0x74, 0x61, 0x72, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d,
-	0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
-	0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69,
-	0x64, 0x22, 0x33, 0x0a, 0x1d, 0x47, 0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d,
-	0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x22, 0x20, 0x0a, 0x1e, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x69,
-	0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x61, 0x74,
-	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x29, 0x0a, 0x13, 0x44, 0x65, 0x6c, 0x65,
-	0x74, 0x65, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
-	0x61, 0x6d, 0x65, 0x22, 0x60, 0x0a, 0x1a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x4c, 0x41,
-	0x4e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x17, 0x0a, 0x07, 0x76, 0x6c, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x06, 0x76, 0x6c, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x61,
-	0x72, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x49, 0x6e, 0x74, 0x65,
-	0x72, 0x66, 0x61, 0x63, 0x65, 0x22, 0x58, 0x0a, 0x20, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49,
-	0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x42, 0x72, 0x69, 0x64,
-	0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x72, 0x69,
-	0x64, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x72, 0x69, 0x64, 0x67,
-	0x65, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x22,
-	0x31, 0x0a, 0x1b, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c,
-	0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
-	0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75,
-	0x69, 0x64, 0x22, 0x69, 0x0a, 0x1c, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
-	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x6f, 0x72, 0x74, 0x61, 0x6c, 0x5f, 0x61, 0x64, 0x64,
-	0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x6f,
-	0x72, 0x74, 0x61, 0x6c, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x1e, 0x0a,
-	0x0b, 0x68, 0x6f, 0x73, 0x74, 0x5f, 0x6c, 0x75, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x09, 0x68, 0x6f, 0x73, 0x74, 0x4c, 0x75, 0x6e, 0x49, 0x64, 0x22, 0x2f, 0x0a,
-	0x19, 0x53, 0x74, 0x6f, 0x70, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68,
-	0x69, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75,
-	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x22, 0x78,
-	0x0a, 0x18, 0x44, 0x65, 0x74, 0x61, 0x63, 0x68, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76,
-	0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75,
-	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x23,
-	0x0a, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x44, 0x65, 0x76,
-	0x69, 0x63, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x5f, 0x64, 0x65,
-	0x76, 0x69, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x74, 0x61, 0x72, 0x67,
-	0x65, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x22, 0xcd, 0x01, 0x0a, 0x16, 0x44, 0x65, 0x74,
-	0x61, 0x63, 0x68, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x72, 0x69, 0x64, 0x67,
-	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x12,
-	0x27, 0x0a, 0x0f, 0x69, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x61, 0x76, 0x65, 0x72, 0x61,
-	0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x69, 0x6e, 0x62, 0x6f, 0x75, 0x6e,
-	0x64, 0x41, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x6f, 0x75, 0x74, 0x62,
-	0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x0f, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x41, 0x76, 0x65, 0x72,
-	0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x61, 0x63, 0x5f, 0x61,
-	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x61,
-	0x63, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0x31, 0x0a, 0x1d, 0x47, 0x65, 0x74, 0x49,
-	0x53, 0x43, 0x53, 0x49, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x66, 0x69, 0x65, 0x64, 0x4e, 0x61, 0x6d,
-	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x69, 0x71, 0x6e,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x69, 0x71, 0x6e, 0x22, 0x15, 0x0a, 0x13, 0x47,
-	0x65, 0x74, 0x49, 0x50, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x23, 0x0a, 0x21, 0x53, 0x65, 0x74, 0x75, 0x70, 0x44, 0x65, 0x66, 0x61, 0x75,
-	0x6c, 0x74, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1a, 0x0a, 0x18, 0x41, 0x64, 0x64, 0x53, 0x65,
-	0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x22, 0x41, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66,
-	0x61, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x25, 0x0a, 0x0e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61,
-	0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x13, 0x0a, 0x11, 0x41, 0x64, 0x64, 0x42, 0x72, 0x69,
-	0x64, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1a, 0x0a, 0x18, 0x41,
-	0x64, 0x64, 0x56, 0x4c, 0x41, 0x4e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1e, 0x0a, 0x1c, 0x41, 0x64, 0x64, 0x49, 0x6e,
-	0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x54, 0x6f, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x43, 0x0a, 0x19, 0x41, 0x64, 0x64, 0x56, 0x69,
-	0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x3d, 0x0a, 0x1a,
-	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69,
-	0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x65,
-	0x76, 0x69, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0a, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x45, 0x0a, 0x1b, 0x53,
-	0x74, 0x61, 0x72, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69,
-	0x6e, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75,
-	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x12,
-	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x22, 0x52, 0x0a, 0x1e, 0x47, 0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c,
-	0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x56, 0x69, 0x72, 0x74,
-	0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52,
-	0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x55, 0x0a, 0x1f, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x69,
-	0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x61, 0x74,
-	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x06, 0x73, 0x74, 0x61,
-	0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x61, 0x67, 0x65, 0x6e,
-	0x74, 0x2e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65,
-	0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x65, 0x73, 0x22, 0x43, 0x0a,
-	0x19, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69,
-	0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75,
-	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x12,
-	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x22, 0x41, 0x0a, 0x17, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x49, 0x6e, 0x74, 0x65,
-	0x72, 0x66, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a,
-	0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69,
-	0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x16, 0x0a, 0x14, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42,
-	0x72, 0x69, 0x64, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1d, 0x0a,
-	0x1b, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x4c, 0x41, 0x4e, 0x49, 0x6e, 0x74, 0x65, 0x72,
-	0x66, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x23, 0x0a, 0x21,
-	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x46,
-	0x72, 0x6f, 0x6d, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x1e, 0x0a, 0x1c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75,
-	0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x1f, 0x0a, 0x1d, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x42,
-	0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x1c, 0x0a, 0x1a, 0x53, 0x74, 0x6f, 0x70, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61,
-	0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x22, 0x1b, 0x0a, 0x19, 0x44, 0x65, 0x74, 0x61, 0x63, 0x68, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44,
-	0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x19, 0x0a,
-	0x17, 0x44, 0x65, 0x74, 0x61, 0x63, 0x68, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xdc, 0x10, 0x0a, 0x05, 0x41, 0x67, 0x65,
-	0x6e, 0x74, 0x12, 0x64, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x49, 0x53, 0x43, 0x53, 0x49, 0x51, 0x75,
-	0x61, 0x6c, 0x69, 0x66, 0x69, 0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x23, 0x2e, 0x61, 0x67,
-	0x65, 0x6e, 0x74, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x53, 0x43, 0x53, 0x49, 0x51, 0x75, 0x61, 0x6c,
-	0x69, 0x66, 0x69, 0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x24, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x53, 0x43, 0x53,
-	0x49, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x66, 0x69, 0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x46, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x49,
-	0x50, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x12, 0x19, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e,
-	0x47, 0x65, 0x74, 0x49, 0x50, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x50,
-	0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
-	0x12, 0x70, 0x0a, 0x19, 0x53, 0x65, 0x74, 0x75, 0x70, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74,
-	0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x27, 0x2e,
-	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x65, 0x74, 0x75, 0x70, 0x44, 0x65, 0x66, 0x61, 0x75,
-	0x6c, 0x74, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x53,
-	0x65, 0x74, 0x75, 0x70, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x53, 0x65, 0x63, 0x75, 0x72,
-	0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x22, 0x00, 0x12, 0x55, 0x0a, 0x10, 0x41, 0x64, 0x64, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74,
-	0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x1e, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41,
-	0x64, 0x64, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41,
-	0x64, 0x64, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x55, 0x0a, 0x10, 0x47, 0x65, 0x74,
-	0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x2e,
-	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61,
-	0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e,
-	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61,
-	0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
-	0x12, 0x40, 0x0a, 0x09, 0x41, 0x64, 0x64, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x12, 0x17, 0x2e,
-	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x64, 0x64, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41,
-	0x64, 0x64, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x22, 0x00, 0x12, 0x55, 0x0a, 0x10, 0x41, 0x64, 0x64, 0x56, 0x4c, 0x41, 0x4e, 0x49, 0x6e, 0x74,
-	0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x1e, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41,
-	0x64, 0x64, 0x56, 0x4c, 0x41, 0x4e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41,
-	0x64, 0x64, 0x56, 0x4c, 0x41, 0x4e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x61, 0x0a, 0x14, 0x41, 0x64, 0x64,
-	0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x54, 0x6f, 0x42, 0x72, 0x69, 0x64, 0x67,
-	0x65, 0x12, 0x22, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x64, 0x64, 0x49, 0x6e, 0x74,
-	0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x54, 0x6f, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x64,
-	0x64, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x54, 0x6f, 0x42, 0x72, 0x69, 0x64,
-	0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x11,
-	0x41, 0x64, 0x64, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e,
-	0x65, 0x12, 0x1f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x64, 0x64, 0x56, 0x69, 0x72,
-	0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x20, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x64, 0x64, 0x56, 0x69,
-	0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5b, 0x0a, 0x12, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
-	0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x20, 0x2e, 0x61,
-	0x67, 0x65, 0x6e, 0x74, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x42, 0x6c, 0x6f, 0x63,
-	0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21,
-	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x42, 0x6c,
-	0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x00, 0x12, 0x67, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61,
-	0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x24, 0x2e,
-	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x47, 0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c,
-	0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x47, 0x65, 0x74, 0x56,
-	0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x61,
-	0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x6a, 0x0a, 0x17,
-	0x4c, 0x69, 0x73, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69,
-	0x6e, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x25, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e,
-	0x4c, 0x69, 0x73, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69,
-	0x6e, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26,
-	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75,
-	0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5e, 0x0a, 0x13, 0x53, 0x74, 0x61, 0x72,
-	0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x12,
-	0x21, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x56, 0x69, 0x72,
-	0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x22, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74,
-	0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x11, 0x41, 0x74, 0x74, 0x61,
-	0x63, 0x68, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1f, 0x2e,
-	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x42, 0x6c, 0x6f, 0x63,
-	0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20,
-	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x42, 0x6c, 0x6f,
-	0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x22, 0x00, 0x12, 0x52, 0x0a, 0x0f, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x49, 0x6e, 0x74, 0x65,
-	0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x1d, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x74,
-	0x74, 0x61, 0x63, 0x68, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x74, 0x74,
-	0x61, 0x63, 0x68, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x49, 0x0a, 0x0c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
-	0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x12, 0x1a, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44,
-	0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74,
-	0x65, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x00, 0x12, 0x5e, 0x0a, 0x13, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x4c, 0x41, 0x4e, 0x49,
-	0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x21, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
-	0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x4c, 0x41, 0x4e, 0x49, 0x6e, 0x74, 0x65, 0x72,
-	0x66, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x61, 0x67,
-	0x65, 0x6e, 0x74, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x4c, 0x41, 0x4e, 0x49, 0x6e,
-	0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x00, 0x12, 0x70, 0x0a, 0x19, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x72,
-	0x66, 0x61, 0x63, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x12, 0x27,
-	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x6e, 0x74,
-	0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e,
-	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x46,
-	0x72, 0x6f, 0x6d, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x00, 0x12, 0x61, 0x0a, 0x14, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x69, 0x72,
-	0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x12, 0x22, 0x2e, 0x61, 0x67,
-	0x65, 0x6e, 0x74, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61,
-	0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x23, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x69,
-	0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x64, 0x0a, 0x15, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e,
-	0x6e, 0x65, 0x63, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12,
-	0x23, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65,
-	0x63, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44, 0x69, 0x73,
-	0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69,
-	0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5b, 0x0a, 0x12,
-	0x53, 0x74, 0x6f, 0x70, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69,
-	0x6e, 0x65, 0x12, 0x20, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x56,
-	0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x74, 0x6f,
-	0x70, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x11, 0x44, 0x65, 0x74,
-	0x61, 0x63, 0x68, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1f,
-	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44, 0x65, 0x74, 0x61, 0x63, 0x68, 0x42, 0x6c, 0x6f,
-	0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x20, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44, 0x65, 0x74, 0x61, 0x63, 0x68, 0x42, 0x6c,
-	0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x00, 0x12, 0x52, 0x0a, 0x0f, 0x44, 0x65, 0x74, 0x61, 0x63, 0x68, 0x49, 0x6e, 0x74,
-	0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x1d, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44,
-	0x65, 0x74, 0x61, 0x63, 0x68, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44, 0x65,
-	0x74, 0x61, 0x63, 0x68, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x09, 0x5a, 0x07, 0x2e, 0x3b, 0x61, 0x67, 0x65,
-	0x6e, 0x74, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+type RemoveSecurityGroupResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 }
 
-var (
-	file_agent_proto_rawDescOnce sync.Once
-	file_agent_proto_rawDescData = file_agent_proto_rawDesc
+func (x *RemoveSecurityGroupResponse) Reset() {
+	*x = RemoveSecurityGroupResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RemoveSecurityGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveSecurityGroupResponse) ProtoMessage() {}
+
+func (x *RemoveSecurityGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveSecurityGroupResponse.ProtoReflect.Descriptor instead.
+func (*RemoveSecurityGroupResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{47}
+}
+
+type UpdateSecurityGroupRulesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *UpdateSecurityGroupRulesResponse) Reset() {
+	*x = UpdateSecurityGroupRulesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[48]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateSecurityGroupRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSecurityGroupRulesResponse) ProtoMessage() {}
+
+func (x *UpdateSecurityGroupRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[48]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSecurityGroupRulesResponse.ProtoReflect.Descriptor instead.
+func (*UpdateSecurityGroupRulesResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{48}
+}
+
+type AddAllowedAddressPairsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AddAllowedAddressPairsResponse) Reset() {
+	*x = AddAllowedAddressPairsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[49]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddAllowedAddressPairsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddAllowedAddressPairsResponse) ProtoMessage() {}
+
+func (x *AddAllowedAddressPairsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[49]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddAllowedAddressPairsResponse.ProtoReflect.Descriptor instead.
+func (*AddAllowedAddressPairsResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{49}
+}
+
+type RemoveAllowedAddressPairsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *RemoveAllowedAddressPairsResponse) Reset() {
+	*x = RemoveAllowedAddressPairsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[50]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RemoveAllowedAddressPairsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveAllowedAddressPairsResponse) ProtoMessage() {}
+
+func (x *RemoveAllowedAddressPairsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[50]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveAllowedAddressPairsResponse.ProtoReflect.Descriptor instead.
+func (*RemoveAllowedAddressPairsResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{50}
+}
+
+type SetRemoteGroupMembersResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *SetRemoteGroupMembersResponse) Reset() {
+	*x = SetRemoteGroupMembersResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[51]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetRemoteGroupMembersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRemoteGroupMembersResponse) ProtoMessage() {}
+
+func (x *SetRemoteGroupMembersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[51]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRemoteGroupMembersResponse.ProtoReflect.Descriptor instead.
+func (*SetRemoteGroupMembersResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{51}
+}
+
+type DeleteRemoteGroupResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DeleteRemoteGroupResponse) Reset() {
+	*x = DeleteRemoteGroupResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[52]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteRemoteGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRemoteGroupResponse) ProtoMessage() {}
+
+func (x *DeleteRemoteGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[52]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRemoteGroupResponse.ProtoReflect.Descriptor instead.
+func (*DeleteRemoteGroupResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{52}
+}
+
+type ListRemoteGroupsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RemoteGroups []*RemoteGroup `protobuf:"bytes,1,rep,name=remote_groups,json=remoteGroups,proto3" json:"remote_groups,omitempty"`
+}
+
+func (x *ListRemoteGroupsResponse) Reset() {
+	*x = ListRemoteGroupsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[53]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListRemoteGroupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRemoteGroupsResponse) ProtoMessage() {}
+
+func (x *ListRemoteGroupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[53]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRemoteGroupsResponse.ProtoReflect.Descriptor instead.
+func (*ListRemoteGroupsResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ListRemoteGroupsResponse) GetRemoteGroups() []*RemoteGroup {
+	if x != nil {
+		return x.RemoteGroups
+	}
+	return nil
+}
+
+type GetInterfaceNameResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	InterfaceName string `protobuf:"bytes,1,opt,name=interface_name,json=interfaceName,proto3" json:"interface_name,omitempty"`
+}
+
+func (x *GetInterfaceNameResponse) Reset() {
+	*x = GetInterfaceNameResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[54]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetInterfaceNameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInterfaceNameResponse) ProtoMessage() {}
+
+func (x *GetInterfaceNameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[54]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInterfaceNameResponse.ProtoReflect.Descriptor instead.
+func (*GetInterfaceNameResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *GetInterfaceNameResponse) GetInterfaceName() string {
+	if x != nil {
+		return x.InterfaceName
+	}
+	return ""
+}
+
+type AddBridgeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AddBridgeResponse) Reset() {
+	*x = AddBridgeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[55]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddBridgeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddBridgeResponse) ProtoMessage() {}
+
+func (x *AddBridgeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[55]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddBridgeResponse.ProtoReflect.Descriptor instead.
+func (*AddBridgeResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{55}
+}
+
+type AddVLANInterfaceResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AddVLANInterfaceResponse) Reset() {
+	*x = AddVLANInterfaceResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[56]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddVLANInterfaceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddVLANInterfaceResponse) ProtoMessage() {}
+
+func (x *AddVLANInterfaceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[56]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddVLANInterfaceResponse.ProtoReflect.Descriptor instead.
+func (*AddVLANInterfaceResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{56}
+}
+
+type AddInterfaceToBridgeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AddInterfaceToBridgeResponse) Reset() {
+	*x = AddInterfaceToBridgeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[57]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddInterfaceToBridgeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddInterfaceToBridgeResponse) ProtoMessage() {}
+
+func (x *AddInterfaceToBridgeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[57]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddInterfaceToBridgeResponse.ProtoReflect.Descriptor instead.
+func (*AddInterfaceToBridgeResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{57}
+}
+
+type AddVirtualMachineResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uuid string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *AddVirtualMachineResponse) Reset() {
+	*x = AddVirtualMachineResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[58]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddVirtualMachineResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddVirtualMachineResponse) ProtoMessage() {}
+
+func (x *AddVirtualMachineResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[58]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddVirtualMachineResponse.ProtoReflect.Descriptor instead.
+func (*AddVirtualMachineResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *AddVirtualMachineResponse) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *AddVirtualMachineResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type ConnectBlockDeviceResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DeviceName  string `protobuf:"bytes,1,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
+	OperationId string `protobuf:"bytes,2,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+}
+
+func (x *ConnectBlockDeviceResponse) Reset() {
+	*x = ConnectBlockDeviceResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[59]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConnectBlockDeviceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConnectBlockDeviceResponse) ProtoMessage() {}
+
+func (x *ConnectBlockDeviceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[59]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConnectBlockDeviceResponse.ProtoReflect.Descriptor instead.
+func (*ConnectBlockDeviceResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *ConnectBlockDeviceResponse) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
+func (x *ConnectBlockDeviceResponse) GetOperationId() string {
+	if x != nil {
+		return x.OperationId
+	}
+	return ""
+}
+
+type StartVirtualMachineResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uuid string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *StartVirtualMachineResponse) Reset() {
+	*x = StartVirtualMachineResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[60]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StartVirtualMachineResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartVirtualMachineResponse) ProtoMessage() {}
+
+func (x *StartVirtualMachineResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[60]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartVirtualMachineResponse.ProtoReflect.Descriptor instead.
+func (*StartVirtualMachineResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *StartVirtualMachineResponse) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *StartVirtualMachineResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetVirtualMachineStateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	State *VirtualMachineState `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+}
+
+func (x *GetVirtualMachineStateResponse) Reset() {
+	*x = GetVirtualMachineStateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[61]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetVirtualMachineStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVirtualMachineStateResponse) ProtoMessage() {}
+
+func (x *GetVirtualMachineStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[61]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVirtualMachineStateResponse.ProtoReflect.Descriptor instead.
+func (*GetVirtualMachineStateResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *GetVirtualMachineStateResponse) GetState() *VirtualMachineState {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+type ListVirtualMachineStateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	States []*VirtualMachineState `protobuf:"bytes,1,rep,name=states,proto3" json:"states,omitempty"`
+}
+
+func (x *ListVirtualMachineStateResponse) Reset() {
+	*x = ListVirtualMachineStateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[62]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListVirtualMachineStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVirtualMachineStateResponse) ProtoMessage() {}
+
+func (x *ListVirtualMachineStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[62]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVirtualMachineStateResponse.ProtoReflect.Descriptor instead.
+func (*ListVirtualMachineStateResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *ListVirtualMachineStateResponse) GetStates() []*VirtualMachineState {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+type AttachBlockDeviceResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uuid string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *AttachBlockDeviceResponse) Reset() {
+	*x = AttachBlockDeviceResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[63]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AttachBlockDeviceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachBlockDeviceResponse) ProtoMessage() {}
+
+func (x *AttachBlockDeviceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[63]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachBlockDeviceResponse.ProtoReflect.Descriptor instead.
+func (*AttachBlockDeviceResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *AttachBlockDeviceResponse) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *AttachBlockDeviceResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type AttachInterfaceResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uuid string `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *AttachInterfaceResponse) Reset() {
+	*x = AttachInterfaceResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[64]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AttachInterfaceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachInterfaceResponse) ProtoMessage() {}
+
+func (x *AttachInterfaceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[64]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachInterfaceResponse.ProtoReflect.Descriptor instead.
+func (*AttachInterfaceResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *AttachInterfaceResponse) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *AttachInterfaceResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type DeleteBridgeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DeleteBridgeResponse) Reset() {
+	*x = DeleteBridgeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[65]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteBridgeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBridgeResponse) ProtoMessage() {}
+
+func (x *DeleteBridgeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[65]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBridgeResponse.ProtoReflect.Descriptor instead.
+func (*DeleteBridgeResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{65}
+}
+
+type DeleteVLANInterfaceResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DeleteVLANInterfaceResponse) Reset() {
+	*x = DeleteVLANInterfaceResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[66]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteVLANInterfaceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteVLANInterfaceResponse) ProtoMessage() {}
+
+func (x *DeleteVLANInterfaceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[66]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteVLANInterfaceResponse.ProtoReflect.Descriptor instead.
+func (*DeleteVLANInterfaceResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{66}
+}
+
+type DeleteInterfaceFromBridgeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DeleteInterfaceFromBridgeResponse) Reset() {
+	*x = DeleteInterfaceFromBridgeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[67]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteInterfaceFromBridgeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteInterfaceFromBridgeResponse) ProtoMessage() {}
+
+func (x *DeleteInterfaceFromBridgeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[67]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteInterfaceFromBridgeResponse.ProtoReflect.Descriptor instead.
+func (*DeleteInterfaceFromBridgeResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{67}
+}
+
+type DeleteVirtualMachineResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DeleteVirtualMachineResponse) Reset() {
+	*x = DeleteVirtualMachineResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[68]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteVirtualMachineResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteVirtualMachineResponse) ProtoMessage() {}
+
+func (x *DeleteVirtualMachineResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[68]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteVirtualMachineResponse.ProtoReflect.Descriptor instead.
+func (*DeleteVirtualMachineResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{68}
+}
+
+type DisconnectBlockDeviceResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OperationId string `protobuf:"bytes,1,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+}
+
+func (x *DisconnectBlockDeviceResponse) Reset() {
+	*x = DisconnectBlockDeviceResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[69]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DisconnectBlockDeviceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisconnectBlockDeviceResponse) ProtoMessage() {}
+
+func (x *DisconnectBlockDeviceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[69]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisconnectBlockDeviceResponse.ProtoReflect.Descriptor instead.
+func (*DisconnectBlockDeviceResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *DisconnectBlockDeviceResponse) GetOperationId() string {
+	if x != nil {
+		return x.OperationId
+	}
+	return ""
+}
+
+type StopVirtualMachineResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *StopVirtualMachineResponse) Reset() {
+	*x = StopVirtualMachineResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[70]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StopVirtualMachineResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopVirtualMachineResponse) ProtoMessage() {}
+
+func (x *StopVirtualMachineResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[70]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopVirtualMachineResponse.ProtoReflect.Descriptor instead.
+func (*StopVirtualMachineResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{70}
+}
+
+type DetachBlockDeviceResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DetachBlockDeviceResponse) Reset() {
+	*x = DetachBlockDeviceResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[71]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DetachBlockDeviceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DetachBlockDeviceResponse) ProtoMessage() {}
+
+func (x *DetachBlockDeviceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[71]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DetachBlockDeviceResponse.ProtoReflect.Descriptor instead.
+func (*DetachBlockDeviceResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{71}
+}
+
+type DetachInterfaceResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DetachInterfaceResponse) Reset() {
+	*x = DetachInterfaceResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[72]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DetachInterfaceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DetachInterfaceResponse) ProtoMessage() {}
+
+func (x *DetachInterfaceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[72]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DetachInterfaceResponse.ProtoReflect.Descriptor instead.
+func (*DetachInterfaceResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{72}
+}
+
+type GetOperationResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Operation *Operation `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+}
+
+func (x *GetOperationResponse) Reset() {
+	*x = GetOperationResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[73]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOperationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOperationResponse) ProtoMessage() {}
+
+func (x *GetOperationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[73]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOperationResponse.ProtoReflect.Descriptor instead.
+func (*GetOperationResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *GetOperationResponse) GetOperation() *Operation {
+	if x != nil {
+		return x.Operation
+	}
+	return nil
+}
+
+type ListOperationsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Operations []*Operation `protobuf:"bytes,1,rep,name=operations,proto3" json:"operations,omitempty"`
+}
+
+func (x *ListOperationsResponse) Reset() {
+	*x = ListOperationsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[74]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListOperationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOperationsResponse) ProtoMessage() {}
+
+func (x *ListOperationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[74]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOperationsResponse.ProtoReflect.Descriptor instead.
+func (*ListOperationsResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *ListOperationsResponse) GetOperations() []*Operation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+type CancelOperationResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *CancelOperationResponse) Reset() {
+	*x = CancelOperationResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[75]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CancelOperationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOperationResponse) ProtoMessage() {}
+
+func (x *CancelOperationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[75]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOperationResponse.ProtoReflect.Descriptor instead.
+func (*CancelOperationResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{75}
+}
+
+type WaitOperationResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Operation *Operation `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+}
+
+func (x *WaitOperationResponse) Reset() {
+	*x = WaitOperationResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[76]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WaitOperationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WaitOperationResponse) ProtoMessage() {}
+
+func (x *WaitOperationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[76]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WaitOperationResponse.ProtoReflect.Descriptor instead.
+func (*WaitOperationResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *WaitOperationResponse) GetOperation() *Operation {
+	if x != nil {
+		return x.Operation
+	}
+	return nil
+}
+
+type RunPreflightChecksResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Checks []*PreflightCheck `protobuf:"bytes,1,rep,name=checks,proto3" json:"checks,omitempty"`
+}
+
+func (x *RunPreflightChecksResponse) Reset() {
+	*x = RunPreflightChecksResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_agent_proto_msgTypes[77]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunPreflightChecksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunPreflightChecksResponse) ProtoMessage() {}
+
+func (x *RunPreflightChecksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_agent_proto_msgTypes[77]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunPreflightChecksResponse.ProtoReflect.Descriptor instead.
+func (*RunPreflightChecksResponse) Descriptor() ([]byte, []int) {
+	return file_agent_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *RunPreflightChecksResponse) GetChecks() []*PreflightCheck {
+	if x != nil {
+		return x.Checks
+	}
+	return nil
+}
+
+var File_agent_proto protoreflect.FileDescriptor
+
+var file_agent_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x22, 0xec, 0x01, 0x0a, 0x13, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c,
+	0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x36, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x20, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x56, 0x69, 0x72, 0x74,
+	0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x2e,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x75, 0x0a, 0x05,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0c, 0x0a, 0x08, 0x4e, 0x4f, 0x5f, 0x53, 0x54, 0x41, 0x54,
+	0x45, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x52, 0x55, 0x4e, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x01,
+	0x12, 0x0b, 0x0a, 0x07, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0a, 0x0a,
+	0x06, 0x50, 0x41, 0x55, 0x53, 0x45, 0x44, 0x10, 0x03, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x48, 0x55,
+	0x54, 0x44, 0x4f, 0x57, 0x4e, 0x10, 0x04, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x48, 0x55, 0x54, 0x4f,
+	0x46, 0x46, 0x10, 0x05, 0x12, 0x0b, 0x0a, 0x07, 0x43, 0x52, 0x41, 0x53, 0x48, 0x45, 0x44, 0x10,
+	0x06, 0x12, 0x10, 0x0a, 0x0c, 0x50, 0x4d, 0x5f, 0x53, 0x55, 0x53, 0x50, 0x45, 0x4e, 0x44, 0x45,
+	0x44, 0x10, 0x07, 0x22, 0xcb, 0x04, 0x0a, 0x09, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x2c, 0x0a, 0x05, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x72, 0x6f, 0x67, 0x72,
+	0x65, 0x73, 0x73, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x50, 0x65, 0x72, 0x63, 0x65,
+	0x6e, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x5f, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x72,
+	0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1d, 0x0a,
+	0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x23, 0x0a, 0x0d,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x69,
+	0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54,
+	0x69, 0x6d, 0x65, 0x12, 0x55, 0x0a, 0x14, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x5f, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x21, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x12, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x5e, 0x0a, 0x17, 0x64, 0x69,
+	0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x64,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x48, 0x00, 0x52, 0x15, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x22, 0x4b, 0x0a, 0x05, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x10, 0x00,
+	0x12, 0x0b, 0x0a, 0x07, 0x52, 0x55, 0x4e, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x0d, 0x0a,
+	0x09, 0x53, 0x55, 0x43, 0x43, 0x45, 0x45, 0x44, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06,
+	0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x03, 0x12, 0x0d, 0x0a, 0x09, 0x43, 0x41, 0x4e, 0x43,
+	0x45, 0x4c, 0x4c, 0x45, 0x44, 0x10, 0x04, 0x42, 0x08, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x22, 0xbc, 0x02, 0x0a, 0x11, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x3d, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x21, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x2e, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52,
+	0x75, 0x6c, 0x65, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x52, 0x08, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x24, 0x0a, 0x0e, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x72,
+	0x61, 0x6e, 0x67, 0x65, 0x5f, 0x6d, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c,
+	0x70, 0x6f, 0x72, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x4d, 0x69, 0x6e, 0x12, 0x24, 0x0a, 0x0e,
+	0x70, 0x6f, 0x72, 0x74, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x6d, 0x61, 0x78, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x4d,
+	0x61, 0x78, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x63, 0x69, 0x64,
+	0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x43,
+	0x69, 0x64, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x63, 0x6d, 0x70, 0x5f, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x69, 0x63, 0x6d, 0x70, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x22, 0x3b, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12,
+	0x07, 0x0a, 0x03, 0x41, 0x4e, 0x59, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x43, 0x50, 0x10,
+	0x01, 0x12, 0x07, 0x0a, 0x03, 0x55, 0x44, 0x50, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x43,
+	0x4d, 0x50, 0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x49, 0x43, 0x4d, 0x50, 0x56, 0x36, 0x10, 0x04,
+	0x22, 0x44, 0x0a, 0x0b, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x70, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x70, 0x41, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x22, 0x84, 0x01, 0x0a, 0x0d, 0x49, 0x50, 0x54, 0x61, 0x62,
+	0x6c, 0x65, 0x73, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x29, 0x0a, 0x05,
+	0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x2e, 0x49, 0x50, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x52, 0x75, 0x6c, 0x65,
+	0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x66, 0x61, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x22, 0xd9, 0x03,
+	0x0a, 0x0c, 0x49, 0x50, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x75,
+	0x6c, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73,
+	0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x12, 0x2b, 0x0a, 0x11, 0x64,
+	0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x73,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x68, 0x79, 0x73,
+	0x64, 0x65, 0x76, 0x5f, 0x69, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x68,
+	0x79, 0x73, 0x64, 0x65, 0x76, 0x49, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x68, 0x79, 0x73, 0x64,
+	0x65, 0x76, 0x5f, 0x6f, 0x75, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x68,
+	0x79, 0x73, 0x64, 0x65, 0x76, 0x4f, 0x75, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x63, 0x5f,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6d, 0x61,
+	0x63, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1b, 0x0a,
+	0x09, 0x69, 0x63, 0x6d, 0x70, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x69, 0x63, 0x6d, 0x70, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61,
+	0x74, 0x63, 0x68, 0x5f, 0x73, 0x65, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d,
+	0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65,
+	0x6e, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e,
+	0x74, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x0e, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x63,
+	0x6b, 0x65, 0x74, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x70, 0x61, 0x63, 0x6b,
+	0x65, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x10, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x05, 0x62, 0x79, 0x74, 0x65, 0x73, 0x22, 0xd0, 0x01, 0x0a, 0x0e, 0x50, 0x72,
+	0x65, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x34, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x1c, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x50, 0x72, 0x65, 0x66, 0x6c, 0x69, 0x67,
+	0x68, 0x74, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x20, 0x0a, 0x0b, 0x72, 0x65, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72, 0x65, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x22, 0x38, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x0b, 0x0a, 0x07,
+	0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x50, 0x41, 0x53,
+	0x53, 0x45, 0x44, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10,
+	0x02, 0x12, 0x09, 0x0a, 0x05, 0x46, 0x49, 0x58, 0x45, 0x44, 0x10, 0x03, 0x22, 0x1e, 0x0a, 0x1c,
+	0x47, 0x65, 0x74, 0x49, 0x53, 0x43, 0x53, 0x49, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x66, 0x69, 0x65,
+	0x64, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x32, 0x0a, 0x12,
+	0x47, 0x65, 0x74, 0x49, 0x50, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65,
+	0x22, 0x41, 0x0a, 0x20, 0x53, 0x65, 0x74, 0x75, 0x70, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74,
+	0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x49, 0x64, 0x22, 0x84, 0x03, 0x0a, 0x17, 0x41, 0x64, 0x64, 0x53, 0x65, 0x63, 0x75, 0x72,
+	0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x1d, 0x0a,
+	0x0a, 0x69, 0x70, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x69, 0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x1f, 0x0a, 0x0b,
+	0x6d, 0x61, 0x63, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x6d, 0x61, 0x63, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x1d, 0x0a,
+	0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x12, 0x3d, 0x0a, 0x0d,
+	0x69, 0x6e, 0x67, 0x72, 0x65, 0x73, 0x73, 0x5f, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x05, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x65, 0x63, 0x75,
+	0x72, 0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x0c, 0x69,
+	0x6e, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x3b, 0x0a, 0x0c, 0x65,
+	0x67, 0x72, 0x65, 0x73, 0x73, 0x5f, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x18, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69,
+	0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x0b, 0x65, 0x67, 0x72,
+	0x65, 0x73, 0x73, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x70, 0x76, 0x36,
+	0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x69, 0x70, 0x76, 0x36, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x4d, 0x0a, 0x15, 0x61,
+	0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x5f, 0x70,
+	0x61, 0x69, 0x72, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x2e, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x50, 0x61, 0x69, 0x72, 0x52, 0x13, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x41, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x50, 0x61, 0x69, 0x72, 0x73, 0x22, 0x54, 0x0a, 0x12, 0x41, 0x6c,
+	0x6c, 0x6f, 0x77, 0x65, 0x64, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x50, 0x61, 0x69, 0x72,
+	0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x70, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12,
+	0x1f, 0x0a, 0x0b, 0x6d, 0x61, 0x63, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x61, 0x63, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x22, 0x59, 0x0a, 0x1a, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69,
+	0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c,
+	0x0a, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x1d, 0x0a, 0x0a,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x22, 0xda, 0x01, 0x0a, 0x1f,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x3d, 0x0a,
+	0x0d, 0x69, 0x6e, 0x67, 0x72, 0x65, 0x73, 0x73, 0x5f, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x65, 0x63,
+	0x75, 0x72, 0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x0c,
+	0x69, 0x6e, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x3b, 0x0a, 0x0c,
+	0x65, 0x67, 0x72, 0x65, 0x73, 0x73, 0x5f, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x65, 0x63, 0x75, 0x72,
+	0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x0b, 0x65, 0x67,
+	0x72, 0x65, 0x73, 0x73, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x22, 0xab, 0x01, 0x0a, 0x1d, 0x41, 0x64, 0x64,
+	0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x50, 0x61,
+	0x69, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x4d, 0x0a, 0x15, 0x61, 0x6c, 0x6c, 0x6f,
+	0x77, 0x65, 0x64, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x5f, 0x70, 0x61, 0x69, 0x72,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e,
+	0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x50, 0x61,
+	0x69, 0x72, 0x52, 0x13, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x41, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x50, 0x61, 0x69, 0x72, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x22, 0xae, 0x01, 0x0a, 0x20, 0x52, 0x65, 0x6d, 0x6f, 0x76,
+	0x65, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x50,
+	0x61, 0x69, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x4d, 0x0a, 0x15, 0x61, 0x6c, 0x6c,
+	0x6f, 0x77, 0x65, 0x64, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x5f, 0x70, 0x61, 0x69,
+	0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x2e, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x50,
+	0x61, 0x69, 0x72, 0x52, 0x13, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x50, 0x61, 0x69, 0x72, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x22, 0x74, 0x0a, 0x1c, 0x53, 0x65, 0x74, 0x52, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x69,
+	0x70, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0b, 0x69, 0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x1d,
+	0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x22, 0x4d, 0x0a,
+	0x18, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x0a,
+	0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x22, 0x19, 0x0a, 0x17,
+	0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x19, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x49, 0x6e,
+	0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x22, 0x8f, 0x01, 0x0a, 0x10, 0x41, 0x64, 0x64, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x6d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x63, 0x69, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x43, 0x69, 0x64, 0x72,
+	0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x6f, 0x6e, 0x6c,
+	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61,
+	0x6c, 0x4f, 0x6e, 0x6c, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x49, 0x64, 0x22, 0x7c, 0x0a, 0x17, 0x41, 0x64, 0x64, 0x56, 0x4c, 0x41, 0x4e, 0x49,
+	0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x17, 0x0a, 0x07, 0x76, 0x6c, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x06, 0x76, 0x6c, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x61, 0x72, 0x65,
+	0x6e, 0x74, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0f, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66,
+	0x61, 0x63, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x49, 0x64, 0x22, 0x72, 0x0a, 0x1b, 0x41, 0x64, 0x64, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61,
+	0x63, 0x65, 0x54, 0x6f, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x22, 0xe9, 0x02, 0x0a, 0x18, 0x41, 0x64, 0x64, 0x56, 0x69,
+	0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x63, 0x70, 0x75, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x76, 0x63, 0x70, 0x75, 0x73, 0x12, 0x1d, 0x0a,
+	0x0a, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x6b, 0x69, 0x62, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x09, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x4b, 0x69, 0x62, 0x12, 0x1f, 0x0a, 0x0b,
+	0x62, 0x6f, 0x6f, 0x74, 0x5f, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x62, 0x6f, 0x6f, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x24, 0x0a,
+	0x0e, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x73, 0x65, 0x63, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x72, 0x65, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73,
+	0x53, 0x65, 0x63, 0x12, 0x26, 0x0a, 0x0f, 0x77, 0x72, 0x69, 0x74, 0x65, 0x5f, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x77, 0x72,
+	0x69, 0x74, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x53, 0x65, 0x63, 0x12, 0x22, 0x0a, 0x0d, 0x72,
+	0x65, 0x61, 0x64, 0x5f, 0x69, 0x6f, 0x70, 0x73, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0b, 0x72, 0x65, 0x61, 0x64, 0x49, 0x6f, 0x70, 0x73, 0x53, 0x65, 0x63, 0x12,
+	0x24, 0x0a, 0x0e, 0x77, 0x72, 0x69, 0x74, 0x65, 0x5f, 0x69, 0x6f, 0x70, 0x73, 0x5f, 0x73, 0x65,
+	0x63, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x77, 0x72, 0x69, 0x74, 0x65, 0x49, 0x6f,
+	0x70, 0x73, 0x53, 0x65, 0x63, 0x12, 0x2c, 0x0a, 0x12, 0x70, 0x69, 0x6e, 0x6e, 0x69, 0x6e, 0x67,
+	0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x10, 0x70, 0x69, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69,
+	0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x49, 0x64, 0x22, 0x9b, 0x01, 0x0a, 0x19, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x29, 0x0a, 0x10, 0x70, 0x6f, 0x72, 0x74, 0x61, 0x6c, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x6f, 0x72, 0x74,
+	0x61, 0x6c, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0b, 0x68,
+	0x6f, 0x73, 0x74, 0x5f, 0x6c, 0x75, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x09, 0x68, 0x6f, 0x73, 0x74, 0x4c, 0x75, 0x6e, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x61,
+	0x73, 0x79, 0x6e, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x61, 0x73, 0x79, 0x6e,
+	0x63, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64,
+	0x22, 0xaf, 0x02, 0x0a, 0x18, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69,
+	0x64, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x64, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x5f, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x24, 0x0a, 0x0e, 0x72,
+	0x65, 0x61, 0x64, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x0c, 0x72, 0x65, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x53, 0x65,
+	0x63, 0x12, 0x26, 0x0a, 0x0f, 0x77, 0x72, 0x69, 0x74, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73,
+	0x5f, 0x73, 0x65, 0x63, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x77, 0x72, 0x69, 0x74,
+	0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x53, 0x65, 0x63, 0x12, 0x22, 0x0a, 0x0d, 0x72, 0x65, 0x61,
+	0x64, 0x5f, 0x69, 0x6f, 0x70, 0x73, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x0b, 0x72, 0x65, 0x61, 0x64, 0x49, 0x6f, 0x70, 0x73, 0x53, 0x65, 0x63, 0x12, 0x24, 0x0a,
+	0x0e, 0x77, 0x72, 0x69, 0x74, 0x65, 0x5f, 0x69, 0x6f, 0x70, 0x73, 0x5f, 0x73, 0x65, 0x63, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x77, 0x72, 0x69, 0x74, 0x65, 0x49, 0x6f, 0x70, 0x73,
+	0x53, 0x65, 0x63, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69,
+	0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x49, 0x64, 0x22, 0xec, 0x01, 0x0a, 0x16, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x49, 0x6e, 0x74,
+	0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69,
+	0x64, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x6e, 0x62,
+	0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0e, 0x69, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x41, 0x76, 0x65, 0x72, 0x61,
+	0x67, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x61,
+	0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x6f, 0x75,
+	0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x41, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x61, 0x63, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x61, 0x63, 0x41, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49,
+	0x64, 0x22, 0x4f, 0x0a, 0x1a, 0x53, 0x74, 0x61, 0x72, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61,
+	0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75,
+	0x75, 0x69, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x49, 0x64, 0x22, 0x33, 0x0a, 0x1d, 0x47, 0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c,
+	0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x22, 0x20, 0x0a, 0x1e, 0x4c, 0x69, 0x73, 0x74, 0x56,
+	0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x48, 0x0a, 0x13, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f,
+	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x49, 0x64, 0x22, 0x7f, 0x0a, 0x1a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x4c, 0x41,
+	0x4e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x17, 0x0a, 0x07, 0x76, 0x6c, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x06, 0x76, 0x6c, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x61,
+	0x72, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x49, 0x6e, 0x74, 0x65,
+	0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x49, 0x64, 0x22, 0x77, 0x0a, 0x20, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x6e,
+	0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x42, 0x72, 0x69, 0x64, 0x67,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x72, 0x69, 0x64,
+	0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65,
+	0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x1d,
+	0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x22, 0x50, 0x0a,
+	0x1b, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61,
+	0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64,
+	0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x22,
+	0x9e, 0x01, 0x0a, 0x1c, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x29, 0x0a, 0x10, 0x70, 0x6f, 0x72, 0x74, 0x61, 0x6c, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x6f, 0x72, 0x74,
+	0x61, 0x6c, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0b, 0x68,
+	0x6f, 0x73, 0x74, 0x5f, 0x6c, 0x75, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x09, 0x68, 0x6f, 0x73, 0x74, 0x4c, 0x75, 0x6e, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x61,
+	0x73, 0x79, 0x6e, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x61, 0x73, 0x79, 0x6e,
+	0x63, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64,
+	0x22, 0x4e, 0x0a, 0x19, 0x53, 0x74, 0x6f, 0x70, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d,
+	0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69,
+	0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64,
+	0x22, 0x97, 0x01, 0x0a, 0x18, 0x44, 0x65, 0x74, 0x61, 0x63, 0x68, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69,
+	0x64, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x64, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x5f, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x22, 0xec, 0x01, 0x0a, 0x16, 0x44,
+	0x65, 0x74, 0x61, 0x63, 0x68, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x72, 0x69,
+	0x64, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x72, 0x69, 0x64, 0x67,
+	0x65, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x61, 0x76, 0x65,
+	0x72, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x69, 0x6e, 0x62, 0x6f,
+	0x75, 0x6e, 0x64, 0x41, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x6f, 0x75,
+	0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x5f, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x41, 0x76,
+	0x65, 0x72, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x61, 0x63,
+	0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x6d, 0x61, 0x63, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x22, 0x25, 0x0a, 0x13, 0x47, 0x65, 0x74,
+	0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
+	0x22, 0x2f, 0x0a, 0x15, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74,
+	0x68, 0x6f, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f,
+	0x64, 0x22, 0x47, 0x0a, 0x16, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x4f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x22, 0x4f, 0x0a, 0x14, 0x57, 0x61,
+	0x69, 0x74, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x73, 0x65,
+	0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x74, 0x69, 0x6d,
+	0x65, 0x6f, 0x75, 0x74, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x22, 0x4c, 0x0a, 0x19, 0x52,
+	0x75, 0x6e, 0x50, 0x72, 0x65, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x43, 0x68, 0x65, 0x63, 0x6b,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x66, 0x69, 0x78, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x66, 0x69, 0x78, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x22, 0x31, 0x0a, 0x1d, 0x47, 0x65, 0x74,
+	0x49, 0x53, 0x43, 0x53, 0x49, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x66, 0x69, 0x65, 0x64, 0x4e, 0x61,
+	0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x69, 0x71,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x69, 0x71, 0x6e, 0x22, 0x43, 0x0a, 0x13,
+	0x47, 0x65, 0x74, 0x49, 0x50, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x06, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x49, 0x50, 0x54, 0x61,
+	0x62, 0x6c, 0x65, 0x73, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x52, 0x06, 0x63, 0x68, 0x61, 0x69, 0x6e,
+	0x73, 0x22, 0x23, 0x0a, 0x21, 0x53, 0x65, 0x74, 0x75, 0x70, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c,
+	0x74, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1a, 0x0a, 0x18, 0x41, 0x64, 0x64, 0x53, 0x65, 0x63,
+	0x75, 0x72, 0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x1d, 0x0a, 0x1b, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x53, 0x65, 0x63, 0x75,
+	0x72, 0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x22, 0x0a, 0x20, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x65, 0x63, 0x75, 0x72,
+	0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x20, 0x0a, 0x1e, 0x41, 0x64, 0x64, 0x41, 0x6c, 0x6c, 0x6f,
+	0x77, 0x65, 0x64, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x50, 0x61, 0x69, 0x72, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x23, 0x0a, 0x21, 0x52, 0x65, 0x6d, 0x6f, 0x76,
+	0x65, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x50,
+	0x61, 0x69, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1f, 0x0a, 0x1d,
+	0x53, 0x65, 0x74, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4d, 0x65,
+	0x6d, 0x62, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1b, 0x0a,
+	0x19, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x53, 0x0a, 0x18, 0x4c, 0x69,
+	0x73, 0x74, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x0d, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65,
+	0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75,
+	0x70, 0x52, 0x0c, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x22,
+	0x41, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x4e,
+	0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0d, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x4e, 0x61,
+	0x6d, 0x65, 0x22, 0x13, 0x0a, 0x11, 0x41, 0x64, 0x64, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1a, 0x0a, 0x18, 0x41, 0x64, 0x64, 0x56, 0x4c,
+	0x41, 0x4e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x1e, 0x0a, 0x1c, 0x41, 0x64, 0x64, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66,
+	0x61, 0x63, 0x65, 0x54, 0x6f, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x43, 0x0a, 0x19, 0x41, 0x64, 0x64, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61,
+	0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x75, 0x75, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x60, 0x0a, 0x1a, 0x43, 0x6f, 0x6e, 0x6e,
+	0x65, 0x63, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6f,
+	0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x45, 0x0a, 0x1b, 0x53, 0x74,
+	0x61, 0x72, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x22, 0x52, 0x0a, 0x1e, 0x47, 0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d,
+	0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x56, 0x69, 0x72, 0x74, 0x75,
+	0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x55, 0x0a, 0x1f, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x69, 0x72,
+	0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x2e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x65, 0x73, 0x22, 0x43, 0x0a, 0x19,
+	0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x22, 0x41, 0x0a, 0x17, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x49, 0x6e, 0x74, 0x65, 0x72,
+	0x66, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x22, 0x16, 0x0a, 0x14, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x72,
+	0x69, 0x64, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1d, 0x0a, 0x1b,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x4c, 0x41, 0x4e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66,
+	0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x23, 0x0a, 0x21, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x46, 0x72,
+	0x6f, 0x6d, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x1e, 0x0a, 0x1c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61,
+	0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x42, 0x0a, 0x1d, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x49, 0x64, 0x22, 0x1c, 0x0a, 0x1a, 0x53, 0x74, 0x6f, 0x70, 0x56, 0x69, 0x72, 0x74,
+	0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x1b, 0x0a, 0x19, 0x44, 0x65, 0x74, 0x61, 0x63, 0x68, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x19, 0x0a, 0x17, 0x44, 0x65, 0x74, 0x61, 0x63, 0x68, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61,
+	0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x46, 0x0a, 0x14, 0x47, 0x65,
+	0x74, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x2e, 0x0a, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x4f, 0x70,
+	0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x22, 0x4a, 0x0a, 0x16, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x0a,
+	0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x10, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x0a, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x19,
+	0x0a, 0x17, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x47, 0x0a, 0x15, 0x57, 0x61, 0x69,
+	0x74, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x2e, 0x0a, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x4f, 0x70,
+	0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x22, 0x4b, 0x0a, 0x1a, 0x52, 0x75, 0x6e, 0x50, 0x72, 0x65, 0x66, 0x6c, 0x69, 0x67,
+	0x68, 0x74, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x2d, 0x0a, 0x06, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x15, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x50, 0x72, 0x65, 0x66, 0x6c, 0x69, 0x67,
+	0x68, 0x74, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x06, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x32,
+	0xb8, 0x19, 0x0a, 0x05, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x12, 0x64, 0x0a, 0x15, 0x47, 0x65, 0x74,
+	0x49, 0x53, 0x43, 0x53, 0x49, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x66, 0x69, 0x65, 0x64, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x23, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x53,
+	0x43, 0x53, 0x49, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x66, 0x69, 0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e,
+	0x47, 0x65, 0x74, 0x49, 0x53, 0x43, 0x53, 0x49, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x66, 0x69, 0x65,
+	0x64, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x46, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x49, 0x50, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x12, 0x19,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x50, 0x54, 0x61, 0x62, 0x6c,
+	0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x50, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x70, 0x0a, 0x19, 0x53, 0x65, 0x74, 0x75, 0x70,
+	0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x12, 0x27, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x65, 0x74,
+	0x75, 0x70, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74,
+	0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x65, 0x74, 0x75, 0x70, 0x44, 0x65, 0x66, 0x61, 0x75,
+	0x6c, 0x74, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x55, 0x0a, 0x10, 0x41, 0x64, 0x64,
+	0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x1e, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x64, 0x64, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74,
+	0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x64, 0x64, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74,
+	0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x5e, 0x0a, 0x13, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69,
+	0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x21, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e,
+	0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74,
+	0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x6d, 0x0a, 0x18, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69,
+	0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x26, 0x2e, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x65, 0x63, 0x75, 0x72,
+	0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x53, 0x65, 0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x67, 0x0a, 0x16, 0x41, 0x64, 0x64, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x41, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x50, 0x61, 0x69, 0x72, 0x73, 0x12, 0x24, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x2e, 0x41, 0x64, 0x64, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x50, 0x61, 0x69, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x25, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x64, 0x64, 0x41, 0x6c, 0x6c, 0x6f, 0x77,
+	0x65, 0x64, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x50, 0x61, 0x69, 0x72, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x70, 0x0a, 0x19, 0x52, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x50, 0x61, 0x69, 0x72, 0x73, 0x12, 0x27, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x52, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x41, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x50, 0x61, 0x69, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x41, 0x6c, 0x6c,
+	0x6f, 0x77, 0x65, 0x64, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x50, 0x61, 0x69, 0x72, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x64, 0x0a, 0x15, 0x53, 0x65,
+	0x74, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4d, 0x65, 0x6d, 0x62,
+	0x65, 0x72, 0x73, 0x12, 0x23, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x65, 0x74, 0x52,
+	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x2e, 0x53, 0x65, 0x74, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4d,
+	0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x58, 0x0a, 0x11, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x1f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x55, 0x0a, 0x10, 0x4c, 0x69,
+	0x73, 0x74, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x12, 0x1e,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x6d, 0x6f, 0x74,
+	0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x6d, 0x6f, 0x74,
+	0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x55, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63,
+	0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x47, 0x65,
+	0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x47, 0x65,
+	0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x09, 0x41, 0x64, 0x64, 0x42,
+	0x72, 0x69, 0x64, 0x67, 0x65, 0x12, 0x17, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x64,
+	0x64, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x64, 0x64, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x55, 0x0a, 0x10, 0x41, 0x64,
+	0x64, 0x56, 0x4c, 0x41, 0x4e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x1e,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x64, 0x64, 0x56, 0x4c, 0x41, 0x4e, 0x49, 0x6e,
+	0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x64, 0x64, 0x56, 0x4c, 0x41, 0x4e, 0x49, 0x6e,
+	0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x61, 0x0a, 0x14, 0x41, 0x64, 0x64, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63,
+	0x65, 0x54, 0x6f, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x12, 0x22, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x2e, 0x41, 0x64, 0x64, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x54, 0x6f,
+	0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x64, 0x64, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61,
+	0x63, 0x65, 0x54, 0x6f, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x11, 0x41, 0x64, 0x64, 0x56, 0x69, 0x72, 0x74, 0x75,
+	0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x12, 0x1f, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x2e, 0x41, 0x64, 0x64, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68,
+	0x69, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x2e, 0x41, 0x64, 0x64, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63,
+	0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5b,
+	0x0a, 0x12, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x12, 0x20, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x43, 0x6f, 0x6e,
+	0x6e, 0x65, 0x63, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x43,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x67, 0x0a, 0x16, 0x47,
+	0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x24, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x47, 0x65,
+	0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x2e, 0x47, 0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61,
+	0x63, 0x68, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x6a, 0x0a, 0x17, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x69, 0x72, 0x74,
+	0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x25, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x69, 0x72, 0x74,
+	0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e,
+	0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x5e, 0x0a, 0x13, 0x53, 0x74, 0x61, 0x72, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c,
+	0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x12, 0x21, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e,
+	0x53, 0x74, 0x61, 0x72, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68,
+	0x69, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d,
+	0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x58, 0x0a, 0x11, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x74,
+	0x74, 0x61, 0x63, 0x68, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41,
+	0x74, 0x74, 0x61, 0x63, 0x68, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x52, 0x0a, 0x0f, 0x41, 0x74,
+	0x74, 0x61, 0x63, 0x68, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x1d, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x49, 0x6e, 0x74, 0x65,
+	0x72, 0x66, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x49, 0x6e, 0x74, 0x65, 0x72,
+	0x66, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x49,
+	0x0a, 0x0c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x12, 0x1a,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x72, 0x69,
+	0x64, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5e, 0x0a, 0x13, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x56, 0x4c, 0x41, 0x4e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65,
+	0x12, 0x21, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56,
+	0x4c, 0x41, 0x4e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x56, 0x4c, 0x41, 0x4e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x70, 0x0a, 0x19, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x46, 0x72, 0x6f, 0x6d,
+	0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x12, 0x27, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x46, 0x72,
+	0x6f, 0x6d, 0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x28, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x6e,
+	0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x42, 0x72, 0x69, 0x64, 0x67,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x61, 0x0a, 0x14, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68,
+	0x69, 0x6e, 0x65, 0x12, 0x22, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63,
+	0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x64,
+	0x0a, 0x15, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x23, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e,
+	0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x5b, 0x0a, 0x12, 0x53, 0x74, 0x6f, 0x70, 0x56, 0x69, 0x72, 0x74,
+	0x75, 0x61, 0x6c, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x12, 0x20, 0x2e, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x4d, 0x61,
+	0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c,
+	0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x58, 0x0a, 0x11, 0x44, 0x65, 0x74, 0x61, 0x63, 0x68, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1f, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44,
+	0x65, 0x74, 0x61, 0x63, 0x68, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e,
+	0x44, 0x65, 0x74, 0x61, 0x63, 0x68, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x52, 0x0a, 0x0f, 0x44,
+	0x65, 0x74, 0x61, 0x63, 0x68, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x1d,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44, 0x65, 0x74, 0x61, 0x63, 0x68, 0x49, 0x6e, 0x74,
+	0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x44, 0x65, 0x74, 0x61, 0x63, 0x68, 0x49, 0x6e, 0x74, 0x65,
+	0x72, 0x66, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x49, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x1a, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x47, 0x65, 0x74, 0x4f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x2e, 0x47, 0x65, 0x74, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x0e, 0x4c, 0x69,
+	0x73, 0x74, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1c, 0x2e, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x52, 0x0a, 0x0f, 0x43,
+	0x61, 0x6e, 0x63, 0x65, 0x6c, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x4f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x4f, 0x70, 0x65, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x4c, 0x0a, 0x0d, 0x57, 0x61, 0x69, 0x74, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x1b, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x57, 0x61, 0x69, 0x74, 0x4f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x57, 0x61, 0x69, 0x74, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5b, 0x0a,
+	0x12, 0x52, 0x75, 0x6e, 0x50, 0x72, 0x65, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x43, 0x68, 0x65,
+	0x63, 0x6b, 0x73, 0x12, 0x20, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x52, 0x75, 0x6e, 0x50,
+	0x72, 0x65, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x52, 0x75,
+	0x6e, 0x50, 0x72, 0x65, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x09, 0x5a, 0x07, 0x2e, 0x3b,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_agent_proto_rawDescOnce sync.Once
+	file_agent_proto_rawDescData = file_agent_proto_rawDesc
 )
 
 func file_agent_proto_rawDescGZIP() []byte {
@@ -2844,113 +5464,190 @@ func file_agent_proto_rawDescGZIP() []byte {
 	return file_agent_proto_rawDescData
 }
 
-var file_agent_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 47)
+var file_agent_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 78)
 var file_agent_proto_goTypes = []interface{}{
 	(VirtualMachineState_State)(0),            // 0: agent.VirtualMachineState.State
-	(*VirtualMachineState)(nil),               // 1: agent.VirtualMachineState
-	(*GetISCSIQualifiedNameRequest)(nil),      // 2: agent.GetISCSIQualifiedNameRequest
-	(*GetIPTablesRequest)(nil),                // 3: agent.GetIPTablesRequest
-	(*SetupDefaultSecurityGroupRequest)(nil),  // 4: agent.SetupDefaultSecurityGroupRequest
-	(*AddSecurityGroupRequest)(nil),           // 5: agent.AddSecurityGroupRequest
-	(*GetInterfaceNameRequest)(nil),           // 6: agent.GetInterfaceNameRequest
-	(*AddBridgeRequest)(nil),                  // 7: agent.AddBridgeRequest
-	(*AddVLANInterfaceRequest)(nil),           // 8: agent.AddVLANInterfaceRequest
-	(*AddInterfaceToBridgeRequest)(nil),       // 9: agent.AddInterfaceToBridgeRequest
-	(*AddVirtualMachineRequest)(nil),          // 10: agent.AddVirtualMachineRequest
-	(*ConnectBlockDeviceRequest)(nil),         // 11: agent.ConnectBlockDeviceRequest
-	(*AttachBlockDeviceRequest)(nil),          // 12: agent.AttachBlockDeviceRequest
-	(*AttachInterfaceRequest)(nil),            // 13: agent.AttachInterfaceRequest
-	(*StartVirtualMachineRequest)(nil),        // 14: agent.StartVirtualMachineRequest
-	(*GetVirtualMachineStateRequest)(nil),     // 15: agent.GetVirtualMachineStateRequest
-	(*ListVirtualMachineStateRequest)(nil),    // 16: agent.ListVirtualMachineStateRequest
-	(*DeleteBridgeRequest)(nil),               // 17: agent.DeleteBridgeRequest
-	(*DeleteVLANInterfaceRequest)(nil),        // 18: agent.DeleteVLANInterfaceRequest
-	(*DeleteInterfaceFromBridgeRequest)(nil),  // 19: agent.DeleteInterfaceFromBridgeRequest
-	(*DeleteVirtualMachineRequest)(nil),       // 20: agent.DeleteVirtualMachineRequest
-	(*DisconnectBlockDeviceRequest)(nil),      // 21: agent.DisconnectBlockDeviceRequest
-	(*StopVirtualMachineRequest)(nil),         // 22: agent.StopVirtualMachineRequest
-	(*DetachBlockDeviceRequest)(nil),          // 23: agent.DetachBlockDeviceRequest
-	(*DetachInterfaceRequest)(nil),            // 24: agent.DetachInterfaceRequest
-	(*GetISCSIQualifiedNameResponse)(nil),     // 25: agent.GetISCSIQualifiedNameResponse
-	(*GetIPTablesResponse)(nil),               // 26: agent.GetIPTablesResponse
-	(*SetupDefaultSecurityGroupResponse)(nil), // 27: agent.SetupDefaultSecurityGroupResponse
-	(*AddSecurityGroupResponse)(nil),          // 28: agent.AddSecurityGroupResponse
-	(*GetInterfaceNameResponse)(nil),          // 29: agent.GetInterfaceNameResponse
-	(*AddBridgeResponse)(nil),                 // 30: agent.AddBridgeResponse
-	(*AddVLANInterfaceResponse)(nil),          // 31: agent.AddVLANInterfaceResponse
-	(*AddInterfaceToBridgeResponse)(nil),      // 32: agent.AddInterfaceToBridgeResponse
-	(*AddVirtualMachineResponse)(nil),         // 33: agent.AddVirtualMachineResponse
-	(*ConnectBlockDeviceResponse)(nil),        // 34: agent.ConnectBlockDeviceResponse
-	(*StartVirtualMachineResponse)(nil),       // 35: agent.StartVirtualMachineResponse
-	(*GetVirtualMachineStateResponse)(nil),    // 36: agent.GetVirtualMachineStateResponse
-	(*ListVirtualMachineStateResponse)(nil),   // 37: agent.ListVirtualMachineStateResponse
-	(*AttachBlockDeviceResponse)(nil),         // 38: agent.AttachBlockDeviceResponse
-	(*AttachInterfaceResponse)(nil),           // 39: agent.AttachInterfaceResponse
-	(*DeleteBridgeResponse)(nil),              // 40: agent.DeleteBridgeResponse
-	(*DeleteVLANInterfaceResponse)(nil),       // 41: agent.DeleteVLANInterfaceResponse
-	(*DeleteInterfaceFromBridgeResponse)(nil), // 42: agent.DeleteInterfaceFromBridgeResponse
-	(*DeleteVirtualMachineResponse)(nil),      // 43: agent.DeleteVirtualMachineResponse
-	(*DisconnectBlockDeviceResponse)(nil),     // 44: agent.DisconnectBlockDeviceResponse
-	(*StopVirtualMachineResponse)(nil),        // 45: agent.StopVirtualMachineResponse
-	(*DetachBlockDeviceResponse)(nil),         // 46: agent.DetachBlockDeviceResponse
-	(*DetachInterfaceResponse)(nil),           // 47: agent.DetachInterfaceResponse
+	(Operation_State)(0),                      // 1: agent.Operation.State
+	(SecurityGroupRule_Protocol)(0),           // 2: agent.SecurityGroupRule.Protocol
+	(PreflightCheck_Result)(0),                // 3: agent.PreflightCheck.Result
+	(*VirtualMachineState)(nil),               // 4: agent.VirtualMachineState
+	(*Operation)(nil),                         // 5: agent.Operation
+	(*SecurityGroupRule)(nil),                 // 6: agent.SecurityGroupRule
+	(*RemoteGroup)(nil),                       // 7: agent.RemoteGroup
+	(*IPTablesChain)(nil),                     // 8: agent.IPTablesChain
+	(*IPTablesRule)(nil),                      // 9: agent.IPTablesRule
+	(*PreflightCheck)(nil),                    // 10: agent.PreflightCheck
+	(*GetISCSIQualifiedNameRequest)(nil),      // 11: agent.GetISCSIQualifiedNameRequest
+	(*GetIPTablesRequest)(nil),                // 12: agent.GetIPTablesRequest
+	(*SetupDefaultSecurityGroupRequest)(nil),  // 13: agent.SetupDefaultSecurityGroupRequest
+	(*AddSecurityGroupRequest)(nil),           // 14: agent.AddSecurityGroupRequest
+	(*AllowedAddressPair)(nil),                // 15: agent.AllowedAddressPair
+	(*RemoveSecurityGroupRequest)(nil),        // 16: agent.RemoveSecurityGroupRequest
+	(*UpdateSecurityGroupRulesRequest)(nil),   // 17: agent.UpdateSecurityGroupRulesRequest
+	(*AddAllowedAddressPairsRequest)(nil),     // 18: agent.AddAllowedAddressPairsRequest
+	(*RemoveAllowedAddressPairsRequest)(nil),  // 19: agent.RemoveAllowedAddressPairsRequest
+	(*SetRemoteGroupMembersRequest)(nil),      // 20: agent.SetRemoteGroupMembersRequest
+	(*DeleteRemoteGroupRequest)(nil),          // 21: agent.DeleteRemoteGroupRequest
+	(*ListRemoteGroupsRequest)(nil),           // 22: agent.ListRemoteGroupsRequest
+	(*GetInterfaceNameRequest)(nil),           // 23: agent.GetInterfaceNameRequest
+	(*AddBridgeRequest)(nil),                  // 24: agent.AddBridgeRequest
+	(*AddVLANInterfaceRequest)(nil),           // 25: agent.AddVLANInterfaceRequest
+	(*AddInterfaceToBridgeRequest)(nil),       // 26: agent.AddInterfaceToBridgeRequest
+	(*AddVirtualMachineRequest)(nil),          // 27: agent.AddVirtualMachineRequest
+	(*ConnectBlockDeviceRequest)(nil),         // 28: agent.ConnectBlockDeviceRequest
+	(*AttachBlockDeviceRequest)(nil),          // 29: agent.AttachBlockDeviceRequest
+	(*AttachInterfaceRequest)(nil),            // 30: agent.AttachInterfaceRequest
+	(*StartVirtualMachineRequest)(nil),        // 31: agent.StartVirtualMachineRequest
+	(*GetVirtualMachineStateRequest)(nil),     // 32: agent.GetVirtualMachineStateRequest
+	(*ListVirtualMachineStateRequest)(nil),    // 33: agent.ListVirtualMachineStateRequest
+	(*DeleteBridgeRequest)(nil),               // 34: agent.DeleteBridgeRequest
+	(*DeleteVLANInterfaceRequest)(nil),        // 35: agent.DeleteVLANInterfaceRequest
+	(*DeleteInterfaceFromBridgeRequest)(nil),  // 36: agent.DeleteInterfaceFromBridgeRequest
+	(*DeleteVirtualMachineRequest)(nil),       // 37: agent.DeleteVirtualMachineRequest
+	(*DisconnectBlockDeviceRequest)(nil),      // 38: agent.DisconnectBlockDeviceRequest
+	(*StopVirtualMachineRequest)(nil),         // 39: agent.StopVirtualMachineRequest
+	(*DetachBlockDeviceRequest)(nil),          // 40: agent.DetachBlockDeviceRequest
+	(*DetachInterfaceRequest)(nil),            // 41: agent.DetachInterfaceRequest
+	(*GetOperationRequest)(nil),               // 42: agent.GetOperationRequest
+	(*ListOperationsRequest)(nil),             // 43: agent.ListOperationsRequest
+	(*CancelOperationRequest)(nil),            // 44: agent.CancelOperationRequest
+	(*WaitOperationRequest)(nil),              // 45: agent.WaitOperationRequest
+	(*RunPreflightChecksRequest)(nil),         // 46: agent.RunPreflightChecksRequest
+	(*GetISCSIQualifiedNameResponse)(nil),     // 47: agent.GetISCSIQualifiedNameResponse
+	(*GetIPTablesResponse)(nil),               // 48: agent.GetIPTablesResponse
+	(*SetupDefaultSecurityGroupResponse)(nil), // 49: agent.SetupDefaultSecurityGroupResponse
+	(*AddSecurityGroupResponse)(nil),          // 50: agent.AddSecurityGroupResponse
+	(*RemoveSecurityGroupResponse)(nil),       // 51: agent.RemoveSecurityGroupResponse
+	(*UpdateSecurityGroupRulesResponse)(nil),  // 52: agent.UpdateSecurityGroupRulesResponse
+	(*AddAllowedAddressPairsResponse)(nil),    // 53: agent.AddAllowedAddressPairsResponse
+	(*RemoveAllowedAddressPairsResponse)(nil), // 54: agent.RemoveAllowedAddressPairsResponse
+	(*SetRemoteGroupMembersResponse)(nil),     // 55: agent.SetRemoteGroupMembersResponse
+	(*DeleteRemoteGroupResponse)(nil),         // 56: agent.DeleteRemoteGroupResponse
+	(*ListRemoteGroupsResponse)(nil),          // 57: agent.ListRemoteGroupsResponse
+	(*GetInterfaceNameResponse)(nil),          // 58: agent.GetInterfaceNameResponse
+	(*AddBridgeResponse)(nil),                 // 59: agent.AddBridgeResponse
+	(*AddVLANInterfaceResponse)(nil),          // 60: agent.AddVLANInterfaceResponse
+	(*AddInterfaceToBridgeResponse)(nil),      // 61: agent.AddInterfaceToBridgeResponse
+	(*AddVirtualMachineResponse)(nil),         // 62: agent.AddVirtualMachineResponse
+	(*ConnectBlockDeviceResponse)(nil),        // 63: agent.ConnectBlockDeviceResponse
+	(*StartVirtualMachineResponse)(nil),       // 64: agent.StartVirtualMachineResponse
+	(*GetVirtualMachineStateResponse)(nil),    // 65: agent.GetVirtualMachineStateResponse
+	(*ListVirtualMachineStateResponse)(nil),   // 66: agent.ListVirtualMachineStateResponse
+	(*AttachBlockDeviceResponse)(nil),         // 67: agent.AttachBlockDeviceResponse
+	(*AttachInterfaceResponse)(nil),           // 68: agent.AttachInterfaceResponse
+	(*DeleteBridgeResponse)(nil),              // 69: agent.DeleteBridgeResponse
+	(*DeleteVLANInterfaceResponse)(nil),       // 70: agent.DeleteVLANInterfaceResponse
+	(*DeleteInterfaceFromBridgeResponse)(nil), // 71: agent.DeleteInterfaceFromBridgeResponse
+	(*DeleteVirtualMachineResponse)(nil),      // 72: agent.DeleteVirtualMachineResponse
+	(*DisconnectBlockDeviceResponse)(nil),     // 73: agent.DisconnectBlockDeviceResponse
+	(*StopVirtualMachineResponse)(nil),        // 74: agent.StopVirtualMachineResponse
+	(*DetachBlockDeviceResponse)(nil),         // 75: agent.DetachBlockDeviceResponse
+	(*DetachInterfaceResponse)(nil),           // 76: agent.DetachInterfaceResponse
+	(*GetOperationResponse)(nil),              // 77: agent.GetOperationResponse
+	(*ListOperationsResponse)(nil),            // 78: agent.ListOperationsResponse
+	(*CancelOperationResponse)(nil),           // 79: agent.CancelOperationResponse
+	(*WaitOperationResponse)(nil),             // 80: agent.WaitOperationResponse
+	(*RunPreflightChecksResponse)(nil),        // 81: agent.RunPreflightChecksResponse
 }
 var file_agent_proto_depIdxs = []int32{
 	0,  // 0: agent.VirtualMachineState.state:type_name -> agent.VirtualMachineState.State
-	1,  // 1: agent.GetVirtualMachineStateResponse.state:type_name -> agent.VirtualMachineState
-	1,  // 2: agent.ListVirtualMachineStateResponse.states:type_name -> agent.VirtualMachineState
-	2,  // 3: agent.Agent.GetISCSIQualifiedName:input_type -> agent.GetISCSIQualifiedNameRequest
-	3,  // 4: agent.Agent.GetIPTables:input_type -> agent.GetIPTablesRequest
-	4,  // 5: agent.Agent.SetupDefaultSecurityGroup:input_type -> agent.SetupDefaultSecurityGroupRequest
-	5,  // 6: agent.Agent.AddSecurityGroup:input_type -> agent.AddSecurityGroupRequest
-	6,  // 7: agent.Agent.GetInterfaceName:input_type -> agent.GetInterfaceNameRequest
-	7,  // 8: agent.Agent.AddBridge:input_type -> agent.AddBridgeRequest
-	8,  // 9: agent.Agent.AddVLANInterface:input_type -> agent.AddVLANInterfaceRequest
-	9,  // 10: agent.Agent.AddInterfaceToBridge:input_type -> agent.AddInterfaceToBridgeRequest
-	10, // 11: agent.Agent.AddVirtualMachine:input_type -> agent.AddVirtualMachineRequest
-	11, // 12: agent.Agent.ConnectBlockDevice:input_type -> agent.ConnectBlockDeviceRequest
-	15, // 13: agent.Agent.GetVirtualMachineState:input_type -> agent.GetVirtualMachineStateRequest
-	16, // 14: agent.Agent.ListVirtualMachineState:input_type -> agent.ListVirtualMachineStateRequest
-	14, // 15: agent.Agent.StartVirtualMachine:input_type -> agent.StartVirtualMachineRequest
-	12, // 16: agent.Agent.AttachBlockDevice:input_type -> agent.AttachBlockDeviceRequest
-	13, // 17: agent.Agent.AttachInterface:input_type -> agent.AttachInterfaceRequest
-	17, // 18: agent.Agent.DeleteBridge:input_type -> agent.DeleteBridgeRequest
-	18, // 19: agent.Agent.DeleteVLANInterface:input_type -> agent.DeleteVLANInterfaceRequest
-	19, // 20: agent.Agent.DeleteInterfaceFromBridge:input_type -> agent.DeleteInterfaceFromBridgeRequest
-	20, // 21: agent.Agent.DeleteVirtualMachine:input_type -> agent.DeleteVirtualMachineRequest
-	21, // 22: agent.Agent.DisconnectBlockDevice:input_type -> agent.DisconnectBlockDeviceRequest
-	22, // 23: agent.Agent.StopVirtualMachine:input_type -> agent.StopVirtualMachineRequest
-	23, // 24: agent.Agent.DetachBlockDevice:input_type -> agent.DetachBlockDeviceRequest
-	24, // 25: agent.Agent.DetachInterface:input_type -> agent.DetachInterfaceRequest
-	25, // 26: agent.Agent.GetISCSIQualifiedName:output_type -> agent.GetISCSIQualifiedNameResponse
-	26, // 27: agent.Agent.GetIPTables:output_type -> agent.GetIPTablesResponse
-	27, // 28: agent.Agent.SetupDefaultSecurityGroup:output_type -> agent.SetupDefaultSecurityGroupResponse
-	28, // 29: agent.Agent.AddSecurityGroup:output_type -> agent.AddSecurityGroupResponse
-	29, // 30: agent.Agent.GetInterfaceName:output_type -> agent.GetInterfaceNameResponse
-	30, // 31: agent.Agent.AddBridge:output_type -> agent.AddBridgeResponse
-	31, // 32: agent.Agent.AddVLANInterface:output_type -> agent.AddVLANInterfaceResponse
-	32, // 33: agent.Agent.AddInterfaceToBridge:output_type -> agent.AddInterfaceToBridgeResponse
-	33, // 34: agent.Agent.AddVirtualMachine:output_type -> agent.AddVirtualMachineResponse
-	34, // 35: agent.Agent.ConnectBlockDevice:output_type -> agent.ConnectBlockDeviceResponse
-	36, // 36: agent.Agent.GetVirtualMachineState:output_type -> agent.GetVirtualMachineStateResponse
-	37, // 37: agent.Agent.ListVirtualMachineState:output_type -> agent.ListVirtualMachineStateResponse
-	35, // 38: agent.Agent.StartVirtualMachine:output_type -> agent.StartVirtualMachineResponse
-	38, // 39: agent.Agent.AttachBlockDevice:output_type -> agent.AttachBlockDeviceResponse
-	39, // 40: agent.Agent.AttachInterface:output_type -> agent.AttachInterfaceResponse
-	40, // 41: agent.Agent.DeleteBridge:output_type -> agent.DeleteBridgeResponse
-	41, // 42: agent.Agent.DeleteVLANInterface:output_type -> agent.DeleteVLANInterfaceResponse
-	42, // 43: agent.Agent.DeleteInterfaceFromBridge:output_type -> agent.DeleteInterfaceFromBridgeResponse
-	43, // 44: agent.Agent.DeleteVirtualMachine:output_type -> agent.DeleteVirtualMachineResponse
-	44, // 45: agent.Agent.DisconnectBlockDevice:output_type -> agent.DisconnectBlockDeviceResponse
-	45, // 46: agent.Agent.StopVirtualMachine:output_type -> agent.StopVirtualMachineResponse
-	46, // 47: agent.Agent.DetachBlockDevice:output_type -> agent.DetachBlockDeviceResponse
-	47, // 48: agent.Agent.DetachInterface:output_type -> agent.DetachInterfaceResponse
-	26, // [26:49] is the sub-list for method output_type
-	3,  // [3:26] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	1,  // 1: agent.Operation.state:type_name -> agent.Operation.State
+	63, // 2: agent.Operation.connect_block_device:type_name -> agent.ConnectBlockDeviceResponse
+	73, // 3: agent.Operation.disconnect_block_device:type_name -> agent.DisconnectBlockDeviceResponse
+	2,  // 4: agent.SecurityGroupRule.protocol:type_name -> agent.SecurityGroupRule.Protocol
+	9,  // 5: agent.IPTablesChain.rules:type_name -> agent.IPTablesRule
+	3,  // 6: agent.PreflightCheck.result:type_name -> agent.PreflightCheck.Result
+	6,  // 7: agent.AddSecurityGroupRequest.ingress_rules:type_name -> agent.SecurityGroupRule
+	6,  // 8: agent.AddSecurityGroupRequest.egress_rules:type_name -> agent.SecurityGroupRule
+	15, // 9: agent.AddSecurityGroupRequest.allowed_address_pairs:type_name -> agent.AllowedAddressPair
+	6,  // 10: agent.UpdateSecurityGroupRulesRequest.ingress_rules:type_name -> agent.SecurityGroupRule
+	6,  // 11: agent.UpdateSecurityGroupRulesRequest.egress_rules:type_name -> agent.SecurityGroupRule
+	15, // 12: agent.AddAllowedAddressPairsRequest.allowed_address_pairs:type_name -> agent.AllowedAddressPair
+	15, // 13: agent.RemoveAllowedAddressPairsRequest.allowed_address_pairs:type_name -> agent.AllowedAddressPair
+	8,  // 14: agent.GetIPTablesResponse.chains:type_name -> agent.IPTablesChain
+	7,  // 15: agent.ListRemoteGroupsResponse.remote_groups:type_name -> agent.RemoteGroup
+	4,  // 16: agent.GetVirtualMachineStateResponse.state:type_name -> agent.VirtualMachineState
+	4,  // 17: agent.ListVirtualMachineStateResponse.states:type_name -> agent.VirtualMachineState
+	5,  // 18: agent.GetOperationResponse.operation:type_name -> agent.Operation
+	5,  // 19: agent.ListOperationsResponse.operations:type_name -> agent.Operation
+	5,  // 20: agent.WaitOperationResponse.operation:type_name -> agent.Operation
+	10, // 21: agent.RunPreflightChecksResponse.checks:type_name -> agent.PreflightCheck
+	11, // 22: agent.Agent.GetISCSIQualifiedName:input_type -> agent.GetISCSIQualifiedNameRequest
+	12, // 23: agent.Agent.GetIPTables:input_type -> agent.GetIPTablesRequest
+	13, // 24: agent.Agent.SetupDefaultSecurityGroup:input_type -> agent.SetupDefaultSecurityGroupRequest
+	14, // 25: agent.Agent.AddSecurityGroup:input_type -> agent.AddSecurityGroupRequest
+	16, // 26: agent.Agent.RemoveSecurityGroup:input_type -> agent.RemoveSecurityGroupRequest
+	17, // 27: agent.Agent.UpdateSecurityGroupRules:input_type -> agent.UpdateSecurityGroupRulesRequest
+	18, // 28: agent.Agent.AddAllowedAddressPairs:input_type -> agent.AddAllowedAddressPairsRequest
+	19, // 29: agent.Agent.RemoveAllowedAddressPairs:input_type -> agent.RemoveAllowedAddressPairsRequest
+	20, // 30: agent.Agent.SetRemoteGroupMembers:input_type -> agent.SetRemoteGroupMembersRequest
+	21, // 31: agent.Agent.DeleteRemoteGroup:input_type -> agent.DeleteRemoteGroupRequest
+	22, // 32: agent.Agent.ListRemoteGroups:input_type -> agent.ListRemoteGroupsRequest
+	23, // 33: agent.Agent.GetInterfaceName:input_type -> agent.GetInterfaceNameRequest
+	24, // 34: agent.Agent.AddBridge:input_type -> agent.AddBridgeRequest
+	25, // 35: agent.Agent.AddVLANInterface:input_type -> agent.AddVLANInterfaceRequest
+	26, // 36: agent.Agent.AddInterfaceToBridge:input_type -> agent.AddInterfaceToBridgeRequest
+	27, // 37: agent.Agent.AddVirtualMachine:input_type -> agent.AddVirtualMachineRequest
+	28, // 38: agent.Agent.ConnectBlockDevice:input_type -> agent.ConnectBlockDeviceRequest
+	32, // 39: agent.Agent.GetVirtualMachineState:input_type -> agent.GetVirtualMachineStateRequest
+	33, // 40: agent.Agent.ListVirtualMachineState:input_type -> agent.ListVirtualMachineStateRequest
+	31, // 41: agent.Agent.StartVirtualMachine:input_type -> agent.StartVirtualMachineRequest
+	29, // 42: agent.Agent.AttachBlockDevice:input_type -> agent.AttachBlockDeviceRequest
+	30, // 43: agent.Agent.AttachInterface:input_type -> agent.AttachInterfaceRequest
+	34, // 44: agent.Agent.DeleteBridge:input_type -> agent.DeleteBridgeRequest
+	35, // 45: agent.Agent.DeleteVLANInterface:input_type -> agent.DeleteVLANInterfaceRequest
+	36, // 46: agent.Agent.DeleteInterfaceFromBridge:input_type -> agent.DeleteInterfaceFromBridgeRequest
+	37, // 47: agent.Agent.DeleteVirtualMachine:input_type -> agent.DeleteVirtualMachineRequest
+	38, // 48: agent.Agent.DisconnectBlockDevice:input_type -> agent.DisconnectBlockDeviceRequest
+	39, // 49: agent.Agent.StopVirtualMachine:input_type -> agent.StopVirtualMachineRequest
+	40, // 50: agent.Agent.DetachBlockDevice:input_type -> agent.DetachBlockDeviceRequest
+	41, // 51: agent.Agent.DetachInterface:input_type -> agent.DetachInterfaceRequest
+	42, // 52: agent.Agent.GetOperation:input_type -> agent.GetOperationRequest
+	43, // 53: agent.Agent.ListOperations:input_type -> agent.ListOperationsRequest
+	44, // 54: agent.Agent.CancelOperation:input_type -> agent.CancelOperationRequest
+	45, // 55: agent.Agent.WaitOperation:input_type -> agent.WaitOperationRequest
+	46, // 56: agent.Agent.RunPreflightChecks:input_type -> agent.RunPreflightChecksRequest
+	47, // 57: agent.Agent.GetISCSIQualifiedName:output_type -> agent.GetISCSIQualifiedNameResponse
+	48, // 58: agent.Agent.GetIPTables:output_type -> agent.GetIPTablesResponse
+	49, // 59: agent.Agent.SetupDefaultSecurityGroup:output_type -> agent.SetupDefaultSecurityGroupResponse
+	50, // 60: agent.Agent.AddSecurityGroup:output_type -> agent.AddSecurityGroupResponse
+	51, // 61: agent.Agent.RemoveSecurityGroup:output_type -> agent.RemoveSecurityGroupResponse
+	52, // 62: agent.Agent.UpdateSecurityGroupRules:output_type -> agent.UpdateSecurityGroupRulesResponse
+	53, // 63: agent.Agent.AddAllowedAddressPairs:output_type -> agent.AddAllowedAddressPairsResponse
+	54, // 64: agent.Agent.RemoveAllowedAddressPairs:output_type -> agent.RemoveAllowedAddressPairsResponse
+	55, // 65: agent.Agent.SetRemoteGroupMembers:output_type -> agent.SetRemoteGroupMembersResponse
+	56, // 66: agent.Agent.DeleteRemoteGroup:output_type -> agent.DeleteRemoteGroupResponse
+	57, // 67: agent.Agent.ListRemoteGroups:output_type -> agent.ListRemoteGroupsResponse
+	58, // 68: agent.Agent.GetInterfaceName:output_type -> agent.GetInterfaceNameResponse
+	59, // 69: agent.Agent.AddBridge:output_type -> agent.AddBridgeResponse
+	60, // 70: agent.Agent.AddVLANInterface:output_type -> agent.AddVLANInterfaceResponse
+	61, // 71: agent.Agent.AddInterfaceToBridge:output_type -> agent.AddInterfaceToBridgeResponse
+	62, // 72: agent.Agent.AddVirtualMachine:output_type -> agent.AddVirtualMachineResponse
+	63, // 73: agent.Agent.ConnectBlockDevice:output_type -> agent.ConnectBlockDeviceResponse
+	65, // 74: agent.Agent.GetVirtualMachineState:output_type -> agent.GetVirtualMachineStateResponse
+	66, // 75: agent.Agent.ListVirtualMachineState:output_type -> agent.ListVirtualMachineStateResponse
+	64, // 76: agent.Agent.StartVirtualMachine:output_type -> agent.StartVirtualMachineResponse
+	67, // 77: agent.Agent.AttachBlockDevice:output_type -> agent.AttachBlockDeviceResponse
+	68, // 78: agent.Agent.AttachInterface:output_type -> agent.AttachInterfaceResponse
+	69, // 79: agent.Agent.DeleteBridge:output_type -> agent.DeleteBridgeResponse
+	70, // 80: agent.Agent.DeleteVLANInterface:output_type -> agent.DeleteVLANInterfaceResponse
+	71, // 81: agent.Agent.DeleteInterfaceFromBridge:output_type -> agent.DeleteInterfaceFromBridgeResponse
+	72, // 82: agent.Agent.DeleteVirtualMachine:output_type -> agent.DeleteVirtualMachineResponse
+	73, // 83: agent.Agent.DisconnectBlockDevice:output_type -> agent.DisconnectBlockDeviceResponse
+	74, // 84: agent.Agent.StopVirtualMachine:output_type -> agent.StopVirtualMachineResponse
+	75, // 85: agent.Agent.DetachBlockDevice:output_type -> agent.DetachBlockDeviceResponse
+	76, // 86: agent.Agent.DetachInterface:output_type -> agent.DetachInterfaceResponse
+	77, // 87: agent.Agent.GetOperation:output_type -> agent.GetOperationResponse
+	78, // 88: agent.Agent.ListOperations:output_type -> agent.ListOperationsResponse
+	79, // 89: agent.Agent.CancelOperation:output_type -> agent.CancelOperationResponse
+	80, // 90: agent.Agent.WaitOperation:output_type -> agent.WaitOperationResponse
+	81, // 91: agent.Agent.RunPreflightChecks:output_type -> agent.RunPreflightChecksResponse
+	57, // [57:92] is the sub-list for method output_type
+	22, // [22:57] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
 }
 
 func init() { file_agent_proto_init() }
@@ -2971,8 +5668,272 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetISCSIQualifiedNameRequest); i {
+		file_agent_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Operation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SecurityGroupRule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoteGroup); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IPTablesChain); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IPTablesRule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PreflightCheck); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetISCSIQualifiedNameRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetIPTablesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetupDefaultSecurityGroupRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddSecurityGroupRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AllowedAddressPair); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveSecurityGroupRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateSecurityGroupRulesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddAllowedAddressPairsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveAllowedAddressPairsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetRemoteGroupMembersRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteRemoteGroupRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListRemoteGroupsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetInterfaceNameRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddBridgeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddVLANInterfaceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddInterfaceToBridgeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddVirtualMachineRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2983,8 +5944,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetIPTablesRequest); i {
+		file_agent_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConnectBlockDeviceRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2995,8 +5956,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SetupDefaultSecurityGroupRequest); i {
+		file_agent_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AttachBlockDeviceRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3007,8 +5968,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddSecurityGroupRequest); i {
+		file_agent_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AttachInterfaceRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3019,8 +5980,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetInterfaceNameRequest); i {
+		file_agent_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StartVirtualMachineRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3031,8 +5992,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddBridgeRequest); i {
+		file_agent_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetVirtualMachineStateRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3043,8 +6004,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddVLANInterfaceRequest); i {
+		file_agent_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListVirtualMachineStateRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3055,8 +6016,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddInterfaceToBridgeRequest); i {
+		file_agent_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteBridgeRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3067,8 +6028,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddVirtualMachineRequest); i {
+		file_agent_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteVLANInterfaceRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3079,8 +6040,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ConnectBlockDeviceRequest); i {
+		file_agent_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteInterfaceFromBridgeRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3091,8 +6052,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AttachBlockDeviceRequest); i {
+		file_agent_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteVirtualMachineRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3103,8 +6064,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AttachInterfaceRequest); i {
+		file_agent_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DisconnectBlockDeviceRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3115,8 +6076,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StartVirtualMachineRequest); i {
+		file_agent_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StopVirtualMachineRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3127,8 +6088,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetVirtualMachineStateRequest); i {
+		file_agent_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DetachBlockDeviceRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3139,8 +6100,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListVirtualMachineStateRequest); i {
+		file_agent_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DetachInterfaceRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3151,8 +6112,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteBridgeRequest); i {
+		file_agent_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOperationRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3163,8 +6124,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteVLANInterfaceRequest); i {
+		file_agent_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListOperationsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3175,8 +6136,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteInterfaceFromBridgeRequest); i {
+		file_agent_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelOperationRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3187,8 +6148,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteVirtualMachineRequest); i {
+		file_agent_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WaitOperationRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3199,8 +6160,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DisconnectBlockDeviceRequest); i {
+		file_agent_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunPreflightChecksRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3211,8 +6172,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StopVirtualMachineRequest); i {
+		file_agent_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetISCSIQualifiedNameResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3223,8 +6184,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DetachBlockDeviceRequest); i {
+		file_agent_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetIPTablesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3235,8 +6196,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DetachInterfaceRequest); i {
+		file_agent_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetupDefaultSecurityGroupResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3247,8 +6208,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetISCSIQualifiedNameResponse); i {
+		file_agent_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddSecurityGroupResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3259,8 +6220,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetIPTablesResponse); i {
+		file_agent_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveSecurityGroupResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3271,8 +6232,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SetupDefaultSecurityGroupResponse); i {
+		file_agent_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateSecurityGroupRulesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3283,8 +6244,8 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddSecurityGroupResponse); i {
+		file_agent_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddAllowedAddressPairsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3295,7 +6256,55 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+		file_agent_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveAllowedAddressPairsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetRemoteGroupMembersResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteRemoteGroupResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListRemoteGroupsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GetInterfaceNameResponse); i {
 			case 0:
 				return &v.state
@@ -3307,7 +6316,7 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+		file_agent_proto_msgTypes[55].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*AddBridgeResponse); i {
 			case 0:
 				return &v.state
@@ -3319,7 +6328,7 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+		file_agent_proto_msgTypes[56].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*AddVLANInterfaceResponse); i {
 			case 0:
 				return &v.state
@@ -3331,7 +6340,7 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+		file_agent_proto_msgTypes[57].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*AddInterfaceToBridgeResponse); i {
 			case 0:
 				return &v.state
@@ -3343,7 +6352,7 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+		file_agent_proto_msgTypes[58].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*AddVirtualMachineResponse); i {
 			case 0:
 				return &v.state
@@ -3355,7 +6364,7 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+		file_agent_proto_msgTypes[59].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ConnectBlockDeviceResponse); i {
 			case 0:
 				return &v.state
@@ -3367,7 +6376,7 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+		file_agent_proto_msgTypes[60].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*StartVirtualMachineResponse); i {
 			case 0:
 				return &v.state
@@ -3379,7 +6388,7 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+		file_agent_proto_msgTypes[61].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GetVirtualMachineStateResponse); i {
 			case 0:
 				return &v.state
@@ -3391,7 +6400,7 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+		file_agent_proto_msgTypes[62].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ListVirtualMachineStateResponse); i {
 			case 0:
 				return &v.state
@@ -3403,7 +6412,7 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+		file_agent_proto_msgTypes[63].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*AttachBlockDeviceResponse); i {
 			case 0:
 				return &v.state
@@ -3415,7 +6424,7 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+		file_agent_proto_msgTypes[64].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*AttachInterfaceResponse); i {
 			case 0:
 				return &v.state
@@ -3427,7 +6436,7 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+		file_agent_proto_msgTypes[65].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DeleteBridgeResponse); i {
 			case 0:
 				return &v.state
@@ -3439,7 +6448,7 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+		file_agent_proto_msgTypes[66].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DeleteVLANInterfaceResponse); i {
 			case 0:
 				return &v.state
@@ -3451,7 +6460,7 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+		file_agent_proto_msgTypes[67].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DeleteInterfaceFromBridgeResponse); i {
 			case 0:
 				return &v.state
@@ -3463,7 +6472,7 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+		file_agent_proto_msgTypes[68].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DeleteVirtualMachineResponse); i {
 			case 0:
 				return &v.state
@@ -3475,7 +6484,7 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+		file_agent_proto_msgTypes[69].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DisconnectBlockDeviceResponse); i {
 			case 0:
 				return &v.state
@@ -3487,7 +6496,7 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+		file_agent_proto_msgTypes[70].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*StopVirtualMachineResponse); i {
 			case 0:
 				return &v.state
@@ -3499,7 +6508,7 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+		file_agent_proto_msgTypes[71].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DetachBlockDeviceResponse); i {
 			case 0:
 				return &v.state
@@ -3511,7 +6520,7 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
-		file_agent_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+		file_agent_proto_msgTypes[72].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DetachInterfaceResponse); i {
 			case 0:
 				return &v.state
@@ -3523,14 +6532,78 @@ func file_agent_proto_init() {
 				return nil
 			}
 		}
+		file_agent_proto_msgTypes[73].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOperationResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[74].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListOperationsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[75].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelOperationResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[76].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WaitOperationResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_agent_proto_msgTypes[77].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunPreflightChecksResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_agent_proto_msgTypes[1].OneofWrappers = []interface{}{
+		(*Operation_ConnectBlockDevice)(nil),
+		(*Operation_DisconnectBlockDevice)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_agent_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   47,
+			NumEnums:      4,
+			NumMessages:   78,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -3561,6 +6634,13 @@ type AgentClient interface {
 	GetIPTables(ctx context.Context, in *GetIPTablesRequest, opts ...grpc.CallOption) (*GetIPTablesResponse, error)
 	SetupDefaultSecurityGroup(ctx context.Context, in *SetupDefaultSecurityGroupRequest, opts ...grpc.CallOption) (*SetupDefaultSecurityGroupResponse, error)
 	AddSecurityGroup(ctx context.Context, in *AddSecurityGroupRequest, opts ...grpc.CallOption) (*AddSecurityGroupResponse, error)
+	RemoveSecurityGroup(ctx context.Context, in *RemoveSecurityGroupRequest, opts ...grpc.CallOption) (*RemoveSecurityGroupResponse, error)
+	UpdateSecurityGroupRules(ctx context.Context, in *UpdateSecurityGroupRulesRequest, opts ...grpc.CallOption) (*UpdateSecurityGroupRulesResponse, error)
+	AddAllowedAddressPairs(ctx context.Context, in *AddAllowedAddressPairsRequest, opts ...grpc.CallOption) (*AddAllowedAddressPairsResponse, error)
+	RemoveAllowedAddressPairs(ctx context.Context, in *RemoveAllowedAddressPairsRequest, opts ...grpc.CallOption) (*RemoveAllowedAddressPairsResponse, error)
+	SetRemoteGroupMembers(ctx context.Context, in *SetRemoteGroupMembersRequest, opts ...grpc.CallOption) (*SetRemoteGroupMembersResponse, error)
+	DeleteRemoteGroup(ctx context.Context, in *DeleteRemoteGroupRequest, opts ...grpc.CallOption) (*DeleteRemoteGroupResponse, error)
+	ListRemoteGroups(ctx context.Context, in *ListRemoteGroupsRequest, opts ...grpc.CallOption) (*ListRemoteGroupsResponse, error)
 	GetInterfaceName(ctx context.Context, in *GetInterfaceNameRequest, opts ...grpc.CallOption) (*GetInterfaceNameResponse, error)
 	AddBridge(ctx context.Context, in *AddBridgeRequest, opts ...grpc.CallOption) (*AddBridgeResponse, error)
 	AddVLANInterface(ctx context.Context, in *AddVLANInterfaceRequest, opts ...grpc.CallOption) (*AddVLANInterfaceResponse, error)
@@ -3580,46 +6660,114 @@ type AgentClient interface {
 	StopVirtualMachine(ctx context.Context, in *StopVirtualMachineRequest, opts ...grpc.CallOption) (*StopVirtualMachineResponse, error)
 	DetachBlockDevice(ctx context.Context, in *DetachBlockDeviceRequest, opts ...grpc.CallOption) (*DetachBlockDeviceResponse, error)
 	DetachInterface(ctx context.Context, in *DetachInterfaceRequest, opts ...grpc.CallOption) (*DetachInterfaceResponse, error)
+	GetOperation(ctx context.Context, in *GetOperationRequest, opts ...grpc.CallOption) (*GetOperationResponse, error)
+	ListOperations(ctx context.Context, in *ListOperationsRequest, opts ...grpc.CallOption) (*ListOperationsResponse, error)
+	CancelOperation(ctx context.Context, in *CancelOperationRequest, opts ...grpc.CallOption) (*CancelOperationResponse, error)
+	WaitOperation(ctx context.Context, in *WaitOperationRequest, opts ...grpc.CallOption) (*WaitOperationResponse, error)
+	RunPreflightChecks(ctx context.Context, in *RunPreflightChecksRequest, opts ...grpc.CallOption) (*RunPreflightChecksResponse, error)
+}
+
+type agentClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAgentClient(cc grpc.ClientConnInterface) AgentClient {
+	return &agentClient{cc}
+}
+
+func (c *agentClient) GetISCSIQualifiedName(ctx context.Context, in *GetISCSIQualifiedNameRequest, opts ...grpc.CallOption) (*GetISCSIQualifiedNameResponse, error) {
+	out := new(GetISCSIQualifiedNameResponse)
+	err := c.cc.Invoke(ctx, "/agent.Agent/GetISCSIQualifiedName", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) GetIPTables(ctx context.Context, in *GetIPTablesRequest, opts ...grpc.CallOption) (*GetIPTablesResponse, error) {
+	out := new(GetIPTablesResponse)
+	err := c.cc.Invoke(ctx, "/agent.Agent/GetIPTables", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) SetupDefaultSecurityGroup(ctx context.Context, in *SetupDefaultSecurityGroupRequest, opts ...grpc.CallOption) (*SetupDefaultSecurityGroupResponse, error) {
+	out := new(SetupDefaultSecurityGroupResponse)
+	err := c.cc.Invoke(ctx, "/agent.Agent/SetupDefaultSecurityGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) AddSecurityGroup(ctx context.Context, in *AddSecurityGroupRequest, opts ...grpc.CallOption) (*AddSecurityGroupResponse, error) {
+	out := new(AddSecurityGroupResponse)
+	err := c.cc.Invoke(ctx, "/agent.Agent/AddSecurityGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-type agentClient struct {
-	cc grpc.ClientConnInterface
+func (c *agentClient) RemoveSecurityGroup(ctx context.Context, in *RemoveSecurityGroupRequest, opts ...grpc.CallOption) (*RemoveSecurityGroupResponse, error) {
+	out := new(RemoveSecurityGroupResponse)
+	err := c.cc.Invoke(ctx, "/agent.Agent/RemoveSecurityGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func NewAgentClient(cc grpc.ClientConnInterface) AgentClient {
-	return &agentClient{cc}
+func (c *agentClient) UpdateSecurityGroupRules(ctx context.Context, in *UpdateSecurityGroupRulesRequest, opts ...grpc.CallOption) (*UpdateSecurityGroupRulesResponse, error) {
+	out := new(UpdateSecurityGroupRulesResponse)
+	err := c.cc.Invoke(ctx, "/agent.Agent/UpdateSecurityGroupRules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (c *agentClient) GetISCSIQualifiedName(ctx context.Context, in *GetISCSIQualifiedNameRequest, opts ...grpc.CallOption) (*GetISCSIQualifiedNameResponse, error) {
-	out := new(GetISCSIQualifiedNameResponse)
-	err := c.cc.Invoke(ctx, "/agent.Agent/GetISCSIQualifiedName", in, out, opts...)
+func (c *agentClient) AddAllowedAddressPairs(ctx context.Context, in *AddAllowedAddressPairsRequest, opts ...grpc.CallOption) (*AddAllowedAddressPairsResponse, error) {
+	out := new(AddAllowedAddressPairsResponse)
+	err := c.cc.Invoke(ctx, "/agent.Agent/AddAllowedAddressPairs", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *agentClient) GetIPTables(ctx context.Context, in *GetIPTablesRequest, opts ...grpc.CallOption) (*GetIPTablesResponse, error) {
-	out := new(GetIPTablesResponse)
-	err := c.cc.Invoke(ctx, "/agent.Agent/GetIPTables", in, out, opts...)
+func (c *agentClient) RemoveAllowedAddressPairs(ctx context.Context, in *RemoveAllowedAddressPairsRequest, opts ...grpc.CallOption) (*RemoveAllowedAddressPairsResponse, error) {
+	out := new(RemoveAllowedAddressPairsResponse)
+	err := c.cc.Invoke(ctx, "/agent.Agent/RemoveAllowedAddressPairs", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *agentClient) SetupDefaultSecurityGroup(ctx context.Context, in *SetupDefaultSecurityGroupRequest, opts ...grpc.CallOption) (*SetupDefaultSecurityGroupResponse, error) {
-	out := new(SetupDefaultSecurityGroupResponse)
-	err := c.cc.Invoke(ctx, "/agent.Agent/SetupDefaultSecurityGroup", in, out, opts...)
+func (c *agentClient) SetRemoteGroupMembers(ctx context.Context, in *SetRemoteGroupMembersRequest, opts ...grpc.CallOption) (*SetRemoteGroupMembersResponse, error) {
+	out := new(SetRemoteGroupMembersResponse)
+	err := c.cc.Invoke(ctx, "/agent.Agent/SetRemoteGroupMembers", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *agentClient) AddSecurityGroup(ctx context.Context, in *AddSecurityGroupRequest, opts ...grpc.CallOption) (*AddSecurityGroupResponse, error) {
-	out := new(AddSecurityGroupResponse)
-	err := c.cc.Invoke(ctx, "/agent.Agent/AddSecurityGroup", in, out, opts...)
+func (c *agentClient) DeleteRemoteGroup(ctx context.Context, in *DeleteRemoteGroupRequest, opts ...grpc.CallOption) (*DeleteRemoteGroupResponse, error) {
+	out := new(DeleteRemoteGroupResponse)
+	err := c.cc.Invoke(ctx, "/agent.Agent/DeleteRemoteGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) ListRemoteGroups(ctx context.Context, in *ListRemoteGroupsRequest, opts ...grpc.CallOption) (*ListRemoteGroupsResponse, error) {
+	out := new(ListRemoteGroupsResponse)
+	err := c.cc.Invoke(ctx, "/agent.Agent/ListRemoteGroups", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -3797,12 +6945,64 @@ func (c *agentClient) DetachInterface(ctx context.Context, in *DetachInterfaceRe
 	return out, nil
 }
 
+func (c *agentClient) GetOperation(ctx context.Context, in *GetOperationRequest, opts ...grpc.CallOption) (*GetOperationResponse, error) {
+	out := new(GetOperationResponse)
+	err := c.cc.Invoke(ctx, "/agent.Agent/GetOperation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) ListOperations(ctx context.Context, in *ListOperationsRequest, opts ...grpc.CallOption) (*ListOperationsResponse, error) {
+	out := new(ListOperationsResponse)
+	err := c.cc.Invoke(ctx, "/agent.Agent/ListOperations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) CancelOperation(ctx context.Context, in *CancelOperationRequest, opts ...grpc.CallOption) (*CancelOperationResponse, error) {
+	out := new(CancelOperationResponse)
+	err := c.cc.Invoke(ctx, "/agent.Agent/CancelOperation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) WaitOperation(ctx context.Context, in *WaitOperationRequest, opts ...grpc.CallOption) (*WaitOperationResponse, error) {
+	out := new(WaitOperationResponse)
+	err := c.cc.Invoke(ctx, "/agent.Agent/WaitOperation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) RunPreflightChecks(ctx context.Context, in *RunPreflightChecksRequest, opts ...grpc.CallOption) (*RunPreflightChecksResponse, error) {
+	out := new(RunPreflightChecksResponse)
+	err := c.cc.Invoke(ctx, "/agent.Agent/RunPreflightChecks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AgentServer is the server API for Agent service.
 type AgentServer interface {
 	GetISCSIQualifiedName(context.Context, *GetISCSIQualifiedNameRequest) (*GetISCSIQualifiedNameResponse, error)
 	GetIPTables(context.Context, *GetIPTablesRequest) (*GetIPTablesResponse, error)
 	SetupDefaultSecurityGroup(context.Context, *SetupDefaultSecurityGroupRequest) (*SetupDefaultSecurityGroupResponse, error)
 	AddSecurityGroup(context.Context, *AddSecurityGroupRequest) (*AddSecurityGroupResponse, error)
+	RemoveSecurityGroup(context.Context, *RemoveSecurityGroupRequest) (*RemoveSecurityGroupResponse, error)
+	UpdateSecurityGroupRules(context.Context, *UpdateSecurityGroupRulesRequest) (*UpdateSecurityGroupRulesResponse, error)
+	AddAllowedAddressPairs(context.Context, *AddAllowedAddressPairsRequest) (*AddAllowedAddressPairsResponse, error)
+	RemoveAllowedAddressPairs(context.Context, *RemoveAllowedAddressPairsRequest) (*RemoveAllowedAddressPairsResponse, error)
+	SetRemoteGroupMembers(context.Context, *SetRemoteGroupMembersRequest) (*SetRemoteGroupMembersResponse, error)
+	DeleteRemoteGroup(context.Context, *DeleteRemoteGroupRequest) (*DeleteRemoteGroupResponse, error)
+	ListRemoteGroups(context.Context, *ListRemoteGroupsRequest) (*ListRemoteGroupsResponse, error)
 	GetInterfaceName(context.Context, *GetInterfaceNameRequest) (*GetInterfaceNameResponse, error)
 	AddBridge(context.Context, *AddBridgeRequest) (*AddBridgeResponse, error)
 	AddVLANInterface(context.Context, *AddVLANInterfaceRequest) (*AddVLANInterfaceResponse, error)
@@ -3822,6 +7022,11 @@ type AgentServer interface {
 	StopVirtualMachine(context.Context, *StopVirtualMachineRequest) (*StopVirtualMachineResponse, error)
 	DetachBlockDevice(context.Context, *DetachBlockDeviceRequest) (*DetachBlockDeviceResponse, error)
 	DetachInterface(context.Context, *DetachInterfaceRequest) (*DetachInterfaceResponse, error)
+	GetOperation(context.Context, *GetOperationRequest) (*GetOperationResponse, error)
+	ListOperations(context.Context, *ListOperationsRequest) (*ListOperationsResponse, error)
+	CancelOperation(context.Context, *CancelOperationRequest) (*CancelOperationResponse, error)
+	WaitOperation(context.Context, *WaitOperationRequest) (*WaitOperationResponse, error)
+	RunPreflightChecks(context.Context, *RunPreflightChecksRequest) (*RunPreflightChecksResponse, error)
 }
 
 // UnimplementedAgentServer can be embedded to have forward compatible implementations.
@@ -3840,6 +7045,27 @@ func (*UnimplementedAgentServer) SetupDefaultSecurityGroup(context.Context, *Set
 func (*UnimplementedAgentServer) AddSecurityGroup(context.Context, *AddSecurityGroupRequest) (*AddSecurityGroupResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddSecurityGroup not implemented")
 }
+func (*UnimplementedAgentServer) RemoveSecurityGroup(context.Context, *RemoveSecurityGroupRequest) (*RemoveSecurityGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveSecurityGroup not implemented")
+}
+func (*UnimplementedAgentServer) UpdateSecurityGroupRules(context.Context, *UpdateSecurityGroupRulesRequest) (*UpdateSecurityGroupRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSecurityGroupRules not implemented")
+}
+func (*UnimplementedAgentServer) AddAllowedAddressPairs(context.Context, *AddAllowedAddressPairsRequest) (*AddAllowedAddressPairsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddAllowedAddressPairs not implemented")
+}
+func (*UnimplementedAgentServer) RemoveAllowedAddressPairs(context.Context, *RemoveAllowedAddressPairsRequest) (*RemoveAllowedAddressPairsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveAllowedAddressPairs not implemented")
+}
+func (*UnimplementedAgentServer) SetRemoteGroupMembers(context.Context, *SetRemoteGroupMembersRequest) (*SetRemoteGroupMembersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRemoteGroupMembers not implemented")
+}
+func (*UnimplementedAgentServer) DeleteRemoteGroup(context.Context, *DeleteRemoteGroupRequest) (*DeleteRemoteGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRemoteGroup not implemented")
+}
+func (*UnimplementedAgentServer) ListRemoteGroups(context.Context, *ListRemoteGroupsRequest) (*ListRemoteGroupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRemoteGroups not implemented")
+}
 func (*UnimplementedAgentServer) GetInterfaceName(context.Context, *GetInterfaceNameRequest) (*GetInterfaceNameResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetInterfaceName not implemented")
 }
@@ -3897,6 +7123,21 @@ func (*UnimplementedAgentServer) DetachBlockDevice(context.Context, *DetachBlock
 func (*UnimplementedAgentServer) DetachInterface(context.Context, *DetachInterfaceRequest) (*DetachInterfaceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DetachInterface not implemented")
 }
+func (*UnimplementedAgentServer) GetOperation(context.Context, *GetOperationRequest) (*GetOperationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOperation not implemented")
+}
+func (*UnimplementedAgentServer) ListOperations(context.Context, *ListOperationsRequest) (*ListOperationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOperations not implemented")
+}
+func (*UnimplementedAgentServer) CancelOperation(context.Context, *CancelOperationRequest) (*CancelOperationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelOperation not implemented")
+}
+func (*UnimplementedAgentServer) WaitOperation(context.Context, *WaitOperationRequest) (*WaitOperationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WaitOperation not implemented")
+}
+func (*UnimplementedAgentServer) RunPreflightChecks(context.Context, *RunPreflightChecksRequest) (*RunPreflightChecksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunPreflightChecks not implemented")
+}
 
 func RegisterAgentServer(s *grpc.Server, srv AgentServer) {
 	s.RegisterService(&_Agent_serviceDesc, srv)
@@ -3974,6 +7215,132 @@ func _Agent_AddSecurityGroup_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Agent_RemoveSecurityGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveSecurityGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).RemoveSecurityGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.Agent/RemoveSecurityGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).RemoveSecurityGroup(ctx, req.(*RemoveSecurityGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_UpdateSecurityGroupRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSecurityGroupRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).UpdateSecurityGroupRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.Agent/UpdateSecurityGroupRules",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).UpdateSecurityGroupRules(ctx, req.(*UpdateSecurityGroupRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_AddAllowedAddressPairs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddAllowedAddressPairsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).AddAllowedAddressPairs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.Agent/AddAllowedAddressPairs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).AddAllowedAddressPairs(ctx, req.(*AddAllowedAddressPairsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_RemoveAllowedAddressPairs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveAllowedAddressPairsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).RemoveAllowedAddressPairs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.Agent/RemoveAllowedAddressPairs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).RemoveAllowedAddressPairs(ctx, req.(*RemoveAllowedAddressPairsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_SetRemoteGroupMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRemoteGroupMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).SetRemoteGroupMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.Agent/SetRemoteGroupMembers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).SetRemoteGroupMembers(ctx, req.(*SetRemoteGroupMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_DeleteRemoteGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRemoteGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).DeleteRemoteGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.Agent/DeleteRemoteGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).DeleteRemoteGroup(ctx, req.(*DeleteRemoteGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_ListRemoteGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRemoteGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).ListRemoteGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.Agent/ListRemoteGroups",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).ListRemoteGroups(ctx, req.(*ListRemoteGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Agent_GetInterfaceName_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetInterfaceNameRequest)
 	if err := dec(in); err != nil {
@@ -4316,6 +7683,96 @@ func _Agent_DetachInterface_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Agent_GetOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).GetOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.Agent/GetOperation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).GetOperation(ctx, req.(*GetOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_ListOperations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOperationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).ListOperations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.Agent/ListOperations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).ListOperations(ctx, req.(*ListOperationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_CancelOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).CancelOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.Agent/CancelOperation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).CancelOperation(ctx, req.(*CancelOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_WaitOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WaitOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).WaitOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.Agent/WaitOperation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).WaitOperation(ctx, req.(*WaitOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_RunPreflightChecks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunPreflightChecksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).RunPreflightChecks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/agent.Agent/RunPreflightChecks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).RunPreflightChecks(ctx, req.(*RunPreflightChecksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Agent_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "agent.Agent",
 	HandlerType: (*AgentServer)(nil),
@@ -4336,6 +7793,34 @@ var _Agent_serviceDesc = grpc.ServiceDesc{
 			MethodName: "AddSecurityGroup",
 			Handler:    _Agent_AddSecurityGroup_Handler,
 		},
+		{
+			MethodName: "RemoveSecurityGroup",
+			Handler:    _Agent_RemoveSecurityGroup_Handler,
+		},
+		{
+			MethodName: "UpdateSecurityGroupRules",
+			Handler:    _Agent_UpdateSecurityGroupRules_Handler,
+		},
+		{
+			MethodName: "AddAllowedAddressPairs",
+			Handler:    _Agent_AddAllowedAddressPairs_Handler,
+		},
+		{
+			MethodName: "RemoveAllowedAddressPairs",
+			Handler:    _Agent_RemoveAllowedAddressPairs_Handler,
+		},
+		{
+			MethodName: "SetRemoteGroupMembers",
+			Handler:    _Agent_SetRemoteGroupMembers_Handler,
+		},
+		{
+			MethodName: "DeleteRemoteGroup",
+			Handler:    _Agent_DeleteRemoteGroup_Handler,
+		},
+		{
+			MethodName: "ListRemoteGroups",
+			Handler:    _Agent_ListRemoteGroups_Handler,
+		},
 		{
 			MethodName: "GetInterfaceName",
 			Handler:    _Agent_GetInterfaceName_Handler,
@@ -4412,6 +7897,26 @@ var _Agent_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DetachInterface",
 			Handler:    _Agent_DetachInterface_Handler,
 		},
+		{
+			MethodName: "GetOperation",
+			Handler:    _Agent_GetOperation_Handler,
+		},
+		{
+			MethodName: "ListOperations",
+			Handler:    _Agent_ListOperations_Handler,
+		},
+		{
+			MethodName: "CancelOperation",
+			Handler:    _Agent_CancelOperation_Handler,
+		},
+		{
+			MethodName: "WaitOperation",
+			Handler:    _Agent_WaitOperation_Handler,
+		},
+		{
+			MethodName: "RunPreflightChecks",
+			Handler:    _Agent_RunPreflightChecks_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "agent.proto",
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	libvirt "github.com/digitalocean/go-libvirt"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	dspb "github.com/lovi-cloud/satelit/api/satelit_datastore"
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+const (
+	defaultReconcileInterval = 60 * time.Second
+
+	teleskopInterfaceSuffix = "-dhcp"
+
+	driftResourceBridge            = "bridge"
+	driftResourceTeleskopInterface = "dhcp_interface"
+	driftResourceVLAN              = "vlan"
+	driftResourceBridgeMember      = "bridge_member"
+
+	driftActionCreate = "create"
+	driftActionDelete = "delete"
+	driftActionUpdate = "update"
+)
+
+// teleskopBridgePattern matches the names of bridges that are owned by teleskop.
+var teleskopBridgePattern = regexp.MustCompile(`^br[0-9]+$`)
+
+// bridgeReconciler converges local bridges, VLAN interfaces and bridge
+// membership to the desired state stored in satelit.
+type bridgeReconciler struct {
+	agent           *agent
+	parentInterface string
+	logger          *zap.Logger
+
+	// mu serializes reconcile passes and guards the options below
+	mu       sync.Mutex
+	interval time.Duration
+	dryRun   bool
+	// deleteStale enables deleting the bridges and teleskop interfaces that satelit does not list,
+	// which are only reported as drift otherwise
+	deleteStale bool
+}
+
+func newBridgeReconciler(a *agent, parentInterface string, c reconcileConfig, logger *zap.Logger) *bridgeReconciler {
+	return &bridgeReconciler{
+		agent:           a,
+		parentInterface: parentInterface,
+		logger:          logger.Named("reconciler"),
+		interval:        c.Interval,
+		dryRun:          c.DryRun,
+		deleteStale:     c.DeleteStale,
+	}
+}
+
+// SetOptions changes the interval, the dry-run mode and the deletion of stale resources of the reconciler.
+func (r *bridgeReconciler) SetOptions(c reconcileConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.interval = c.Interval
+	r.dryRun = c.DryRun
+	r.deleteStale = c.DeleteStale
+}
+
+func (r *bridgeReconciler) getInterval() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.interval
+}
+
+// Run reconciles bridges every interval until ctx is done.
+func (r *bridgeReconciler) Run(ctx context.Context) error {
+	interval := r.getInterval()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.Reconcile(ctx); err != nil {
+				r.logger.Warn("failed to reconcile bridges", zap.Error(err))
+			}
+		}
+
+		if next := r.getInterval(); next != interval {
+			interval = next
+			ticker.Reset(interval)
+		}
+	}
+}
+
+// Reconcile runs a single reconcile pass.
+func (r *bridgeReconciler) Reconcile(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.reconcile(ctx); err != nil {
+		reconcileRunsTotal.WithLabelValues("failure").Inc()
+		return err
+	}
+
+	reconcileRunsTotal.WithLabelValues("success").Inc()
+	reconcileLastSuccess.SetToCurrentTime()
+	return nil
+}
+
+func (r *bridgeReconciler) reconcile(ctx context.Context) error {
+	resp, err := r.agent.datastoreClient.ListBridge(ctx, &dspb.ListBridgeRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to list bridges: %w", err)
+	}
+
+	desired := make(map[string]*dspb.Bridge, len(resp.Bridges))
+	for _, bridge := range resp.Bridges {
+		desired[bridge.Name] = bridge
+		if err := r.reconcileBridge(ctx, bridge); err != nil {
+			return fmt.Errorf("failed to reconcile bridge name=%s: %w", bridge.Name, err)
+		}
+	}
+
+	// satelit listing no bridges is more likely a failure of satelit than a host without networks,
+	// and removing every bridge would disconnect all virtual machines
+	if len(desired) == 0 {
+		r.logger.Warn("skipped removing stale bridges, because satelit lists no bridges")
+		return nil
+	}
+	if err := r.removeStaleBridges(ctx, desired); err != nil {
+		return err
+	}
+	if err := r.removeStaleTeleskopInterfaces(ctx, desired); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *bridgeReconciler) reconcileBridge(ctx context.Context, bridge *dspb.Bridge) error {
+	libvirtClient, err := r.agent.libvirtClient()
+	if err != nil {
+		return err
+	}
+
+	_, err = libvirtClient.NetworkLookupByName(ctx, bridge.Name)
+	switch {
+	case isNetworkNotFound(err):
+		r.drift(driftResourceBridge, driftActionCreate, zap.String("bridge", bridge.Name))
+		if !r.dryRun {
+			_, err := r.agent.AddBridge(ctx, &pb.AddBridgeRequest{
+				Name:         bridge.Name,
+				MetadataCidr: bridge.MetadataCidr,
+				InternalOnly: bridge.InternalOnly,
+			})
+			if err != nil && status.Code(err) != codes.AlreadyExists {
+				return err
+			}
+		}
+	case err != nil:
+		return fmt.Errorf("failed to lookup network name=%s: %w", bridge.Name, err)
+	case !bridge.InternalOnly:
+		vethName := bridge.Name + teleskopInterfaceSuffix
+		_, err := r.agent.netlink.LinkByName(vethName)
+		if err != nil && !isLinkNotFound(err) {
+			return fmt.Errorf("failed to find teleskop interface name=%s: %w", vethName, err)
+		}
+		if err != nil {
+			r.drift(driftResourceTeleskopInterface, driftActionCreate, zap.String("interface", vethName))
+			if !r.dryRun {
+				ip, ipnet, err := net.ParseCIDR(bridge.MetadataCidr)
+				if err != nil {
+					return fmt.Errorf("failed to parse metadata cidr=%s: %w", bridge.MetadataCidr, err)
+				}
+				if err := r.agent.addTeleskopInterface(ctx, bridge.Name, ip, ipnet); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if bridge.InternalOnly {
+		return nil
+	}
+
+	vlanName := fmt.Sprintf("%s.%d", r.parentInterface, bridge.VlanId)
+	vlan, err := r.agent.netlink.LinkByName(vlanName)
+	if err != nil && !isLinkNotFound(err) {
+		return fmt.Errorf("failed to find vlan interface name=%s: %w", vlanName, err)
+	}
+	if err != nil {
+		r.drift(driftResourceVLAN, driftActionCreate, zap.String("interface", vlanName))
+		if r.dryRun {
+			r.drift(driftResourceBridgeMember, driftActionCreate, zap.String("bridge", bridge.Name), zap.String("interface", vlanName))
+			return nil
+		}
+		_, err := r.agent.AddVLANInterface(ctx, &pb.AddVLANInterfaceRequest{
+			VlanId:          bridge.VlanId,
+			ParentInterface: r.parentInterface,
+		})
+		if err != nil && status.Code(err) != codes.AlreadyExists {
+			return err
+		}
+		vlan, err = r.agent.netlink.LinkByName(vlanName)
+		if err != nil {
+			return fmt.Errorf("failed to find vlan interface name=%s: %w", vlanName, err)
+		}
+	}
+
+	br, err := r.agent.netlink.LinkByName(bridge.Name)
+	if err != nil {
+		if r.dryRun {
+			// the bridge would have been created above
+			r.drift(driftResourceBridgeMember, driftActionCreate, zap.String("bridge", bridge.Name), zap.String("interface", vlanName))
+			return nil
+		}
+		return fmt.Errorf("failed to find bridge name=%s: %w", bridge.Name, err)
+	}
+	if vlan.Attrs().MasterIndex == br.Attrs().Index {
+		return nil
+	}
+
+	action := driftActionCreate
+	if vlan.Attrs().MasterIndex != 0 {
+		action = driftActionUpdate
+	}
+	r.drift(driftResourceBridgeMember, action, zap.String("bridge", bridge.Name), zap.String("interface", vlanName))
+	if r.dryRun {
+		return nil
+	}
+	if err := r.agent.netlink.LinkSetMaster(vlan, br); err != nil {
+		return fmt.Errorf("failed to set link master link=%s bridge=%s: %w", vlanName, bridge.Name, err)
+	}
+
+	return nil
+}
+
+func (r *bridgeReconciler) removeStaleBridges(ctx context.Context, desired map[string]*dspb.Bridge) error {
+	libvirtClient, err := r.agent.libvirtClient()
+	if err != nil {
+		return err
+	}
+
+	flags := libvirt.ConnectListNetworksActive | libvirt.ConnectListNetworksInactive
+	networks, _, err := libvirtClient.ConnectListAllNetworks(ctx, 1, flags)
+	if err != nil {
+		return fmt.Errorf("failed to get network list: %w", err)
+	}
+
+	for _, network := range networks {
+		if !teleskopBridgePattern.MatchString(network.Name) {
+			continue
+		}
+		if _, ok := desired[network.Name]; ok {
+			continue
+		}
+
+		inUse, err := r.agent.bridgeInUse(ctx, network.Name)
+		if err != nil {
+			return fmt.Errorf("failed to check domains on bridge name=%s: %w", network.Name, err)
+		}
+		r.drift(driftResourceBridge, driftActionDelete, zap.String("bridge", network.Name), zap.Bool("in_use", inUse))
+		if r.dryRun || !r.deleteStale {
+			continue
+		}
+		if inUse {
+			r.logger.Warn("refused to delete stale bridge with domain interfaces", zap.String("bridge", network.Name))
+			continue
+		}
+		if _, err := r.agent.DeleteBridge(ctx, &pb.DeleteBridgeRequest{Name: network.Name}); err != nil && status.Code(err) != codes.NotFound {
+			return fmt.Errorf("failed to delete stale bridge name=%s: %w", network.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *bridgeReconciler) removeStaleTeleskopInterfaces(ctx context.Context, desired map[string]*dspb.Bridge) error {
+	links, err := r.agent.netlink.LinkList()
+	if err != nil {
+		return fmt.Errorf("failed to get link list: %w", err)
+	}
+
+	for _, link := range links {
+		name := link.Attrs().Name
+		if !strings.HasSuffix(name, teleskopInterfaceSuffix) {
+			continue
+		}
+		bridgeName := strings.TrimSuffix(name, teleskopInterfaceSuffix)
+		if !teleskopBridgePattern.MatchString(bridgeName) {
+			continue
+		}
+		if bridge, ok := desired[bridgeName]; ok && !bridge.InternalOnly {
+			continue
+		}
+
+		r.drift(driftResourceTeleskopInterface, driftActionDelete, zap.String("interface", name))
+		if r.dryRun || !r.deleteStale {
+			continue
+		}
+		if err := r.agent.deleteTeleskopInterfaceIfExists(ctx, bridgeName); err != nil {
+			return fmt.Errorf("failed to delete stale teleskop interface name=%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *bridgeReconciler) drift(resource, action string, fields ...zap.Field) {
+	reconcileDriftTotal.WithLabelValues(resource, action, strconv.FormatBool(r.dryRun)).Inc()
+
+	fields = append([]zap.Field{
+		zap.String("resource", resource),
+		zap.String("action", action),
+		zap.Bool("dry_run", r.dryRun),
+	}, fields...)
+	r.logger.Info("detected drift", fields...)
+}
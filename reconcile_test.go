@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	libvirt "github.com/digitalocean/go-libvirt"
+	"github.com/vishvananda/netlink"
+	"go.uber.org/zap"
+
+	dspb "github.com/lovi-cloud/satelit/api/satelit_datastore"
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+func TestBridgeReconcilerReconcile(t *testing.T) {
+	tests := []struct {
+		name    string
+		desired []*dspb.Bridge
+		// existing are the bridges defined before the reconcile pass
+		existing []string
+		// attached is the bridge that a domain interface is connected to
+		attached    string
+		dryRun      bool
+		deleteStale bool
+		// wantBridges and wantLinks are the networks and the links that must exist after the pass
+		wantBridges []string
+		wantLinks   []string
+		// wantMissing are the networks that must not exist after the pass
+		wantMissing []string
+	}{
+		{
+			name:        "create missing bridge",
+			desired:     []*dspb.Bridge{{Name: "br1000", VlanId: 1000, MetadataCidr: "169.254.169.254/16"}},
+			wantBridges: []string{"br1000"},
+			wantLinks:   []string{"br1000", "br1000-dhcp", "bond0.1000"},
+		},
+		{
+			name:        "create missing internal only bridge",
+			desired:     []*dspb.Bridge{{Name: "br1000", InternalOnly: true}},
+			wantBridges: []string{"br1000"},
+		},
+		{
+			name:        "keep stale bridge by default",
+			desired:     []*dspb.Bridge{{Name: "br1000", InternalOnly: true}},
+			existing:    []string{"br2000"},
+			wantBridges: []string{"br1000", "br2000"},
+		},
+		{
+			name:        "delete stale bridge",
+			desired:     []*dspb.Bridge{{Name: "br1000", InternalOnly: true}},
+			existing:    []string{"br2000"},
+			deleteStale: true,
+			wantBridges: []string{"br1000"},
+			wantMissing: []string{"br2000"},
+		},
+		{
+			name:        "keep stale bridge with domain interfaces",
+			desired:     []*dspb.Bridge{{Name: "br1000", InternalOnly: true}},
+			existing:    []string{"br2000"},
+			attached:    "br2000",
+			deleteStale: true,
+			wantBridges: []string{"br1000", "br2000"},
+		},
+		{
+			name:        "empty desired set",
+			existing:    []string{"br2000"},
+			deleteStale: true,
+			wantBridges: []string{"br2000"},
+		},
+		{
+			name:        "dry run",
+			desired:     []*dspb.Bridge{{Name: "br1000", VlanId: 1000, MetadataCidr: "169.254.169.254/16"}},
+			existing:    []string{"br2000"},
+			dryRun:      true,
+			deleteStale: true,
+			wantBridges: []string{"br2000"},
+			wantMissing: []string{"br1000"},
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		ctx := context.Background()
+		f.netlink.LinkAdd(&netlink.Bond{LinkAttrs: netlink.LinkAttrs{Name: "bond0"}})
+		for _, name := range test.existing {
+			if _, err := a.AddBridge(ctx, &pb.AddBridgeRequest{Name: name, InternalOnly: true}); err != nil {
+				t.Fatalf("%s: failed to add bridge: %+v", test.name, err)
+			}
+		}
+		if test.attached != "" {
+			f.libvirt.addDomain("vm1", libvirt.DomainRunning)
+			domains, _, err := f.libvirt.ConnectListAllDomains(ctx, 1, 0)
+			if err != nil {
+				t.Fatalf("%s: failed to list domains: %+v", test.name, err)
+			}
+			device := "<interface type='bridge'><source bridge='" + test.attached + "'/><target dev='tap0'/></interface>"
+			if err := f.libvirt.DomainAttachDeviceFlags(ctx, domains[0], device, 0); err != nil {
+				t.Fatalf("%s: failed to attach interface: %+v", test.name, err)
+			}
+		}
+		f.datastore.bridges = test.desired
+
+		r := newBridgeReconciler(a, "bond0", reconcileConfig{
+			Interval:    defaultReconcileInterval,
+			DryRun:      test.dryRun,
+			DeleteStale: test.deleteStale,
+		}, zap.NewNop())
+		if err := r.Reconcile(ctx); err != nil {
+			t.Errorf("%s: should not be error but: %+v", test.name, err)
+			continue
+		}
+
+		for _, name := range test.wantBridges {
+			if _, err := f.libvirt.NetworkLookupByName(ctx, name); err != nil {
+				t.Errorf("%s: network %s should exist but: %+v", test.name, name, err)
+			}
+		}
+		for _, name := range test.wantMissing {
+			if _, err := f.libvirt.NetworkLookupByName(ctx, name); !isNetworkNotFound(err) {
+				t.Errorf("%s: network %s should not exist but: %+v", test.name, name, err)
+			}
+		}
+		for _, name := range test.wantLinks {
+			if _, err := f.netlink.LinkByName(name); err != nil {
+				t.Errorf("%s: link %s should exist but: %+v", test.name, name, err)
+			}
+		}
+		if test.dryRun {
+			if _, err := f.netlink.LinkByName("bond0.1000"); !isLinkNotFound(err) {
+				t.Errorf("%s: vlan interface should not be created in dry run but: %+v", test.name, err)
+			}
+		}
+	}
+}
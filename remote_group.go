@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+const (
+	remoteGroupSetPrefix          = "callisto-g"
+	remoteGroupTemporarySetPrefix = "callisto-t"
+
+	// the names of ipsets are up to 31 characters
+	maxRemoteGroupNameLength = 21
+)
+
+var remoteGroupNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+func (a *agent) SetRemoteGroupMembers(ctx context.Context, req *pb.SetRemoteGroupMembersRequest) (*pb.SetRemoteGroupMembersResponse, error) {
+	if err := validateRemoteGroupName(req.Name); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%+v", err)
+	}
+
+	var members []net.IP
+	seen := map[string]bool{}
+	for _, addr := range req.IpAddresses {
+		ip := net.ParseIP(addr)
+		if ip == nil || ip.To4() == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to parse IPv4 address: %s", addr)
+		}
+		if seen[ip.String()] {
+			continue
+		}
+		seen[ip.String()] = true
+		members = append(members, ip.To4())
+	}
+
+	unlock, err := a.lockRemoteGroup(ctx, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if err := a.firewall.SetRemoteGroupMembers(ctx, req.Name, members); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to set members of remote group: %+v", err)
+	}
+
+	return &pb.SetRemoteGroupMembersResponse{}, nil
+}
+
+func (a *agent) DeleteRemoteGroup(ctx context.Context, req *pb.DeleteRemoteGroupRequest) (*pb.DeleteRemoteGroupResponse, error) {
+	if err := validateRemoteGroupName(req.Name); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%+v", err)
+	}
+
+	unlock, err := a.lockRemoteGroup(ctx, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if err := a.firewall.DeleteRemoteGroup(ctx, req.Name); err != nil {
+		switch {
+		case errors.Is(err, errRemoteGroupNotFound):
+			return nil, notFoundError("remote group", req.Name)
+		case errors.Is(err, errRemoteGroupInUse):
+			return nil, resourceError(codes.FailedPrecondition, "remote group", req.Name, "is referred by security group rules")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to delete remote group: %+v", err)
+	}
+
+	return &pb.DeleteRemoteGroupResponse{}, nil
+}
+
+func (a *agent) ListRemoteGroups(ctx context.Context, req *pb.ListRemoteGroupsRequest) (*pb.ListRemoteGroupsResponse, error) {
+	sets, err := a.firewall.RemoteGroups(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list remote groups: %+v", err)
+	}
+
+	var groups []*pb.RemoteGroup
+	for name, members := range sets {
+		group := &pb.RemoteGroup{Name: name}
+		for _, member := range members {
+			group.IpAddresses = append(group.IpAddresses, member.String())
+		}
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+
+	return &pb.ListRemoteGroupsResponse{RemoteGroups: groups}, nil
+}
+
+// createRemoteGroups creates the empty sets of the remote groups referred by rules if missing,
+// so that rules can be added before the members of their groups are set.
+func (b *iptablesBackend) createRemoteGroups(ctx context.Context, rules ...[]securityGroupRule) error {
+	for _, group := range getRemoteGroups(rules...) {
+		if err := b.ipset.Create(ctx, getRemoteGroupSetName(group)); err != nil {
+			return fmt.Errorf("failed to create remote group %s: %w", group, err)
+		}
+	}
+	return nil
+}
+
+func (b *iptablesBackend) SetRemoteGroupMembers(ctx context.Context, group string, members []net.IP) error {
+	return b.ipset.Replace(ctx, getRemoteGroupSetName(group), getRemoteGroupTemporarySetName(group), members)
+}
+
+func (b *iptablesBackend) DeleteRemoteGroup(ctx context.Context, group string) error {
+	sets, err := b.ipset.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list ipsets: %w", err)
+	}
+	set := getRemoteGroupSetName(group)
+	if _, ok := sets[set]; !ok {
+		return errRemoteGroupNotFound
+	}
+
+	if err := b.ipset.Destroy(ctx, set); err != nil {
+		if errors.Is(err, errIPSetInUse) {
+			return errRemoteGroupInUse
+		}
+		return err
+	}
+	return nil
+}
+
+func (b *iptablesBackend) RemoteGroups(ctx context.Context) (map[string][]net.IP, error) {
+	sets, err := b.ipset.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ipsets: %w", err)
+	}
+
+	groups := map[string][]net.IP{}
+	for set, members := range sets {
+		if strings.HasPrefix(set, remoteGroupSetPrefix) {
+			groups[strings.TrimPrefix(set, remoteGroupSetPrefix)] = members
+		}
+	}
+	return groups, nil
+}
+
+// getRemoteGroups returns the remote groups referred by rules without duplicates.
+func getRemoteGroups(rules ...[]securityGroupRule) []string {
+	var groups []string
+	seen := map[string]bool{}
+	for _, rs := range rules {
+		for _, r := range rs {
+			if r.remoteGroup == "" || seen[r.remoteGroup] {
+				continue
+			}
+			seen[r.remoteGroup] = true
+			groups = append(groups, r.remoteGroup)
+		}
+	}
+	return groups
+}
+
+func validateRemoteGroupName(name string) error {
+	if len(name) > maxRemoteGroupNameLength || !remoteGroupNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid remote group name %q: up to %d letters, digits, '_', '.' and '-' are allowed", name, maxRemoteGroupNameLength)
+	}
+	return nil
+}
+
+func getRemoteGroupSetName(name string) string {
+	return remoteGroupSetPrefix + name
+}
+
+func getRemoteGroupTemporarySetName(name string) string {
+	return remoteGroupTemporarySetPrefix + name
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+func TestSetRemoteGroupMembers(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         *pb.SetRemoteGroupMembersRequest
+		want        codes.Code
+		wantMembers []string
+	}{
+		{
+			name:        "members",
+			req:         &pb.SetRemoteGroupMembersRequest{Name: "db-clients", IpAddresses: []string{"192.0.2.1", "192.0.2.2"}},
+			want:        codes.OK,
+			wantMembers: []string{"192.0.2.1", "192.0.2.2"},
+		},
+		{
+			name:        "duplicated members",
+			req:         &pb.SetRemoteGroupMembersRequest{Name: "db-clients", IpAddresses: []string{"192.0.2.1", "192.0.2.1"}},
+			want:        codes.OK,
+			wantMembers: []string{"192.0.2.1"},
+		},
+		{
+			name: "no members",
+			req:  &pb.SetRemoteGroupMembersRequest{Name: "db-clients"},
+			want: codes.OK,
+		},
+		{
+			name: "empty name",
+			req:  &pb.SetRemoteGroupMembersRequest{IpAddresses: []string{"192.0.2.1"}},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "too long name",
+			req:  &pb.SetRemoteGroupMembersRequest{Name: strings.Repeat("a", maxRemoteGroupNameLength+1)},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "invalid name",
+			req:  &pb.SetRemoteGroupMembersRequest{Name: "db clients"},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "invalid address",
+			req:  &pb.SetRemoteGroupMembersRequest{Name: "db-clients", IpAddresses: []string{"192.0.2"}},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "ipv6 address",
+			req:  &pb.SetRemoteGroupMembersRequest{Name: "db-clients", IpAddresses: []string{"2001:db8::1"}},
+			want: codes.InvalidArgument,
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+
+		_, err := a.SetRemoteGroupMembers(context.Background(), test.req)
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %+v", test.name, test.want, err)
+			continue
+		}
+		members, ok := f.ipset.members("callisto-g" + test.req.Name)
+		if ok != (test.want == codes.OK) {
+			t.Errorf("%s: want set exists %t, but got %t", test.name, test.want == codes.OK, ok)
+		}
+		var got []string
+		for _, member := range members {
+			got = append(got, member.String())
+		}
+		if strings.Join(got, ",") != strings.Join(test.wantMembers, ",") {
+			t.Errorf("%s: want %s, but got %s", test.name, strings.Join(test.wantMembers, ","), strings.Join(got, ","))
+		}
+	}
+}
+
+func TestDeleteRemoteGroup(t *testing.T) {
+	tests := []struct {
+		name  string
+		group string
+		inUse bool
+		want  codes.Code
+	}{
+		{
+			name:  "remote group",
+			group: "db-clients",
+			want:  codes.OK,
+		},
+		{
+			name:  "missing remote group",
+			group: "web",
+			want:  codes.NotFound,
+		},
+		{
+			name:  "remote group in use",
+			group: "db-clients",
+			inUse: true,
+			want:  codes.FailedPrecondition,
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		ctx := context.Background()
+		if _, err := a.SetRemoteGroupMembers(ctx, &pb.SetRemoteGroupMembersRequest{Name: "db-clients", IpAddresses: []string{"192.0.2.1"}}); err != nil {
+			t.Fatalf("%s: failed to set members: %+v", test.name, err)
+		}
+		if test.inUse {
+			if _, err := a.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{}); err != nil {
+				t.Fatalf("%s: failed to setup default security group: %+v", test.name, err)
+			}
+			_, err := a.AddSecurityGroup(ctx, &pb.AddSecurityGroupRequest{
+				Interface:    "tap0",
+				IpAddress:    "192.0.2.100",
+				MacAddress:   "52:54:00:00:00:01",
+				IngressRules: []*pb.SecurityGroupRule{{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 5432, RemoteGroup: "db-clients"}},
+			})
+			if err != nil {
+				t.Fatalf("%s: failed to add security group: %+v", test.name, err)
+			}
+		}
+
+		_, err := a.DeleteRemoteGroup(ctx, &pb.DeleteRemoteGroupRequest{Name: test.group})
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %+v", test.name, test.want, err)
+			continue
+		}
+		if _, ok := f.ipset.members("callisto-gdb-clients"); ok != (test.want != codes.OK) {
+			t.Errorf("%s: want set exists %t, but got %t", test.name, test.want != codes.OK, ok)
+		}
+	}
+}
+
+func TestListRemoteGroups(t *testing.T) {
+	a, f := newTestAgent(t)
+	ctx := context.Background()
+
+	for _, req := range []*pb.SetRemoteGroupMembersRequest{
+		{Name: "web", IpAddresses: []string{"192.0.2.10"}},
+		{Name: "db-clients", IpAddresses: []string{"192.0.2.1", "192.0.2.2"}},
+	} {
+		if _, err := a.SetRemoteGroupMembers(ctx, req); err != nil {
+			t.Fatalf("failed to set members: %+v", err)
+		}
+	}
+	// sets not managed by the agent are ignored
+	if err := f.ipset.Create(ctx, "kube-proxy"); err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+
+	resp, err := a.ListRemoteGroups(ctx, &pb.ListRemoteGroupsRequest{})
+	if err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	var got []string
+	for _, group := range resp.RemoteGroups {
+		got = append(got, group.Name+"="+strings.Join(group.IpAddresses, ","))
+	}
+	want := "db-clients=192.0.2.1,192.0.2.2 web=192.0.2.10"
+	if strings.Join(got, " ") != want {
+		t.Errorf("want %s, but got %s", want, strings.Join(got, " "))
+	}
+}
+
+func TestAddSecurityGroupCreatesRemoteGroups(t *testing.T) {
+	a, f := newTestAgent(t)
+	ctx := context.Background()
+	if _, err := a.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{}); err != nil {
+		t.Fatalf("failed to setup default security group: %+v", err)
+	}
+
+	_, err := a.AddSecurityGroup(ctx, &pb.AddSecurityGroupRequest{
+		Interface:   "tap0",
+		IpAddress:   "192.0.2.100",
+		MacAddress:  "52:54:00:00:00:01",
+		EgressRules: []*pb.SecurityGroupRule{{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 5432, RemoteGroup: "db"}},
+	})
+	if err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	if _, ok := f.ipset.members("callisto-gdb"); !ok {
+		t.Errorf("set of the remote group should be created")
+	}
+
+	// membership changes do not touch the chains
+	before := f.iptables.rules(tableFilter, "callisto-otap0")
+	if _, err := a.SetRemoteGroupMembers(ctx, &pb.SetRemoteGroupMembersRequest{Name: "db", IpAddresses: []string{"192.0.2.1"}}); err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	after := f.iptables.rules(tableFilter, "callisto-otap0")
+	if strings.Join(before, "\n") != strings.Join(after, "\n") {
+		t.Errorf("want %q, but got %q", before, after)
+	}
+	want := "-m set --match-set callisto-gdb dst -p tcp -m tcp --dport 5432 -j RETURN"
+	if got := after[4]; got != want {
+		t.Errorf("want %s, but got %s", want, got)
+	}
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/vishvananda/netlink"
+	"go.uber.org/zap"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+type link struct {
+	Name         string
+	IPAddress    net.IP
+	IPv6Address  net.IP // nil if the VM has only link-local IPv6 addresses
+	MACAddress   net.HardwareAddr
+	IngressRules []securityGroupRule
+	EgressRules  []securityGroupRule
+	// AllowedAddressPairs are the other addresses that the VM sends traffic from
+	AllowedAddressPairs []allowedAddressPair
+}
+
+// allows returns whether the source chain of l allows traffic from ip with any MAC address.
+func (l link) allows(ip net.IP) bool {
+	if ip.Equal(l.IPAddress) || ip.Equal(l.IPv6Address) {
+		return true
+	}
+	for _, pair := range l.AllowedAddressPairs {
+		if pair.IPNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *agent) GetIPTables(ctx context.Context, req *pb.GetIPTablesRequest) (*pb.GetIPTablesResponse, error) {
+	chains, err := a.firewall.Chains(ctx, a.securityGroupIndex, req.Interface)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get %s chains: %+v", a.firewall.Name(), err)
+	}
+	if req.Interface != "" && len(chains) == 0 {
+		return nil, notFoundError("security group", req.Interface)
+	}
+
+	return &pb.GetIPTablesResponse{Chains: chains}, nil
+}
+
+func (a *agent) SetupDefaultSecurityGroup(ctx context.Context, req *pb.SetupDefaultSecurityGroupRequest) (*pb.SetupDefaultSecurityGroupResponse, error) {
+	if err := a.firewall.Setup(ctx); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to setup default security group: %+v", err)
+	}
+
+	return &pb.SetupDefaultSecurityGroupResponse{}, nil
+}
+
+func (a *agent) AddSecurityGroup(ctx context.Context, req *pb.AddSecurityGroupRequest) (*pb.AddSecurityGroupResponse, error) {
+	if req.Interface == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "interface must be set")
+	}
+
+	intf, err := parseSecurityGroupLink(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse request: %+v", err)
+	}
+
+	unlock, err := a.lockSecurityGroup(ctx, req.Interface)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if err := a.securityGroupIndex.check(req.Interface); err != nil {
+		return nil, err
+	}
+	if err := a.firewall.Add(ctx, intf); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to add security group: %+v", err)
+	}
+	a.securityGroupIndex.add(req.Interface)
+	if err := a.securityGroups.Put(req); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to record security group: %+v", err)
+	}
+
+	return &pb.AddSecurityGroupResponse{}, nil
+}
+
+func (a *agent) RemoveSecurityGroup(ctx context.Context, req *pb.RemoveSecurityGroupRequest) (*pb.RemoveSecurityGroupResponse, error) {
+	if req.Interface == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "interface must be set")
+	}
+
+	unlock, err := a.lockSecurityGroup(ctx, req.Interface)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	ipAddrs, err := a.firewall.Remove(ctx, link{Name: req.Interface}, a.securityGroupIndex.chainsOf(req.Interface))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to remove security group: %+v", err)
+	}
+	a.securityGroupIndex.remove(req.Interface)
+	// the record is kept until the chains are removed, so that a failed call can be retried.
+	// the verifier takes the lock of the interface, so it does not see the removed chains with the record.
+	if err := a.securityGroups.Delete(req.Interface); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete security group record: %+v", err)
+	}
+
+	a.deleteConntrackEntriesOf(req.Interface, ipAddrs)
+
+	return &pb.RemoveSecurityGroupResponse{}, nil
+}
+
+func (a *agent) UpdateSecurityGroupRules(ctx context.Context, req *pb.UpdateSecurityGroupRulesRequest) (*pb.UpdateSecurityGroupRulesResponse, error) {
+	if req.Interface == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "interface must be set")
+	}
+
+	ingress, err := parseSecurityGroupRules(req.IngressRules)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse ingress rules: %+v", err)
+	}
+	egress, err := parseSecurityGroupRules(req.EgressRules)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse egress rules: %+v", err)
+	}
+
+	unlock, err := a.lockSecurityGroup(ctx, req.Interface)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	intf := link{Name: req.Interface, IngressRules: ingress, EgressRules: egress}
+	exists, err := a.firewall.Exists(ctx, intf)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to check security group: %+v", err)
+	}
+	if !exists {
+		return nil, notFoundError("security group", req.Interface)
+	}
+
+	if err := a.firewall.Update(ctx, intf); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update security group rules: %+v", err)
+	}
+
+	// security groups added before the records were introduced are not recorded
+	record, err := a.securityGroups.Get(req.Interface)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get security group record: %+v", err)
+	}
+	if record != nil {
+		record.IngressRules = req.IngressRules
+		record.EgressRules = req.EgressRules
+		if err := a.securityGroups.Put(record); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to record security group: %+v", err)
+		}
+	}
+
+	return &pb.UpdateSecurityGroupRulesResponse{}, nil
+}
+
+// initSecurityGroupChains migrates the chains of older versions and indexes the chains of the security groups.
+func (a *agent) initSecurityGroupChains(ctx context.Context) error {
+	records, err := a.securityGroups.List()
+	if err != nil {
+		return fmt.Errorf("failed to list security group records: %w", err)
+	}
+	interfaces, err := a.firewall.Init(ctx, records)
+	if err != nil {
+		return err
+	}
+	for _, intf := range interfaces {
+		a.securityGroupIndex.add(intf)
+	}
+	return nil
+}
+
+// parseSecurityGroupLink returns the interface secured by req.
+func parseSecurityGroupLink(req *pb.AddSecurityGroupRequest) (link, error) {
+	ipAddr := net.ParseIP(req.IpAddress)
+	if ipAddr == nil || ipAddr.To4() == nil {
+		return link{}, fmt.Errorf("invalid IPv4 address %q", req.IpAddress)
+	}
+	var ipv6Addr net.IP
+	if req.Ipv6Address != "" {
+		ipv6Addr = net.ParseIP(req.Ipv6Address)
+		if ipv6Addr == nil || ipv6Addr.To4() != nil {
+			return link{}, fmt.Errorf("invalid IPv6 address %q", req.Ipv6Address)
+		}
+	}
+	macAddr, err := net.ParseMAC(req.MacAddress)
+	if err != nil {
+		return link{}, fmt.Errorf("failed to parse MAC address: %w", err)
+	}
+	ingress, err := parseSecurityGroupRules(req.IngressRules)
+	if err != nil {
+		return link{}, fmt.Errorf("failed to parse ingress rules: %w", err)
+	}
+	egress, err := parseSecurityGroupRules(req.EgressRules)
+	if err != nil {
+		return link{}, fmt.Errorf("failed to parse egress rules: %w", err)
+	}
+	pairs, err := parseAllowedAddressPairs(req.AllowedAddressPairs, macAddr)
+	if err != nil {
+		return link{}, fmt.Errorf("failed to parse allowed address pairs: %w", err)
+	}
+	return link{
+		Name:                req.Interface,
+		IPAddress:           ipAddr,
+		IPv6Address:         ipv6Addr,
+		MACAddress:          macAddr,
+		IngressRules:        ingress,
+		EgressRules:         egress,
+		AllowedAddressPairs: pairs,
+	}, nil
+}
+
+// deleteConntrackEntriesOf deletes the connections of ipAddrs that were allowed by the security group of intf.
+// Failures are only logged: the rules are already changed, so a retry would not find the addresses again.
+func (a *agent) deleteConntrackEntriesOf(intf string, ipAddrs []net.IP) {
+	for _, ipAddr := range ipAddrs {
+		if err := a.deleteConntrackEntries(ipAddr); err != nil {
+			a.logger.Warn("failed to delete conntrack entries", zap.String("interface", intf), zap.Stringer("ip_address", ipAddr), zap.Error(err))
+		}
+	}
+}
+
+// deleteConntrackEntries deletes the connections from and to ipAddr, so that established
+// connections are not allowed by the rules of the next owner of the address.
+func (a *agent) deleteConntrackEntries(ipAddr net.IP) error {
+	family := netlink.InetFamily(netlink.FAMILY_V6)
+	if ipAddr.To4() != nil {
+		family = netlink.InetFamily(netlink.FAMILY_V4)
+	}
+	for _, tp := range []netlink.ConntrackFilterType{netlink.ConntrackOrigSrcIP, netlink.ConntrackOrigDstIP} {
+		filter := &netlink.ConntrackFilter{}
+		if err := filter.AddIP(tp, ipAddr); err != nil {
+			return err
+		}
+		if _, err := a.netlink.ConntrackDeleteFilter(netlink.ConntrackTable, family, filter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+// securityGroupChainPrefixes are the prefixes of the chains of an interface in the order they are created.
+var securityGroupChainPrefixes = []string{
+	chainCallistoSOURCEPrefix,
+	chainCallistoOUTPUPrefix,
+	chainCallistoINPUTPrefix,
+}
+
+// getChainNames returns the chains of intf in the order they are created.
+func getChainNames(intf link) []string {
+	names := make([]string, 0, len(securityGroupChainPrefixes))
+	for _, prefix := range securityGroupChainPrefixes {
+		names = append(names, getValidChainName(prefix, intf.Name))
+	}
+	return names
+}
+
+// securityGroupIndex maps the chains of the security groups to their interfaces, because the hashed
+// chain names of long interface names cannot be mapped back.
+type securityGroupIndex struct {
+	mu     sync.Mutex
+	chains map[string]string
+}
+
+func newSecurityGroupIndex() *securityGroupIndex {
+	return &securityGroupIndex{chains: map[string]string{}}
+}
+
+// check returns an error if a chain of intf is a shared chain or a chain of another interface.
+func (x *securityGroupIndex) check(intf string) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	for _, chain := range getChainNames(link{Name: intf}) {
+		for _, shared := range sharedChains {
+			if chain == shared {
+				return status.Errorf(codes.InvalidArgument, "chain %s of interface %s is a shared chain", chain, intf)
+			}
+		}
+		if owner, ok := x.chains[chain]; ok && owner != intf {
+			return resourceError(codes.FailedPrecondition, "security group", intf, fmt.Sprintf("has chain %s of interface %s", chain, owner))
+		}
+	}
+	return nil
+}
+
+// add records the chains of intf.
+func (x *securityGroupIndex) add(intf string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	for _, chain := range getChainNames(link{Name: intf}) {
+		x.chains[chain] = intf
+	}
+}
+
+func (x *securityGroupIndex) remove(intf string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	for chain, owner := range x.chains {
+		if owner == intf {
+			delete(x.chains, chain)
+		}
+	}
+}
+
+// lookup returns the interface of chain, or an empty string if chain is not a chain of a security group.
+func (x *securityGroupIndex) lookup(chain string) string {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	return x.chains[chain]
+}
+
+// chainsOf returns the chains of intf, or the chains named after intf if it is not indexed.
+func (x *securityGroupIndex) chainsOf(intf string) []string {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	var chains []string
+	for chain, owner := range x.chains {
+		if owner == intf {
+			chains = append(chains, chain)
+		}
+	}
+	if len(chains) == 0 {
+		return getChainNames(link{Name: intf})
+	}
+	sort.Strings(chains)
+	return chains
+}
+
+// getSecurityGroupInterfaces returns the recorded interfaces and the interfaces that the shared chains jump for.
+func getSecurityGroupInterfaces(saved map[string][]string, records []*pb.AddSecurityGroupRequest) ([]string, error) {
+	found := map[string]bool{}
+	for _, record := range records {
+		found[record.Interface] = true
+	}
+	for _, chain := range []string{chainCallistoSG, chainCallistoFORWARD} {
+		for _, line := range saved[chain] {
+			_, rule, err := parseIPTablesRule("-A " + chain + " " + line)
+			if err != nil {
+				return nil, err
+			}
+			for _, intf := range []string{rule.PhysdevIn, rule.PhysdevOut} {
+				if intf != "" {
+					found[intf] = true
+				}
+			}
+		}
+	}
+
+	interfaces := make([]string, 0, len(found))
+	for intf := range found {
+		interfaces = append(interfaces, intf)
+	}
+	sort.Strings(interfaces)
+	return interfaces, nil
+}
+
+// legacyChain is a chain named by older versions.
+type legacyChain struct {
+	name   string
+	legacy string
+}
+
+// migrateSecurityGroupChains renames the chains that older versions named by truncating long interface names,
+// and the jump rules follow them. Chains shared by the interfaces whose truncated names collided are rebuilt
+// from the records, or left as they are if one of the interfaces is not recorded.
+func (b *iptablesBackend) migrateSecurityGroupChains(ctx context.Context, client iptablesAPI, saved map[string][]string, interfaces []string, records []*pb.AddSecurityGroupRequest) error {
+	recorded := map[string]*pb.AddSecurityGroupRequest{}
+	for _, record := range records {
+		recorded[record.Interface] = record
+	}
+
+	legacyChains := map[string][]legacyChain{}
+	owners := map[string][]string{}
+	for _, name := range interfaces {
+		var chains []legacyChain
+		for _, prefix := range securityGroupChainPrefixes {
+			chain := legacyChain{name: getValidChainName(prefix, name), legacy: getLegacyChainName(prefix, name)}
+			if _, ok := saved[chain.legacy]; !ok || chain.name == chain.legacy {
+				continue
+			}
+			if _, ok := saved[chain.name]; ok {
+				b.logger.Warn("failed to migrate security group chain, because the new chain exists",
+					zap.String("interface", name), zap.String("chain", chain.legacy), zap.String("new_chain", chain.name))
+				chains = nil
+				break
+			}
+			chains = append(chains, chain)
+		}
+		legacyChains[name] = chains
+		for _, chain := range chains {
+			owners[chain.legacy] = append(owners[chain.legacy], name)
+		}
+	}
+
+	tx := newIPTablesTransaction(tableFilter)
+	renamed := 0
+	var rebuild []string
+	for _, name := range interfaces {
+		collided := false
+		for _, chain := range legacyChains[name] {
+			if len(owners[chain.legacy]) > 1 {
+				collided = true
+			}
+		}
+		if collided {
+			rebuild = append(rebuild, name)
+			continue
+		}
+		for _, chain := range legacyChains[name] {
+			tx.renameChain(chain.legacy, chain.name)
+			renamed++
+		}
+	}
+
+	for _, name := range rebuild {
+		if recorded[name] == nil {
+			b.logger.Warn("failed to migrate security group chains shared by interfaces, because a security group is not recorded",
+				zap.Strings("interfaces", rebuild), zap.String("interface", name))
+			rebuild = nil
+			break
+		}
+	}
+
+	var deleted []string
+	seen := map[string]bool{}
+	var intfs []link
+	for _, name := range rebuild {
+		intf, err := parseSecurityGroupLink(recorded[name])
+		if err != nil {
+			return fmt.Errorf("invalid security group record of %s: %w", name, err)
+		}
+		if err := b.createRemoteGroups(ctx, intf.IngressRules, intf.EgressRules); err != nil {
+			return fmt.Errorf("failed to create remote groups: %w", err)
+		}
+
+		legacyOf := map[string]string{}
+		for _, chain := range legacyChains[name] {
+			legacyOf[chain.name] = chain.legacy
+			if !seen[chain.legacy] {
+				seen[chain.legacy] = true
+				deleted = append(deleted, chain.legacy)
+			}
+		}
+		for _, jump := range getJumpRules(intf) {
+			if legacy, ok := legacyOf[jump.target()]; ok {
+				jump = jump.withTarget(legacy)
+			}
+			exists, err := containsRule(ctx, client, saved, jump.chain, jump.rule)
+			if err != nil {
+				return err
+			}
+			if exists {
+				tx.deleteRule(jump.chain, jump.rule...)
+			}
+		}
+		intfs = append(intfs, intf)
+	}
+	// all chains are flushed before deleting them, because the output chains jump to the source chains
+	for _, chain := range deleted {
+		tx.flushChain(chain)
+	}
+	for _, chain := range deleted {
+		tx.deleteChain(chain)
+	}
+	for _, intf := range intfs {
+		for _, fn := range addFunctions {
+			fn(tx, intf, ipFamilyIPv4)
+		}
+	}
+
+	if err := tx.commit(ctx, client); err != nil {
+		return fmt.Errorf("failed to migrate security group chains: %w", err)
+	}
+	if renamed != 0 || len(rebuild) != 0 {
+		b.logger.Info("migrated security group chains", zap.Int("renamed", renamed), zap.Strings("rebuilt", rebuild))
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+const (
+	testLongInterface0 = "tap-0123456789abcdef-0"
+	testLongInterface1 = "tap-0123456789abcdef-1"
+)
+
+func TestAddSecurityGroupChainConflict(t *testing.T) {
+	tests := []struct {
+		name string
+		intf string
+		// owner is the interface that the chains of intf are indexed to
+		owner string
+		want  codes.Code
+	}{
+		{
+			name: "long interface",
+			intf: testLongInterface0,
+			want: codes.OK,
+		},
+		{
+			name: "shared chain",
+			intf: "g-chain",
+			want: codes.InvalidArgument,
+		},
+		{
+			name:  "chain of another interface",
+			intf:  testLongInterface0,
+			owner: testLongInterface1,
+			want:  codes.FailedPrecondition,
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		ctx := context.Background()
+		if _, err := a.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{}); err != nil {
+			t.Fatalf("%s: failed to setup default security group: %+v", test.name, err)
+		}
+		if test.owner != "" {
+			for _, chain := range getChainNames(link{Name: test.intf}) {
+				a.securityGroupIndex.chains[chain] = test.owner
+			}
+		}
+
+		_, err := a.AddSecurityGroup(ctx, &pb.AddSecurityGroupRequest{Interface: test.intf, IpAddress: "192.0.2.100", MacAddress: "52:54:00:00:00:01"})
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %+v", test.name, test.want, err)
+			continue
+		}
+		chain := getINPUTChainName(link{Name: test.intf})
+		if created := f.iptables.hasChain(tableFilter, chain); created != (test.want == codes.OK) {
+			t.Errorf("%s: want created=%t of %s, but got %t", test.name, test.want == codes.OK, chain, created)
+		}
+		if test.want == codes.OK {
+			if got := a.securityGroupIndex.lookup(chain); got != test.intf {
+				t.Errorf("%s: want %s, but got %q", test.name, test.intf, got)
+			}
+		}
+	}
+}
+
+func TestInitSecurityGroupChains(t *testing.T) {
+	reqs := []*pb.AddSecurityGroupRequest{
+		{Interface: "tap0", IpAddress: "192.0.2.100", MacAddress: "52:54:00:00:00:01"},
+		{
+			Interface:    testLongInterface0,
+			IpAddress:    "192.0.2.101",
+			MacAddress:   "52:54:00:00:00:02",
+			IngressRules: []*pb.SecurityGroupRule{{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 22}},
+		},
+		{Interface: testLongInterface1, IpAddress: "192.0.2.102", MacAddress: "52:54:00:00:00:03"},
+	}
+
+	tests := []struct {
+		name string
+		reqs []*pb.AddSecurityGroupRequest
+		// unrecorded are the interfaces added before the records were introduced
+		unrecorded   []string
+		wantMigrated bool
+	}{
+		{
+			name:         "chains of a long interface are renamed",
+			reqs:         reqs[:2],
+			wantMigrated: true,
+		},
+		{
+			name:         "unrecorded chains are renamed",
+			reqs:         reqs[:2],
+			unrecorded:   []string{testLongInterface0},
+			wantMigrated: true,
+		},
+		{
+			name:         "collided chains are rebuilt",
+			reqs:         reqs,
+			wantMigrated: true,
+		},
+		{
+			name:         "unrecorded collided chains are kept",
+			reqs:         reqs,
+			unrecorded:   []string{testLongInterface1},
+			wantMigrated: false,
+		},
+	}
+	for _, test := range tests {
+		ctx := context.Background()
+		fresh, freshBackends := newTestAgent(t)
+		setupLegacyTestSecurityGroups(t, fresh, freshBackends, test.reqs, false)
+		a, f := newTestAgent(t)
+		setupLegacyTestSecurityGroups(t, a, f, test.reqs, true)
+		for _, intf := range test.unrecorded {
+			if err := a.securityGroups.Delete(intf); err != nil {
+				t.Fatalf("%s: should not be error but: %+v", test.name, err)
+			}
+		}
+
+		want := savedFakeIPTables(t, f.iptables)
+		if test.wantMigrated {
+			want = savedFakeIPTables(t, freshBackends.iptables)
+		}
+
+		a.securityGroupIndex = newSecurityGroupIndex()
+		if err := a.initSecurityGroupChains(ctx); err != nil {
+			t.Errorf("%s: should not be error but: %+v", test.name, err)
+			continue
+		}
+
+		got := savedFakeIPTables(t, f.iptables)
+		if got, want := formatSavedIPTables(got), formatSavedIPTables(want); got != want {
+			t.Errorf("%s: want %s, but got %s", test.name, want, got)
+		}
+		for _, req := range test.reqs {
+			chain := getINPUTChainName(link{Name: req.Interface})
+			if got := a.securityGroupIndex.lookup(chain); got != req.Interface {
+				t.Errorf("%s: want %s, but got %q", test.name, req.Interface, got)
+			}
+		}
+	}
+}
+
+// setupLegacyTestSecurityGroups adds reqs, and renames their chains as older versions named them if legacy is set.
+// The jump rules of a chain that is already taken are switched to it, as the chain was overwritten.
+func setupLegacyTestSecurityGroups(t *testing.T, a *agent, f *fakeBackends, reqs []*pb.AddSecurityGroupRequest, legacy bool) {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := a.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{}); err != nil {
+		t.Fatalf("failed to setup default security group: %+v", err)
+	}
+	for _, req := range reqs {
+		if _, err := a.AddSecurityGroup(ctx, req); err != nil {
+			t.Fatalf("failed to add security group: %+v", err)
+		}
+		if !legacy {
+			continue
+		}
+
+		intf := link{Name: req.Interface}
+		for _, prefix := range securityGroupChainPrefixes {
+			chain, legacy := getValidChainName(prefix, intf.Name), getLegacyChainName(prefix, intf.Name)
+			if chain == legacy {
+				continue
+			}
+			if !f.iptables.hasChain(tableFilter, legacy) {
+				if err := f.iptables.RenameChain(ctx, tableFilter, chain, legacy); err != nil {
+					t.Fatalf("should not be error but: %+v", err)
+				}
+				continue
+			}
+			for _, jump := range getJumpRulesTo(getJumpRules(intf), chain) {
+				if err := f.iptables.Delete(ctx, tableFilter, jump.chain, jump.rule...); err != nil {
+					t.Fatalf("should not be error but: %+v", err)
+				}
+				if err := f.iptables.Insert(ctx, tableFilter, jump.chain, 1, jump.withTarget(legacy).rule...); err != nil {
+					t.Fatalf("should not be error but: %+v", err)
+				}
+			}
+			if err := f.iptables.ClearChain(ctx, tableFilter, chain); err != nil {
+				t.Fatalf("should not be error but: %+v", err)
+			}
+		}
+		for _, chain := range getChainNames(intf) {
+			if rules := f.iptables.rules(tableFilter, chain); f.iptables.hasChain(tableFilter, chain) && len(rules) == 0 {
+				if err := f.iptables.DeleteChain(ctx, tableFilter, chain); err != nil {
+					t.Fatalf("should not be error but: %+v", err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/google/nftables/expr"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+type direction int
+
+const (
+	directionIngress direction = iota
+	directionEgress
+)
+
+var icmpTypePattern = regexp.MustCompile(`^(\d+(/\d+)?|[a-z][a-z-]*)$`)
+
+// icmpTypeNames are the ICMP type names of iptables with their type and code. The code is -1 for any code.
+var icmpTypeNames = map[string][2]int{
+	"echo-reply":                 {0, -1},
+	"destination-unreachable":    {3, -1},
+	"network-unreachable":        {3, 0},
+	"host-unreachable":           {3, 1},
+	"protocol-unreachable":       {3, 2},
+	"port-unreachable":           {3, 3},
+	"fragmentation-needed":       {3, 4},
+	"source-route-failed":        {3, 5},
+	"network-unknown":            {3, 6},
+	"host-unknown":               {3, 7},
+	"network-prohibited":         {3, 9},
+	"host-prohibited":            {3, 10},
+	"communication-prohibited":   {3, 13},
+	"host-precedence-violation":  {3, 14},
+	"precedence-cutoff":          {3, 15},
+	"source-quench":              {4, -1},
+	"redirect":                   {5, -1},
+	"network-redirect":           {5, 0},
+	"host-redirect":              {5, 1},
+	"echo-request":               {8, -1},
+	"router-advertisement":       {9, -1},
+	"router-solicitation":        {10, -1},
+	"time-exceeded":              {11, -1},
+	"ttl-zero-during-transit":    {11, 0},
+	"ttl-zero-during-reassembly": {11, 1},
+	"parameter-problem":          {12, -1},
+	"ip-header-bad":              {12, 0},
+	"required-option-missing":    {12, 1},
+	"timestamp-request":          {13, -1},
+	"timestamp-reply":            {14, -1},
+	"address-mask-request":       {17, -1},
+	"address-mask-reply":         {18, -1},
+}
+
+// icmpv6TypeNames are the ICMPv6 type names of ip6tables with their type and code, like icmpTypeNames.
+var icmpv6TypeNames = map[string][2]int{
+	"destination-unreachable":    {1, -1},
+	"no-route":                   {1, 0},
+	"communication-prohibited":   {1, 1},
+	"beyond-scope":               {1, 2},
+	"address-unreachable":        {1, 3},
+	"port-unreachable":           {1, 4},
+	"failed-policy":              {1, 5},
+	"reject-route":               {1, 6},
+	"packet-too-big":             {2, -1},
+	"time-exceeded":              {3, -1},
+	"ttl-exceeded":               {3, -1},
+	"ttl-zero-during-transit":    {3, 0},
+	"ttl-zero-during-reassembly": {3, 1},
+	"parameter-problem":          {4, -1},
+	"bad-header":                 {4, 0},
+	"unknown-header-type":        {4, 1},
+	"unknown-option":             {4, 2},
+	"echo-request":               {128, -1},
+	"ping":                       {128, -1},
+	"echo-reply":                 {129, -1},
+	"pong":                       {129, -1},
+	"router-solicitation":        {133, -1},
+	"router-advertisement":       {134, -1},
+	"neighbour-solicitation":     {135, -1},
+	"neighbor-solicitation":      {135, -1},
+	"neighbour-advertisement":    {136, -1},
+	"neighbor-advertisement":     {136, -1},
+	"redirect":                   {137, -1},
+}
+
+// securityGroupRule is a validated rule of a security group.
+type securityGroupRule struct {
+	protocol     pb.SecurityGroupRule_Protocol
+	portRangeMin uint32
+	portRangeMax uint32
+	remote       *net.IPNet
+	remoteGroup  string
+	icmpType     string
+}
+
+func parseSecurityGroupRules(rules []*pb.SecurityGroupRule) ([]securityGroupRule, error) {
+	parsed := make([]securityGroupRule, 0, len(rules))
+	for i, rule := range rules {
+		r, err := parseSecurityGroupRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule %d: %w", i, err)
+		}
+		parsed = append(parsed, r)
+	}
+	return parsed, nil
+}
+
+func parseSecurityGroupRule(rule *pb.SecurityGroupRule) (securityGroupRule, error) {
+	if _, ok := pb.SecurityGroupRule_Protocol_name[int32(rule.Protocol)]; !ok {
+		return securityGroupRule{}, fmt.Errorf("unknown protocol %d", rule.Protocol)
+	}
+	r := securityGroupRule{
+		protocol:     rule.Protocol,
+		portRangeMin: rule.PortRangeMin,
+		portRangeMax: rule.PortRangeMax,
+		icmpType:     rule.IcmpType,
+	}
+
+	if r.portRangeMin != 0 || r.portRangeMax != 0 {
+		if r.protocol != pb.SecurityGroupRule_TCP && r.protocol != pb.SecurityGroupRule_UDP {
+			return securityGroupRule{}, fmt.Errorf("port range requires TCP or UDP, but got %s", r.protocol)
+		}
+		if r.portRangeMax == 0 {
+			r.portRangeMax = r.portRangeMin
+		}
+		if r.portRangeMin == 0 || r.portRangeMin > r.portRangeMax || r.portRangeMax > 65535 {
+			return securityGroupRule{}, fmt.Errorf("invalid port range %d-%d", rule.PortRangeMin, rule.PortRangeMax)
+		}
+	}
+
+	if r.icmpType != "" {
+		if r.protocol != pb.SecurityGroupRule_ICMP && r.protocol != pb.SecurityGroupRule_ICMPV6 {
+			return securityGroupRule{}, fmt.Errorf("ICMP type requires ICMP or ICMPV6, but got %s", r.protocol)
+		}
+		if _, _, err := r.icmpTypeCode(); err != nil {
+			return securityGroupRule{}, err
+		}
+	}
+
+	if rule.RemoteCidr != "" {
+		_, remote, err := net.ParseCIDR(rule.RemoteCidr)
+		if err != nil {
+			return securityGroupRule{}, fmt.Errorf("failed to parse remote CIDR: %w", err)
+		}
+		r.remote = remote
+	}
+
+	if rule.RemoteGroup != "" {
+		if r.remote != nil {
+			return securityGroupRule{}, fmt.Errorf("remote CIDR and remote group are exclusive")
+		}
+		if r.protocol == pb.SecurityGroupRule_ICMPV6 {
+			return securityGroupRule{}, fmt.Errorf("remote group requires IPv4, but got %s", r.protocol)
+		}
+		if err := validateRemoteGroupName(rule.RemoteGroup); err != nil {
+			return securityGroupRule{}, err
+		}
+		r.remoteGroup = rule.RemoteGroup
+	}
+
+	if r.remote != nil && (r.protocol == pb.SecurityGroupRule_ICMP || r.protocol == pb.SecurityGroupRule_ICMPV6) {
+		want := ipFamilyIPv4
+		if r.protocol == pb.SecurityGroupRule_ICMPV6 {
+			want = ipFamilyIPv6
+		}
+		if getIPFamily(r.remote.IP) != want {
+			return securityGroupRule{}, fmt.Errorf("%s requires an %s remote CIDR, but got %s", r.protocol, want, rule.RemoteCidr)
+		}
+	}
+
+	return r, nil
+}
+
+// family returns the family of the traffic that r allows, or false if r allows both families.
+// Remote groups are sets of IPv4 addresses.
+func (r securityGroupRule) family() (ipFamily, bool) {
+	switch {
+	case r.remote != nil:
+		return getIPFamily(r.remote.IP), true
+	case r.remoteGroup != "", r.protocol == pb.SecurityGroupRule_ICMP:
+		return ipFamilyIPv4, true
+	case r.protocol == pb.SecurityGroupRule_ICMPV6:
+		return ipFamilyIPv6, true
+	}
+	return 0, false
+}
+
+// appliesTo returns whether r allows traffic of family.
+func (r securityGroupRule) appliesTo(family ipFamily) bool {
+	f, ok := r.family()
+	return !ok || f == family
+}
+
+// icmpTypeCode returns the type and code of the ICMP or ICMPv6 type of r.
+func (r securityGroupRule) icmpTypeCode() (int, int, error) {
+	if r.protocol == pb.SecurityGroupRule_ICMPV6 {
+		return parseICMPType(r.icmpType, icmpv6TypeNames)
+	}
+	return parseICMPType(r.icmpType, icmpTypeNames)
+}
+
+// parseICMPType returns the type and code of an ICMP type of iptables, which is a number, a type/code pair,
+// a name in names or any. The type and code are -1 when they match any.
+func parseICMPType(icmpType string, names map[string][2]int) (int, int, error) {
+	if !icmpTypePattern.MatchString(icmpType) {
+		return 0, 0, fmt.Errorf("invalid ICMP type %s", icmpType)
+	}
+	if icmpType == "any" {
+		return -1, -1, nil
+	}
+	if v, ok := names[icmpType]; ok {
+		return v[0], v[1], nil
+	}
+
+	values := []int{-1, -1}
+	for i, n := range strings.Split(icmpType, "/") {
+		v, err := strconv.Atoi(n)
+		if err != nil {
+			return 0, 0, fmt.Errorf("unknown ICMP type %s", icmpType)
+		}
+		if v > 255 {
+			return 0, 0, fmt.Errorf("invalid ICMP type %s", icmpType)
+		}
+		values[i] = v
+	}
+	return values[0], values[1], nil
+}
+
+// rulespec returns the iptables rule that returns the traffic allowed by r. The remote address is
+// the source of ingress traffic and the destination of egress traffic.
+func (r securityGroupRule) rulespec(d direction) []string {
+	var spec []string
+	if r.remote != nil {
+		flag := "-s"
+		if d == directionEgress {
+			flag = "-d"
+		}
+		spec = append(spec, flag, r.remote.String())
+	}
+	if r.remoteGroup != "" {
+		flag := "src"
+		if d == directionEgress {
+			flag = "dst"
+		}
+		spec = append(spec, "-m", "set", "--match-set", getRemoteGroupSetName(r.remoteGroup), flag)
+	}
+
+	switch r.protocol {
+	case pb.SecurityGroupRule_TCP, pb.SecurityGroupRule_UDP:
+		protocol := strings.ToLower(r.protocol.String())
+		spec = append(spec, "-p", protocol, "-m", protocol)
+		if r.portRangeMin != 0 {
+			ports := strconv.Itoa(int(r.portRangeMin))
+			if r.portRangeMax != r.portRangeMin {
+				ports = fmt.Sprintf("%d:%d", r.portRangeMin, r.portRangeMax)
+			}
+			spec = append(spec, "--dport", ports)
+		}
+	case pb.SecurityGroupRule_ICMP:
+		spec = append(spec, "-p", "icmp")
+		if r.icmpType != "" {
+			spec = append(spec, "-m", "icmp", "--icmp-type", r.icmpType)
+		}
+	case pb.SecurityGroupRule_ICMPV6:
+		spec = append(spec, "-p", "ipv6-icmp")
+		if r.icmpType != "" {
+			spec = append(spec, "-m", "icmp6", "--icmpv6-type", r.icmpType)
+		}
+	}
+
+	return append(spec, "-j", actionRETURN)
+}
+
+// nftRule returns the nftables rule that returns the traffic allowed by r, like rulespec. The rule matches
+// the protocol of the family of r, because the chains of the nftables backend filter both families.
+func (r securityGroupRule) nftRule(d direction) nftRule {
+	var exprs [][]expr.Any
+	offset := uint32(nftablesOffsetIPSource)
+	if d == directionEgress {
+		offset = nftablesOffsetIPDestination
+	}
+	if family, ok := r.family(); ok {
+		exprs = append(exprs, nftProtocol(family))
+		if family == ipFamilyIPv6 {
+			offset = nftablesOffsetIPv6Source
+			if d == directionEgress {
+				offset = nftablesOffsetIPv6Destination
+			}
+		}
+	}
+	if r.remote != nil {
+		exprs = append(exprs, nftIPNet(offset, r.remote))
+	}
+	if r.remoteGroup != "" {
+		exprs = append(exprs, nftSet(offset, getRemoteGroupSetName(r.remoteGroup)))
+	}
+
+	switch r.protocol {
+	case pb.SecurityGroupRule_TCP, pb.SecurityGroupRule_UDP:
+		protocol := byte(syscall.IPPROTO_TCP)
+		if r.protocol == pb.SecurityGroupRule_UDP {
+			protocol = syscall.IPPROTO_UDP
+		}
+		exprs = append(exprs, nftL4Proto(protocol))
+		if r.portRangeMin != 0 {
+			exprs = append(exprs, nftPorts(nftablesOffsetDestPort, uint16(r.portRangeMin), uint16(r.portRangeMax)))
+		}
+	case pb.SecurityGroupRule_ICMP, pb.SecurityGroupRule_ICMPV6:
+		protocol := byte(syscall.IPPROTO_ICMP)
+		if r.protocol == pb.SecurityGroupRule_ICMPV6 {
+			protocol = syscall.IPPROTO_ICMPV6
+		}
+		exprs = append(exprs, nftL4Proto(protocol))
+		// the ICMP type is validated by parseSecurityGroupRule
+		if icmpType, icmpCode, _ := r.icmpTypeCode(); r.icmpType != "" && icmpType >= 0 {
+			exprs = append(exprs, nftICMP(icmpType, icmpCode))
+		}
+	}
+
+	return newNFTRule("", append(exprs, nftVerdict(expr.VerdictReturn, ""))...)
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+func TestParseSecurityGroupRule(t *testing.T) {
+	tests := []struct {
+		name        string
+		rule        *pb.SecurityGroupRule
+		wantIngress string
+		wantEgress  string
+		// families that the rule applies to, separated by commas
+		wantFamilies string
+		wantErr      bool
+	}{
+		{
+			name:         "any",
+			rule:         &pb.SecurityGroupRule{},
+			wantIngress:  "-j RETURN",
+			wantEgress:   "-j RETURN",
+			wantFamilies: "ipv4,ipv6",
+		},
+		{
+			name:         "any from cidr",
+			rule:         &pb.SecurityGroupRule{RemoteCidr: "192.0.2.1/24"},
+			wantIngress:  "-s 192.0.2.0/24 -j RETURN",
+			wantEgress:   "-d 192.0.2.0/24 -j RETURN",
+			wantFamilies: "ipv4",
+		},
+		{
+			name:         "tcp port from ipv6 cidr",
+			rule:         &pb.SecurityGroupRule{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 22, RemoteCidr: "2001:db8::1/32"},
+			wantIngress:  "-s 2001:db8::/32 -p tcp -m tcp --dport 22 -j RETURN",
+			wantEgress:   "-d 2001:db8::/32 -p tcp -m tcp --dport 22 -j RETURN",
+			wantFamilies: "ipv6",
+		},
+		{
+			name:        "tcp port",
+			rule:        &pb.SecurityGroupRule{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 22},
+			wantIngress: "-p tcp -m tcp --dport 22 -j RETURN",
+			wantEgress:  "-p tcp -m tcp --dport 22 -j RETURN",
+		},
+		{
+			name:        "udp port range",
+			rule:        &pb.SecurityGroupRule{Protocol: pb.SecurityGroupRule_UDP, PortRangeMin: 8000, PortRangeMax: 8100},
+			wantIngress: "-p udp -m udp --dport 8000:8100 -j RETURN",
+			wantEgress:  "-p udp -m udp --dport 8000:8100 -j RETURN",
+		},
+		{
+			name:        "all tcp ports",
+			rule:        &pb.SecurityGroupRule{Protocol: pb.SecurityGroupRule_TCP},
+			wantIngress: "-p tcp -m tcp -j RETURN",
+			wantEgress:  "-p tcp -m tcp -j RETURN",
+		},
+		{
+			name:        "icmp type and code",
+			rule:        &pb.SecurityGroupRule{Protocol: pb.SecurityGroupRule_ICMP, IcmpType: "3/4"},
+			wantIngress: "-p icmp -m icmp --icmp-type 3/4 -j RETURN",
+			wantEgress:  "-p icmp -m icmp --icmp-type 3/4 -j RETURN",
+		},
+		{
+			name:         "icmp type name",
+			rule:         &pb.SecurityGroupRule{Protocol: pb.SecurityGroupRule_ICMP, IcmpType: "echo-request"},
+			wantIngress:  "-p icmp -m icmp --icmp-type echo-request -j RETURN",
+			wantEgress:   "-p icmp -m icmp --icmp-type echo-request -j RETURN",
+			wantFamilies: "ipv4",
+		},
+		{
+			name:         "icmpv6 type name",
+			rule:         &pb.SecurityGroupRule{Protocol: pb.SecurityGroupRule_ICMPV6, IcmpType: "packet-too-big"},
+			wantIngress:  "-p ipv6-icmp -m icmp6 --icmpv6-type packet-too-big -j RETURN",
+			wantEgress:   "-p ipv6-icmp -m icmp6 --icmpv6-type packet-too-big -j RETURN",
+			wantFamilies: "ipv6",
+		},
+		{
+			name:         "any icmpv6 from ipv6 cidr",
+			rule:         &pb.SecurityGroupRule{Protocol: pb.SecurityGroupRule_ICMPV6, RemoteCidr: "2001:db8::/64"},
+			wantIngress:  "-s 2001:db8::/64 -p ipv6-icmp -j RETURN",
+			wantEgress:   "-d 2001:db8::/64 -p ipv6-icmp -j RETURN",
+			wantFamilies: "ipv6",
+		},
+		{
+			name:         "remote group",
+			rule:         &pb.SecurityGroupRule{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 5432, RemoteGroup: "db-clients"},
+			wantIngress:  "-m set --match-set callisto-gdb-clients src -p tcp -m tcp --dport 5432 -j RETURN",
+			wantEgress:   "-m set --match-set callisto-gdb-clients dst -p tcp -m tcp --dport 5432 -j RETURN",
+			wantFamilies: "ipv4",
+		},
+		{
+			name:    "remote cidr and group",
+			rule:    &pb.SecurityGroupRule{RemoteCidr: "192.0.2.0/24", RemoteGroup: "db-clients"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid remote group",
+			rule:    &pb.SecurityGroupRule{RemoteGroup: "db clients"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown protocol",
+			rule:    &pb.SecurityGroupRule{Protocol: 99},
+			wantErr: true,
+		},
+		{
+			name:    "port without protocol",
+			rule:    &pb.SecurityGroupRule{PortRangeMin: 22},
+			wantErr: true,
+		},
+		{
+			name:    "reversed port range",
+			rule:    &pb.SecurityGroupRule{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 443, PortRangeMax: 80},
+			wantErr: true,
+		},
+		{
+			name:    "port range without min",
+			rule:    &pb.SecurityGroupRule{Protocol: pb.SecurityGroupRule_TCP, PortRangeMax: 80},
+			wantErr: true,
+		},
+		{
+			name:    "too large port",
+			rule:    &pb.SecurityGroupRule{Protocol: pb.SecurityGroupRule_UDP, PortRangeMin: 65536},
+			wantErr: true,
+		},
+		{
+			name:    "icmp type of tcp",
+			rule:    &pb.SecurityGroupRule{Protocol: pb.SecurityGroupRule_TCP, IcmpType: "8"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid icmp type",
+			rule:    &pb.SecurityGroupRule{Protocol: pb.SecurityGroupRule_ICMP, IcmpType: "8 -j ACCEPT"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown icmp type name",
+			rule:    &pb.SecurityGroupRule{Protocol: pb.SecurityGroupRule_ICMP, IcmpType: "echo"},
+			wantErr: true,
+		},
+		{
+			name:    "too large icmp type",
+			rule:    &pb.SecurityGroupRule{Protocol: pb.SecurityGroupRule_ICMP, IcmpType: "256"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid cidr",
+			rule:    &pb.SecurityGroupRule{RemoteCidr: "192.0.2.0"},
+			wantErr: true,
+		},
+		{
+			name:    "icmp from ipv6 cidr",
+			rule:    &pb.SecurityGroupRule{Protocol: pb.SecurityGroupRule_ICMP, RemoteCidr: "2001:db8::/32"},
+			wantErr: true,
+		},
+		{
+			name:    "icmpv6 from ipv4 cidr",
+			rule:    &pb.SecurityGroupRule{Protocol: pb.SecurityGroupRule_ICMPV6, RemoteCidr: "192.0.2.0/24"},
+			wantErr: true,
+		},
+		{
+			name:    "icmpv6 from remote group",
+			rule:    &pb.SecurityGroupRule{Protocol: pb.SecurityGroupRule_ICMPV6, RemoteGroup: "db-clients"},
+			wantErr: true,
+		},
+		{
+			name:    "icmp type name of icmpv6",
+			rule:    &pb.SecurityGroupRule{Protocol: pb.SecurityGroupRule_ICMPV6, IcmpType: "timestamp-request"},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		got, err := parseSecurityGroupRule(test.rule)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%s: should be error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: should not be error but: %+v", test.name, err)
+			continue
+		}
+		if ingress := strings.Join(got.rulespec(directionIngress), " "); ingress != test.wantIngress {
+			t.Errorf("%s: want %s, but got %s", test.name, test.wantIngress, ingress)
+		}
+		if egress := strings.Join(got.rulespec(directionEgress), " "); egress != test.wantEgress {
+			t.Errorf("%s: want %s, but got %s", test.name, test.wantEgress, egress)
+		}
+		if test.wantFamilies == "" {
+			continue
+		}
+		var families []string
+		for _, family := range ipFamilies {
+			if got.appliesTo(family) {
+				families = append(families, family.String())
+			}
+		}
+		if strings.Join(families, ",") != test.wantFamilies {
+			t.Errorf("%s: want families %s, but got %s", test.name, test.wantFamilies, strings.Join(families, ","))
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+const (
+	defaultSecurityGroupStateDir = "/var/lib/teleskop/security-groups"
+
+	securityGroupStateExt = ".json"
+)
+
+// securityGroupStore records the desired security group of every interface secured by the agent,
+// so that the verifier can repair the chains after they are flushed by someone else.
+// A record is the AddSecurityGroupRequest of the interface with its current rules.
+type securityGroupStore struct {
+	dir string
+
+	mu sync.Mutex
+}
+
+func newSecurityGroupStore(dir string) (*securityGroupStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create security group state directory: %w", err)
+	}
+	return &securityGroupStore{dir: dir}, nil
+}
+
+// Get returns the record of intf, or nil if it does not exist.
+func (s *securityGroupStore) Get(intf string) (*pb.AddSecurityGroupRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, err := s.path(intf)
+	if err != nil {
+		return nil, err
+	}
+	return s.read(path)
+}
+
+// List returns the records sorted by interface name.
+func (s *securityGroupStore) List() ([]*pb.AddSecurityGroupRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read security group state directory: %w", err)
+	}
+	var records []*pb.AddSecurityGroupRequest
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != securityGroupStateExt || strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		record, err := s.read(filepath.Join(s.dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if record != nil {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Interface < records[j].Interface })
+	return records, nil
+}
+
+// Put writes record atomically, without its request_id.
+func (s *securityGroupStore) Put(record *pb.AddSecurityGroupRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, err := s.path(record.Interface)
+	if err != nil {
+		return err
+	}
+	record = proto.Clone(record).(*pb.AddSecurityGroupRequest)
+	record.RequestId = ""
+	b, err := protojson.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal security group state: %w", err)
+	}
+
+	f, err := ioutil.TempFile(s.dir, "."+filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("failed to create security group state: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write security group state: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to sync security group state: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close security group state: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to rename security group state: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes the record of intf. A missing record is not an error.
+func (s *securityGroupStore) Delete(intf string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, err := s.path(intf)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove security group state: %w", err)
+	}
+	return nil
+}
+
+func (s *securityGroupStore) read(path string) (*pb.AddSecurityGroupRequest, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read security group state: %w", err)
+	}
+	record := &pb.AddSecurityGroupRequest{}
+	if err := protojson.Unmarshal(b, record); err != nil {
+		return nil, fmt.Errorf("failed to parse security group state %s: %w", filepath.Base(path), err)
+	}
+	return record, nil
+}
+
+// path returns the file of intf. Interface names never contain slashes.
+func (s *securityGroupStore) path(intf string) (string, error) {
+	if intf == "" || intf == "." || intf == ".." || strings.ContainsAny(intf, "/\x00") {
+		return "", fmt.Errorf("invalid interface name %q", intf)
+	}
+	return filepath.Join(s.dir, intf+securityGroupStateExt), nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+func TestSecurityGroupStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "teleskop-security-groups")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %+v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := newSecurityGroupStore(dir)
+	if err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+
+	for _, intf := range []string{"tap1", "tap0"} {
+		record := &pb.AddSecurityGroupRequest{Interface: intf, IpAddress: "192.0.2.100", MacAddress: "52:54:00:00:00:01", RequestId: "req"}
+		if err := s.Put(record); err != nil {
+			t.Fatalf("should not be error but: %+v", err)
+		}
+	}
+
+	got, err := s.Get("tap0")
+	if err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	want := &pb.AddSecurityGroupRequest{Interface: "tap0", IpAddress: "192.0.2.100", MacAddress: "52:54:00:00:00:01"}
+	if !proto.Equal(got, want) {
+		t.Errorf("want %s, but got %s", want, got)
+	}
+
+	if err := s.Delete("tap1"); err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	if err := s.Delete("tap1"); err != nil {
+		t.Errorf("deleting a missing record should not be error but: %+v", err)
+	}
+	records, err := s.List()
+	if err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	if len(records) != 1 || records[0].Interface != "tap0" {
+		t.Errorf("want [tap0], but got %v", records)
+	}
+	if got, err := s.Get("tap1"); err != nil || got != nil {
+		t.Errorf("want nil, but got %v, %+v", got, err)
+	}
+
+	for _, intf := range []string{"", ".", "..", "../tap0"} {
+		if err := s.Put(&pb.AddSecurityGroupRequest{Interface: intf}); err == nil {
+			t.Errorf("%q: should be error", intf)
+		}
+	}
+}
+
+func TestSecurityGroupRecords(t *testing.T) {
+	a, _ := newTestAgent(t)
+	ctx := context.Background()
+	if _, err := a.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{}); err != nil {
+		t.Fatalf("failed to setup default security group: %+v", err)
+	}
+
+	_, err := a.AddSecurityGroup(ctx, &pb.AddSecurityGroupRequest{
+		Interface:  "tap0",
+		IpAddress:  "192.0.2.100",
+		MacAddress: "52:54:00:00:00:01",
+		RequestId:  "add",
+	})
+	if err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+
+	rules := []*pb.SecurityGroupRule{{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 22}}
+	_, err = a.UpdateSecurityGroupRules(ctx, &pb.UpdateSecurityGroupRulesRequest{Interface: "tap0", IngressRules: rules})
+	if err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	record, err := a.securityGroups.Get("tap0")
+	if err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	want := &pb.AddSecurityGroupRequest{
+		Interface:    "tap0",
+		IpAddress:    "192.0.2.100",
+		MacAddress:   "52:54:00:00:00:01",
+		IngressRules: rules,
+	}
+	if !proto.Equal(record, want) {
+		t.Errorf("want %s, but got %s", want, record)
+	}
+
+	if _, err := a.RemoveSecurityGroup(ctx, &pb.RemoveSecurityGroupRequest{Interface: "tap0"}); err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	records, err := a.securityGroups.List()
+	if err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("want no records, but got %d", len(records))
+	}
+}
@@ -0,0 +1,431 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSecurityGroupVerifyInterval = 30 * time.Second
+
+	repairResourceChain = "chain"
+	repairResourceRule  = "rule"
+	repairResourceHook  = "hook"
+	repairResourceJump  = "jump"
+)
+
+type securityGroupConfig struct {
+	// Backend is the firewall that security groups are applied to, iptables or nftables.
+	Backend string `yaml:"backend"`
+	// StateDir is the directory that the desired security groups are recorded to.
+	StateDir string `yaml:"state_dir"`
+	// VerifyInterval is how often the chains are compared with the records and repaired.
+	VerifyInterval time.Duration `yaml:"verify_interval"`
+}
+
+func (c securityGroupConfig) validate() error {
+	if c.Backend != firewallBackendIPTables && c.Backend != firewallBackendNFTables {
+		return fmt.Errorf("backend must be %s or %s: %q", firewallBackendIPTables, firewallBackendNFTables, c.Backend)
+	}
+	if c.StateDir == "" {
+		return fmt.Errorf("state_dir must be set")
+	}
+	if c.VerifyInterval <= 0 {
+		return fmt.Errorf("verify_interval must be positive: %s", c.VerifyInterval)
+	}
+	return nil
+}
+
+// sharedRule is a rule of the shared chains that every security group depends on.
+type sharedRule struct {
+	resource string
+	chain    string
+	rule     []string
+	// position is where the rule is inserted if it is missing, or 0 to append it
+	position int
+}
+
+var (
+	sharedChains = []string{
+		chainCallistoSGFallback,
+		chainCallistoSG,
+		chainCallistoINPUT,
+		chainCallistoFORWARD,
+	}
+	sharedRules = []sharedRule{
+		{resource: repairResourceRule, chain: chainCallistoSGFallback, rule: ruleSGFallback},
+		{resource: repairResourceRule, chain: chainCallistoSG, rule: ruleSG},
+		// hooks are restored at the top, so that rules added by other daemons do not shadow them
+		{resource: repairResourceHook, chain: chainINPUT, rule: ruleINPUT, position: 1},
+		{resource: repairResourceHook, chain: chainFORWARD, rule: ruleFORWARD, position: 1},
+	}
+)
+
+// securityGroupChain is a chain of an interface.
+type securityGroupChain struct {
+	name  string
+	rules [][]string
+}
+
+// getSecurityGroupChains returns the chains of intf of family in the order they are created.
+func getSecurityGroupChains(intf link, family ipFamily) []securityGroupChain {
+	return []securityGroupChain{
+		{name: getSOURCEChainName(intf), rules: getSOURCESGChainRules(intf, family)},
+		{name: getOUTPUTChainName(intf), rules: getOUTPUTSGChainRules(intf, family)},
+		{name: getINPUTChainName(intf), rules: getINPUTSGChainRules(intf, family)},
+	}
+}
+
+// securityGroupRepair is a repair logged after its transaction is applied.
+type securityGroupRepair struct {
+	resource string
+	action   string
+	fields   []zap.Field
+}
+
+// securityGroupDiff is the difference between the desired security group of an interface and iptables.
+type securityGroupDiff struct {
+	missingChains []securityGroupChain
+	driftedChains []securityGroupChain
+	missingJumps  []jumpRule
+}
+
+func (d securityGroupDiff) empty() bool {
+	return len(d.missingChains) == 0 && len(d.driftedChains) == 0 && len(d.missingJumps) == 0
+}
+
+// securityGroupVerifier compares the chains of the recorded security groups with the firewall
+// periodically, and repairs missing hooks, chains and rules, e.g. after iptables -F.
+type securityGroupVerifier struct {
+	agent  *agent
+	logger *zap.Logger
+
+	// mu serializes verify passes and guards interval
+	mu       sync.Mutex
+	interval time.Duration
+}
+
+func newSecurityGroupVerifier(a *agent, interval time.Duration, logger *zap.Logger) *securityGroupVerifier {
+	return &securityGroupVerifier{
+		agent:    a,
+		logger:   logger.Named("security-group-verifier"),
+		interval: interval,
+	}
+}
+
+// SetInterval changes the interval of the verifier.
+func (v *securityGroupVerifier) SetInterval(interval time.Duration) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.interval = interval
+}
+
+func (v *securityGroupVerifier) getInterval() time.Duration {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.interval
+}
+
+// Run verifies security groups every interval until ctx is done.
+func (v *securityGroupVerifier) Run(ctx context.Context) error {
+	interval := v.getInterval()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := v.Verify(ctx); err != nil {
+				v.logger.Warn("failed to verify security groups", zap.Error(err))
+			}
+		}
+
+		if next := v.getInterval(); next != interval {
+			interval = next
+			ticker.Reset(interval)
+		}
+	}
+}
+
+// Verify runs a single verify pass.
+func (v *securityGroupVerifier) Verify(ctx context.Context) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if err := v.verify(ctx); err != nil {
+		securityGroupVerifyRunsTotal.WithLabelValues("failure").Inc()
+		return err
+	}
+
+	securityGroupVerifyRunsTotal.WithLabelValues("success").Inc()
+	securityGroupVerifyLastSuccess.SetToCurrentTime()
+	return nil
+}
+
+func (v *securityGroupVerifier) verify(ctx context.Context) error {
+	records, err := v.agent.securityGroups.List()
+	if err != nil {
+		return fmt.Errorf("failed to list security group records: %w", err)
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	failed := 0
+	var intfs []link
+	for _, record := range records {
+		intf, err := parseSecurityGroupLink(record)
+		if err != nil {
+			v.logger.Warn("failed to verify security group", zap.String("interface", record.Interface), zap.Error(fmt.Errorf("invalid security group record: %w", err)))
+			failed++
+			continue
+		}
+		intfs = append(intfs, intf)
+	}
+
+	repairs, drifted, err := v.agent.firewall.Verify(ctx, intfs)
+	if err != nil {
+		return err
+	}
+	v.repaired(repairs)
+
+	for _, name := range drifted {
+		if err := v.repairInterface(ctx, name); err != nil {
+			v.logger.Warn("failed to verify security group", zap.String("interface", name), zap.Error(err))
+			failed++
+		}
+	}
+	if failed != 0 {
+		return fmt.Errorf("failed to verify %d of %d security groups", failed, len(records))
+	}
+	return nil
+}
+
+// repairInterface repairs the drifted security group of name by its latest record.
+func (v *securityGroupVerifier) repairInterface(ctx context.Context, name string) error {
+	// the security group may have been changed by the api since it was verified
+	unlock, err := v.agent.lockSecurityGroup(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	record, err := v.agent.securityGroups.Get(name)
+	if err != nil || record == nil {
+		return err
+	}
+	intf, err := parseSecurityGroupLink(record)
+	if err != nil {
+		return fmt.Errorf("invalid security group record: %w", err)
+	}
+
+	repairs, err := v.agent.firewall.Repair(ctx, intf)
+	if err != nil {
+		return err
+	}
+	v.repaired(repairs)
+	return nil
+}
+
+func (v *securityGroupVerifier) repaired(repairs []securityGroupRepair) {
+	for _, repair := range repairs {
+		securityGroupRepairsTotal.WithLabelValues(repair.resource, repair.action).Inc()
+
+		fields := append([]zap.Field{
+			zap.String("resource", repair.resource),
+			zap.String("action", repair.action),
+		}, repair.fields...)
+		v.logger.Warn("repaired security group drift", fields...)
+	}
+}
+
+// diffSecurityGroup compares the chains of family and jump rules of intf with saved.
+func diffSecurityGroup(ctx context.Context, client iptablesAPI, saved map[string][]string, intf link, family ipFamily) (securityGroupDiff, error) {
+	var diff securityGroupDiff
+	for _, chain := range getSecurityGroupChains(intf, family) {
+		rules, ok := saved[chain.name]
+		if !ok {
+			diff.missingChains = append(diff.missingChains, chain)
+			continue
+		}
+		equal, err := rulesEqual(ctx, client, chain.name, rules, chain.rules)
+		if err != nil {
+			return securityGroupDiff{}, err
+		}
+		if !equal {
+			diff.driftedChains = append(diff.driftedChains, chain)
+		}
+	}
+
+	for _, jump := range getJumpRules(intf) {
+		exists, err := containsRule(ctx, client, saved, jump.chain, jump.rule)
+		if err != nil {
+			return securityGroupDiff{}, err
+		}
+		if !exists {
+			diff.missingJumps = append(diff.missingJumps, jump)
+		}
+	}
+
+	return diff, nil
+}
+
+// rulesEqual returns whether the saved rules of chain are specs.
+func rulesEqual(ctx context.Context, client iptablesAPI, chain string, rules []string, specs [][]string) (bool, error) {
+	if len(rules) != len(specs) {
+		return false, nil
+	}
+	for i, spec := range specs {
+		if sameRule(rules[i], spec) {
+			continue
+		}
+		exists, err := client.Exists(ctx, tableFilter, chain, spec...)
+		if err != nil {
+			return false, fmt.Errorf("failed to check %s rule: %w", chain, err)
+		}
+		if !exists {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Verify restores the shared chains, their default rules and the hooks in INPUT and FORWARD in a transaction
+// per family, and compares the chains and jump rules of intfs with iptables-save and ip6tables-save.
+func (b *iptablesBackend) Verify(ctx context.Context, intfs []link) ([]securityGroupRepair, []string, error) {
+	var repairs []securityGroupRepair
+	var drifted []string
+	found := map[string]bool{}
+	for _, family := range ipFamilies {
+		r, d, err := b.verifyFamily(ctx, family, intfs)
+		repairs = append(repairs, r...)
+		if err != nil {
+			return repairs, nil, err
+		}
+		for _, name := range d {
+			if !found[name] {
+				found[name] = true
+				drifted = append(drifted, name)
+			}
+		}
+	}
+	return repairs, drifted, nil
+}
+
+func (b *iptablesBackend) verifyFamily(ctx context.Context, family ipFamily, intfs []link) ([]securityGroupRepair, []string, error) {
+	client, err := b.client(family)
+	if err != nil {
+		return nil, nil, err
+	}
+	saved, err := saveIPTables(ctx, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx := newIPTablesTransaction(tableFilter)
+	var repairs []securityGroupRepair
+
+	created := map[string]bool{}
+	for _, chain := range sharedChains {
+		if _, ok := saved[chain]; ok {
+			continue
+		}
+		tx.newChain(chain)
+		created[chain] = true
+		repairs = append(repairs, securityGroupRepair{repairResourceChain, driftActionCreate, []zap.Field{zap.Stringer("family", family), zap.String("chain", chain)}})
+	}
+
+	for _, rule := range sharedRules {
+		if !created[rule.chain] {
+			exists, err := containsRule(ctx, client, saved, rule.chain, rule.rule)
+			if err != nil {
+				return nil, nil, err
+			}
+			if exists {
+				continue
+			}
+		}
+		if rule.position == 0 {
+			tx.appendRule(rule.chain, rule.rule...)
+		} else {
+			tx.insertRule(rule.chain, rule.position, rule.rule...)
+		}
+		repairs = append(repairs, securityGroupRepair{rule.resource, driftActionCreate, []zap.Field{zap.Stringer("family", family), zap.String("chain", rule.chain)}})
+	}
+
+	if err := tx.commit(ctx, client); err != nil {
+		return nil, nil, err
+	}
+
+	var drifted []string
+	for _, intf := range intfs {
+		diff, err := diffSecurityGroup(ctx, client, saved, intf, family)
+		if err != nil {
+			return repairs, nil, err
+		}
+		if !diff.empty() {
+			drifted = append(drifted, intf.Name)
+		}
+	}
+	return repairs, drifted, nil
+}
+
+// Repair creates the missing chains and jump rules of intf, and refills the drifted chains in a transaction
+// per family.
+func (b *iptablesBackend) Repair(ctx context.Context, intf link) ([]securityGroupRepair, error) {
+	var repairs []securityGroupRepair
+	for _, family := range ipFamilies {
+		r, err := b.repairFamily(ctx, family, intf)
+		if err != nil {
+			return repairs, err
+		}
+		repairs = append(repairs, r...)
+	}
+	return repairs, nil
+}
+
+func (b *iptablesBackend) repairFamily(ctx context.Context, family ipFamily, intf link) ([]securityGroupRepair, error) {
+	client, err := b.client(family)
+	if err != nil {
+		return nil, err
+	}
+	saved, err := saveIPTables(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := diffSecurityGroup(ctx, client, saved, intf, family)
+	if err != nil || diff.empty() {
+		return nil, err
+	}
+	if err := b.createRemoteGroups(ctx, intf.IngressRules, intf.EgressRules); err != nil {
+		return nil, fmt.Errorf("failed to create remote groups: %w", err)
+	}
+
+	tx := newIPTablesTransaction(tableFilter)
+	var repairs []securityGroupRepair
+	for _, chain := range diff.missingChains {
+		addSGChain(tx, chain.name, chain.rules)
+		repairs = append(repairs, securityGroupRepair{repairResourceChain, driftActionCreate, []zap.Field{zap.String("interface", intf.Name), zap.Stringer("family", family), zap.String("chain", chain.name)}})
+	}
+	for _, chain := range diff.driftedChains {
+		replaceSGChain(tx, chain.name, chain.rules)
+		repairs = append(repairs, securityGroupRepair{repairResourceChain, driftActionUpdate, []zap.Field{zap.String("interface", intf.Name), zap.Stringer("family", family), zap.String("chain", chain.name)}})
+	}
+	for _, jump := range diff.missingJumps {
+		tx.insertRule(jump.chain, jump.position, jump.rule...)
+		repairs = append(repairs, securityGroupRepair{repairResourceJump, driftActionCreate, []zap.Field{zap.String("interface", intf.Name), zap.Stringer("family", family), zap.String("chain", jump.chain), zap.String("target", jump.target())}})
+	}
+
+	if err := tx.commit(ctx, client); err != nil {
+		return nil, err
+	}
+	return repairs, nil
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+func TestSecurityGroupVerifierVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(ctx context.Context, f *fakeIPTables) error
+		// wantFORWARD is the FORWARD chain after the repair, if it differs from before the drift
+		wantFORWARD []string
+	}{
+		{
+			name:   "no drift",
+			mutate: func(ctx context.Context, f *fakeIPTables) error { return nil },
+		},
+		{
+			name: "hook deleted",
+			mutate: func(ctx context.Context, f *fakeIPTables) error {
+				return f.Delete(ctx, tableFilter, chainFORWARD, ruleFORWARD...)
+			},
+		},
+		{
+			name: "FORWARD rewritten by another daemon",
+			mutate: func(ctx context.Context, f *fakeIPTables) error {
+				if err := f.ClearChain(ctx, tableFilter, chainFORWARD); err != nil {
+					return err
+				}
+				return f.AppendUnique(ctx, tableFilter, chainFORWARD, "-j", "DOCKER-USER")
+			},
+			wantFORWARD: []string{"-j callisto-FORWARD", "-j DOCKER-USER"},
+		},
+		{
+			name:   "all chains flushed and deleted",
+			mutate: flushFakeIPTables,
+		},
+		{
+			name: "jump rule deleted",
+			mutate: func(ctx context.Context, f *fakeIPTables) error {
+				return f.Delete(ctx, tableFilter, chainCallistoSG, getSGRules(link{Name: "tap0"})[0]...)
+			},
+		},
+		{
+			name: "ingress rule deleted",
+			mutate: func(ctx context.Context, f *fakeIPTables) error {
+				return f.Delete(ctx, tableFilter, "callisto-itap0", "-p", "tcp", "-m", "tcp", "--dport", "22", "-j", actionRETURN)
+			},
+		},
+		{
+			name: "source chain allows any address",
+			mutate: func(ctx context.Context, f *fakeIPTables) error {
+				if err := f.ClearChain(ctx, tableFilter, "callisto-stap0"); err != nil {
+					return err
+				}
+				return f.Insert(ctx, tableFilter, "callisto-stap0", 1, "-j", actionRETURN)
+			},
+		},
+		{
+			name: "jump to source chain deleted",
+			mutate: func(ctx context.Context, f *fakeIPTables) error {
+				return f.Delete(ctx, tableFilter, "callisto-otap0", "-j", "callisto-stap0")
+			},
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		ctx := context.Background()
+		setupVerifierTestSecurityGroups(t, a)
+
+		want := savedFakeIPTables(t, f.iptables)
+		if test.wantFORWARD != nil {
+			want[chainFORWARD] = test.wantFORWARD
+		}
+		if err := test.mutate(ctx, f.iptables); err != nil {
+			t.Fatalf("%s: failed to mutate iptables: %+v", test.name, err)
+		}
+
+		v := newSecurityGroupVerifier(a, defaultSecurityGroupVerifyInterval, a.logger)
+		if err := v.Verify(ctx); err != nil {
+			t.Errorf("%s: should not be error but: %+v", test.name, err)
+			continue
+		}
+
+		got := savedFakeIPTables(t, f.iptables)
+		if got, want := formatSavedIPTables(got), formatSavedIPTables(want); got != want {
+			t.Errorf("%s: want %s, but got %s", test.name, want, got)
+		}
+		rules := got[chainCallistoSG]
+		if len(rules) == 0 || rules[len(rules)-1] != "-j ACCEPT" {
+			t.Errorf("%s: want -j ACCEPT at the end of %s, but got %q", test.name, chainCallistoSG, rules)
+		}
+	}
+}
+
+func TestSecurityGroupVerifierSkipsRemoved(t *testing.T) {
+	a, f := newTestAgent(t)
+	ctx := context.Background()
+	setupVerifierTestSecurityGroups(t, a)
+
+	if _, err := a.RemoveSecurityGroup(ctx, &pb.RemoveSecurityGroupRequest{Interface: "tap1"}); err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	if err := flushFakeIPTables(ctx, f.iptables); err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+
+	v := newSecurityGroupVerifier(a, defaultSecurityGroupVerifyInterval, a.logger)
+	if err := v.Verify(ctx); err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+	if !f.iptables.hasChain(tableFilter, "callisto-itap0") {
+		t.Errorf("chain of tap0 should be restored")
+	}
+	if f.iptables.hasChain(tableFilter, "callisto-itap1") {
+		t.Errorf("chain of removed tap1 should not be restored")
+	}
+}
+
+func setupVerifierTestSecurityGroups(t *testing.T, a *agent) {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := a.SetupDefaultSecurityGroup(ctx, &pb.SetupDefaultSecurityGroupRequest{}); err != nil {
+		t.Fatalf("failed to setup default security group: %+v", err)
+	}
+	for _, req := range []*pb.AddSecurityGroupRequest{
+		{
+			Interface:  "tap0",
+			IpAddress:  "192.0.2.100",
+			MacAddress: "52:54:00:00:00:01",
+			IngressRules: []*pb.SecurityGroupRule{
+				{Protocol: pb.SecurityGroupRule_TCP, PortRangeMin: 22},
+				{Protocol: pb.SecurityGroupRule_ICMP, IcmpType: "echo-request"},
+			},
+			EgressRules: []*pb.SecurityGroupRule{{}},
+		},
+		{
+			Interface:  "tap1",
+			IpAddress:  "192.0.2.101",
+			MacAddress: "52:54:00:00:00:02",
+		},
+	} {
+		if _, err := a.AddSecurityGroup(ctx, req); err != nil {
+			t.Fatalf("failed to add security group: %+v", err)
+		}
+	}
+}
+
+// flushFakeIPTables deletes every rule and user-defined chain like iptables -F && iptables -X.
+func flushFakeIPTables(ctx context.Context, f *fakeIPTables) error {
+	chains, err := f.ListChains(ctx, tableFilter)
+	if err != nil {
+		return err
+	}
+	for _, chain := range chains {
+		if err := f.ClearChain(ctx, tableFilter, chain); err != nil {
+			return err
+		}
+	}
+	for _, chain := range chains {
+		if chain == chainINPUT || chain == chainFORWARD || chain == "OUTPUT" {
+			continue
+		}
+		if err := f.DeleteChain(ctx, tableFilter, chain); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func savedFakeIPTables(t *testing.T, f *fakeIPTables) map[string][]string {
+	t.Helper()
+
+	out, err := f.Save(context.Background(), tableFilter)
+	if err != nil {
+		t.Fatalf("failed to save iptables: %+v", err)
+	}
+	saved, err := parseIPTablesSave(out)
+	if err != nil {
+		t.Fatalf("failed to parse iptables-save: %+v", err)
+	}
+	return saved
+}
+
+// formatSavedIPTables formats saved for comparison. Jump rules in the shared chains are sorted,
+// because repairs move them to the top.
+func formatSavedIPTables(saved map[string][]string) string {
+	var chains []string
+	for chain := range saved {
+		chains = append(chains, chain)
+	}
+	sort.Strings(chains)
+
+	var lines []string
+	for _, chain := range chains {
+		rules := append([]string(nil), saved[chain]...)
+		if chain == chainCallistoSG || chain == chainCallistoFORWARD {
+			sort.Strings(rules)
+		}
+		lines = append(lines, chain+": "+strings.Join(rules, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
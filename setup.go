@@ -4,7 +4,8 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/lovi-cloud/go-os-brick/osbrick"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	dspb "github.com/lovi-cloud/satelit/api/satelit_datastore"
 	pb "github.com/lovi-cloud/teleskop/protoc/agent"
@@ -22,7 +23,7 @@ func (a *agent) setup(ctx context.Context, hostname, endpoint, parentInterfaceNa
 			MetadataCidr: bridge.MetadataCidr,
 			InternalOnly: bridge.InternalOnly,
 		})
-		if err != nil {
+		if err != nil && status.Code(err) != codes.AlreadyExists {
 			return err
 		}
 		if bridge.InternalOnly {
@@ -32,14 +33,14 @@ func (a *agent) setup(ctx context.Context, hostname, endpoint, parentInterfaceNa
 			VlanId:          bridge.VlanId,
 			ParentInterface: parentInterfaceName,
 		})
-		if err != nil {
+		if err != nil && status.Code(err) != codes.AlreadyExists {
 			return err
 		}
 		_, err = a.AddInterfaceToBridge(ctx, &pb.AddInterfaceToBridgeRequest{
 			Bridge:    bridge.Name,
 			Interface: fmt.Sprintf("%s.%d", parentInterfaceName, bridge.VlanId),
 		})
-		if err != nil {
+		if err != nil && status.Code(err) != codes.AlreadyExists {
 			return err
 		}
 	}
@@ -49,7 +50,7 @@ func (a *agent) setup(ctx context.Context, hostname, endpoint, parentInterfaceNa
 		return err
 	}
 
-	iqn, err := osbrick.GetIQN(ctx)
+	iqn, err := a.osbrick.GetIQN(ctx)
 	if err != nil {
 		return err
 	}
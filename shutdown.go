@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+
+	"github.com/lovi-cloud/teleskop/dhcp"
+	"github.com/lovi-cloud/teleskop/metadata"
+)
+
+const (
+	defaultShutdownTimeout = 30 * time.Second
+)
+
+// notifyShutdown returns a context that is canceled when SIGTERM or SIGINT is received.
+func notifyShutdown(logger *zap.Logger) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		defer signal.Stop(sigCh)
+
+		select {
+		case sig := <-sigCh:
+			logger.Info("received signal", zap.String("signal", sig.String()))
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
+
+// shutdowner stops the servers of teleskop and closes the connections to the dependencies.
+type shutdowner struct {
+	grpcServer     *grpc.Server
+	healthServer   *health.Server
+	dhcpServer     *dhcp.Server
+	metadataServer *metadata.Server
+	operations     *operationManager
+	libvirtConn    *libvirtConnection
+	satelitConn    *grpc.ClientConn
+	shutdownTracer func(context.Context) error
+	logger         *zap.Logger
+}
+
+// Shutdown drains in-flight requests until ctx is done, and then closes the connections.
+func (s *shutdowner) Shutdown(ctx context.Context) error {
+	// let load balancers and satelit stop sending new requests
+	s.healthServer.Shutdown()
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, 3)
+	wg.Add(4)
+	go func() {
+		defer wg.Done()
+		gracefulStop(ctx, s.grpcServer)
+	}()
+	go func() {
+		defer wg.Done()
+		if err := s.dhcpServer.Shutdown(ctx); err != nil {
+			errCh <- fmt.Errorf("failed to shutdown dhcp server: %w", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		if err := s.metadataServer.Shutdown(ctx); err != nil {
+			errCh <- fmt.Errorf("failed to shutdown metadata server: %w", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		if err := s.operations.Shutdown(ctx); err != nil {
+			errCh <- fmt.Errorf("failed to wait for operations: %w", err)
+		}
+	}()
+	wg.Wait()
+	close(errCh)
+
+	var result error
+	for err := range errCh {
+		s.logger.Warn("failed to shutdown gracefully", zap.Error(err))
+		result = err
+	}
+
+	if err := s.libvirtConn.Close(); err != nil {
+		s.logger.Warn("failed to close libvirt connection", zap.Error(err))
+	}
+	if err := s.satelitConn.Close(); err != nil {
+		s.logger.Warn("failed to close satelit connection", zap.Error(err))
+	}
+	if err := s.shutdownTracer(ctx); err != nil {
+		s.logger.Warn("failed to flush spans", zap.Error(err))
+	}
+
+	s.logger.Info("shutdown completed")
+	return result
+}
+
+// gracefulStop stops server gracefully, or forcibly when ctx is done.
+func gracefulStop(ctx context.Context, server *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		server.Stop()
+		<-stopped
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+// blockingAgent blocks GetVirtualMachineState until release is closed.
+type blockingAgent struct {
+	pb.UnimplementedAgentServer
+
+	started chan struct{}
+	release chan struct{}
+}
+
+func (a *blockingAgent) GetVirtualMachineState(ctx context.Context, req *pb.GetVirtualMachineStateRequest) (*pb.GetVirtualMachineStateResponse, error) {
+	close(a.started)
+	<-a.release
+	return &pb.GetVirtualMachineStateResponse{}, nil
+}
+
+func TestGracefulStop(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		// release finishes the in-flight request while stopping
+		release bool
+		want    codes.Code
+	}{
+		{
+			name:    "drain in-flight request",
+			timeout: 5 * time.Second,
+			release: true,
+			want:    codes.OK,
+		},
+		{
+			name:    "stop forcibly after timeout",
+			timeout: 50 * time.Millisecond,
+			release: false,
+			want:    codes.Unavailable,
+		},
+	}
+	for _, test := range tests {
+		agent := &blockingAgent{started: make(chan struct{}), release: make(chan struct{})}
+		lis := bufconn.Listen(1024 * 1024)
+		server := grpc.NewServer()
+		pb.RegisterAgentServer(server, agent)
+		go server.Serve(lis)
+
+		conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return lis.Dial()
+		}))
+		if err != nil {
+			t.Fatalf("%s: failed to dial: %+v", test.name, err)
+		}
+
+		errCh := make(chan error, 1)
+		go func() {
+			_, err := pb.NewAgentClient(conn).GetVirtualMachineState(context.Background(), &pb.GetVirtualMachineStateRequest{Uuid: "uuid"})
+			errCh <- err
+		}()
+		<-agent.started
+
+		ctx, cancel := context.WithTimeout(context.Background(), test.timeout)
+		stopped := make(chan struct{})
+		go func() {
+			gracefulStop(ctx, server)
+			close(stopped)
+		}()
+		if test.release {
+			close(agent.release)
+		}
+
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s: server should be stopped", test.name)
+		}
+		if got := status.Code(<-errCh); got != test.want {
+			t.Errorf("%s: want %s, but got %s", test.name, test.want, got)
+		}
+
+		if !test.release {
+			close(agent.release)
+		}
+		cancel()
+		conn.Close()
+	}
+}
+
+func TestNotifyShutdown(t *testing.T) {
+	ctx, cancel := notifyShutdown(zap.NewNop())
+	defer cancel()
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatalf("failed to send signal: %+v", err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("context should be canceled by SIGTERM")
+	}
+}
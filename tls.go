@@ -0,0 +1,204 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type tlsConfig struct {
+	CAFile     string `yaml:"ca_file"`
+	CertFile   string `yaml:"cert_file"`
+	KeyFile    string `yaml:"key_file"`
+	ServerName string `yaml:"server_name"`
+}
+
+// Enabled reports whether TLS is configured.
+func (c tlsConfig) Enabled() bool {
+	return c.CAFile != "" || c.CertFile != "" || c.KeyFile != ""
+}
+
+func (c tlsConfig) validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if c.CAFile == "" || c.CertFile == "" || c.KeyFile == "" {
+		return fmt.Errorf("ca_file, cert_file and key_file must be set together")
+	}
+	return nil
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (c tlsConfig) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("ca_file", c.CAFile)
+	enc.AddString("cert_file", c.CertFile)
+	enc.AddString("key_file", c.KeyFile)
+	if c.ServerName != "" {
+		enc.AddString("server_name", c.ServerName)
+	}
+	return nil
+}
+
+// certificateReloader holds a key pair and a CA bundle loaded from files,
+// and reloads them when one of the files is modified.
+type certificateReloader struct {
+	config tlsConfig
+	logger *zap.Logger
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	pool    *x509.CertPool
+	modTime time.Time
+}
+
+func newCertificateReloader(config tlsConfig, logger *zap.Logger) (*certificateReloader, error) {
+	r := &certificateReloader{
+		config: config,
+		logger: logger.Named("tls"),
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ServerConfig returns a tls.Config for a server that verifies client certificates.
+// If allowTokenOnly is true, clients without a certificate are accepted
+// so that they can authenticate with a token instead.
+func (r *certificateReloader) ServerConfig(allowTokenOnly bool) *tls.Config {
+	clientAuth := tls.RequireAndVerifyClientCert
+	if allowTokenOnly {
+		clientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := r.current()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				ClientCAs:    pool,
+				ClientAuth:   clientAuth,
+			}, nil
+		},
+	}
+}
+
+// ClientConfig returns a tls.Config for a client that presents its certificate.
+// The server certificate is verified against the latest CA bundle on every handshake.
+func (r *certificateReloader) ClientConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: r.config.ServerName,
+		// RootCAs would pin the CA bundle loaded at startup, so the verification is done by VerifyConnection
+		InsecureSkipVerify: true,
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			_, pool := r.current()
+			return verifyServerCertificate(cs, pool)
+		},
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := r.current()
+			return cert, nil
+		},
+	}
+}
+
+// verifyServerCertificate verifies the certificate chain and the name of the server like crypto/tls does.
+func verifyServerCertificate(cs tls.ConnectionState, roots *x509.CertPool) error {
+	if len(cs.PeerCertificates) == 0 {
+		return fmt.Errorf("server presented no certificate")
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		DNSName:       cs.ServerName,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	if _, err := cs.PeerCertificates[0].Verify(opts); err != nil {
+		return fmt.Errorf("failed to verify server certificate: %w", err)
+	}
+	return nil
+}
+
+// current returns the latest key pair and CA bundle.
+// When reloading fails, the previously loaded ones are kept.
+func (r *certificateReloader) current() (*tls.Certificate, *x509.CertPool) {
+	if modified, err := r.modified(); err != nil {
+		r.logger.Warn("failed to stat certificate files", zap.Error(err))
+	} else if modified {
+		if err := r.load(); err != nil {
+			r.logger.Warn("failed to reload certificate", zap.Error(err))
+		} else {
+			r.logger.Info("reloaded certificate", zap.String("cert_file", r.config.CertFile))
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cert, r.pool
+}
+
+func (r *certificateReloader) modified() (bool, error) {
+	modTime, err := r.latestModTime()
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return modTime.After(r.modTime), nil
+}
+
+func (r *certificateReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, path := range []string{r.config.CAFile, r.config.CertFile, r.config.KeyFile} {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return latest, err
+		}
+		if fi.ModTime().After(latest) {
+			latest = fi.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+func (r *certificateReloader) load() error {
+	modTime, err := r.latestModTime()
+	if err != nil {
+		return fmt.Errorf("failed to stat certificate files: %w", err)
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load key pair: %w", err)
+	}
+
+	ca, err := ioutil.ReadFile(r.config.CAFile)
+	if err != nil {
+		return fmt.Errorf("failed to read CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return fmt.Errorf("failed to parse CA file: %s", r.config.CAFile)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = &cert
+	r.pool = pool
+	r.modTime = modTime
+
+	return nil
+}
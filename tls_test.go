@@ -0,0 +1,155 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// testCertificate is a certificate and its key, signed by parent or self-signed.
+type testCertificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func newTestCertificate(t *testing.T, name string, parent *testCertificate) *testCertificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %+v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		template.DNSNames = []string{name}
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %+v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %+v", err)
+	}
+	return &testCertificate{cert: cert, key: key, der: der}
+}
+
+func (c *testCertificate) certPEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der})
+}
+
+func (c *testCertificate) keyPEM(t *testing.T) []byte {
+	t.Helper()
+
+	b, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %+v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: b})
+}
+
+func (c *testCertificate) tlsCertificate(t *testing.T) tls.Certificate {
+	t.Helper()
+
+	cert, err := tls.X509KeyPair(c.certPEM(), c.keyPEM(t))
+	if err != nil {
+		t.Fatalf("failed to load key pair: %+v", err)
+	}
+	return cert
+}
+
+// handshake connects the client config to a server presenting cert, and returns the error of the client.
+func handshake(client *tls.Config, cert tls.Certificate) error {
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	server := tls.Server(serverConn, &tls.Config{Certificates: []tls.Certificate{cert}})
+	go server.Handshake()
+
+	return tls.Client(clientConn, client).Handshake()
+}
+
+func TestCertificateReloaderClientConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "teleskop-tls")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %+v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	oldCA := newTestCertificate(t, "old-ca", nil)
+	newCA := newTestCertificate(t, "new-ca", nil)
+	agent := newTestCertificate(t, "agent", oldCA)
+	satelit := newTestCertificate(t, "satelit.example.com", newCA)
+
+	config := tlsConfig{
+		CAFile:     filepath.Join(dir, "ca.pem"),
+		CertFile:   filepath.Join(dir, "agent.pem"),
+		KeyFile:    filepath.Join(dir, "agent-key.pem"),
+		ServerName: "satelit.example.com",
+	}
+	for path, content := range map[string][]byte{
+		config.CAFile:   oldCA.certPEM(),
+		config.CertFile: agent.certPEM(),
+		config.KeyFile:  agent.keyPEM(t),
+	} {
+		if err := ioutil.WriteFile(path, content, 0600); err != nil {
+			t.Fatalf("failed to write %s: %+v", path, err)
+		}
+	}
+
+	r, err := newCertificateReloader(config, zap.NewNop())
+	if err != nil {
+		t.Fatalf("failed to create certificate reloader: %+v", err)
+	}
+	client := r.ClientConfig()
+
+	if err := handshake(client, satelit.tlsCertificate(t)); err == nil {
+		t.Fatalf("certificate signed by an unknown CA should be rejected")
+	}
+
+	// rotate the CA bundle of satelit
+	if err := ioutil.WriteFile(config.CAFile, append(oldCA.certPEM(), newCA.certPEM()...), 0600); err != nil {
+		t.Fatalf("failed to write CA file: %+v", err)
+	}
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(config.CAFile, future, future); err != nil {
+		t.Fatalf("failed to change modification time: %+v", err)
+	}
+
+	if err := handshake(client, satelit.tlsCertificate(t)); err != nil {
+		t.Errorf("should not be error after rotating CA but: %+v", err)
+	}
+
+	// the server name is verified too
+	other := newTestCertificate(t, "other.example.com", newCA)
+	if err := handshake(client, other.tlsCertificate(t)); err == nil {
+		t.Errorf("certificate of another name should be rejected")
+	}
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/coreos/go-iptables/iptables"
+	libvirt "github.com/digitalocean/go-libvirt"
+	"github.com/google/nftables"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName  = "github.com/lovi-cloud/teleskop"
+	serviceName = "teleskop"
+)
+
+var tracer = otel.Tracer(tracerName)
+
+type tracingConfig struct {
+	// OTLPEndpoint is the address of an OTLP/gRPC collector. Spans are not exported if empty.
+	OTLPEndpoint string  `yaml:"otlp_endpoint"`
+	SampleRatio  float64 `yaml:"sample_ratio"`
+}
+
+func (c tracingConfig) validate() error {
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return fmt.Errorf("sample_ratio must be between 0 and 1: %g", c.SampleRatio)
+	}
+	return nil
+}
+
+// initTracerProvider sets up the global tracer provider that exports spans to the OTLP collector.
+// The returned function flushes the remaining spans and stops the exporter.
+func initTracerProvider(ctx context.Context, c tracingConfig) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if c.OTLPEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracegrpc.New(ctx,
+		otlptracegrpc.WithEndpoint(c.OTLPEndpoint),
+		otlptracegrpc.WithInsecure(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hostname: %w", err)
+	}
+	res := resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceNameKey.String(serviceName),
+		semconv.HostNameKey.String(hostname),
+	)
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(c.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+
+	return tp.Shutdown, nil
+}
+
+// startSpan starts a child span of the span in ctx.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan records err to span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// tracedLibvirt records a span for every call to libvirtd.
+type tracedLibvirt struct {
+	client *libvirt.Libvirt
+}
+
+// API returns the client of the current libvirtd connection that records spans.
+func (c *libvirtConnection) API() (libvirtAPI, error) {
+	client, err := c.Client()
+	if err != nil {
+		return nil, err
+	}
+	return &tracedLibvirt{client: client}, nil
+}
+
+func startLibvirtSpan(ctx context.Context, procedure string) trace.Span {
+	_, span := startSpan(ctx, "libvirt."+procedure, attribute.String("libvirt.procedure", procedure))
+	return span
+}
+
+func (l *tracedLibvirt) ConnectListAllDomains(ctx context.Context, needResults int32, flags libvirt.ConnectListAllDomainsFlags) (domains []libvirt.Domain, ret uint32, err error) {
+	span := startLibvirtSpan(ctx, "ConnectListAllDomains")
+	defer func() { endSpan(span, err) }()
+	return l.client.ConnectListAllDomains(needResults, flags)
+}
+
+func (l *tracedLibvirt) ConnectListAllNetworks(ctx context.Context, needResults int32, flags libvirt.ConnectListAllNetworksFlags) (networks []libvirt.Network, ret uint32, err error) {
+	span := startLibvirtSpan(ctx, "ConnectListAllNetworks")
+	defer func() { endSpan(span, err) }()
+	return l.client.ConnectListAllNetworks(needResults, flags)
+}
+
+func (l *tracedLibvirt) DomainAttachDeviceFlags(ctx context.Context, dom libvirt.Domain, xml string, flags uint32) (err error) {
+	span := startLibvirtSpan(ctx, "DomainAttachDeviceFlags")
+	defer func() { endSpan(span, err) }()
+	return l.client.DomainAttachDeviceFlags(dom, xml, flags)
+}
+
+func (l *tracedLibvirt) DomainCreate(ctx context.Context, dom libvirt.Domain) (err error) {
+	span := startLibvirtSpan(ctx, "DomainCreate")
+	defer func() { endSpan(span, err) }()
+	return l.client.DomainCreate(dom)
+}
+
+func (l *tracedLibvirt) DomainDefineXML(ctx context.Context, xml string) (dom libvirt.Domain, err error) {
+	span := startLibvirtSpan(ctx, "DomainDefineXML")
+	defer func() { endSpan(span, err) }()
+	return l.client.DomainDefineXML(xml)
+}
+
+func (l *tracedLibvirt) DomainDestroy(ctx context.Context, dom libvirt.Domain) (err error) {
+	span := startLibvirtSpan(ctx, "DomainDestroy")
+	defer func() { endSpan(span, err) }()
+	return l.client.DomainDestroy(dom)
+}
+
+func (l *tracedLibvirt) DomainDetachDeviceFlags(ctx context.Context, dom libvirt.Domain, xml string, flags uint32) (err error) {
+	span := startLibvirtSpan(ctx, "DomainDetachDeviceFlags")
+	defer func() { endSpan(span, err) }()
+	return l.client.DomainDetachDeviceFlags(dom, xml, flags)
+}
+
+func (l *tracedLibvirt) DomainGetState(ctx context.Context, dom libvirt.Domain, flags uint32) (state int32, reason int32, err error) {
+	span := startLibvirtSpan(ctx, "DomainGetState")
+	defer func() { endSpan(span, err) }()
+	return l.client.DomainGetState(dom, flags)
+}
+
+func (l *tracedLibvirt) DomainGetXMLDesc(ctx context.Context, dom libvirt.Domain, flags libvirt.DomainXMLFlags) (xml string, err error) {
+	span := startLibvirtSpan(ctx, "DomainGetXMLDesc")
+	defer func() { endSpan(span, err) }()
+	return l.client.DomainGetXMLDesc(dom, flags)
+}
+
+func (l *tracedLibvirt) DomainLookupByUUID(ctx context.Context, uuid libvirt.UUID) (dom libvirt.Domain, err error) {
+	span := startLibvirtSpan(ctx, "DomainLookupByUUID")
+	defer func() { endSpan(span, err) }()
+	return l.client.DomainLookupByUUID(uuid)
+}
+
+func (l *tracedLibvirt) DomainUndefine(ctx context.Context, dom libvirt.Domain) (err error) {
+	span := startLibvirtSpan(ctx, "DomainUndefine")
+	defer func() { endSpan(span, err) }()
+	return l.client.DomainUndefine(dom)
+}
+
+func (l *tracedLibvirt) NetworkCreate(ctx context.Context, network libvirt.Network) (err error) {
+	span := startLibvirtSpan(ctx, "NetworkCreate")
+	defer func() { endSpan(span, err) }()
+	return l.client.NetworkCreate(network)
+}
+
+func (l *tracedLibvirt) NetworkDefineXML(ctx context.Context, xml string) (network libvirt.Network, err error) {
+	span := startLibvirtSpan(ctx, "NetworkDefineXML")
+	defer func() { endSpan(span, err) }()
+	return l.client.NetworkDefineXML(xml)
+}
+
+func (l *tracedLibvirt) NetworkDestroy(ctx context.Context, network libvirt.Network) (err error) {
+	span := startLibvirtSpan(ctx, "NetworkDestroy")
+	defer func() { endSpan(span, err) }()
+	return l.client.NetworkDestroy(network)
+}
+
+func (l *tracedLibvirt) NetworkLookupByName(ctx context.Context, name string) (network libvirt.Network, err error) {
+	span := startLibvirtSpan(ctx, "NetworkLookupByName")
+	defer func() { endSpan(span, err) }()
+	return l.client.NetworkLookupByName(name)
+}
+
+func (l *tracedLibvirt) NetworkUndefine(ctx context.Context, network libvirt.Network) (err error) {
+	span := startLibvirtSpan(ctx, "NetworkUndefine")
+	defer func() { endSpan(span, err) }()
+	return l.client.NetworkUndefine(network)
+}
+
+// tracedIPTables records a span for every iptables or ip6tables command.
+type tracedIPTables struct {
+	client  *iptables.IPTables
+	family  ipFamily
+	command string
+}
+
+func newTracedIPTables(family ipFamily) (iptablesAPI, error) {
+	protocol := iptables.ProtocolIPv4
+	if family == ipFamilyIPv6 {
+		protocol = iptables.ProtocolIPv6
+	}
+	client, err := iptables.NewWithProtocol(protocol)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedIPTables{client: client, family: family, command: getIPTablesCommand(family)}, nil
+}
+
+func startIPTablesSpan(ctx context.Context, family ipFamily, operation, table, chain string) trace.Span {
+	_, span := startSpan(ctx, "iptables."+operation,
+		attribute.String("iptables.family", family.String()),
+		attribute.String("iptables.table", table),
+		attribute.String("iptables.chain", chain),
+	)
+	return span
+}
+
+func (t *tracedIPTables) ChainExists(ctx context.Context, table, chain string) (exists bool, err error) {
+	span := startIPTablesSpan(ctx, t.family, "ChainExists", table, chain)
+	defer func() { endSpan(span, err) }()
+	return t.client.ChainExists(table, chain)
+}
+
+func (t *tracedIPTables) ListChains(ctx context.Context, table string) (chains []string, err error) {
+	span := startIPTablesSpan(ctx, t.family, "ListChains", table, "")
+	defer func() { endSpan(span, err) }()
+	return t.client.ListChains(table)
+}
+
+func (t *tracedIPTables) ListWithCounters(ctx context.Context, table, chain string) (rules []string, err error) {
+	span := startIPTablesSpan(ctx, t.family, "ListWithCounters", table, chain)
+	defer func() { endSpan(span, err) }()
+	return t.client.ListWithCounters(table, chain)
+}
+
+func (t *tracedIPTables) Save(ctx context.Context, table string) (out string, err error) {
+	span := startIPTablesSpan(ctx, t.family, "Save", table, "")
+	defer func() { endSpan(span, err) }()
+	// go-iptables does not support iptables-save
+	b, err := exec.CommandContext(ctx, t.command+"-save", "-t", table).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to execute %s-save: %w", t.command, err)
+	}
+	return string(b), nil
+}
+
+func (t *tracedIPTables) Restore(ctx context.Context, table, rules string) (err error) {
+	span := startIPTablesSpan(ctx, t.family, "Restore", table, "")
+	defer func() { endSpan(span, err) }()
+	// go-iptables does not support iptables-restore
+	cmd := exec.CommandContext(ctx, t.command+"-restore", "--noflush", "--wait", "--table", table)
+	cmd.Stdin = strings.NewReader(rules)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to execute %s-restore: %w (output: %s)", t.command, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (t *tracedIPTables) Exists(ctx context.Context, table, chain string, rulespec ...string) (exists bool, err error) {
+	span := startIPTablesSpan(ctx, t.family, "Exists", table, chain)
+	defer func() { endSpan(span, err) }()
+	return t.client.Exists(table, chain, rulespec...)
+}
+
+// tracedNFTables records a span for every nftables query and batch. The changes are queued by the embedded connection.
+type tracedNFTables struct {
+	*nftables.Conn
+}
+
+func newTracedNFTables() (nftablesAPI, error) {
+	conn, err := nftables.New()
+	if err != nil {
+		return nil, err
+	}
+	return &tracedNFTables{Conn: conn}, nil
+}
+
+func startNFTablesSpan(ctx context.Context, operation, chain string) trace.Span {
+	_, span := startSpan(ctx, "nftables."+operation,
+		attribute.String("nftables.table", nftablesTable),
+		attribute.String("nftables.chain", chain),
+	)
+	return span
+}
+
+func (t *tracedNFTables) ListTablesOfFamily(ctx context.Context, family nftables.TableFamily) (tables []*nftables.Table, err error) {
+	span := startNFTablesSpan(ctx, "ListTablesOfFamily", "")
+	defer func() { endSpan(span, err) }()
+	return t.Conn.ListTablesOfFamily(family)
+}
+
+func (t *tracedNFTables) ListChainsOfTableFamily(ctx context.Context, family nftables.TableFamily) (chains []*nftables.Chain, err error) {
+	span := startNFTablesSpan(ctx, "ListChainsOfTableFamily", "")
+	defer func() { endSpan(span, err) }()
+	return t.Conn.ListChainsOfTableFamily(family)
+}
+
+func (t *tracedNFTables) GetRules(ctx context.Context, table *nftables.Table, chain *nftables.Chain) (rules []*nftables.Rule, err error) {
+	span := startNFTablesSpan(ctx, "GetRules", chain.Name)
+	defer func() { endSpan(span, err) }()
+	return t.Conn.GetRules(table, chain)
+}
+
+func (t *tracedNFTables) GetSets(ctx context.Context, table *nftables.Table) (sets []*nftables.Set, err error) {
+	span := startNFTablesSpan(ctx, "GetSets", "")
+	defer func() { endSpan(span, err) }()
+	return t.Conn.GetSets(table)
+}
+
+func (t *tracedNFTables) GetSetElements(ctx context.Context, set *nftables.Set) (elements []nftables.SetElement, err error) {
+	span := startNFTablesSpan(ctx, "GetSetElements", "")
+	defer func() { endSpan(span, err) }()
+	return t.Conn.GetSetElements(set)
+}
+
+func (t *tracedNFTables) Flush(ctx context.Context) (err error) {
+	span := startNFTablesSpan(ctx, "Flush", "")
+	defer func() { endSpan(span, err) }()
+	return t.Conn.Flush()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	libvirt "github.com/digitalocean/go-libvirt"
+	"github.com/digitalocean/go-libvirt/libvirttest"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newTestSpanRecorder records the spans of the global tracer provider.
+func newTestSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+	return recorder
+}
+
+func TestTracing(t *testing.T) {
+	recorder := newTestSpanRecorder(t)
+
+	conn, err := libvirttest.New().Dial()
+	if err != nil {
+		t.Fatalf("failed to dial: %+v", err)
+	}
+	client := libvirt.New(conn)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("failed to connect: %+v", err)
+	}
+	defer client.Disconnect()
+
+	ctx, parent := startSpan(context.Background(), "AddVirtualMachine")
+	l := &tracedLibvirt{client: client}
+	if _, _, err := l.ConnectListAllDomains(ctx, 1, libvirt.ConnectListDomainsActive); err != nil {
+		t.Fatalf("failed to list domains: %+v", err)
+	}
+	_, failed := startSpan(ctx, "iscsi.ConnectVolume")
+	endSpan(failed, errors.New("failed to login"))
+	endSpan(parent, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("want 3 spans, but got %d", len(spans))
+	}
+	libvirtSpan, iscsiSpan, rootSpan := spans[0], spans[1], spans[2]
+
+	if libvirtSpan.Name() != "libvirt.ConnectListAllDomains" {
+		t.Errorf("want libvirt.ConnectListAllDomains, but got %s", libvirtSpan.Name())
+	}
+	for _, span := range []sdktrace.ReadOnlySpan{libvirtSpan, iscsiSpan} {
+		if span.Parent().SpanID() != rootSpan.SpanContext().SpanID() {
+			t.Errorf("%s should be a child of %s", span.Name(), rootSpan.Name())
+		}
+	}
+	if libvirtSpan.Status().Code != codes.Unset {
+		t.Errorf("want unset status of %s, but got %s", libvirtSpan.Name(), libvirtSpan.Status().Code)
+	}
+	if iscsiSpan.Status().Code != codes.Error || iscsiSpan.Status().Description != "failed to login" {
+		t.Errorf("want error status of %s, but got %+v", iscsiSpan.Name(), iscsiSpan.Status())
+	}
+	if len(iscsiSpan.Events()) != 1 {
+		t.Errorf("want the error recorded as an event of %s, but got %d events", iscsiSpan.Name(), len(iscsiSpan.Events()))
+	}
+}
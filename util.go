@@ -1,26 +1,75 @@
 package main
 
 import (
+	"context"
 	"encoding/hex"
+	"errors"
 	"strings"
 
+	"github.com/vishvananda/netlink"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	libvirt "github.com/digitalocean/go-libvirt"
 )
 
-func (a *agent) domainLookupByUUID(uuidStr string) (*libvirt.Domain, error) {
+func (a *agent) domainLookupByUUID(ctx context.Context, uuidStr string) (*libvirt.Domain, error) {
+	libvirtClient, err := a.libvirtClient()
+	if err != nil {
+		return nil, err
+	}
+
 	uuidStr = strings.ReplaceAll(uuidStr, "-", "")
 	var uuid libvirt.UUID
 	if _, err := hex.Decode(uuid[:], []byte(uuidStr)); err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "failed to parse uuid string: %+v", err)
 	}
 
-	domain, err := a.libvirtClient.DomainLookupByUUID(uuid)
+	domain, err := libvirtClient.DomainLookupByUUID(ctx, uuid)
 	if err != nil {
+		if libvirt.IsNotFound(err) {
+			return nil, notFoundError("domain", uuidStr)
+		}
 		return nil, status.Errorf(codes.Internal, "failed to lookup domain: %+v", err)
 	}
 
 	return &domain, nil
 }
+
+// alreadyExistsError returns an AlreadyExists error with the information of the resource.
+func alreadyExistsError(resourceType, name string) error {
+	return resourceError(codes.AlreadyExists, resourceType, name, "already exists")
+}
+
+// notFoundError returns a NotFound error with the information of the resource.
+func notFoundError(resourceType, name string) error {
+	return resourceError(codes.NotFound, resourceType, name, "is not found")
+}
+
+func resourceError(code codes.Code, resourceType, name, description string) error {
+	st := status.Newf(code, "%s %s %s", resourceType, name, description)
+	detailed, err := st.WithDetails(&errdetails.ResourceInfo{
+		ResourceType: resourceType,
+		ResourceName: name,
+		Description:  description,
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// isNetworkNotFound reports whether err is libvirt's ERR_NO_NETWORK. Other errors, such as the ones of
+// a disconnected libvirtd, do not tell whether the network exists.
+func isNetworkNotFound(err error) bool {
+	var e libvirt.Error
+	return errors.As(err, &e) && e.Code == uint32(libvirt.ErrNoNetwork)
+}
+
+// isLinkNotFound reports whether err is returned by netlink for a missing link.
+func isLinkNotFound(err error) bool {
+	var e netlink.LinkNotFoundError
+	return errors.As(err, &e)
+}
@@ -3,12 +3,14 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/xml"
 	"fmt"
 	"os"
 	"strings"
 	"text/template"
 
 	libvirt "github.com/digitalocean/go-libvirt"
+	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -86,7 +88,7 @@ const domainTmplStr = `
   </devices>
   <qemu:commandline>
     <qemu:arg value='-smbios'/>
-    <qemu:arg value='type=1,serial=ds=nocloud-net;s=http://169.254.169.254/'/>
+    <qemu:arg value='type=1,serial=ds=nocloud-net;s={{.DatasourceURL}}'/>
   </qemu:commandline>
 </domain>
 `
@@ -125,29 +127,28 @@ const interfaceTmplStr = `
 `
 
 var (
-	domainTmpl     *template.Template
-	attachDiskTmpl *template.Template
-	detachDiskTmpl *template.Template
-	interfaceTmpl  *template.Template
+	domainTmpl     = template.Must(template.New("domainTmpl").Parse(domainTmplStr))
+	attachDiskTmpl = template.Must(template.New("attachDiskTmpl").Parse(attachDiskTmplStr))
+	detachDiskTmpl = template.Must(template.New("detachDiskTmpl").Parse(detachDiskTmplStr))
+	interfaceTmpl  = template.Must(template.New("interfaceTmpl").Parse(interfaceTmplStr))
 )
 
 type domainParams struct {
 	*pb.AddVirtualMachineRequest
-	CPUSets []string
+	CPUSets       []string
+	DatasourceURL string
 }
 
 func (a *agent) AddVirtualMachine(ctx context.Context, req *pb.AddVirtualMachineRequest) (*pb.AddVirtualMachineResponse, error) {
-	if domainTmpl == nil {
-		tmp, err := template.New("domainTmpl").Parse(domainTmplStr)
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "failed to parse domain template: %+v", err)
-		}
-		domainTmpl = tmp
+	libvirtClient, err := a.libvirtClient()
+	if err != nil {
+		return nil, err
 	}
 
 	param := &domainParams{
 		AddVirtualMachineRequest: req,
 		CPUSets:                  []string{},
+		DatasourceURL:            a.datasourceURL,
 	}
 	if req.PinningGroupName != "" {
 		hostname, err := os.Hostname()
@@ -180,12 +181,12 @@ func (a *agent) AddVirtualMachine(ctx context.Context, req *pb.AddVirtualMachine
 		return nil, status.Errorf(codes.Internal, "failed to exec domain template: %+v", err)
 	}
 
-	domain, err := a.libvirtClient.DomainDefineXML(buff.String())
+	domain, err := libvirtClient.DomainDefineXML(ctx, buff.String())
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "failed to define domain: %+v xml=%s", err, buff.String())
 	}
 
-	fmt.Printf("creating domain: %s\t%x\n", domain.Name, domain.UUID)
+	a.logger.Info("creating domain", zap.String("name", domain.Name), zap.String("uuid", fmt.Sprintf("%x", domain.UUID)))
 
 	if req.BootDevice != "" {
 		_, err = a.AttachBlockDevice(ctx, &pb.AttachBlockDeviceRequest{
@@ -209,16 +210,27 @@ func (a *agent) AddVirtualMachine(ctx context.Context, req *pb.AddVirtualMachine
 }
 
 func (a *agent) StartVirtualMachine(ctx context.Context, req *pb.StartVirtualMachineRequest) (*pb.StartVirtualMachineResponse, error) {
-	domain, err := a.domainLookupByUUID(req.Uuid)
+	libvirtClient, err := a.libvirtClient()
 	if err != nil {
 		return nil, err
 	}
 
-	if err := a.libvirtClient.DomainCreate(*domain); err != nil {
+	unlock, err := a.lockDomain(ctx, req.Uuid)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	domain, err := a.domainLookupByUUID(ctx, req.Uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := libvirtClient.DomainCreate(ctx, *domain); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to start domain: %+v", err)
 	}
 
-	fmt.Printf("starting domain: %s\t%x\n", domain.Name, domain.UUID)
+	a.logger.Info("starting domain", zap.String("name", domain.Name), zap.String("uuid", fmt.Sprintf("%x", domain.UUID)))
 
 	return &pb.StartVirtualMachineResponse{
 		Uuid: fmt.Sprintf("%x", domain.UUID),
@@ -227,17 +239,20 @@ func (a *agent) StartVirtualMachine(ctx context.Context, req *pb.StartVirtualMac
 }
 
 func (a *agent) AttachBlockDevice(ctx context.Context, req *pb.AttachBlockDeviceRequest) (*pb.AttachBlockDeviceResponse, error) {
-	domain, err := a.domainLookupByUUID(req.Uuid)
+	libvirtClient, err := a.libvirtClient()
 	if err != nil {
 		return nil, err
 	}
 
-	if attachDiskTmpl == nil {
-		tmp, err := template.New("attachDiskTmpl").Parse(attachDiskTmplStr)
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "failed to parse disk template: %+v", err)
-		}
-		attachDiskTmpl = tmp
+	unlock, err := a.lockDomain(ctx, req.Uuid)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	domain, err := a.domainLookupByUUID(ctx, req.Uuid)
+	if err != nil {
+		return nil, err
 	}
 
 	var buff bytes.Buffer
@@ -259,11 +274,11 @@ func (a *agent) AttachBlockDevice(ctx context.Context, req *pb.AttachBlockDevice
 		flags = libvirt.DomainDeviceModifyConfig | libvirt.DomainDeviceModifyForce
 	}
 
-	if err := a.libvirtClient.DomainAttachDeviceFlags(*domain, buff.String(), uint32(flags)); err != nil {
+	if err := libvirtClient.DomainAttachDeviceFlags(ctx, *domain, buff.String(), uint32(flags)); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to attach block device: %+v", err)
 	}
 
-	fmt.Printf("attaching block: %s\t%x\n", domain.Name, domain.UUID)
+	a.logger.Info("attaching block device", zap.String("name", domain.Name), zap.String("uuid", fmt.Sprintf("%x", domain.UUID)))
 
 	return &pb.AttachBlockDeviceResponse{
 		Uuid: fmt.Sprintf("%x", domain.UUID),
@@ -272,17 +287,28 @@ func (a *agent) AttachBlockDevice(ctx context.Context, req *pb.AttachBlockDevice
 }
 
 func (a *agent) AttachInterface(ctx context.Context, req *pb.AttachInterfaceRequest) (*pb.AttachInterfaceResponse, error) {
-	domain, err := a.domainLookupByUUID(req.Uuid)
+	libvirtClient, err := a.libvirtClient()
 	if err != nil {
 		return nil, err
 	}
 
-	if interfaceTmpl == nil {
-		tmp, err := template.New("interfaceTmpl").Parse(interfaceTmplStr)
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "failed to parse interface template: %+v", err)
-		}
-		interfaceTmpl = tmp
+	unlock, err := a.lockDomain(ctx, req.Uuid)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	domain, err := a.domainLookupByUUID(ctx, req.Uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	attached, err := a.interfaceAttached(ctx, *domain, req.Name, req.MacAddress)
+	if err != nil {
+		return nil, err
+	}
+	if attached {
+		return nil, alreadyExistsError("interface", req.Name)
 	}
 
 	var buff bytes.Buffer
@@ -304,11 +330,11 @@ func (a *agent) AttachInterface(ctx context.Context, req *pb.AttachInterfaceRequ
 		flags = libvirt.DomainDeviceModifyConfig | libvirt.DomainDeviceModifyForce
 	}
 
-	if err := a.libvirtClient.DomainAttachDeviceFlags(*domain, buff.String(), uint32(flags)); err != nil {
+	if err := libvirtClient.DomainAttachDeviceFlags(ctx, *domain, buff.String(), uint32(flags)); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to attach interface: %+v", err)
 	}
 
-	fmt.Printf("attaching interface: %s\t%x\n", domain.Name, domain.UUID)
+	a.logger.Info("attaching interface", zap.String("name", domain.Name), zap.String("uuid", fmt.Sprintf("%x", domain.UUID)))
 
 	return &pb.AttachInterfaceResponse{
 		Uuid: fmt.Sprintf("%x", domain.UUID),
@@ -317,12 +343,23 @@ func (a *agent) AttachInterface(ctx context.Context, req *pb.AttachInterfaceRequ
 }
 
 func (a *agent) DeleteVirtualMachine(ctx context.Context, req *pb.DeleteVirtualMachineRequest) (*pb.DeleteVirtualMachineResponse, error) {
-	domain, err := a.domainLookupByUUID(req.Uuid)
+	libvirtClient, err := a.libvirtClient()
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := a.lockDomain(ctx, req.Uuid)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	domain, err := a.domainLookupByUUID(ctx, req.Uuid)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := a.libvirtClient.DomainUndefine(*domain); err != nil {
+	if err := libvirtClient.DomainUndefine(ctx, *domain); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to undefine domain: %+v", err)
 	}
 
@@ -330,12 +367,23 @@ func (a *agent) DeleteVirtualMachine(ctx context.Context, req *pb.DeleteVirtualM
 }
 
 func (a *agent) StopVirtualMachine(ctx context.Context, req *pb.StopVirtualMachineRequest) (*pb.StopVirtualMachineResponse, error) {
-	domain, err := a.domainLookupByUUID(req.Uuid)
+	libvirtClient, err := a.libvirtClient()
 	if err != nil {
 		return nil, err
 	}
 
-	if err := a.libvirtClient.DomainDestroy(*domain); err != nil {
+	unlock, err := a.lockDomain(ctx, req.Uuid)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	domain, err := a.domainLookupByUUID(ctx, req.Uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := libvirtClient.DomainDestroy(ctx, *domain); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to destory domain: %+v", err)
 	}
 
@@ -343,17 +391,20 @@ func (a *agent) StopVirtualMachine(ctx context.Context, req *pb.StopVirtualMachi
 }
 
 func (a *agent) DetachBlockDevice(ctx context.Context, req *pb.DetachBlockDeviceRequest) (*pb.DetachBlockDeviceResponse, error) {
-	domain, err := a.domainLookupByUUID(req.Uuid)
+	libvirtClient, err := a.libvirtClient()
 	if err != nil {
 		return nil, err
 	}
 
-	if detachDiskTmpl == nil {
-		tmp, err := template.New("detachDiskTmpl").Parse(detachDiskTmplStr)
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "failed to parse disk template: %+v", err)
-		}
-		detachDiskTmpl = tmp
+	unlock, err := a.lockDomain(ctx, req.Uuid)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	domain, err := a.domainLookupByUUID(ctx, req.Uuid)
+	if err != nil {
+		return nil, err
 	}
 
 	var buff bytes.Buffer
@@ -375,7 +426,7 @@ func (a *agent) DetachBlockDevice(ctx context.Context, req *pb.DetachBlockDevice
 		flags = libvirt.DomainDeviceModifyConfig | libvirt.DomainDeviceModifyForce
 	}
 
-	if err := a.libvirtClient.DomainDetachDeviceFlags(*domain, buff.String(), uint32(flags)); err != nil {
+	if err := libvirtClient.DomainDetachDeviceFlags(ctx, *domain, buff.String(), uint32(flags)); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to detach block device: %+v", err)
 	}
 
@@ -383,17 +434,20 @@ func (a *agent) DetachBlockDevice(ctx context.Context, req *pb.DetachBlockDevice
 }
 
 func (a *agent) DetachInterface(ctx context.Context, req *pb.DetachInterfaceRequest) (*pb.DetachInterfaceResponse, error) {
-	domain, err := a.domainLookupByUUID(req.Uuid)
+	libvirtClient, err := a.libvirtClient()
 	if err != nil {
 		return nil, err
 	}
 
-	if interfaceTmpl == nil {
-		tmp, err := template.New("interfaceTmpl").Parse(interfaceTmplStr)
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "failed to parse interface template: %+v", err)
-		}
-		interfaceTmpl = tmp
+	unlock, err := a.lockDomain(ctx, req.Uuid)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	domain, err := a.domainLookupByUUID(ctx, req.Uuid)
+	if err != nil {
+		return nil, err
 	}
 
 	var buff bytes.Buffer
@@ -415,7 +469,7 @@ func (a *agent) DetachInterface(ctx context.Context, req *pb.DetachInterfaceRequ
 		flags = libvirt.DomainDeviceModifyConfig | libvirt.DomainDeviceModifyForce
 	}
 
-	if err := a.libvirtClient.DomainDetachDeviceFlags(*domain, buff.String(), uint32(flags)); err != nil {
+	if err := libvirtClient.DomainDetachDeviceFlags(ctx, *domain, buff.String(), uint32(flags)); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to detach interface: %+v", err)
 	}
 
@@ -423,12 +477,17 @@ func (a *agent) DetachInterface(ctx context.Context, req *pb.DetachInterfaceRequ
 }
 
 func (a *agent) GetVirtualMachineState(ctx context.Context, req *pb.GetVirtualMachineStateRequest) (*pb.GetVirtualMachineStateResponse, error) {
-	domain, err := a.domainLookupByUUID(req.Uuid)
+	libvirtClient, err := a.libvirtClient()
 	if err != nil {
 		return nil, err
 	}
 
-	state, _, err := a.libvirtClient.DomainGetState(*domain, 0)
+	domain, err := a.domainLookupByUUID(ctx, req.Uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	state, _, err := libvirtClient.DomainGetState(ctx, *domain, 0)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get domain state: %+v", err)
 	}
@@ -444,15 +503,20 @@ func (a *agent) GetVirtualMachineState(ctx context.Context, req *pb.GetVirtualMa
 }
 
 func (a *agent) ListVirtualMachineState(ctx context.Context, req *pb.ListVirtualMachineStateRequest) (*pb.ListVirtualMachineStateResponse, error) {
+	libvirtClient, err := a.libvirtClient()
+	if err != nil {
+		return nil, err
+	}
+
 	flags := libvirt.ConnectListDomainsActive | libvirt.ConnectListDomainsInactive
-	domains, _, err := a.libvirtClient.ConnectListAllDomains(1, flags)
+	domains, _, err := libvirtClient.ConnectListAllDomains(ctx, 1, flags)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get domain list: %+v", err)
 	}
 
 	vms := make([]*pb.VirtualMachineState, len(domains))
 	for i, domain := range domains {
-		state, _, err := a.libvirtClient.DomainGetState(domain, 0)
+		state, _, err := libvirtClient.DomainGetState(ctx, domain, 0)
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to get domain state: %+v", err)
 		}
@@ -468,8 +532,84 @@ func (a *agent) ListVirtualMachineState(ctx context.Context, req *pb.ListVirtual
 	}, nil
 }
 
+// domainInterfaces is the part of the domain XML that lists network interfaces.
+type domainInterfaces struct {
+	Interfaces []struct {
+		MAC struct {
+			Address string `xml:"address,attr"`
+		} `xml:"mac"`
+		Target struct {
+			Dev string `xml:"dev,attr"`
+		} `xml:"target"`
+		Source struct {
+			Bridge  string `xml:"bridge,attr"`
+			Network string `xml:"network,attr"`
+		} `xml:"source"`
+	} `xml:"devices>interface"`
+}
+
+// interfaceAttached reports whether an interface with the name or the MAC address is defined in the domain.
+func (a *agent) interfaceAttached(ctx context.Context, domain libvirt.Domain, name, macAddress string) (bool, error) {
+	libvirtClient, err := a.libvirtClient()
+	if err != nil {
+		return false, err
+	}
+
+	desc, err := libvirtClient.DomainGetXMLDesc(ctx, domain, libvirt.DomainXMLInactive)
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "failed to get domain xml: %+v", err)
+	}
+	var d domainInterfaces
+	if err := xml.Unmarshal([]byte(desc), &d); err != nil {
+		return false, status.Errorf(codes.Internal, "failed to parse domain xml: %+v", err)
+	}
+
+	for _, intf := range d.Interfaces {
+		if (name != "" && intf.Target.Dev == name) || (macAddress != "" && strings.EqualFold(intf.MAC.Address, macAddress)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// bridgeInUse reports whether an interface of any domain is connected to the bridge.
+func (a *agent) bridgeInUse(ctx context.Context, name string) (bool, error) {
+	libvirtClient, err := a.libvirtClient()
+	if err != nil {
+		return false, err
+	}
+
+	flags := libvirt.ConnectListDomainsActive | libvirt.ConnectListDomainsInactive
+	domains, _, err := libvirtClient.ConnectListAllDomains(ctx, 1, flags)
+	if err != nil {
+		return false, fmt.Errorf("failed to get domain list: %w", err)
+	}
+
+	for _, domain := range domains {
+		desc, err := libvirtClient.DomainGetXMLDesc(ctx, domain, libvirt.DomainXMLInactive)
+		if err != nil {
+			return false, fmt.Errorf("failed to get domain xml name=%s: %w", domain.Name, err)
+		}
+		var d domainInterfaces
+		if err := xml.Unmarshal([]byte(desc), &d); err != nil {
+			return false, fmt.Errorf("failed to parse domain xml name=%s: %w", domain.Name, err)
+		}
+		for _, intf := range d.Interfaces {
+			if intf.Source.Bridge == name || intf.Source.Network == name {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
 func (a *agent) getDomainState(ctx context.Context, domain libvirt.Domain) (int32, error) {
-	state, _, err := a.libvirtClient.DomainGetState(domain, 0)
+	libvirtClient, err := a.libvirtClient()
+	if err != nil {
+		return -1, err
+	}
+
+	state, _, err := libvirtClient.DomainGetState(ctx, domain, 0)
 	if err != nil {
 		return -1, status.Errorf(codes.Internal, "failed to get domain stat: %+v", err)
 	}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	libvirt "github.com/digitalocean/go-libvirt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	dspb "github.com/lovi-cloud/satelit/api/satelit_datastore"
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+// modifyFlagTests are the domain states and the modify flags expected for them.
+var modifyFlagTests = []struct {
+	name  string
+	state libvirt.DomainState
+	want  libvirt.DomainDeviceModifyFlags
+}{
+	{
+		name:  "running",
+		state: libvirt.DomainRunning,
+		want:  libvirt.DomainDeviceModifyConfig | libvirt.DomainDeviceModifyLive,
+	},
+	{
+		name:  "shutoff",
+		state: libvirt.DomainShutoff,
+		want:  libvirt.DomainDeviceModifyConfig,
+	},
+	{
+		name:  "paused",
+		state: libvirt.DomainPaused,
+		want:  libvirt.DomainDeviceModifyConfig | libvirt.DomainDeviceModifyForce,
+	},
+}
+
+func TestAddVirtualMachine(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         *pb.AddVirtualMachineRequest
+		wantDevices int
+	}{
+		{
+			name: "virtual machine",
+			req:  &pb.AddVirtualMachineRequest{Name: "vm1", Vcpus: 2, MemoryKib: 1048576},
+		},
+		{
+			name:        "virtual machine with boot device",
+			req:         &pb.AddVirtualMachineRequest{Name: "vm1", Vcpus: 2, MemoryKib: 1048576, BootDevice: "/dev/dm-0"},
+			wantDevices: 1,
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		ctx := context.Background()
+
+		resp, err := a.AddVirtualMachine(ctx, test.req)
+		if err != nil {
+			t.Fatalf("%s: should not be error but: %+v", test.name, err)
+		}
+		if resp.Name != test.req.Name {
+			t.Errorf("%s: want %s, but got %s", test.name, test.req.Name, resp.Name)
+		}
+
+		state, err := a.GetVirtualMachineState(ctx, &pb.GetVirtualMachineStateRequest{Uuid: resp.Uuid})
+		if err != nil {
+			t.Fatalf("%s: failed to get state: %+v", test.name, err)
+		}
+		if state.State.State != pb.VirtualMachineState_SHUTOFF {
+			t.Errorf("%s: want %s, but got %s", test.name, pb.VirtualMachineState_SHUTOFF, state.State.State)
+		}
+		if len(f.libvirt.attached) != test.wantDevices {
+			t.Errorf("%s: want %d devices, but got %d", test.name, test.wantDevices, len(f.libvirt.attached))
+		}
+		if test.wantDevices != 0 && !strings.Contains(f.libvirt.attached[0].xml, "<target dev='vda'/>") {
+			t.Errorf("%s: boot device should be vda, but got %s", test.name, f.libvirt.attached[0].xml)
+		}
+	}
+}
+
+func TestAddVirtualMachineCPUPinning(t *testing.T) {
+	a, f := newTestAgent(t)
+	f.datastore.pairs = []*dspb.CorePair{{PhysicalCore: 2}, {PhysicalCore: 3}}
+
+	if _, err := a.AddVirtualMachine(context.Background(), &pb.AddVirtualMachineRequest{Name: "vm1", Vcpus: 2, PinningGroupName: "group1"}); err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+
+	if len(f.libvirt.defined) != 1 {
+		t.Fatalf("want 1 domain, but got %d", len(f.libvirt.defined))
+	}
+	for _, want := range []string{"<vcpupin vcpu='0' cpuset='3,", "<vcpupin vcpu='1' cpuset='3,"} {
+		if !strings.Contains(f.libvirt.defined[0], want) {
+			t.Errorf("want %s in domain xml, but got %s", want, f.libvirt.defined[0])
+		}
+	}
+}
+
+func TestStartStopVirtualMachine(t *testing.T) {
+	tests := []struct {
+		name      string
+		state     libvirt.DomainState
+		start     bool
+		want      codes.Code
+		wantState pb.VirtualMachineState_State
+	}{
+		{
+			name:      "start shutoff domain",
+			state:     libvirt.DomainShutoff,
+			start:     true,
+			want:      codes.OK,
+			wantState: pb.VirtualMachineState_RUNNING,
+		},
+		{
+			name:      "start running domain",
+			state:     libvirt.DomainRunning,
+			start:     true,
+			want:      codes.Internal,
+			wantState: pb.VirtualMachineState_RUNNING,
+		},
+		{
+			name:      "stop running domain",
+			state:     libvirt.DomainRunning,
+			want:      codes.OK,
+			wantState: pb.VirtualMachineState_SHUTOFF,
+		},
+		{
+			name:      "stop shutoff domain",
+			state:     libvirt.DomainShutoff,
+			want:      codes.Internal,
+			wantState: pb.VirtualMachineState_SHUTOFF,
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		ctx := context.Background()
+		uuid := f.libvirt.addDomain("vm1", test.state)
+
+		var err error
+		if test.start {
+			_, err = a.StartVirtualMachine(ctx, &pb.StartVirtualMachineRequest{Uuid: uuid})
+		} else {
+			_, err = a.StopVirtualMachine(ctx, &pb.StopVirtualMachineRequest{Uuid: uuid})
+		}
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %+v", test.name, test.want, err)
+		}
+
+		state, err := a.GetVirtualMachineState(ctx, &pb.GetVirtualMachineStateRequest{Uuid: uuid})
+		if err != nil {
+			t.Fatalf("%s: failed to get state: %+v", test.name, err)
+		}
+		if state.State.State != test.wantState {
+			t.Errorf("%s: want %s, but got %s", test.name, test.wantState, state.State.State)
+		}
+	}
+}
+
+func TestDeleteVirtualMachine(t *testing.T) {
+	tests := []struct {
+		name string
+		uuid string
+		want codes.Code
+	}{
+		{
+			name: "existing domain",
+			want: codes.OK,
+		},
+		{
+			name: "missing domain",
+			uuid: "00000000-0000-0000-0000-000000000000",
+			want: codes.Internal,
+		},
+		{
+			name: "invalid uuid",
+			uuid: "vm1",
+			want: codes.InvalidArgument,
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		uuid := f.libvirt.addDomain("vm1", libvirt.DomainShutoff)
+		if test.uuid != "" {
+			uuid = test.uuid
+		}
+
+		_, err := a.DeleteVirtualMachine(context.Background(), &pb.DeleteVirtualMachineRequest{Uuid: uuid})
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %+v", test.name, test.want, err)
+		}
+		if test.want == codes.OK && len(f.libvirt.domains) != 0 {
+			t.Errorf("%s: domain should be undefined", test.name)
+		}
+	}
+}
+
+func TestListVirtualMachineState(t *testing.T) {
+	a, f := newTestAgent(t)
+	f.libvirt.addDomain("vm1", libvirt.DomainRunning)
+	f.libvirt.addDomain("vm2", libvirt.DomainShutoff)
+
+	resp, err := a.ListVirtualMachineState(context.Background(), &pb.ListVirtualMachineStateRequest{})
+	if err != nil {
+		t.Fatalf("should not be error but: %+v", err)
+	}
+
+	want := map[string]pb.VirtualMachineState_State{
+		"vm1": pb.VirtualMachineState_RUNNING,
+		"vm2": pb.VirtualMachineState_SHUTOFF,
+	}
+	if len(resp.States) != len(want) {
+		t.Fatalf("want %d states, but got %d", len(want), len(resp.States))
+	}
+	for _, state := range resp.States {
+		if state.State != want[state.Name] {
+			t.Errorf("%s: want %s, but got %s", state.Name, want[state.Name], state.State)
+		}
+	}
+}
+
+func TestAttachBlockDevice(t *testing.T) {
+	for _, test := range modifyFlagTests {
+		a, f := newTestAgent(t)
+		uuid := f.libvirt.addDomain("vm1", test.state)
+
+		_, err := a.AttachBlockDevice(context.Background(), &pb.AttachBlockDeviceRequest{
+			Uuid:         uuid,
+			SourceDevice: "/dev/dm-1",
+			TargetDevice: "vdb",
+			ReadIopsSec:  1000,
+		})
+		if err != nil {
+			t.Fatalf("%s: should not be error but: %+v", test.name, err)
+		}
+
+		if len(f.libvirt.attached) != 1 {
+			t.Fatalf("%s: want 1 device, but got %d", test.name, len(f.libvirt.attached))
+		}
+		got := f.libvirt.attached[0]
+		if got.flags != test.want {
+			t.Errorf("%s: want flags %d, but got %d", test.name, test.want, got.flags)
+		}
+		for _, want := range []string{"<source dev='/dev/dm-1'/>", "<read_iops_sec>1000</read_iops_sec>"} {
+			if !strings.Contains(got.xml, want) {
+				t.Errorf("%s: want %s in device xml, but got %s", test.name, want, got.xml)
+			}
+		}
+		if strings.Contains(got.xml, "write_iops_sec") {
+			t.Errorf("%s: unset limits should be omitted, but got %s", test.name, got.xml)
+		}
+	}
+}
+
+func TestDetachBlockDevice(t *testing.T) {
+	for _, test := range modifyFlagTests {
+		a, f := newTestAgent(t)
+		uuid := f.libvirt.addDomain("vm1", test.state)
+
+		_, err := a.DetachBlockDevice(context.Background(), &pb.DetachBlockDeviceRequest{
+			Uuid:         uuid,
+			SourceDevice: "/dev/dm-1",
+			TargetDevice: "vdb",
+		})
+		if err != nil {
+			t.Fatalf("%s: should not be error but: %+v", test.name, err)
+		}
+
+		if len(f.libvirt.detached) != 1 {
+			t.Fatalf("%s: want 1 device, but got %d", test.name, len(f.libvirt.detached))
+		}
+		if got := f.libvirt.detached[0].flags; got != test.want {
+			t.Errorf("%s: want flags %d, but got %d", test.name, test.want, got)
+		}
+	}
+}
+
+func TestAttachInterface(t *testing.T) {
+	req := &pb.AttachInterfaceRequest{
+		Bridge:          "br1000",
+		InboundAverage:  1000,
+		OutboundAverage: 1000,
+		Name:            "tap0",
+		MacAddress:      "52:54:00:00:00:01",
+	}
+
+	for _, test := range modifyFlagTests {
+		a, f := newTestAgent(t)
+		req.Uuid = f.libvirt.addDomain("vm1", test.state)
+
+		if _, err := a.AttachInterface(context.Background(), req); err != nil {
+			t.Fatalf("%s: should not be error but: %+v", test.name, err)
+		}
+		if len(f.libvirt.attached) != 1 {
+			t.Fatalf("%s: want 1 device, but got %d", test.name, len(f.libvirt.attached))
+		}
+		if got := f.libvirt.attached[0].flags; got != test.want {
+			t.Errorf("%s: want flags %d, but got %d", test.name, test.want, got)
+		}
+
+		_, err := a.AttachInterface(context.Background(), req)
+		if got := status.Code(err); got != codes.AlreadyExists {
+			t.Errorf("%s: want %s, but got %+v", test.name, codes.AlreadyExists, err)
+		}
+	}
+}
+
+func TestAttachInterfaceAlreadyExists(t *testing.T) {
+	tests := []struct {
+		name       string
+		intfName   string
+		macAddress string
+		want       codes.Code
+	}{
+		{
+			name:       "same name",
+			intfName:   "tap0",
+			macAddress: "52:54:00:00:00:02",
+			want:       codes.AlreadyExists,
+		},
+		{
+			name:       "same mac address",
+			intfName:   "tap1",
+			macAddress: "52:54:00:00:00:0a",
+			want:       codes.AlreadyExists,
+		},
+		{
+			name:       "same mac address in upper case",
+			intfName:   "tap1",
+			macAddress: "52:54:00:00:00:0A",
+			want:       codes.AlreadyExists,
+		},
+		{
+			name:       "different interface",
+			intfName:   "tap1",
+			macAddress: "52:54:00:00:00:02",
+			want:       codes.OK,
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		ctx := context.Background()
+		uuid := f.libvirt.addDomain("vm1", libvirt.DomainShutoff)
+		if _, err := a.AttachInterface(ctx, &pb.AttachInterfaceRequest{Uuid: uuid, Bridge: "br1000", Name: "tap0", MacAddress: "52:54:00:00:00:0a"}); err != nil {
+			t.Fatalf("%s: failed to attach interface: %+v", test.name, err)
+		}
+
+		_, err := a.AttachInterface(ctx, &pb.AttachInterfaceRequest{Uuid: uuid, Bridge: "br1000", Name: test.intfName, MacAddress: test.macAddress})
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %+v", test.name, test.want, err)
+		}
+	}
+}
+
+func TestDetachInterface(t *testing.T) {
+	for _, test := range modifyFlagTests {
+		a, f := newTestAgent(t)
+		uuid := f.libvirt.addDomain("vm1", test.state)
+
+		_, err := a.DetachInterface(context.Background(), &pb.DetachInterfaceRequest{
+			Uuid:       uuid,
+			Bridge:     "br1000",
+			Name:       "tap0",
+			MacAddress: "52:54:00:00:00:01",
+		})
+		if err != nil {
+			t.Fatalf("%s: should not be error but: %+v", test.name, err)
+		}
+
+		if len(f.libvirt.detached) != 1 {
+			t.Fatalf("%s: want 1 device, but got %d", test.name, len(f.libvirt.detached))
+		}
+		if got := f.libvirt.detached[0].flags; got != test.want {
+			t.Errorf("%s: want flags %d, but got %d", test.name, test.want, got)
+		}
+	}
+}
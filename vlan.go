@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"syscall"
 
 	"github.com/vishvananda/netlink"
 	"google.golang.org/grpc/codes"
@@ -12,15 +14,27 @@ import (
 )
 
 func (a *agent) AddVLANInterface(ctx context.Context, req *pb.AddVLANInterfaceRequest) (*pb.AddVLANInterfaceResponse, error) {
-	_, err := netlink.LinkByName(fmt.Sprintf("%s.%d", req.ParentInterface, req.VlanId))
-	if err == nil {
-		// TODO: already exist
-		return &pb.AddVLANInterfaceResponse{}, nil
+	name := fmt.Sprintf("%s.%d", req.ParentInterface, req.VlanId)
+	unlock, err := a.lockBridge(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	_, err = a.netlink.LinkByName(name)
+	switch {
+	case err == nil:
+		return nil, alreadyExistsError("vlan interface", name)
+	case !isLinkNotFound(err):
+		return nil, status.Errorf(codes.Internal, "failed to find vlan interface: %+v", err)
 	}
 
-	parentLink, err := netlink.LinkByName(req.ParentInterface)
+	parentLink, err := a.netlink.LinkByName(req.ParentInterface)
+	if isLinkNotFound(err) {
+		return nil, notFoundError("parent interface", req.ParentInterface)
+	}
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "failed to find parent interface: %+v\n", err)
+		return nil, status.Errorf(codes.Internal, "failed to find parent interface: %+v", err)
 	}
 
 	vlan := &netlink.Vlan{
@@ -32,11 +46,15 @@ func (a *agent) AddVLANInterface(ctx context.Context, req *pb.AddVLANInterfaceRe
 		VlanProtocol: netlink.VLAN_PROTOCOL_8021Q,
 	}
 
-	if err := netlink.LinkAdd(vlan); err != nil {
+	if err := a.netlink.LinkAdd(vlan); err != nil {
+		// the interface may be created outside of teleskop
+		if errors.Is(err, syscall.EEXIST) {
+			return nil, alreadyExistsError("vlan interface", name)
+		}
 		return nil, status.Errorf(codes.Internal, "failed to create vlan interface: %+v", err)
 	}
 
-	if err := netlink.LinkSetUp(vlan); err != nil {
+	if err := a.netlink.LinkSetUp(vlan); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to set up vlan interface: %+v", err)
 	}
 
@@ -44,16 +62,26 @@ func (a *agent) AddVLANInterface(ctx context.Context, req *pb.AddVLANInterfaceRe
 }
 
 func (a *agent) DeleteVLANInterface(ctx context.Context, req *pb.DeleteVLANInterfaceRequest) (*pb.DeleteVLANInterfaceResponse, error) {
-	link, err := netlink.LinkByName(fmt.Sprintf("%s.%d", req.ParentInterface, req.VlanId))
+	name := fmt.Sprintf("%s.%d", req.ParentInterface, req.VlanId)
+	unlock, err := a.lockBridge(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	link, err := a.netlink.LinkByName(name)
+	if isLinkNotFound(err) {
+		return nil, notFoundError("vlan interface", name)
+	}
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "failed to find vlan interface: %+v\n", err)
+		return nil, status.Errorf(codes.Internal, "failed to find vlan interface: %+v", err)
 	}
 
-	if err := netlink.LinkSetDown(link); err != nil {
+	if err := a.netlink.LinkSetDown(link); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to set down vlan interface: %+v", err)
 	}
 
-	if err := netlink.LinkDel(link); err != nil {
+	if err := a.netlink.LinkDel(link); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to delete vlan interface: %+v", err)
 	}
 
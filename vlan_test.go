@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/vishvananda/netlink"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/lovi-cloud/teleskop/protoc/agent"
+)
+
+func TestAddVLANInterface(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing bool
+		// lookupErr is returned by netlink when looking up links
+		lookupErr error
+		req       *pb.AddVLANInterfaceRequest
+		want      codes.Code
+	}{
+		{
+			name: "new vlan interface",
+			req:  &pb.AddVLANInterfaceRequest{ParentInterface: "bond0", VlanId: 1000},
+			want: codes.OK,
+		},
+		{
+			name:     "existing vlan interface",
+			existing: true,
+			req:      &pb.AddVLANInterfaceRequest{ParentInterface: "bond0", VlanId: 1000},
+			want:     codes.AlreadyExists,
+		},
+		{
+			name: "missing parent interface",
+			req:  &pb.AddVLANInterfaceRequest{ParentInterface: "bond1", VlanId: 1000},
+			want: codes.NotFound,
+		},
+		{
+			name:      "lookup failure",
+			lookupErr: errors.New("no buffer space available"),
+			req:       &pb.AddVLANInterfaceRequest{ParentInterface: "bond0", VlanId: 1000},
+			want:      codes.Internal,
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		ctx := context.Background()
+		parent := &netlink.Bond{LinkAttrs: netlink.LinkAttrs{Name: "bond0"}}
+		f.netlink.LinkAdd(parent)
+		if test.existing {
+			f.netlink.LinkAdd(&netlink.Vlan{LinkAttrs: netlink.LinkAttrs{Name: "bond0.1000", ParentIndex: parent.Index}, VlanId: 1000})
+		}
+		f.netlink.lookupErr = test.lookupErr
+
+		_, err := a.AddVLANInterface(ctx, test.req)
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %+v", test.name, test.want, err)
+			continue
+		}
+		if test.want != codes.OK {
+			continue
+		}
+
+		link, err := f.netlink.LinkByName("bond0.1000")
+		if err != nil {
+			t.Fatalf("%s: vlan interface should be created but: %+v", test.name, err)
+		}
+		vlan, ok := link.(*netlink.Vlan)
+		if !ok || vlan.VlanId != 1000 || vlan.ParentIndex != parent.Index {
+			t.Errorf("%s: want vlan 1000 on bond0, but got %+v", test.name, link)
+		}
+		if link.Attrs().Flags&net.FlagUp == 0 {
+			t.Errorf("%s: vlan interface should be up", test.name)
+		}
+	}
+}
+
+func TestAddVLANInterfaceConcurrent(t *testing.T) {
+	a, f := newTestAgent(t)
+	f.netlink.LinkAdd(&netlink.Bond{LinkAttrs: netlink.LinkAttrs{Name: "bond0"}})
+
+	codeCh := make(chan codes.Code, 10)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := a.AddVLANInterface(context.Background(), &pb.AddVLANInterfaceRequest{ParentInterface: "bond0", VlanId: 1000})
+			codeCh <- status.Code(err)
+		}()
+	}
+	wg.Wait()
+	close(codeCh)
+
+	got := map[codes.Code]int{}
+	for code := range codeCh {
+		got[code]++
+	}
+	if got[codes.OK] != 1 || got[codes.AlreadyExists] != 9 {
+		t.Errorf("want 1 %s and 9 %s, but got %v", codes.OK, codes.AlreadyExists, got)
+	}
+}
+
+func TestDeleteVLANInterface(t *testing.T) {
+	tests := []struct {
+		name string
+		// lookupErr is returned by netlink when looking up links
+		lookupErr error
+		req       *pb.DeleteVLANInterfaceRequest
+		want      codes.Code
+	}{
+		{
+			name: "existing vlan interface",
+			req:  &pb.DeleteVLANInterfaceRequest{ParentInterface: "bond0", VlanId: 1000},
+			want: codes.OK,
+		},
+		{
+			name: "missing vlan interface",
+			req:  &pb.DeleteVLANInterfaceRequest{ParentInterface: "bond0", VlanId: 1001},
+			want: codes.NotFound,
+		},
+		{
+			name:      "lookup failure",
+			lookupErr: errors.New("no buffer space available"),
+			req:       &pb.DeleteVLANInterfaceRequest{ParentInterface: "bond0", VlanId: 1000},
+			want:      codes.Internal,
+		},
+	}
+	for _, test := range tests {
+		a, f := newTestAgent(t)
+		parent := &netlink.Bond{LinkAttrs: netlink.LinkAttrs{Name: "bond0"}}
+		f.netlink.LinkAdd(parent)
+		f.netlink.LinkAdd(&netlink.Vlan{LinkAttrs: netlink.LinkAttrs{Name: "bond0.1000", ParentIndex: parent.Index}, VlanId: 1000})
+		f.netlink.lookupErr = test.lookupErr
+
+		_, err := a.DeleteVLANInterface(context.Background(), test.req)
+		if got := status.Code(err); got != test.want {
+			t.Errorf("%s: want %s, but got %+v", test.name, test.want, err)
+			continue
+		}
+		if _, err := f.netlink.LinkByName("bond0.1000"); test.want == codes.OK && err == nil {
+			t.Errorf("%s: vlan interface should be deleted", test.name)
+		}
+	}
+}